@@ -5,7 +5,10 @@ go 1.13
 require (
 	github.com/gin-gonic/gin v1.5.0
 	github.com/jstemmer/go-junit-report v0.9.1 // indirect
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/stretchr/testify v1.4.0
+	go.etcd.io/bbolt v1.3.6
 	go.uber.org/zap v1.13.0
+	gopkg.in/yaml.v2 v2.2.2
 	gotest.tools/gotestsum v0.4.0 // indirect
 )
@@ -1,7 +1,14 @@
 package app
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/notifications"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+	"github.com/greendinosaur/gh-commit-info/src/api/tracker"
 )
 
 var (
@@ -14,9 +21,133 @@ func init() {
 
 //StartApp is the main entry point to the REST API app
 func StartApp() {
+	openDataStore()
+	startPeriodicSync()
+	loadNotifications()
+	loadPolicies()
+	loadMailmap()
+	loadTeams()
+	loadIssueTracker()
+	startScheduler()
 	mapURLs()
 
 	if err := router.Run(":8080"); err != nil {
 		panic(err)
 	}
 }
+
+//openDataStore opens the database used to persist the Github data, if one has been configured
+func openDataStore() {
+	path := config.GetDataStorePath()
+	if path == "" {
+		log.Println("no data store configured, all data will be fetched from Github")
+		return
+	}
+
+	dataStore, err := store.NewBoltStore(path)
+	if err != nil {
+		panic(err)
+	}
+	services.SetDataStore(dataStore)
+}
+
+//startPeriodicSync keeps the configured repos synced into the data store
+func startPeriodicSync() {
+	repos := config.GetSyncRepos()
+	interval := config.GetSyncInterval()
+	if len(repos) == 0 || interval == 0 {
+		return
+	}
+
+	log.Println("syncing", repos, "every", interval)
+	services.StartPeriodicSync(repos, interval)
+}
+
+//loadNotifications sets where the outcome of the scheduled reports is sent, if a notifications file has been configured
+func loadNotifications() {
+	path := config.GetNotificationsFile()
+	if path == "" {
+		return
+	}
+
+	notifier, err := notifications.LoadConfig(path)
+	if err != nil {
+		panic(err)
+	}
+	services.SetReportNotifier(notifier)
+}
+
+//loadPolicies sets the review rules open PRs are evaluated against, if a policy file has been configured
+func loadPolicies() {
+	path := config.GetPolicyFile()
+	if path == "" {
+		return
+	}
+
+	policies, err := config.LoadPolicies(path)
+	if err != nil {
+		panic(err)
+	}
+	services.SetReviewPolicies(policies)
+}
+
+//loadMailmap sets the mailmap used to link commit authors to people, if a mailmap file has been configured
+func loadMailmap() {
+	path := config.GetMailmapFile()
+	if path == "" {
+		return
+	}
+
+	mailmap, err := config.LoadMailmap(path)
+	if err != nil {
+		panic(err)
+	}
+	services.SetIdentityMailmap(mailmap)
+}
+
+//loadTeams sets the teams the reports and metrics are broken down by, if a teams file has been configured
+//the members of any linked Github teams are synced straight away, the directory file is still used if that fails
+func loadTeams() {
+	path := config.GetTeamsFile()
+	if path == "" {
+		return
+	}
+
+	directory, err := config.LoadTeams(path)
+	if err != nil {
+		panic(err)
+	}
+	services.SetTeamDirectory(directory)
+
+	if _, err := services.TeamService.SyncTeams(); err != nil {
+		log.Println("unable to sync the teams from Github:", err.Message())
+	}
+}
+
+//loadIssueTracker sets where the tickets referenced by commits and PRs are looked up, if a Jira has been configured
+func loadIssueTracker() {
+	URL := config.GetJiraURL()
+	if URL == "" {
+		return
+	}
+
+	log.Println("looking up tickets in", URL)
+	services.SetIssueTracker(tracker.NewJiraTracker(URL, config.GetJiraUser(), config.GetJiraToken()))
+}
+
+//startScheduler runs the reports defined in the schedules file, if one has been configured
+func startScheduler() {
+	path := config.GetSchedulesFile()
+	if path == "" {
+		return
+	}
+
+	schedules, err := config.LoadSchedules(path)
+	if err != nil {
+		panic(err)
+	}
+	if _, err := services.StartScheduler(schedules); err != nil {
+		panic(err)
+	}
+	log.Println("running", len(schedules), "scheduled reports from", path)
+}
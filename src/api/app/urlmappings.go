@@ -1,17 +1,39 @@
 package app
 
 import (
+	"github.com/greendinosaur/gh-commit-info/src/api/controllers/admin"
 	"github.com/greendinosaur/gh-commit-info/src/api/controllers/bobby"
+	"github.com/greendinosaur/gh-commit-info/src/api/controllers/metrics"
+	"github.com/greendinosaur/gh-commit-info/src/api/controllers/orgs"
 	"github.com/greendinosaur/gh-commit-info/src/api/controllers/repos"
+	"github.com/greendinosaur/gh-commit-info/src/api/controllers/webhooks"
 )
 
 func mapURLs() {
 	router.GET("/bobby", bobby.Chariot)
+	router.GET("/repos/:owner/:repo", repos.GetRepo)
 	router.GET("/repos/:owner/:repo/pulls", repos.GetRepoPRs)
 	router.GET("/repos/:owner/:repo/pulls/:pull", repos.GetRepoSinglePR)
+	router.GET("/repos/:owner/:repo/pulls/:pull/reviews", repos.GetPRReviews)
+	router.GET("/repos/:owner/:repo/pulls/:pull/files", repos.GetPRFiles)
+	router.GET("/repos/:owner/:repo/pulls/:pull/compliance", repos.GetPRCompliance)
 	router.GET("/repos/:owner/:repo/commits", repos.GetRepoCommits)
 	router.GET("/repos/:owner/:repo/commits/:sha", repos.GetRepoSingleCommit)
 	router.GET("/repos/:owner/:repo/commits/:sha/pulls", repos.GetPRsForSingleCommit)
+	router.POST("/repos/:owner/:repo/commits/:sha/compliance", admin.RequireAdminToken, repos.PublishCommitCompliance)
+	router.GET("/repos/:owner/:repo/releasenotes", repos.GetReleaseNotes)
+	router.GET("/repos/:owner/:repo/protection", repos.GetBranchProtection)
+	router.GET("/repos/:owner/:repo/sync", repos.GetSyncState)
+	router.POST("/repos/:owner/:repo/sync", admin.RequireAdminToken, repos.SyncRepo)
 	router.GET("/codereview/:owner/:repo", repos.GetCodeReviewReport)
+	router.GET("/orgs/:owner/protection", orgs.GetBranchProtection)
+	router.GET("/metrics/:owner/:repo/pulls", metrics.GetPRMetrics)
+	router.GET("/metrics/:owner/:repo/dora", metrics.GetDORAMetrics)
+	router.GET("/metrics/:owner/:repo/contributors", metrics.GetContributorActivity)
+	router.POST("/webhooks/github", webhooks.ReceiveGithubEvent)
+	router.GET("/admin/schedules", admin.RequireAdminToken, admin.GetSchedules)
+	router.GET("/admin/schedules/:name/runs", admin.RequireAdminToken, admin.GetScheduleRuns)
+	router.GET("/admin/teams", admin.RequireAdminToken, admin.GetTeams)
+	router.POST("/admin/teams/sync", admin.RequireAdminToken, admin.SyncTeams)
 
 }
@@ -1,20 +1,26 @@
 package app
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
 	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
 	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
 	"github.com/stretchr/testify/assert"
 )
 
+const testAdminToken = "myadmintoken"
+
 func TestMain(m *testing.M) {
 	restclient.StartMockups()
+	os.Setenv("SECRET_ADMIN_TOKEN", testAdminToken)
 	mapURLs()
 	os.Exit(m.Run())
 }
@@ -28,6 +34,15 @@ func performRequest(r http.Handler, method, path string) *httptest.ResponseRecor
 
 }
 
+//performAdminRequest performs the request with the admin token
+func performAdminRequest(r http.Handler, method, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
 func TestMapURLsServiceRunning(t *testing.T) {
 
 	w := performRequest(router, "GET", "/bobby")
@@ -47,7 +62,7 @@ func TestGetRepoPRsErrorFromGithub(t *testing.T) {
 
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myowner/myrepo/pulls?state=all",
+		URL:        "https://api.github.com/repos/myowner/myrepo/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
@@ -91,5 +106,207 @@ func TestGetRepoSinglePRErrorFromGithub(t *testing.T) {
 	assert.EqualValues(t, "Requires authentication", apiErr.Message())
 }
 
+func TestGetPRReviewsErrorFromGithub(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myowner/myrepo/pulls/1/reviews?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+		Err: nil,
+	})
+
+	w := performRequest(router, "GET", "/repos/myowner/myrepo/pulls/1/reviews")
+
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(w.Body.Bytes())
+	assert.Nil(t, err)
+	assert.NotNil(t, apiErr)
+	assert.EqualValues(t, "Requires authentication", apiErr.Message())
+}
+
+func TestGetPRFilesMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetService()
+
+	w := performRequest(router, http.MethodGet, "/repos/myowner/myrepo/pulls/abc/files")
+	assert.EqualValues(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetRepoErrorFromGithub(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myowner/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+		Err: nil,
+	})
+
+	w := performRequest(router, "GET", "/repos/myowner/myrepo")
+
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(w.Body.Bytes())
+	assert.Nil(t, err)
+	assert.NotNil(t, apiErr)
+	assert.EqualValues(t, http.StatusUnauthorized, apiErr.Status())
+	assert.EqualValues(t, "Requires authentication", apiErr.Message())
+}
+
 //TODO: missing tests for the three commit based API calls, not added as likely these won't be exposed
 //in this way in the future as the API exposed to clients will change
+
+func TestReceiveGithubEventMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetWebhookService()
+	os.Setenv("SECRET_GITHUB_WEBHOOK_SECRET", "mysecret")
+
+	payload := testutils.GetMockDataPingEventPayload()
+	req, _ := http.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "ping")
+	req.Header.Set("X-GitHub-Delivery", "delivery1")
+	req.Header.Set("X-Hub-Signature-256", testutils.GetWebhookSignature("mysecret", payload))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSyncRepoMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetService()
+	services.ResetSyncService()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myowner/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	w := performAdminRequest(router, http.MethodPost, "/repos/myowner/myrepo/sync")
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/repos/myowner/myrepo/sync")
+	assert.EqualValues(t, http.StatusNotFound, w.Code)
+}
+
+func TestSchedulesMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetSchedulerService()
+
+	w := performAdminRequest(router, http.MethodGet, "/admin/schedules")
+	assert.EqualValues(t, http.StatusOK, w.Code)
+	assert.EqualValues(t, "[]", strings.TrimSpace(w.Body.String()))
+
+	w = performAdminRequest(router, http.MethodGet, "/admin/schedules/weekly/runs")
+	assert.EqualValues(t, http.StatusNotFound, w.Code)
+}
+
+func TestTeamsMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetTeamService()
+
+	w := performAdminRequest(router, http.MethodGet, "/admin/teams")
+	assert.EqualValues(t, http.StatusOK, w.Code)
+
+	w = performAdminRequest(router, http.MethodPost, "/admin/teams/sync")
+	assert.EqualValues(t, http.StatusOK, w.Code)
+}
+
+func TestPublishCommitComplianceMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetPublishService()
+
+	w := performAdminRequest(router, http.MethodPost, "/repos/myowner/myrepo/commits/AAA111/compliance?target=comment")
+	assert.EqualValues(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminRoutesNeedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, route := range [][]string{
+		{http.MethodPost, "/repos/myowner/myrepo/commits/AAA111/compliance?target=comment"},
+		{http.MethodPost, "/repos/myowner/myrepo/sync"},
+		{http.MethodGet, "/admin/schedules"},
+		{http.MethodGet, "/admin/schedules/weekly/runs"},
+		{http.MethodGet, "/admin/teams"},
+		{http.MethodPost, "/admin/teams/sync"},
+	} {
+		w := performRequest(router, route[0], route[1])
+		assert.EqualValues(t, http.StatusUnauthorized, w.Code, route[1])
+	}
+}
+
+func TestPRComplianceMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetComplianceService()
+
+	w := performRequest(router, http.MethodGet, "/repos/myowner/myrepo/pulls/abc/compliance")
+	assert.EqualValues(t, http.StatusBadRequest, w.Code)
+}
+
+func TestReleaseNotesMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetReleaseNotesService()
+
+	w := performRequest(router, http.MethodGet, "/repos/myowner/myrepo/releasenotes?to=v1.3.0")
+	assert.EqualValues(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMetricsMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetMetricsService()
+
+	w := performRequest(router, http.MethodGet, "/metrics/myowner/myrepo/pulls?from=yesterday")
+	assert.EqualValues(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/metrics/myowner/myrepo/dora?source=tags")
+	assert.EqualValues(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/metrics/myowner/myrepo/contributors?include_bots=maybe")
+	assert.EqualValues(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBranchProtectionMapped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetService()
+	services.ResetProtectionService()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myowner/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/repos?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	w := performRequest(router, http.MethodGet, "/repos/myowner/myrepo/protection")
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/orgs/myorg/protection")
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 var (
@@ -15,6 +16,8 @@ var (
 
 const (
 	missingMock = "no mockup found for given request"
+	//requestTimeout stops a request hanging when the other end doesn't answer
+	requestTimeout = 60 * time.Second
 )
 
 //Mock is used to mock up responses given a request
@@ -68,7 +71,7 @@ func getContent(method string, URL string, body interface{}, headers http.Header
 	request, err := http.NewRequest(method, URL, bytes.NewReader(jsonBytes))
 	request.Header = headers
 
-	client := http.Client{}
+	client := http.Client{Timeout: requestTimeout}
 	return client.Do(request)
 }
 
@@ -1,9 +1,19 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strings"
+	"time"
+)
 
 const (
-	apiGitHubAccessToken = "SECRET_GITHUB_ACCESS_TOKEN"
+	apiGitHubAccessToken   = "SECRET_GITHUB_ACCESS_TOKEN"
+	apiGitHubWebhookSecret = "SECRET_GITHUB_WEBHOOK_SECRET"
+	apiAdminToken          = "SECRET_ADMIN_TOKEN"
+	apiDataStorePath       = "DATA_STORE_PATH"
+	apiSyncRepos           = "SYNC_REPOS"
+	apiSyncInterval        = "SYNC_INTERVAL"
+	apiNotificationsFile   = "NOTIFICATIONS_FILE"
 	//LogLevel to be used across the application
 	LogLevel = "info"
 )
@@ -16,3 +26,48 @@ var (
 func GetGithubAccessToken() string {
 	return githubAccessToken
 }
+
+//GetGithubWebhookSecret returns the secret shared with Github that is used to sign webhook deliveries
+//read each time so the secret can be rotated without restarting
+func GetGithubWebhookSecret() string {
+	return os.Getenv(apiGitHubWebhookSecret)
+}
+
+//GetAdminToken returns the token callers must present to use the routes that change data or send messages
+//read each time so the token can be rotated without restarting, an empty token turns those routes off
+func GetAdminToken() string {
+	return os.Getenv(apiAdminToken)
+}
+
+//GetDataStorePath returns the path of the database used to persist the Github data
+//an empty path means nothing is persisted and Github is called every time
+func GetDataStorePath() string {
+	return os.Getenv(apiDataStorePath)
+}
+
+//GetSyncRepos returns the repos, each given as owner/repo, that are synced into the data store on a schedule
+func GetSyncRepos() []string {
+	var repos []string
+	for _, repo := range strings.Split(os.Getenv(apiSyncRepos), ",") {
+		if repo = strings.TrimSpace(repo); repo != "" {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+//GetSyncInterval returns how often the repos are synced, such as 30m or 1h
+//zero is returned if it isn't set or is invalid, the repos are then only synced on demand
+func GetSyncInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv(apiSyncInterval))
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return interval
+}
+
+//GetNotificationsFile returns the path of the yaml file defining where the outcome of the scheduled reports is sent
+//an empty path means no notifications are sent
+func GetNotificationsFile() string {
+	return os.Getenv(apiNotificationsFile)
+}
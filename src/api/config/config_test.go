@@ -1,13 +1,70 @@
 package config
 
 import (
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestConstants(t *testing.T) {
 	assert.EqualValues(t, "SECRET_GITHUB_ACCESS_TOKEN", apiGitHubAccessToken)
+	assert.EqualValues(t, "SECRET_GITHUB_WEBHOOK_SECRET", apiGitHubWebhookSecret)
+	assert.EqualValues(t, "SECRET_ADMIN_TOKEN", apiAdminToken)
+	assert.EqualValues(t, "DATA_STORE_PATH", apiDataStorePath)
+	assert.EqualValues(t, "SYNC_REPOS", apiSyncRepos)
+	assert.EqualValues(t, "SYNC_INTERVAL", apiSyncInterval)
+	assert.EqualValues(t, "NOTIFICATIONS_FILE", apiNotificationsFile)
 	assert.EqualValues(t, "info", LogLevel)
 
 }
+
+func TestGetGithubWebhookSecret(t *testing.T) {
+	os.Setenv(apiGitHubWebhookSecret, "my secret")
+	defer os.Unsetenv(apiGitHubWebhookSecret)
+	assert.EqualValues(t, "my secret", GetGithubWebhookSecret())
+}
+
+func TestGetAdminToken(t *testing.T) {
+	os.Setenv(apiAdminToken, "my token")
+	defer os.Unsetenv(apiAdminToken)
+	assert.EqualValues(t, "my token", GetAdminToken())
+}
+
+func TestGetDataStorePath(t *testing.T) {
+	os.Setenv(apiDataStorePath, "data/gh-commit-info.db")
+	defer os.Unsetenv(apiDataStorePath)
+	assert.EqualValues(t, "data/gh-commit-info.db", GetDataStorePath())
+}
+
+func TestGetSyncRepos(t *testing.T) {
+	os.Setenv(apiSyncRepos, "")
+	assert.Nil(t, GetSyncRepos())
+
+	os.Setenv(apiSyncRepos, "myuser/myrepo, myuser/another ,")
+	defer os.Unsetenv(apiSyncRepos)
+	assert.EqualValues(t, []string{"myuser/myrepo", "myuser/another"}, GetSyncRepos())
+}
+
+func TestGetSyncInterval(t *testing.T) {
+	defer os.Unsetenv(apiSyncInterval)
+
+	os.Setenv(apiSyncInterval, "")
+	assert.EqualValues(t, 0, GetSyncInterval())
+
+	os.Setenv(apiSyncInterval, "rubbish")
+	assert.EqualValues(t, 0, GetSyncInterval())
+
+	os.Setenv(apiSyncInterval, "-1h")
+	assert.EqualValues(t, 0, GetSyncInterval())
+
+	os.Setenv(apiSyncInterval, "30m")
+	assert.EqualValues(t, 30*time.Minute, GetSyncInterval())
+}
+
+func TestGetNotificationsFile(t *testing.T) {
+	os.Setenv(apiNotificationsFile, "notifications.yaml")
+	defer os.Unsetenv(apiNotificationsFile)
+	assert.EqualValues(t, "notifications.yaml", GetNotificationsFile())
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/identitydomain"
+)
+
+const (
+	apiMailmapFile = "MAILMAP_FILE"
+)
+
+//GetMailmapFile returns the path of the file, in the git .mailmap format, mapping the names and emails used in commits to people
+//an empty path means only the Github logins and noreply emails are used to identify people
+func GetMailmapFile() string {
+	return os.Getenv(apiMailmapFile)
+}
+
+//LoadMailmap reads the mailmap file
+func LoadMailmap(path string) (*identitydomain.Mailmap, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mailmap, err := identitydomain.ParseMailmap(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid mailmap file %s: %s", path, err.Error())
+	}
+	return mailmap, nil
+}
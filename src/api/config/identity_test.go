@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMailmapFile(t *testing.T) {
+	assert.EqualValues(t, "MAILMAP_FILE", apiMailmapFile)
+	os.Setenv(apiMailmapFile, ".mailmap")
+	defer os.Unsetenv(apiMailmapFile)
+	assert.EqualValues(t, ".mailmap", GetMailmapFile())
+}
+
+func TestLoadMailmap(t *testing.T) {
+	path, cleanup := writeSchedulesFile(t, "Jane Doe <jane@example.com> <jane@old.example.com>\n")
+	defer cleanup()
+
+	mailmap, err := LoadMailmap(path)
+	assert.Nil(t, err)
+	name, email := mailmap.Lookup("Jane", "jane@old.example.com")
+	assert.EqualValues(t, "Jane Doe", name)
+	assert.EqualValues(t, "jane@example.com", email)
+}
+
+func TestLoadMailmapInvalid(t *testing.T) {
+	mailmap, err := LoadMailmap("does-not-exist")
+	assert.Nil(t, mailmap)
+	assert.NotNil(t, err)
+
+	path, cleanup := writeSchedulesFile(t, "Jane Doe\n")
+	defer cleanup()
+	mailmap, err = LoadMailmap(path)
+	assert.Nil(t, mailmap)
+	assert.Contains(t, err.Error(), "invalid mailmap file")
+}
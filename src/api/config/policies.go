@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	apiPolicyFile = "POLICY_FILE"
+)
+
+//GetPolicyFile returns the path of the yaml file defining the review rules PRs are evaluated against
+//an empty path means the default rules are used
+func GetPolicyFile() string {
+	return os.Getenv(apiPolicyFile)
+}
+
+//LoadPolicies reads the review rules from the yaml file, anything left out of the default takes the default value
+func LoadPolicies(path string) (*policydomain.Policies, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := policydomain.NewDefaultPolicies()
+	if err := yaml.UnmarshalStrict(data, policies); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %s", path, err.Error())
+	}
+
+	if err := validateReviewPolicy(&policies.Default); err != nil {
+		return nil, fmt.Errorf("invalid default policy in %s: %s", path, err.Error())
+	}
+	for i := range policies.Repos {
+		if policies.Repos[i].Repo == "" {
+			return nil, fmt.Errorf("invalid repo policy %d in %s: a repo is required", i+1, path)
+		}
+		if err := validateReviewPolicy(&policies.Repos[i].ReviewPolicy); err != nil {
+			return nil, fmt.Errorf("invalid repo policy %d in %s: %s", i+1, path, err.Error())
+		}
+	}
+	return policies, nil
+}
+
+//validateReviewPolicy checks the policy can be met
+func validateReviewPolicy(policy *policydomain.ReviewPolicy) error {
+	if policy.RequiredApprovals < 0 {
+		return fmt.Errorf("required_approvals can't be negative")
+	}
+	if policy.LargeChangeLines < 0 {
+		return fmt.Errorf("large_change_lines can't be negative")
+	}
+	for _, check := range policy.RequiredStatusChecks {
+		if check == "" {
+			return fmt.Errorf("a required status check can't be empty")
+		}
+	}
+	if err := validateCommitMessageRules(&policy.CommitMessages); err != nil {
+		return err
+	}
+	if err := validateTicketRules(&policy.Tickets); err != nil {
+		return err
+	}
+	for _, exempt := range policy.Signatures.Exempt {
+		if exempt == "" {
+			return fmt.Errorf("an author exempt from signing can't be empty")
+		}
+	}
+	return validateRubberStampRules(&policy.RubberStamps)
+}
+
+//validateRubberStampRules checks the thresholds and that enough signals are turned on for an approval to ever be flagged
+func validateRubberStampRules(rules *policydomain.RubberStampRules) error {
+	if rules.MinReviewSeconds < 0 || rules.LargeChangeLines < 0 || rules.MinReviewComments < 0 || rules.MinSignals < 0 {
+		return fmt.Errorf("the rubber_stamps thresholds can't be negative")
+	}
+	if rules.Enabled && rules.MinSignals > rules.CountSignals() {
+		return fmt.Errorf("rubber_stamps needs min_signals %d but only %d signals are turned on", rules.MinSignals, rules.CountSignals())
+	}
+	return nil
+}
+
+//validateCommitMessageRules checks the rules for commit messages, any format is allowed when it is left out
+func validateCommitMessageRules(rules *policydomain.CommitMessageRules) error {
+	if rules.Format == "" {
+		rules.Format = policydomain.MessageFormatAny
+	}
+	if rules.Format != policydomain.MessageFormatAny && rules.Format != policydomain.MessageFormatConventional {
+		return fmt.Errorf("unknown commit message format %s, expected any or conventional", rules.Format)
+	}
+	if rules.MaxSubjectLength < 0 {
+		return fmt.Errorf("max_subject_length can't be negative")
+	}
+	for _, messageType := range rules.Types {
+		if messageType == "" {
+			return fmt.Errorf("a commit message type can't be empty")
+		}
+	}
+	return nil
+}
+
+//validateTicketRules checks each pattern used to find the ticket keys is a valid regular expression
+func validateTicketRules(rules *policydomain.TicketRules) error {
+	for _, pattern := range rules.Patterns {
+		if pattern == "" {
+			return fmt.Errorf("a ticket pattern can't be empty")
+		}
+	}
+	if _, err := rules.CompilePatterns(); err != nil {
+		return fmt.Errorf("invalid ticket pattern: %s", err.Error())
+	}
+	for _, state := range rules.AllowedStates {
+		if state == "" {
+			return fmt.Errorf("an allowed ticket state can't be empty")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPolicyFile(t *testing.T) {
+	assert.EqualValues(t, "POLICY_FILE", apiPolicyFile)
+	os.Setenv(apiPolicyFile, "policy.yaml")
+	defer os.Unsetenv(apiPolicyFile)
+	assert.EqualValues(t, "policy.yaml", GetPolicyFile())
+}
+
+func TestLoadPolicies(t *testing.T) {
+	path, cleanup := writeSchedulesFile(t, `
+default:
+  required_approvals: 2
+  require_code_owner_review: true
+  large_change_lines: 500
+  rubber_stamps:
+    enabled: true
+    min_review_seconds: 60
+repos:
+  - repo: myorg/docs
+    required_approvals: 1
+  - repo: myorg/*
+    required_approvals: 2
+    dismiss_stale_approvals: true
+    required_status_checks: [ci/build, ci/test]
+    enforce_admins: true
+    commit_messages:
+      format: conventional
+      types: [feat, fix]
+      max_subject_length: 72
+      require_sign_off: true
+    tickets:
+      required: true
+      patterns: ['\b(?:PAY|OPS)-\d+\b']
+      allowed_states: [In Progress, In Review]
+    signatures:
+      required: true
+      exempt: ["dependabot[bot]", ci@example.com]
+    rubber_stamps:
+      enabled: true
+      min_review_comments: 2
+      min_signals: 1
+`)
+	defer cleanup()
+
+	policies, err := LoadPolicies(path)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, policies.Default.RequiredApprovals)
+	assert.True(t, policies.Default.RequireCodeOwnerReview)
+	assert.True(t, policies.Default.DismissStaleApprovals)
+	assert.EqualValues(t, 500, policies.Default.LargeChangeLines)
+	assert.EqualValues(t, 2, len(policies.Repos))
+	assert.EqualValues(t, 1, policies.ForRepo("myorg", "docs").RequiredApprovals)
+	assert.False(t, policies.ForRepo("myorg", "docs").DismissStaleApprovals)
+	assert.EqualValues(t, []string{"ci/build", "ci/test"}, policies.ForRepo("myorg", "web").RequiredStatusChecks)
+	assert.True(t, policies.ForRepo("myorg", "web").EnforceAdmins)
+	assert.False(t, policies.Default.EnforceAdmins)
+
+	assert.EqualValues(t, policydomain.MessageFormatAny, policies.Default.CommitMessages.Format)
+	rules := policies.ForRepo("myorg", "web").CommitMessages
+	assert.EqualValues(t, policydomain.MessageFormatConventional, rules.Format)
+	assert.EqualValues(t, []string{"feat", "fix"}, rules.Types)
+	assert.EqualValues(t, 72, rules.MaxSubjectLength)
+	assert.True(t, rules.RequireSignOff)
+
+	assert.False(t, policies.Default.Tickets.Required)
+	tickets := policies.ForRepo("myorg", "web").Tickets
+	assert.True(t, tickets.Required)
+	assert.EqualValues(t, []string{`\b(?:PAY|OPS)-\d+\b`}, tickets.Patterns)
+	assert.EqualValues(t, []string{"In Progress", "In Review"}, tickets.AllowedStates)
+
+	assert.False(t, policies.Default.Signatures.Required)
+	signatures := policies.ForRepo("myorg", "web").Signatures
+	assert.True(t, signatures.Required)
+	assert.EqualValues(t, []string{"dependabot[bot]", "ci@example.com"}, signatures.Exempt)
+
+	//the rules left out of the default keep their default values but an override replaces them all
+	assert.EqualValues(t, policydomain.RubberStampRules{Enabled: true, MinReviewSeconds: 60, LargeChangeLines: 500, MinReviewComments: 1, MinSignals: 2}, policies.Default.RubberStamps)
+	assert.EqualValues(t, policydomain.RubberStampRules{Enabled: true, MinReviewComments: 2, MinSignals: 1}, policies.ForRepo("myorg", "web").RubberStamps)
+	assert.False(t, policies.ForRepo("myorg", "docs").RubberStamps.Enabled)
+}
+
+func TestLoadPoliciesMissingFile(t *testing.T) {
+	policies, err := LoadPolicies("does-not-exist.yaml")
+	assert.Nil(t, policies)
+	assert.NotNil(t, err)
+}
+
+func TestLoadPoliciesUnknownField(t *testing.T) {
+	path, cleanup := writeSchedulesFile(t, "default:\n  approvals: 2\n")
+	defer cleanup()
+
+	policies, err := LoadPolicies(path)
+	assert.Nil(t, policies)
+	assert.Contains(t, err.Error(), "invalid policy file")
+}
+
+func TestLoadPoliciesInvalid(t *testing.T) {
+	path, cleanup := writeSchedulesFile(t, "default:\n  required_approvals: -1\n")
+	defer cleanup()
+	_, err := LoadPolicies(path)
+	assert.Contains(t, err.Error(), "required_approvals can't be negative")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  large_change_lines: -1\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "large_change_lines can't be negative")
+
+	path, cleanup = writeSchedulesFile(t, "repos:\n  - required_approvals: 1\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "a repo is required")
+
+	path, cleanup = writeSchedulesFile(t, "repos:\n  - repo: myorg/*\n    required_status_checks: [\"\"]\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "a required status check can't be empty")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  commit_messages:\n    format: gitmoji\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "unknown commit message format gitmoji")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  commit_messages:\n    max_subject_length: -1\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "max_subject_length can't be negative")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  commit_messages:\n    types: [feat, \"\"]\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "a commit message type can't be empty")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  tickets:\n    patterns: ['PAY-(']\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "invalid ticket pattern")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  tickets:\n    patterns: [\"\"]\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "a ticket pattern can't be empty")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  tickets:\n    allowed_states: [Done, \"\"]\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "an allowed ticket state can't be empty")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  signatures:\n    exempt: [\"\"]\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "an author exempt from signing can't be empty")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  rubber_stamps:\n    min_review_seconds: -1\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "the rubber_stamps thresholds can't be negative")
+
+	path, cleanup = writeSchedulesFile(t, "default:\n  rubber_stamps:\n    enabled: true\n    min_review_comments: 0\n    min_signals: 3\n")
+	defer cleanup()
+	_, err = LoadPolicies(path)
+	assert.Contains(t, err.Error(), "rubber_stamps needs min_signals 3 but only 2 signals are turned on")
+}
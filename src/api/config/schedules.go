@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	apiSchedulesFile = "SCHEDULES_FILE"
+)
+
+//schedulesFile is the layout of the file defining the scheduled reports
+type schedulesFile struct {
+	Schedules []scheduledomain.Schedule `yaml:"schedules"`
+}
+
+//GetSchedulesFile returns the path of the yaml file defining the reports that are run on a schedule
+//an empty path means no reports are scheduled
+func GetSchedulesFile() string {
+	return os.Getenv(apiSchedulesFile)
+}
+
+//ParseScheduleCron parses the cron expression of the schedule in its timezone
+//the standard five field expressions are supported along with descriptors such as @daily
+func ParseScheduleCron(schedule *scheduledomain.Schedule) (cron.Schedule, error) {
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone %s: %s", schedule.Timezone, err.Error())
+	}
+	return cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", schedule.Timezone, schedule.Cron))
+}
+
+//LoadSchedules reads the scheduled reports from the yaml file
+//every schedule is validated so a mistake is found on startup rather than when the report is due
+func LoadSchedules(path string) ([]scheduledomain.Schedule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file schedulesFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid schedules file %s: %s", path, err.Error())
+	}
+
+	names := make(map[string]bool)
+	for i := range file.Schedules {
+		schedule := &file.Schedules[i]
+		if err := validateSchedule(schedule); err != nil {
+			return nil, fmt.Errorf("invalid schedule %d in %s: %s", i+1, path, err.Error())
+		}
+		if names[schedule.Name] {
+			return nil, fmt.Errorf("invalid schedule %d in %s: the name %s is used more than once", i+1, path, schedule.Name)
+		}
+		names[schedule.Name] = true
+	}
+	return file.Schedules, nil
+}
+
+//validateSchedule checks the schedule can be run, filling in the defaults for anything left out
+func validateSchedule(schedule *scheduledomain.Schedule) error {
+	if schedule.Name == "" {
+		return fmt.Errorf("a name is required")
+	}
+	if schedule.Owner == "" {
+		return fmt.Errorf("an owner is required for %s", schedule.Name)
+	}
+
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+	if schedule.Report == "" {
+		schedule.Report = scheduledomain.ReportCodeReview
+	}
+	if schedule.MissedRuns == "" {
+		schedule.MissedRuns = scheduledomain.MissedRunsSkip
+	}
+
+	if schedule.Report != scheduledomain.ReportCodeReview {
+		return fmt.Errorf("unknown report %s for %s", schedule.Report, schedule.Name)
+	}
+	switch schedule.MissedRuns {
+	case scheduledomain.MissedRunsSkip, scheduledomain.MissedRunsRunOnce, scheduledomain.MissedRunsRunAll:
+	default:
+		return fmt.Errorf("unknown missed_runs %s for %s", schedule.MissedRuns, schedule.Name)
+	}
+	if _, err := ParseScheduleCron(schedule); err != nil {
+		return fmt.Errorf("invalid cron %s for %s: %s", schedule.Cron, schedule.Name, err.Error())
+	}
+	paths, err := policydomain.NewPathFilter(schedule.Paths)
+	if err != nil {
+		return fmt.Errorf("%s for %s", err.Error(), schedule.Name)
+	}
+	schedule.Paths = paths
+	return nil
+}
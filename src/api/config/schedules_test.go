@@ -0,0 +1,98 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/stretchr/testify/assert"
+)
+
+//writeSchedulesFile writes the contents to a temporary file, the returned function removes it
+func writeSchedulesFile(t *testing.T, contents string) (string, func()) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	path := filepath.Join(dir, "schedules.yaml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGetSchedulesFile(t *testing.T) {
+	assert.EqualValues(t, "SCHEDULES_FILE", apiSchedulesFile)
+	os.Setenv(apiSchedulesFile, "schedules.yaml")
+	defer os.Unsetenv(apiSchedulesFile)
+	assert.EqualValues(t, "schedules.yaml", GetSchedulesFile())
+}
+
+func TestLoadSchedules(t *testing.T) {
+	path, cleanup := writeSchedulesFile(t, `
+schedules:
+  - name: weekly-myrepo
+    cron: "0 9 * * MON"
+    timezone: Europe/London
+    owner: myuser
+    repo: myrepo
+    missed_runs: run_once
+    paths: ["services/payments/", " "]
+  - name: monthly-org
+    cron: "@monthly"
+    owner: myorg
+`)
+	defer cleanup()
+
+	schedules, err := LoadSchedules(path)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(schedules))
+	assert.EqualValues(t, "weekly-myrepo", schedules[0].Name)
+	assert.EqualValues(t, "Europe/London", schedules[0].Timezone)
+	assert.EqualValues(t, scheduledomain.ReportCodeReview, schedules[0].Report)
+	assert.EqualValues(t, scheduledomain.MissedRunsRunOnce, schedules[0].MissedRuns)
+	assert.False(t, schedules[0].IsOrgWide())
+	assert.EqualValues(t, []string{"services/payments/"}, schedules[0].Paths)
+
+	assert.EqualValues(t, "UTC", schedules[1].Timezone)
+	assert.EqualValues(t, scheduledomain.MissedRunsSkip, schedules[1].MissedRuns)
+	assert.True(t, schedules[1].IsOrgWide())
+}
+
+func TestLoadSchedulesMissingFile(t *testing.T) {
+	schedules, err := LoadSchedules(filepath.Join(os.TempDir(), "does-not-exist", "schedules.yaml"))
+	assert.Nil(t, schedules)
+	assert.NotNil(t, err)
+}
+
+func TestLoadSchedulesInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":   "schedules:\n  - name: a\n    cron: '@daily'\n    owner: o\n    branch: main\n",
+		"no name":         "schedules:\n  - cron: '@daily'\n    owner: o\n",
+		"no owner":        "schedules:\n  - name: a\n    cron: '@daily'\n",
+		"bad cron":        "schedules:\n  - name: a\n    cron: '61 * * * *'\n    owner: o\n",
+		"bad timezone":    "schedules:\n  - name: a\n    cron: '@daily'\n    timezone: Mars/Olympus\n    owner: o\n",
+		"unknown report":  "schedules:\n  - name: a\n    cron: '@daily'\n    owner: o\n    report: velocity\n",
+		"unknown missed":  "schedules:\n  - name: a\n    cron: '@daily'\n    owner: o\n    missed_runs: sometimes\n",
+		"bad path":        "schedules:\n  - name: a\n    cron: '@daily'\n    owner: o\n    paths: ['services/[payments']\n",
+		"duplicate names": "schedules:\n  - name: a\n    cron: '@daily'\n    owner: o\n  - name: a\n    cron: '@weekly'\n    owner: o\n",
+	}
+
+	for name, contents := range tests {
+		path, cleanup := writeSchedulesFile(t, contents)
+		schedules, err := LoadSchedules(path)
+		assert.Nil(t, schedules, name)
+		assert.NotNil(t, err, name)
+		cleanup()
+	}
+}
+
+func TestParseScheduleCronUsesTimezone(t *testing.T) {
+	schedule := scheduledomain.Schedule{Name: "a", Cron: "0 9 * * *", Timezone: "America/New_York"}
+	cronSchedule, err := ParseScheduleCron(&schedule)
+	assert.Nil(t, err)
+
+	next := cronSchedule.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, time.Date(2020, 1, 1, 14, 0, 0, 0, time.UTC).Equal(next))
+}
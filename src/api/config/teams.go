@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	apiTeamsFile = "TEAMS_FILE"
+)
+
+//GetTeamsFile returns the path of the yaml file mapping people to teams
+//an empty path means the reports and metrics aren't broken down by team
+func GetTeamsFile() string {
+	return os.Getenv(apiTeamsFile)
+}
+
+//LoadTeams reads the team directory from the yaml file
+func LoadTeams(path string) (*teamdomain.Directory, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	directory := teamdomain.NewDirectory()
+	if err := yaml.UnmarshalStrict(data, directory); err != nil {
+		return nil, fmt.Errorf("invalid teams file %s: %s", path, err.Error())
+	}
+
+	names := make(map[string]bool)
+	for i, team := range directory.Teams {
+		if err := validateTeam(directory, &team, names); err != nil {
+			return nil, fmt.Errorf("invalid team %d in %s: %s", i+1, path, err.Error())
+		}
+	}
+	return directory, nil
+}
+
+//validateTeam checks the team can be told apart from the other teams and, if linked to a Github team, can be synced
+func validateTeam(directory *teamdomain.Directory, team *teamdomain.Team, names map[string]bool) error {
+	name := strings.ToLower(team.Name)
+	if name == "" {
+		return fmt.Errorf("a name is required")
+	}
+	if name == teamdomain.Unassigned {
+		return fmt.Errorf("%s is used for the people not in any team", teamdomain.Unassigned)
+	}
+	if names[name] {
+		return fmt.Errorf("the name %s is used by another team", team.Name)
+	}
+	names[name] = true
+
+	if team.GithubTeam != "" && directory.Org == "" {
+		return fmt.Errorf("an org is required to sync the Github team %s", team.GithubTeam)
+	}
+	return nil
+}
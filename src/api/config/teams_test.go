@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTeamsFile(t *testing.T) {
+	assert.EqualValues(t, "TEAMS_FILE", apiTeamsFile)
+	os.Setenv(apiTeamsFile, "teams.yaml")
+	defer os.Unsetenv(apiTeamsFile)
+	assert.EqualValues(t, "teams.yaml", GetTeamsFile())
+}
+
+func TestLoadTeams(t *testing.T) {
+	path, cleanup := writeSchedulesFile(t, `
+org: myorg
+teams:
+  - name: payments
+    github_team: payments-devs
+    members: [Alice, bob@example.com]
+  - name: platform
+    members: [carol]
+`)
+	defer cleanup()
+
+	directory, err := LoadTeams(path)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "myorg", directory.Org)
+	assert.EqualValues(t, 2, len(directory.Teams))
+	assert.EqualValues(t, "payments-devs", directory.Teams[0].GithubTeam)
+	assert.EqualValues(t, []string{"payments"}, directory.TeamsOf("alice"))
+	assert.EqualValues(t, []string{"platform"}, directory.TeamsOf("carol"))
+}
+
+func TestLoadTeamsInvalid(t *testing.T) {
+	directory, err := LoadTeams("does-not-exist")
+	assert.Nil(t, directory)
+	assert.NotNil(t, err)
+
+	tests := []struct {
+		contents string
+		expected string
+	}{
+		{"teams:\n  - name: payments\n    lead: alice\n", "invalid teams file"},
+		{"teams:\n  - members: [alice]\n", "invalid team 1 in"},
+		{"teams:\n  - name: Unassigned\n", "is used for the people not in any team"},
+		{"teams:\n  - name: payments\n  - name: Payments\n", "the name Payments is used by another team"},
+		{"teams:\n  - name: payments\n    github_team: payments-devs\n", "an org is required to sync the Github team payments-devs"},
+	}
+	for _, test := range tests {
+		path, cleanup := writeSchedulesFile(t, test.contents)
+		directory, err := LoadTeams(path)
+		cleanup()
+		assert.Nil(t, directory, test.contents)
+		assert.Contains(t, err.Error(), test.expected, test.contents)
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+)
+
+const (
+	apiJiraURL   = "JIRA_URL"
+	apiJiraUser  = "SECRET_JIRA_USER"
+	apiJiraToken = "SECRET_JIRA_TOKEN"
+)
+
+//GetJiraURL returns the base URL of the Jira the referenced tickets are looked up in, such as https://myorg.atlassian.net
+//an empty URL means the tickets are only checked for a key and aren't looked up
+func GetJiraURL() string {
+	return os.Getenv(apiJiraURL)
+}
+
+//GetJiraUser returns the user the tickets are looked up as, Jira Cloud expects an email address
+func GetJiraUser() string {
+	return os.Getenv(apiJiraUser)
+}
+
+//GetJiraToken returns the API token of the Jira user
+func GetJiraToken() string {
+	return os.Getenv(apiJiraToken)
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJiraSettings(t *testing.T) {
+	os.Setenv(apiJiraURL, "https://myorg.atlassian.net")
+	os.Setenv(apiJiraUser, "someone@example.com")
+	os.Setenv(apiJiraToken, "mytoken")
+	defer os.Unsetenv(apiJiraURL)
+	defer os.Unsetenv(apiJiraUser)
+	defer os.Unsetenv(apiJiraToken)
+
+	assert.EqualValues(t, "https://myorg.atlassian.net", GetJiraURL())
+	assert.EqualValues(t, "someone@example.com", GetJiraUser())
+	assert.EqualValues(t, "mytoken", GetJiraToken())
+}
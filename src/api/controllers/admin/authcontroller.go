@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+const (
+	headerAuthorization  = "Authorization"
+	bearerPrefix         = "Bearer "
+	errorAdminDisabled   = "no admin token has been configured"
+	errorAdminTokenWrong = "a valid admin token is needed"
+)
+
+//RequireAdminToken only lets the request through when it has the admin token as a bearer token
+//it guards the routes that change data or send messages, they are turned off until a token is configured
+func RequireAdminToken(c *gin.Context) {
+	token := config.GetAdminToken()
+	if token == "" {
+		err := errors.NewAPIError(http.StatusForbidden, errorAdminDisabled)
+		c.AbortWithStatusJSON(err.Status(), err)
+		return
+	}
+
+	header := c.GetHeader(headerAuthorization)
+	if !strings.HasPrefix(header, bearerPrefix) || subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, bearerPrefix)), []byte(token)) != 1 {
+		err := errors.NewAPIError(http.StatusUnauthorized, errorAdminTokenWrong)
+		c.AbortWithStatusJSON(err.Status(), err)
+		return
+	}
+	c.Next()
+}
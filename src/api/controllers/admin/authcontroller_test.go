@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+//performAdminRequest runs the request through the check, returning whether it was stopped
+func performAdminRequest(authorization string) (*httptest.ResponseRecorder, bool) {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/admin/teams/sync", nil)
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	c, _ := testutils.GetMockedContext(request, response)
+	RequireAdminToken(c)
+	return response, c.IsAborted()
+}
+
+func TestRequireAdminTokenNotConfigured(t *testing.T) {
+	os.Unsetenv("SECRET_ADMIN_TOKEN")
+
+	response, aborted := performAdminRequest("Bearer anything")
+	assert.True(t, aborted)
+	assert.EqualValues(t, http.StatusForbidden, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, errorAdminDisabled, apiErr.Message())
+}
+
+func TestRequireAdminToken(t *testing.T) {
+	os.Setenv("SECRET_ADMIN_TOKEN", "mytoken")
+	defer os.Unsetenv("SECRET_ADMIN_TOKEN")
+
+	for _, authorization := range []string{"", "mytoken", "Bearer wrong", "Basic mytoken"} {
+		response, aborted := performAdminRequest(authorization)
+		assert.True(t, aborted)
+		assert.EqualValues(t, http.StatusUnauthorized, response.Code)
+		apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+		assert.Nil(t, err)
+		assert.EqualValues(t, errorAdminTokenWrong, apiErr.Message())
+	}
+
+	_, aborted := performAdminRequest("Bearer mytoken")
+	assert.False(t, aborted)
+}
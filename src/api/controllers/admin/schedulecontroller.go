@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//GetSchedules returns the scheduled reports along with when each next runs and how it last ran
+func GetSchedules(c *gin.Context) {
+	c.JSON(http.StatusOK, services.SchedulerService.GetSchedules())
+}
+
+//GetScheduleRuns returns the most recent runs of a scheduled report, including the reports it produced
+//the number of runs can be set with the limit query parameter
+func GetScheduleRuns(c *gin.Context) {
+	name := c.Param("name")
+	limit := c.Query("limit")
+
+	result, err := services.SchedulerService.GetScheduleRuns(name, limit)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
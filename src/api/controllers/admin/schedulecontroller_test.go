@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcGetSchedules    func() []scheduledomain.ScheduleStatus
+	funcGetScheduleRuns func(name string, limit string) ([]scheduledomain.ScheduleRun, errors.APIError)
+)
+
+type schedulerServiceMock struct{}
+
+func (s *schedulerServiceMock) GetSchedules() []scheduledomain.ScheduleStatus {
+	return funcGetSchedules()
+}
+
+func (s *schedulerServiceMock) GetScheduleRuns(name string, limit string) ([]scheduledomain.ScheduleRun, errors.APIError) {
+	return funcGetScheduleRuns(name, limit)
+}
+
+func TestGetSchedulesMockingEntireService(t *testing.T) {
+	services.SchedulerService = &schedulerServiceMock{}
+	defer services.ResetSchedulerService()
+
+	nextRun := time.Date(2020, 1, 13, 9, 0, 0, 0, time.UTC)
+	funcGetSchedules = func() []scheduledomain.ScheduleStatus {
+		return []scheduledomain.ScheduleStatus{{
+			Schedule: scheduledomain.Schedule{Name: "weekly", Cron: "0 9 * * MON", Timezone: "UTC", Report: scheduledomain.ReportCodeReview, Owner: "myorg"},
+			NextRun:  nextRun,
+		}}
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/admin/schedules", strings.NewReader(``))
+	c, _ := testutils.GetMockedContext(request, response)
+
+	GetSchedules(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result []scheduledomain.ScheduleStatus
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, "weekly", result[0].Name)
+	assert.True(t, result[0].IsOrgWide())
+	assert.True(t, nextRun.Equal(result[0].NextRun))
+	assert.Nil(t, result[0].LastRun)
+}
+
+func TestGetScheduleRunsNoErrorMockingEntireService(t *testing.T) {
+	services.SchedulerService = &schedulerServiceMock{}
+	defer services.ResetSchedulerService()
+
+	funcGetScheduleRuns = func(name string, limit string) ([]scheduledomain.ScheduleRun, errors.APIError) {
+		assert.EqualValues(t, "weekly", name)
+		assert.EqualValues(t, "5", limit)
+		return []scheduledomain.ScheduleRun{{Schedule: name, Status: scheduledomain.RunStatusSkipped, Missed: true}}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/admin/schedules/weekly/runs?limit=5", strings.NewReader(``))
+	params := map[string]string{"name": "weekly"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetScheduleRuns(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result []scheduledomain.ScheduleRun
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, scheduledomain.RunStatusSkipped, result[0].Status)
+	assert.True(t, result[0].Missed)
+}
+
+func TestGetScheduleRunsErrorMockingEntireService(t *testing.T) {
+	services.SchedulerService = &schedulerServiceMock{}
+	defer services.ResetSchedulerService()
+
+	funcGetScheduleRuns = func(name string, limit string) ([]scheduledomain.ScheduleRun, errors.APIError) {
+		return nil, errors.NewNotFoundAPIError("the schedule does not exist")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/admin/schedules/unknown/runs", strings.NewReader(``))
+	params := map[string]string{"name": "unknown"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetScheduleRuns(c)
+
+	assert.EqualValues(t, http.StatusNotFound, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "the schedule does not exist", apiErr.Message())
+}
@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//GetTeams returns the team directory the reports and metrics are broken down by
+func GetTeams(c *gin.Context) {
+	c.JSON(http.StatusOK, services.TeamService.GetTeams())
+}
+
+//SyncTeams refreshes the members of the teams linked to Github teams and returns the updated directory
+func SyncTeams(c *gin.Context) {
+	result, err := services.TeamService.SyncTeams()
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
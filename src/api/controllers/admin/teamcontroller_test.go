@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcGetTeams  func() *teamdomain.Directory
+	funcSyncTeams func() (*teamdomain.Directory, errors.APIError)
+)
+
+type teamServiceMock struct{}
+
+func (s *teamServiceMock) GetTeams() *teamdomain.Directory {
+	return funcGetTeams()
+}
+
+func (s *teamServiceMock) SyncTeams() (*teamdomain.Directory, errors.APIError) {
+	return funcSyncTeams()
+}
+
+func TestGetTeamsMockingEntireService(t *testing.T) {
+	services.TeamService = &teamServiceMock{}
+	defer services.ResetTeamService()
+
+	funcGetTeams = func() *teamdomain.Directory {
+		return &teamdomain.Directory{Org: "myorg", Teams: []teamdomain.Team{{Name: "payments", Members: []string{"alice"}}}}
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/admin/teams", strings.NewReader(``))
+	c, _ := testutils.GetMockedContext(request, response)
+
+	GetTeams(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result teamdomain.Directory
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "myorg", result.Org)
+	assert.EqualValues(t, "payments", result.Teams[0].Name)
+}
+
+func TestSyncTeamsNoErrorMockingEntireService(t *testing.T) {
+	services.TeamService = &teamServiceMock{}
+	defer services.ResetTeamService()
+
+	funcSyncTeams = func() (*teamdomain.Directory, errors.APIError) {
+		return &teamdomain.Directory{Org: "myorg", Teams: []teamdomain.Team{{Name: "payments", GithubTeam: "payments-devs", SyncedMembers: []string{"bob"}}}}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/admin/teams/sync", strings.NewReader(``))
+	c, _ := testutils.GetMockedContext(request, response)
+
+	SyncTeams(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result teamdomain.Directory
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"bob"}, result.Teams[0].SyncedMembers)
+}
+
+func TestSyncTeamsErrorMockingEntireService(t *testing.T) {
+	services.TeamService = &teamServiceMock{}
+	defer services.ResetTeamService()
+
+	funcSyncTeams = func() (*teamdomain.Directory, errors.APIError) {
+		return nil, errors.NewNotFoundAPIError("Not Found")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/admin/teams/sync", strings.NewReader(``))
+	c, _ := testutils.GetMockedContext(request, response)
+
+	SyncTeams(c)
+
+	assert.EqualValues(t, http.StatusNotFound, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Not Found", apiErr.Message())
+}
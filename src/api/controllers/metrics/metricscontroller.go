@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//GetPRMetrics returns the cycle time and review latency of the PRs created between the from and to dates
+//the PRs can be limited to an author and a base branch using the author and base query parameters
+func GetPRMetrics(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	result, err := services.MetricsService.GetPRMetrics(owner, repo, c.Query("from"), c.Query("to"), c.Query("author"), c.Query("base"))
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+//GetDORAMetrics returns the DORA metrics of the repo between the from and to dates
+//the source query parameter chooses between Github deployments, the default, and releases
+//the environment query parameter chooses the environment deployed to, production by default
+func GetDORAMetrics(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	result, err := services.MetricsService.GetDORAMetrics(owner, repo, c.Query("from"), c.Query("to"), c.Query("source"), c.Query("environment"))
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+//GetContributorActivity returns who wrote and who reviewed the code of the repo between the from and to dates
+//bots are left out unless the include_bots query parameter is true
+func GetContributorActivity(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	result, err := services.MetricsService.GetContributorActivity(owner, repo, c.Query("from"), c.Query("to"), c.Query("include_bots"))
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/metricsdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcGetPRMetrics           func(owner string, repo string, fromDate string, toDate string, author string, baseRef string) (*metricsdomain.PullRequestMetrics, errors.APIError)
+	funcGetDORAMetrics         func(owner string, repo string, fromDate string, toDate string, source string, environment string) (*metricsdomain.DORAMetrics, errors.APIError)
+	funcGetContributorActivity func(owner string, repo string, fromDate string, toDate string, includeBots string) (*metricsdomain.ContributorActivityReport, errors.APIError)
+)
+
+type metricsServiceMock struct{}
+
+func (s *metricsServiceMock) GetPRMetrics(owner string, repo string, fromDate string, toDate string, author string, baseRef string) (*metricsdomain.PullRequestMetrics, errors.APIError) {
+	return funcGetPRMetrics(owner, repo, fromDate, toDate, author, baseRef)
+}
+
+func (s *metricsServiceMock) GetDORAMetrics(owner string, repo string, fromDate string, toDate string, source string, environment string) (*metricsdomain.DORAMetrics, errors.APIError) {
+	return funcGetDORAMetrics(owner, repo, fromDate, toDate, source, environment)
+}
+
+func (s *metricsServiceMock) GetContributorActivity(owner string, repo string, fromDate string, toDate string, includeBots string) (*metricsdomain.ContributorActivityReport, errors.APIError) {
+	return funcGetContributorActivity(owner, repo, fromDate, toDate, includeBots)
+}
+
+func TestGetPRMetricsNoErrorMockingEntireService(t *testing.T) {
+	services.MetricsService = &metricsServiceMock{}
+	defer services.ResetMetricsService()
+
+	funcGetPRMetrics = func(owner string, repo string, fromDate string, toDate string, author string, baseRef string) (*metricsdomain.PullRequestMetrics, errors.APIError) {
+		assert.EqualValues(t, "2020-03-01", fromDate)
+		assert.EqualValues(t, "2020-03-14", toDate)
+		assert.EqualValues(t, "octocat", author)
+		assert.EqualValues(t, "main", baseRef)
+		return &metricsdomain.PullRequestMetrics{Owner: owner, Repo: repo, Author: author, BaseRef: baseRef, Overall: metricsdomain.CycleTimeStats{PullRequests: 3}}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/metrics/myowner/myrepo/pulls?from=2020-03-01&to=2020-03-14&author=octocat&base=main", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetPRMetrics(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result metricsdomain.PullRequestMetrics
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "myowner", result.Owner)
+	assert.EqualValues(t, 3, result.Overall.PullRequests)
+}
+
+func TestGetPRMetricsErrorMockingEntireService(t *testing.T) {
+	services.MetricsService = &metricsServiceMock{}
+	defer services.ResetMetricsService()
+
+	funcGetPRMetrics = func(owner string, repo string, fromDate string, toDate string, author string, baseRef string) (*metricsdomain.PullRequestMetrics, errors.APIError) {
+		return nil, errors.NewBadRequestError("invalid from parameter")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/metrics/myowner/myrepo/pulls?from=yesterday", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetPRMetrics(c)
+
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "invalid from parameter", apiErr.Message())
+}
+
+func TestGetDORAMetricsNoErrorMockingEntireService(t *testing.T) {
+	services.MetricsService = &metricsServiceMock{}
+	defer services.ResetMetricsService()
+
+	funcGetDORAMetrics = func(owner string, repo string, fromDate string, toDate string, source string, environment string) (*metricsdomain.DORAMetrics, errors.APIError) {
+		assert.EqualValues(t, "2020-03-01", fromDate)
+		assert.EqualValues(t, "", toDate)
+		assert.EqualValues(t, "deployments", source)
+		assert.EqualValues(t, "staging", environment)
+		return &metricsdomain.DORAMetrics{Owner: owner, Repo: repo, Source: source, Environment: environment, TotalDeployments: 4, ChangeFailureRate: 0.25}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/metrics/myowner/myrepo/dora?from=2020-03-01&source=deployments&environment=staging", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetDORAMetrics(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result metricsdomain.DORAMetrics
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "staging", result.Environment)
+	assert.EqualValues(t, 4, result.TotalDeployments)
+	assert.EqualValues(t, 0.25, result.ChangeFailureRate)
+}
+
+func TestGetDORAMetricsErrorMockingEntireService(t *testing.T) {
+	services.MetricsService = &metricsServiceMock{}
+	defer services.ResetMetricsService()
+
+	funcGetDORAMetrics = func(owner string, repo string, fromDate string, toDate string, source string, environment string) (*metricsdomain.DORAMetrics, errors.APIError) {
+		return nil, errors.NewBadRequestError("invalid source parameter")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/metrics/myowner/myrepo/dora?source=tags", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetDORAMetrics(c)
+
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "invalid source parameter", apiErr.Message())
+}
+
+func TestGetContributorActivityNoErrorMockingEntireService(t *testing.T) {
+	services.MetricsService = &metricsServiceMock{}
+	defer services.ResetMetricsService()
+
+	funcGetContributorActivity = func(owner string, repo string, fromDate string, toDate string, includeBots string) (*metricsdomain.ContributorActivityReport, errors.APIError) {
+		assert.EqualValues(t, "2020-03-14", toDate)
+		assert.EqualValues(t, "true", includeBots)
+		return &metricsdomain.ContributorActivityReport{Owner: owner, Repo: repo, IncludeBots: true,
+			Contributors: []metricsdomain.ContributorActivity{{ID: "octocat", Login: "octocat", Commits: 3}}}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/metrics/myowner/myrepo/contributors?to=2020-03-14&include_bots=true", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetContributorActivity(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result metricsdomain.ContributorActivityReport
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.True(t, result.IncludeBots)
+	assert.EqualValues(t, 3, result.Contributors[0].Commits)
+}
+
+func TestGetContributorActivityErrorMockingEntireService(t *testing.T) {
+	services.MetricsService = &metricsServiceMock{}
+	defer services.ResetMetricsService()
+
+	funcGetContributorActivity = func(owner string, repo string, fromDate string, toDate string, includeBots string) (*metricsdomain.ContributorActivityReport, errors.APIError) {
+		return nil, errors.NewNotFoundAPIError("Not Found")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/metrics/myowner/myrepo/contributors", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetContributorActivity(c)
+
+	assert.EqualValues(t, http.StatusNotFound, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Not Found", apiErr.Message())
+}
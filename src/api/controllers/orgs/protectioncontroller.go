@@ -0,0 +1,21 @@
+package orgs
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//GetBranchProtection compares the protection of the default branch of every repo in the organisation against the baseline of its policy
+//the totals count the repos that have drifted from their baseline, along with how many of them drifted on each setting
+func GetBranchProtection(c *gin.Context) {
+	owner := c.Param("owner")
+
+	result, err := services.ProtectionService.GetOrgBranchProtectionReport(owner)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,76 @@
+package orgs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcGetBranchProtectionReport    func(owner string, repo string) (*reportdomain.BranchProtectionReport, errors.APIError)
+	funcGetOrgBranchProtectionReport func(owner string) (*reportdomain.OrgBranchProtectionReport, errors.APIError)
+)
+
+type protectionServiceMock struct{}
+
+func (s *protectionServiceMock) GetBranchProtectionReport(owner string, repo string) (*reportdomain.BranchProtectionReport, errors.APIError) {
+	return funcGetBranchProtectionReport(owner, repo)
+}
+
+func (s *protectionServiceMock) GetOrgBranchProtectionReport(owner string) (*reportdomain.OrgBranchProtectionReport, errors.APIError) {
+	return funcGetOrgBranchProtectionReport(owner)
+}
+
+func TestGetBranchProtectionNoErrorMockingEntireService(t *testing.T) {
+	services.ProtectionService = &protectionServiceMock{}
+	defer services.ResetProtectionService()
+
+	funcGetOrgBranchProtectionReport = func(owner string) (*reportdomain.OrgBranchProtectionReport, errors.APIError) {
+		report := reportdomain.NewOrgBranchProtectionReport(owner)
+		report.AddRepo(reportdomain.NewBranchProtectionReport(owner, "web", "main", nil, policydomain.BranchProtectionBaseline{}))
+		return report, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/orgs/myorg/protection", strings.NewReader(``))
+	c, _ := testutils.GetMockedContextWithParams(request, response, map[string]string{"owner": "myorg"})
+
+	GetBranchProtection(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result reportdomain.OrgBranchProtectionReport
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "myorg", result.Owner)
+	assert.EqualValues(t, 1, result.TotalUnprotected)
+	assert.EqualValues(t, 1, result.DriftBySetting[reportdomain.ProtectionSettingProtected])
+}
+
+func TestGetBranchProtectionErrorMockingEntireService(t *testing.T) {
+	services.ProtectionService = &protectionServiceMock{}
+	defer services.ResetProtectionService()
+
+	funcGetOrgBranchProtectionReport = func(owner string) (*reportdomain.OrgBranchProtectionReport, errors.APIError) {
+		return nil, errors.NewNotFoundAPIError("Not Found")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/orgs/myorg/protection", strings.NewReader(``))
+	c, _ := testutils.GetMockedContextWithParams(request, response, map[string]string{"owner": "myorg"})
+
+	GetBranchProtection(c)
+
+	assert.EqualValues(t, http.StatusNotFound, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Not Found", apiErr.Message())
+}
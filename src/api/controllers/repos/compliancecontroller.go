@@ -0,0 +1,23 @@
+package repos
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//GetPRCompliance evaluates an open PR against the review policy to determine if merging it now would be compliant
+//the outcome is returned in the body so CI can check the compliant field to gate the merge
+func GetPRCompliance(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+	pullNumber := c.Param("pull")
+
+	result, err := services.ComplianceService.GetPRCompliance(owner, repo, pullNumber)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,73 @@
+package repos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcGetPRCompliance func(owner string, repo string, pullNumber string) (*reportdomain.PullRequestCompliance, errors.APIError)
+)
+
+type complianceServiceMock struct{}
+
+func (s *complianceServiceMock) GetPRCompliance(owner string, repo string, pullNumber string) (*reportdomain.PullRequestCompliance, errors.APIError) {
+	return funcGetPRCompliance(owner, repo, pullNumber)
+}
+
+func TestGetPRComplianceNoErrorMockingEntireService(t *testing.T) {
+	services.ComplianceService = &complianceServiceMock{}
+	defer services.ResetComplianceService()
+
+	funcGetPRCompliance = func(owner string, repo string, pullNumber string) (*reportdomain.PullRequestCompliance, errors.APIError) {
+		assert.EqualValues(t, "9", pullNumber)
+		compliance := &reportdomain.PullRequestCompliance{Owner: owner, Repo: repo, PullNumber: 9}
+		compliance.AddRequirement(reportdomain.RequirementApprovals, false, "0 of 1 required approvals")
+		return compliance, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo/pulls/9/compliance", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo", "pull": "9"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetPRCompliance(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result reportdomain.PullRequestCompliance
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 9, result.PullNumber)
+	assert.False(t, result.Compliant)
+	assert.EqualValues(t, []string{"0 of 1 required approvals"}, result.MissingRequirements)
+}
+
+func TestGetPRComplianceErrorMockingEntireService(t *testing.T) {
+	services.ComplianceService = &complianceServiceMock{}
+	defer services.ResetComplianceService()
+
+	funcGetPRCompliance = func(owner string, repo string, pullNumber string) (*reportdomain.PullRequestCompliance, errors.APIError) {
+		return nil, errors.NewNotFoundAPIError("Not Found")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo/pulls/9/compliance", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo", "pull": "9"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetPRCompliance(c)
+
+	assert.EqualValues(t, http.StatusNotFound, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Not Found", apiErr.Message())
+}
@@ -0,0 +1,22 @@
+package repos
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//GetBranchProtection compares the protection of the default branch of the repo against the baseline of its policy
+//the compliant field is false when the protection has drifted from the baseline
+func GetBranchProtection(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	result, err := services.ProtectionService.GetBranchProtectionReport(owner, repo)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
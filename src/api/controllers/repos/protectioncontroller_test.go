@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcGetBranchProtectionReport    func(owner string, repo string) (*reportdomain.BranchProtectionReport, errors.APIError)
+	funcGetOrgBranchProtectionReport func(owner string) (*reportdomain.OrgBranchProtectionReport, errors.APIError)
+)
+
+type protectionServiceMock struct{}
+
+func (s *protectionServiceMock) GetBranchProtectionReport(owner string, repo string) (*reportdomain.BranchProtectionReport, errors.APIError) {
+	return funcGetBranchProtectionReport(owner, repo)
+}
+
+func (s *protectionServiceMock) GetOrgBranchProtectionReport(owner string) (*reportdomain.OrgBranchProtectionReport, errors.APIError) {
+	return funcGetOrgBranchProtectionReport(owner)
+}
+
+func TestGetBranchProtectionNoErrorMockingEntireService(t *testing.T) {
+	services.ProtectionService = &protectionServiceMock{}
+	defer services.ResetProtectionService()
+
+	funcGetBranchProtectionReport = func(owner string, repo string) (*reportdomain.BranchProtectionReport, errors.APIError) {
+		return reportdomain.NewBranchProtectionReport(owner, repo, "main", nil, policydomain.BranchProtectionBaseline{}), nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo/protection", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetBranchProtection(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result reportdomain.BranchProtectionReport
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "myrepo", result.Repo)
+	assert.False(t, result.Compliant)
+	assert.EqualValues(t, []string{"the branch main isn't protected"}, result.Drift)
+}
+
+func TestGetBranchProtectionErrorMockingEntireService(t *testing.T) {
+	services.ProtectionService = &protectionServiceMock{}
+	defer services.ResetProtectionService()
+
+	funcGetBranchProtectionReport = func(owner string, repo string) (*reportdomain.BranchProtectionReport, errors.APIError) {
+		return nil, errors.NewAPIError(http.StatusForbidden, "Resource not accessible by integration")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo/protection", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetBranchProtection(c)
+
+	assert.EqualValues(t, http.StatusForbidden, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Resource not accessible by integration", apiErr.Message())
+}
@@ -0,0 +1,24 @@
+package repos
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//PublishCommitCompliance publishes the code review verdict of the commit back to Github
+//the target query parameter chooses between a commit status, the default, and a check_run
+func PublishCommitCompliance(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+	sha := c.Param("sha")
+	target := c.Query("target")
+
+	result, err := services.PublishService.PublishCommitCompliance(owner, repo, sha, target)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,73 @@
+package repos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcPublishCommitCompliance func(owner string, repo string, SHA string, target string) (*reportdomain.CommitCompliance, errors.APIError)
+)
+
+type publishServiceMock struct{}
+
+func (s *publishServiceMock) PublishCommitCompliance(owner string, repo string, SHA string, target string) (*reportdomain.CommitCompliance, errors.APIError) {
+	return funcPublishCommitCompliance(owner, repo, SHA, target)
+}
+
+func TestPublishCommitComplianceNoErrorMockingEntireService(t *testing.T) {
+	services.PublishService = &publishServiceMock{}
+	defer services.ResetPublishService()
+
+	funcPublishCommitCompliance = func(owner string, repo string, SHA string, target string) (*reportdomain.CommitCompliance, errors.APIError) {
+		assert.EqualValues(t, "check_run", target)
+		compliance := reportdomain.NewCommitCompliance(owner, repo, "main", reportdomain.CommitReview{SHA: SHA, ReviewStatus: reportdomain.ReviewStatusReviewed})
+		compliance.PublishedTo = target
+		return compliance, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/repos/myowner/myrepo/commits/AAA111/compliance?target=check_run", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo", "sha": "AAA111"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	PublishCommitCompliance(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result reportdomain.CommitCompliance
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "AAA111", result.Commit.SHA)
+	assert.EqualValues(t, reportdomain.CompliancePass, result.Verdict)
+	assert.EqualValues(t, "check_run", result.PublishedTo)
+}
+
+func TestPublishCommitComplianceErrorMockingEntireService(t *testing.T) {
+	services.PublishService = &publishServiceMock{}
+	defer services.ResetPublishService()
+
+	funcPublishCommitCompliance = func(owner string, repo string, SHA string, target string) (*reportdomain.CommitCompliance, errors.APIError) {
+		return nil, errors.NewAPIError(http.StatusForbidden, "You must authenticate via a GitHub App.")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/repos/myowner/myrepo/commits/AAA111/compliance", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo", "sha": "AAA111"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	PublishCommitCompliance(c)
+
+	assert.EqualValues(t, http.StatusForbidden, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "You must authenticate via a GitHub App.", apiErr.Message())
+}
@@ -0,0 +1,27 @@
+package repos
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//GetReleaseNotes returns Markdown release notes for the PRs merged between the from and to query parameters
+//the refs can be tags, branches or commit SHAs and the notes can be returned as JSON by setting the format query parameter to json
+func GetReleaseNotes(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	result, err := services.ReleaseNotesService.GetReleaseNotes(owner, repo, c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	c.Data(http.StatusOK, "text/markdown", []byte(result.Markdown()))
+}
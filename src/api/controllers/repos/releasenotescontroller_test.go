@@ -0,0 +1,85 @@
+package repos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcGetReleaseNotes func(owner string, repo string, from string, to string) (*reportdomain.ReleaseNotes, errors.APIError)
+)
+
+type releaseNotesServiceMock struct{}
+
+func (s *releaseNotesServiceMock) GetReleaseNotes(owner string, repo string, from string, to string) (*reportdomain.ReleaseNotes, errors.APIError) {
+	return funcGetReleaseNotes(owner, repo, from, to)
+}
+
+func getReleaseNotesResponse(t *testing.T, URL string) *httptest.ResponseRecorder {
+	services.ReleaseNotesService = &releaseNotesServiceMock{}
+	defer services.ResetReleaseNotesService()
+
+	funcGetReleaseNotes = func(owner string, repo string, from string, to string) (*reportdomain.ReleaseNotes, errors.APIError) {
+		assert.EqualValues(t, "v1.2.0", from)
+		assert.EqualValues(t, "v1.3.0", to)
+		notes := &reportdomain.ReleaseNotes{Owner: owner, Repo: repo, From: from, To: to}
+		notes.SetSections([]reportdomain.ReleaseNote{{Number: 10, Subject: "add release notes", Author: "alice", Section: reportdomain.SectionFeatures}})
+		return notes, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, URL, nil)
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetReleaseNotes(c)
+	return response
+}
+
+func TestGetReleaseNotesMarkdownMockingEntireService(t *testing.T) {
+	response := getReleaseNotesResponse(t, "/repos/myowner/myrepo/releasenotes?from=v1.2.0&to=v1.3.0")
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "text/markdown", response.Header().Get("Content-Type"))
+	assert.EqualValues(t, "# myowner/myrepo v1.2.0...v1.3.0\n\n## Features\n\n- add release notes (#10) @alice\n", response.Body.String())
+}
+
+func TestGetReleaseNotesJSONMockingEntireService(t *testing.T) {
+	response := getReleaseNotesResponse(t, "/repos/myowner/myrepo/releasenotes?from=v1.2.0&to=v1.3.0&format=json")
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result reportdomain.ReleaseNotes
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, result.TotalPullRequests)
+	assert.EqualValues(t, reportdomain.SectionFeatures, result.Sections[0].Title)
+}
+
+func TestGetReleaseNotesErrorMockingEntireService(t *testing.T) {
+	services.ReleaseNotesService = &releaseNotesServiceMock{}
+	defer services.ResetReleaseNotesService()
+
+	funcGetReleaseNotes = func(owner string, repo string, from string, to string) (*reportdomain.ReleaseNotes, errors.APIError) {
+		return nil, errors.NewNotFoundAPIError("Not Found")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo/releasenotes?from=v9&to=v10", nil)
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetReleaseNotes(c)
+
+	assert.EqualValues(t, http.StatusNotFound, response.Code)
+	APIErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Not Found", APIErr.Message())
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
 	"github.com/greendinosaur/gh-commit-info/src/api/services"
 	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
 	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
@@ -17,16 +18,23 @@ import (
 )
 
 var (
+	funcGetRepo             func(owner string, repo string) (*githubdomain.GetRepoInfo, errors.APIError)
 	funcGetRepoPRs          func(owner string, repo string, scope string) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError)
 	funcGetRepoSinglePR     func(owner string, repo string, pullRequst string) (*githubdomain.GetSinglePullRequestResponse, errors.APIError)
+	funcGetPRReviews        func(owner string, repo string, pullRequest string) ([]githubdomain.PullRequestReview, errors.APIError)
+	funcGetPRFiles          func(owner string, repo string, pullRequest string) ([]githubdomain.ChangedFile, errors.APIError)
 	funcGetSingleCommitPR   func(owner string, repo string, SHA string) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError)
-	funcGetRepoCommits      func(owner string, repo string) ([]githubdomain.GetCommitInfo, errors.APIError)
+	funcGetRepoCommits      func(owner string, repo string, paths []string) ([]githubdomain.GetCommitInfo, errors.APIError)
 	funcGetRepoSingleCommit func(owner string, repo string, SHA string) (*githubdomain.GetCommitInfo, errors.APIError)
-	funcGetCodeReviewReport func(owner string, repo string, fromDate time.Time, endDate time.Time) (string, errors.APIError)
+	funcGetCodeReviewReport func(owner string, repo string, fromDate time.Time, endDate time.Time, paths []string) (*reportdomain.CodeReviewReport, errors.APIError)
 )
 
 type repoServiceMock struct{}
 
+func (s *repoServiceMock) GetRepo(owner string, repo string) (*githubdomain.GetRepoInfo, errors.APIError) {
+	return funcGetRepo(owner, repo)
+}
+
 func (s *repoServiceMock) GetRepoPRs(owner string, repo string, scope string) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError) {
 	return funcGetRepoPRs(owner, repo, scope)
 }
@@ -35,20 +43,28 @@ func (s *repoServiceMock) GetRepoSinglePR(owner string, repo string, pullRequest
 	return funcGetRepoSinglePR(owner, repo, pullRequest)
 }
 
+func (s *repoServiceMock) GetPRReviews(owner string, repo string, pullRequest string) ([]githubdomain.PullRequestReview, errors.APIError) {
+	return funcGetPRReviews(owner, repo, pullRequest)
+}
+
+func (s *repoServiceMock) GetPRFiles(owner string, repo string, pullRequest string) ([]githubdomain.ChangedFile, errors.APIError) {
+	return funcGetPRFiles(owner, repo, pullRequest)
+}
+
 func (s *repoServiceMock) GetSingleCommitPR(owner string, repo string, SHA string) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError) {
 	return funcGetSingleCommitPR(owner, repo, SHA)
 }
 
-func (s *repoServiceMock) GetRepoCommits(owner string, repo string) ([]githubdomain.GetCommitInfo, errors.APIError) {
-	return funcGetRepoCommits(owner, repo)
+func (s *repoServiceMock) GetRepoCommits(owner string, repo string, paths []string) ([]githubdomain.GetCommitInfo, errors.APIError) {
+	return funcGetRepoCommits(owner, repo, paths)
 }
 
 func (s *repoServiceMock) GetRepoSingleCommit(owner string, repo string, SHA string) (*githubdomain.GetCommitInfo, errors.APIError) {
 	return funcGetRepoSingleCommit(owner, repo, SHA)
 }
 
-func (s *repoServiceMock) GetCodeReviewReport(owner string, repo string, fromDate time.Time, endDate time.Time) (string, errors.APIError) {
-	return funcGetCodeReviewReport(owner, repo, fromDate, endDate)
+func (s *repoServiceMock) GetCodeReviewReport(owner string, repo string, fromDate time.Time, endDate time.Time, paths []string) (*reportdomain.CodeReviewReport, errors.APIError) {
+	return funcGetCodeReviewReport(owner, repo, fromDate, endDate, paths)
 }
 
 func TestGetPRsNoErrorMockingEntireService(t *testing.T) {
@@ -223,3 +239,227 @@ func TestGetRepoSinglePRGithubErrorMockingEntireService(t *testing.T) {
 	assert.EqualValues(t, "invalid pull parameter", APIErr.Message())
 
 }
+
+func TestGetPRReviewsNoErrorMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetPRReviews = func(owner string, repo string, pullRequest string) ([]githubdomain.PullRequestReview, errors.APIError) {
+		return []githubdomain.PullRequestReview{
+			{
+				ID:       80,
+				User:     githubdomain.GitUser{Login: "A Second Login ID"},
+				State:    githubdomain.ReviewStateApproved,
+				CommitID: "FEDCBA654321",
+			},
+		}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/owner/myrepo/pulls/9/reviews", strings.NewReader(`{}`))
+	params := map[string]string{"owner": "owner", "repo": "myrepo", "pull": "9"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetPRReviews(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	var result []githubdomain.PullRequestReview
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, 80, result[0].ID)
+	assert.EqualValues(t, githubdomain.ReviewStateApproved, result[0].State)
+}
+
+func TestGetPRReviewsErrorMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetPRReviews = func(owner string, repo string, pullRequest string) ([]githubdomain.PullRequestReview, errors.APIError) {
+		return nil, errors.NewBadRequestError("invalid pull parameter")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/owner/myrepo/pulls/abc/reviews", strings.NewReader(`{}`))
+	params := map[string]string{"owner": "owner", "repo": "myrepo", "pull": "abc"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetPRReviews(c)
+
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	APIErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "invalid pull parameter", APIErr.Message())
+}
+
+func TestGetPRFilesNoErrorMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetPRFiles = func(owner string, repo string, pullRequest string) ([]githubdomain.ChangedFile, errors.APIError) {
+		assert.EqualValues(t, "9", pullRequest)
+		return []githubdomain.ChangedFile{{Filename: "src/main.go", Status: githubdomain.FileStatusModified, Additions: 10, Deletions: 2, Changes: 12}}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/owner/myrepo/pulls/9/files", strings.NewReader(`{}`))
+	params := map[string]string{"owner": "owner", "repo": "myrepo", "pull": "9"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetPRFiles(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	var result []githubdomain.ChangedFile
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, "src/main.go", result[0].Filename)
+	assert.EqualValues(t, 12, result[0].Changes)
+}
+
+func TestGetPRFilesErrorMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetPRFiles = func(owner string, repo string, pullRequest string) ([]githubdomain.ChangedFile, errors.APIError) {
+		return nil, errors.NewBadRequestError("invalid pull parameter")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/owner/myrepo/pulls/abc/files", strings.NewReader(`{}`))
+	params := map[string]string{"owner": "owner", "repo": "myrepo", "pull": "abc"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetPRFiles(c)
+
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	APIErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "invalid pull parameter", APIErr.Message())
+}
+
+func TestGetRepoNoErrorMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetRepo = func(owner string, repo string) (*githubdomain.GetRepoInfo, errors.APIError) {
+		return &githubdomain.GetRepoInfo{
+			ID:            1296269,
+			Name:          repo,
+			FullName:      owner + "/" + repo,
+			DefaultBranch: "develop",
+		}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo", strings.NewReader(`{}`))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetRepo(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	var result githubdomain.GetRepoInfo
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1296269, result.ID)
+	assert.EqualValues(t, "myowner/myrepo", result.FullName)
+	assert.EqualValues(t, "develop", result.DefaultBranch)
+}
+
+func TestGetRepoGithubErrorMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetRepo = func(owner string, repo string) (*githubdomain.GetRepoInfo, errors.APIError) {
+		return nil, errors.NewNotFoundAPIError("Not Found")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo", strings.NewReader(`{}`))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetRepo(c)
+
+	assert.EqualValues(t, http.StatusNotFound, response.Code)
+
+	APIErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.NotNil(t, APIErr)
+	assert.EqualValues(t, http.StatusNotFound, APIErr.Status())
+	assert.EqualValues(t, "Not Found", APIErr.Message())
+}
+
+func TestGetCodeReviewReportJSONMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetCodeReviewReport = func(owner string, repo string, fromDate time.Time, endDate time.Time, paths []string) (*reportdomain.CodeReviewReport, errors.APIError) {
+		return &reportdomain.CodeReviewReport{
+			Owner:                               owner,
+			Repo:                                repo,
+			Branch:                              "main",
+			TotalCommits:                        1,
+			TotalCommitsReviewedOnOtherBranches: 1,
+			Commits: []reportdomain.CommitReview{
+				{SHA: "AABCDEF123456", ReviewStatus: reportdomain.ReviewStatusReviewedOnOtherBranch, PullNumber: 9, PullBaseRef: "develop"},
+			},
+		}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/codereview/myowner/myrepo?format=json", strings.NewReader(`{}`))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetCodeReviewReport(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	var result reportdomain.CodeReviewReport
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "main", result.Branch)
+	assert.EqualValues(t, 1, result.TotalCommitsReviewedOnOtherBranches)
+	assert.EqualValues(t, 1, len(result.Commits))
+	assert.EqualValues(t, "develop", result.Commits[0].PullBaseRef)
+}
+
+func TestGetCodeReviewReportForPathsMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetCodeReviewReport = func(owner string, repo string, fromDate time.Time, endDate time.Time, paths []string) (*reportdomain.CodeReviewReport, errors.APIError) {
+		return &reportdomain.CodeReviewReport{Owner: owner, Repo: repo, Branch: "main", Paths: paths}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/codereview/myowner/myrepo?format=json&path=services/payments/&path=*.go", strings.NewReader(`{}`))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetCodeReviewReport(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	var result reportdomain.CodeReviewReport
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"services/payments/", "*.go"}, result.Paths)
+}
+
+func TestGetRepoCommitsForPathsMockingEntireService(t *testing.T) {
+	services.RepositoryService = &repoServiceMock{}
+
+	funcGetRepoCommits = func(owner string, repo string, paths []string) ([]githubdomain.GetCommitInfo, errors.APIError) {
+		return nil, errors.NewBadRequestError("invalid path pattern " + paths[0])
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo/commits?path=services/%5Bpayments", nil)
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	GetRepoCommits(c)
+
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	APIErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "invalid path pattern services/[payments", APIErr.Message())
+}
@@ -9,6 +9,19 @@ import (
 	"github.com/greendinosaur/gh-commit-info/src/api/services"
 )
 
+//GetRepo returns the metadata for the given repo, including its default branch
+func GetRepo(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	result, err := services.RepositoryService.GetRepo(owner, repo)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 //GetRepoPRs returns the pull requests for the given repo
 func GetRepoPRs(c *gin.Context) {
 	owner := c.Param("owner")
@@ -43,12 +56,42 @@ func GetRepoSinglePR(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+//GetPRReviews returns the reviews of a single pull request
+func GetPRReviews(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+	pullRequest := c.Param("pull")
+
+	result, err := services.RepositoryService.GetPRReviews(owner, repo, pullRequest)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+//GetPRFiles returns the files changed by a single pull request
+func GetPRFiles(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+	pullRequest := c.Param("pull")
+
+	result, err := services.RepositoryService.GetPRFiles(owner, repo, pullRequest)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 //GetRepoCommits returns all commits for a repo
+//the commits can be limited to those changing files matching one or more path query parameters, such as services/payments/**
 func GetRepoCommits(c *gin.Context) {
 	owner := c.Param("owner")
 	repo := c.Param("repo")
+	paths := c.QueryArray("path")
 
-	result, err := services.RepositoryService.GetRepoCommits(owner, repo)
+	result, err := services.RepositoryService.GetRepoCommits(owner, repo, paths)
 	if err != nil {
 		c.JSON(err.Status(), err)
 		return
@@ -85,17 +128,25 @@ func GetPRsForSingleCommit(c *gin.Context) {
 }
 
 //GetCodeReviewReport returns a plain text response with details of the commits and PRs
+//the report can be returned as JSON instead by setting the format query parameter to json
+//the report can be limited to the commits changing files matching one or more path query parameters
 func GetCodeReviewReport(c *gin.Context) {
 	owner := c.Param("owner")
 	repo := c.Param("repo")
+	paths := c.QueryArray("path")
 	//TODO: for now hard code to the last month of commits, will need to pass this in as variables
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
 
-	result, err := services.RepositoryService.GetCodeReviewReport(owner, repo, fromDate, toDate)
+	result, err := services.RepositoryService.GetCodeReviewReport(owner, repo, fromDate, toDate, paths)
 	if err != nil {
 		c.JSON(err.Status(), err)
 		return
 	}
-	c.Data(http.StatusOK, "text/plain", []byte(result))
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	c.Data(http.StatusOK, "text/plain", []byte(result.Text()))
 }
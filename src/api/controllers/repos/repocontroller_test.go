@@ -35,7 +35,7 @@ func TestGetRepoPRsErrorFromGithub(t *testing.T) {
 
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myowner/myrepo/pulls?state=all",
+		URL:        "https://api.github.com/repos/myowner/myrepo/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
@@ -64,7 +64,7 @@ func TestGetRepoPRsNoError(t *testing.T) {
 
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myowner/myrepo/pulls?state=all",
+		URL:        "https://api.github.com/repos/myowner/myrepo/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
@@ -98,7 +98,7 @@ func TestGetRepoPRsMissingStateParam(t *testing.T) {
 
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myowner/myrepo/pulls?state=all",
+		URL:        "https://api.github.com/repos/myowner/myrepo/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
@@ -316,7 +316,7 @@ func TestGetPRsForSingleCommitInvalidResponse(t *testing.T) {
 
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myowner/myrepo/commits/SHA123/pulls",
+		URL:        "https://api.github.com/repos/myowner/myrepo/commits/SHA123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
@@ -345,7 +345,7 @@ func TestGetPRsForSingleCommitNoError(t *testing.T) {
 
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myowner/myrepo/commits/SHA123/pulls",
+		URL:        "https://api.github.com/repos/myowner/myrepo/commits/SHA123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
@@ -379,9 +379,17 @@ func TestGetCodeReviewReportError(t *testing.T) {
 
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myowner/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myowner/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
 
 	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myowner/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
 		HTTPMethod: http.MethodGet,
@@ -414,7 +422,16 @@ func TestCodeReviewReportNoError(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -426,7 +443,7 @@ func TestCodeReviewReportNoError(t *testing.T) {
 	})
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls",
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
@@ -436,7 +453,9 @@ func TestCodeReviewReportNoError(t *testing.T) {
 
 	GetCodeReviewReport(c)
 
-	result := string(response.Body.Bytes())
-	assert.EqualValues(t, "#Total Commits: 1, #Merged Commits: 0,  #Commits with PRs: 1, #Commits with No PRs: 0", result)
+	result := strings.Split(string(response.Body.Bytes()), "\n")
+	assert.EqualValues(t, 2, len(result))
+	assert.EqualValues(t, "#Branch: main, #Total Commits: 1, #Merged Commits: 0,  #Commits with PRs: 1, #Commits reviewed on other branches: 0, #Commits with No PRs: 0", result[0])
+	assert.True(t, strings.HasPrefix(result[1], "#Data as of: "))
 
 }
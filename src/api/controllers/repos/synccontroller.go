@@ -0,0 +1,34 @@
+package repos
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+)
+
+//SyncRepo fetches the commits and PRs that are new since the last sync into the data store
+func SyncRepo(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	result, err := services.SyncService.SyncRepo(owner, repo)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+//GetSyncState returns how far the repo has been synced and the outcome of the last sync
+func GetSyncState(c *gin.Context) {
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	result, err := services.SyncService.GetSyncState(owner, repo)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,106 @@
+package repos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/syncdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcSyncRepo     func(owner string, repo string) (*syncdomain.SyncState, errors.APIError)
+	funcGetSyncState func(owner string, repo string) (*syncdomain.SyncState, errors.APIError)
+)
+
+type syncServiceMock struct{}
+
+func (s *syncServiceMock) SyncRepo(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+	return funcSyncRepo(owner, repo)
+}
+
+func (s *syncServiceMock) GetSyncState(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+	return funcGetSyncState(owner, repo)
+}
+
+func TestSyncRepoNoErrorMockingEntireService(t *testing.T) {
+	services.SyncService = &syncServiceMock{}
+	defer services.ResetSyncService()
+
+	funcSyncRepo = func(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+		return &syncdomain.SyncState{Owner: owner, Repo: repo, Branch: "main", LastSyncCommits: 3}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/repos/myowner/myrepo/sync", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	SyncRepo(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result syncdomain.SyncState
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "myowner", result.Owner)
+	assert.EqualValues(t, "main", result.Branch)
+	assert.EqualValues(t, 3, result.LastSyncCommits)
+}
+
+func TestSyncRepoErrorMockingEntireService(t *testing.T) {
+	services.SyncService = &syncServiceMock{}
+	defer services.ResetSyncService()
+
+	funcSyncRepo = func(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+		return nil, errors.NewAPIError(http.StatusConflict, "a sync of the repo is already in progress")
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/repos/myowner/myrepo/sync", strings.NewReader(``))
+	params := map[string]string{"owner": "myowner", "repo": "myrepo"}
+	c, _ := testutils.GetMockedContextWithParams(request, response, params)
+
+	SyncRepo(c)
+
+	assert.EqualValues(t, http.StatusConflict, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "a sync of the repo is already in progress", apiErr.Message())
+}
+
+func TestGetSyncStateMockingEntireService(t *testing.T) {
+	services.SyncService = &syncServiceMock{}
+	defer services.ResetSyncService()
+
+	funcGetSyncState = func(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+		if repo == "missing" {
+			return nil, errors.NewNotFoundAPIError("the repo has not been synced")
+		}
+		return &syncdomain.SyncState{Owner: owner, Repo: repo, LastError: "Requires authentication"}, nil
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/repos/myowner/myrepo/sync", strings.NewReader(``))
+	c, _ := testutils.GetMockedContextWithParams(request, response, map[string]string{"owner": "myowner", "repo": "myrepo"})
+
+	GetSyncState(c)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result syncdomain.SyncState
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Requires authentication", result.LastError)
+
+	response = httptest.NewRecorder()
+	c, _ = testutils.GetMockedContextWithParams(request, response, map[string]string{"owner": "myowner", "repo": "missing"})
+
+	GetSyncState(c)
+
+	assert.EqualValues(t, http.StatusNotFound, response.Code)
+}
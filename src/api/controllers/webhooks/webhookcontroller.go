@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+//the headers Github sends with every webhook delivery
+const (
+	headerGithubEvent     = "X-GitHub-Event"
+	headerGithubDelivery  = "X-GitHub-Delivery"
+	headerGithubSignature = "X-Hub-Signature-256"
+)
+
+//ReceiveGithubEvent receives a webhook delivery from Github and records the event it contains
+func ReceiveGithubEvent(c *gin.Context) {
+	payload, readErr := c.GetRawData()
+	if readErr != nil {
+		err := errors.NewBadRequestError("unable to read webhook payload")
+		c.JSON(err.Status(), err)
+		return
+	}
+
+	result, err := services.WebhookService.HandleGithubEvent(
+		c.GetHeader(headerGithubEvent),
+		c.GetHeader(headerGithubDelivery),
+		c.GetHeader(headerGithubSignature),
+		payload)
+	if err != nil {
+		c.JSON(err.Status(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
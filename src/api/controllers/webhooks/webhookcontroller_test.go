@@ -0,0 +1,76 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/services"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func performWebhookRequest(event string, deliveryID string, signature string, payload []byte) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(payload))
+	request.Header.Set(headerGithubEvent, event)
+	request.Header.Set(headerGithubDelivery, deliveryID)
+	request.Header.Set(headerGithubSignature, signature)
+	c, _ := testutils.GetMockedContext(request, response)
+
+	ReceiveGithubEvent(c)
+	return response
+}
+
+func TestReceiveGithubEventNoError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetWebhookService()
+	os.Setenv("SECRET_GITHUB_WEBHOOK_SECRET", "mysecret")
+
+	payload := testutils.GetMockDataPushEventPayload()
+	response := performWebhookRequest(githubdomain.WebhookEventPush, "delivery1", testutils.GetWebhookSignature("mysecret", payload), payload)
+
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var result githubdomain.WebhookDelivery
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "delivery1", result.ID)
+	assert.EqualValues(t, githubdomain.WebhookEventPush, result.Event)
+	assert.EqualValues(t, services.WebhookStatusProcessed, result.Status)
+}
+
+func TestReceiveGithubEventInvalidSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetWebhookService()
+	os.Setenv("SECRET_GITHUB_WEBHOOK_SECRET", "mysecret")
+
+	payload := testutils.GetMockDataPushEventPayload()
+	response := performWebhookRequest(githubdomain.WebhookEventPush, "delivery1", testutils.GetWebhookSignature("wrongsecret", payload), payload)
+
+	assert.EqualValues(t, http.StatusUnauthorized, response.Code)
+	apiErr, err := errors.NewAPIErrorFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, apiErr.Status())
+	assert.EqualValues(t, "invalid webhook signature", apiErr.Message())
+}
+
+func TestReceiveGithubEventDuplicateDelivery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services.ResetWebhookService()
+	os.Setenv("SECRET_GITHUB_WEBHOOK_SECRET", "mysecret")
+
+	payload := testutils.GetMockDataPingEventPayload()
+	signature := testutils.GetWebhookSignature("mysecret", payload)
+
+	response := performWebhookRequest(githubdomain.WebhookEventPing, "delivery1", signature, payload)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	response = performWebhookRequest(githubdomain.WebhookEventPing, "delivery1", signature, payload)
+	assert.EqualValues(t, http.StatusConflict, response.Code)
+}
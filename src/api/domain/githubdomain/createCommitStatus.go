@@ -0,0 +1,69 @@
+package githubdomain
+
+import "time"
+
+//the states a commit status can be set to
+const (
+	CommitStatusSuccess = "success"
+	CommitStatusFailure = "failure"
+)
+
+//the status and outcomes of a check run
+const (
+	CheckRunStatusCompleted   = "completed"
+	CheckRunConclusionSuccess = "success"
+	CheckRunConclusionFailure = "failure"
+)
+
+//CreateCommitStatusRequest sets the status of a commit for the given context
+//Github limits the description to 140 characters
+type CreateCommitStatusRequest struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+//CommitStatus is a status that has been set on a commit
+type CommitStatus struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	State       string    `json:"state"`
+	Description string    `json:"description"`
+	TargetURL   string    `json:"target_url"`
+	Context     string    `json:"context"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+//CheckRunOutput is the title and markdown summary shown against a check run
+type CheckRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Text    string `json:"text,omitempty"`
+}
+
+//CreateCheckRunRequest creates a check run against a commit, a completed check run needs a conclusion
+type CreateCheckRunRequest struct {
+	Name        string          `json:"name"`
+	HeadSHA     string          `json:"head_sha"`
+	Status      string          `json:"status"`
+	Conclusion  string          `json:"conclusion,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	DetailsURL  string          `json:"details_url,omitempty"`
+	Output      *CheckRunOutput `json:"output,omitempty"`
+}
+
+//CheckRun is a check run that has been created against a commit
+type CheckRun struct {
+	ID          int64          `json:"id"`
+	HeadSHA     string         `json:"head_sha"`
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	Conclusion  string         `json:"conclusion"`
+	URL         string         `json:"url"`
+	HTMLURL     string         `json:"html_url"`
+	StartedAt   time.Time      `json:"started_at"`
+	CompletedAt time.Time      `json:"completed_at"`
+	Output      CheckRunOutput `json:"output"`
+}
@@ -0,0 +1,65 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitStatusConstants(t *testing.T) {
+	assert.EqualValues(t, "success", CommitStatusSuccess)
+	assert.EqualValues(t, "failure", CommitStatusFailure)
+	assert.EqualValues(t, "completed", CheckRunStatusCompleted)
+	assert.EqualValues(t, "success", CheckRunConclusionSuccess)
+	assert.EqualValues(t, "failure", CheckRunConclusionFailure)
+}
+
+func TestCreateCommitStatusRequest(t *testing.T) {
+	request := CreateCommitStatusRequest{State: CommitStatusFailure, Description: "No PR was merged into main", Context: "code-review-compliance"}
+
+	bytes, err := json.Marshal(request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"state":"failure","description":"No PR was merged into main","context":"code-review-compliance"}`, string(bytes))
+}
+
+func TestCommitStatusFromGithubJSON(t *testing.T) {
+	jsonAsString := `{"url":"https://api.github.com/repos/myuser/myrepo/statuses/AAA111","id":1,"state":"success","description":"Reviewed in PR #12","target_url":null,"context":"code-review-compliance","created_at":"2020-01-10T09:00:00Z","updated_at":"2020-01-10T09:00:00Z"}`
+
+	var target CommitStatus
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, target.ID)
+	assert.EqualValues(t, CommitStatusSuccess, target.State)
+	assert.EqualValues(t, "code-review-compliance", target.Context)
+	assert.EqualValues(t, time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC), target.CreatedAt)
+}
+
+func TestCreateCheckRunRequest(t *testing.T) {
+	completedAt := time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC)
+	request := CreateCheckRunRequest{
+		Name:        "code-review-compliance",
+		HeadSHA:     "AAA111",
+		Status:      CheckRunStatusCompleted,
+		Conclusion:  CheckRunConclusionSuccess,
+		CompletedAt: &completedAt,
+		Output:      &CheckRunOutput{Title: "pass", Summary: "Reviewed in PR #12"},
+	}
+
+	bytes, err := json.Marshal(request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"name":"code-review-compliance","head_sha":"AAA111","status":"completed","conclusion":"success","completed_at":"2020-01-10T09:00:00Z","output":{"title":"pass","summary":"Reviewed in PR #12"}}`, string(bytes))
+}
+
+func TestCheckRunFromGithubJSON(t *testing.T) {
+	jsonAsString := `{"id":4,"head_sha":"AAA111","name":"code-review-compliance","status":"completed","conclusion":"failure","url":"https://api.github.com/repos/myuser/myrepo/check-runs/4","html_url":"https://github.com/myuser/myrepo/runs/4","completed_at":"2020-01-10T09:00:00Z","output":{"title":"fail","summary":"No PR"}}`
+
+	var target CheckRun
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, target.ID)
+	assert.EqualValues(t, CheckRunConclusionFailure, target.Conclusion)
+	assert.EqualValues(t, "https://github.com/myuser/myrepo/runs/4", target.HTMLURL)
+	assert.EqualValues(t, "No PR", target.Output.Summary)
+}
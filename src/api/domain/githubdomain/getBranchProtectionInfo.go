@@ -0,0 +1,62 @@
+package githubdomain
+
+//BranchProtection stores the protection rules of a branch, a rule Github doesn't return isn't turned on
+type BranchProtection struct {
+	URL                        string                      `json:"url"`
+	RequiredStatusChecks       *RequiredStatusChecks       `json:"required_status_checks,omitempty"`
+	EnforceAdmins              *ProtectionSetting          `json:"enforce_admins,omitempty"`
+	RequiredPullRequestReviews *RequiredPullRequestReviews `json:"required_pull_request_reviews,omitempty"`
+	RequiredLinearHistory      *ProtectionSetting          `json:"required_linear_history,omitempty"`
+	AllowForcePushes           *ProtectionSetting          `json:"allow_force_pushes,omitempty"`
+	AllowDeletions             *ProtectionSetting          `json:"allow_deletions,omitempty"`
+}
+
+//ProtectionSetting is a protection rule that is either on or off
+type ProtectionSetting struct {
+	Enabled bool `json:"enabled"`
+}
+
+//RequiredStatusChecks are the checks that must pass before a PR can be merged into the branch
+//a strict check also needs the branch of the PR to be up to date with the protected branch
+type RequiredStatusChecks struct {
+	Strict   bool     `json:"strict"`
+	Contexts []string `json:"contexts"`
+}
+
+//RequiredPullRequestReviews are the reviews a PR needs before it can be merged into the branch
+type RequiredPullRequestReviews struct {
+	DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+	RequireCodeOwnerReviews      bool `json:"require_code_owner_reviews"`
+	RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+}
+
+//GetRequiredApprovals returns the number of approvals a PR needs, zero if PRs aren't required
+func (p *BranchProtection) GetRequiredApprovals() int {
+	if p.RequiredPullRequestReviews == nil {
+		return 0
+	}
+	return p.RequiredPullRequestReviews.RequiredApprovingReviewCount
+}
+
+//IsDismissingStaleReviews returns true if an approval is dismissed when new commits are pushed
+func (p *BranchProtection) IsDismissingStaleReviews() bool {
+	return p.RequiredPullRequestReviews != nil && p.RequiredPullRequestReviews.DismissStaleReviews
+}
+
+//IsRequiringCodeOwnerReviews returns true if the code owners of the changed files have to approve a PR
+func (p *BranchProtection) IsRequiringCodeOwnerReviews() bool {
+	return p.RequiredPullRequestReviews != nil && p.RequiredPullRequestReviews.RequireCodeOwnerReviews
+}
+
+//IsEnforcedForAdmins returns true if admins have to follow the protection rules too
+func (p *BranchProtection) IsEnforcedForAdmins() bool {
+	return p.EnforceAdmins != nil && p.EnforceAdmins.Enabled
+}
+
+//GetRequiredStatusChecks returns the names of the checks that must pass before a PR can be merged
+func (p *BranchProtection) GetRequiredStatusChecks() []string {
+	if p.RequiredStatusChecks == nil {
+		return nil
+	}
+	return p.RequiredStatusChecks.Contexts
+}
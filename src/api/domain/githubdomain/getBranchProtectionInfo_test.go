@@ -0,0 +1,41 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchProtectionFromGithubJSON(t *testing.T) {
+	jsonAsString := `{
+		"url":"https://api.github.com/repos/myuser/myrepo/branches/main/protection",
+		"required_status_checks":{"strict":true,"contexts":["ci/build","ci/test"]},
+		"enforce_admins":{"url":"https://api.github.com/repos/myuser/myrepo/branches/main/protection/enforce_admins","enabled":true},
+		"required_pull_request_reviews":{"dismiss_stale_reviews":true,"require_code_owner_reviews":false,"required_approving_review_count":2},
+		"allow_force_pushes":{"enabled":false}
+	}`
+
+	var target BranchProtection
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, target.GetRequiredApprovals())
+	assert.True(t, target.IsDismissingStaleReviews())
+	assert.False(t, target.IsRequiringCodeOwnerReviews())
+	assert.True(t, target.IsEnforcedForAdmins())
+	assert.EqualValues(t, []string{"ci/build", "ci/test"}, target.GetRequiredStatusChecks())
+	assert.True(t, target.RequiredStatusChecks.Strict)
+	assert.False(t, target.AllowForcePushes.Enabled)
+	assert.Nil(t, target.AllowDeletions)
+}
+
+func TestBranchProtectionWithoutRules(t *testing.T) {
+	var target BranchProtection
+	err := json.Unmarshal([]byte(`{"url":"https://api.github.com/repos/myuser/myrepo/branches/main/protection"}`), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, target.GetRequiredApprovals())
+	assert.False(t, target.IsDismissingStaleReviews())
+	assert.False(t, target.IsRequiringCodeOwnerReviews())
+	assert.False(t, target.IsEnforcedForAdmins())
+	assert.EqualValues(t, 0, len(target.GetRequiredStatusChecks()))
+}
@@ -0,0 +1,27 @@
+package githubdomain
+
+//the status of a file changed by a commit or PR
+const (
+	FileStatusAdded    = "added"
+	FileStatusModified = "modified"
+	FileStatusRemoved  = "removed"
+	FileStatusRenamed  = "renamed"
+)
+
+//ChangedFile stores information about a single file changed by a commit or PR
+type ChangedFile struct {
+	SHA              string `json:"sha"`
+	Filename         string `json:"filename"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Changes          int    `json:"changes"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+}
+
+//CommitStats stores the number of lines a commit added and deleted across all its files
+type CommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Total     int `json:"total"`
+}
@@ -0,0 +1,30 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStatusConstants(t *testing.T) {
+	assert.EqualValues(t, "added", FileStatusAdded)
+	assert.EqualValues(t, "modified", FileStatusModified)
+	assert.EqualValues(t, "removed", FileStatusRemoved)
+	assert.EqualValues(t, "renamed", FileStatusRenamed)
+}
+
+func TestChangedFileFromGithubJSON(t *testing.T) {
+	jsonAsString := `[{"sha":"bbcd538c8e72b8c175046e27cc8f907076331401","filename":"src/new.go","status":"renamed","additions":103,"deletions":21,"changes":124,"blob_url":"https://github.com/myuser/myrepo/blob/6dcb09b5/src/new.go","patch":"@@ -132,7 +132,7 @@","previous_filename":"src/old.go"}]`
+
+	var target []ChangedFile
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(target))
+	assert.EqualValues(t, "src/new.go", target[0].Filename)
+	assert.EqualValues(t, FileStatusRenamed, target[0].Status)
+	assert.EqualValues(t, 103, target[0].Additions)
+	assert.EqualValues(t, 21, target[0].Deletions)
+	assert.EqualValues(t, 124, target[0].Changes)
+	assert.EqualValues(t, "src/old.go", target[0].PreviousFilename)
+}
@@ -0,0 +1,109 @@
+package githubdomain
+
+import "time"
+
+//the other statuses and outcomes of a check run, a check run is only finished once it is completed
+const (
+	CheckRunStatusQueued             = "queued"
+	CheckRunStatusInProgress         = "in_progress"
+	CheckRunConclusionNeutral        = "neutral"
+	CheckRunConclusionSkipped        = "skipped"
+	CheckRunConclusionCancelled      = "cancelled"
+	CheckRunConclusionTimedOut       = "timed_out"
+	CheckRunConclusionActionRequired = "action_required"
+)
+
+//CheckRunList is a page of the check runs on a commit
+type CheckRunList struct {
+	TotalCount int        `json:"total_count"`
+	CheckRuns  []CheckRun `json:"check_runs"`
+}
+
+//GetState returns the check run as the state of a status check so both can be treated alike
+//Github lets a neutral or skipped check run satisfy a required check
+func (r *CheckRun) GetState() string {
+	if r.Status != CheckRunStatusCompleted {
+		return StatusStatePending
+	}
+	switch r.Conclusion {
+	case CheckRunConclusionSuccess, CheckRunConclusionNeutral, CheckRunConclusionSkipped:
+		return StatusStateSuccess
+	case "":
+		return StatusStatePending
+	default:
+		return StatusStateFailure
+	}
+}
+
+//CommitChecks holds the commit statuses and the check runs on a commit, Github accepts either of them for a required check
+type CommitChecks struct {
+	SHA       string          `json:"sha"`
+	Statuses  *CombinedStatus `json:"statuses"`
+	CheckRuns []CheckRun      `json:"check_runs"`
+}
+
+//GetCheckRun returns the latest check run with the given name, nil if the commit has no check run for it
+func (c *CommitChecks) GetCheckRun(name string) *CheckRun {
+	return c.getCheckRunAt(name, time.Time{})
+}
+
+//getCheckRunAt returns the latest check run with the given name that had started by the time, a zero time means now
+//a check run that is run again gets a new ID so the highest ID is the latest
+func (c *CommitChecks) getCheckRunAt(name string, at time.Time) *CheckRun {
+	var result *CheckRun
+	for counter := range c.CheckRuns {
+		checkRun := &c.CheckRuns[counter]
+		if checkRun.Name != name || (!at.IsZero() && checkRun.StartedAt.After(at)) {
+			continue
+		}
+		if result == nil || checkRun.ID > result.ID {
+			result = checkRun
+		}
+	}
+	return result
+}
+
+//GetState returns the state of the required check, an empty state means the commit has neither a status nor a check run for it
+func (c *CommitChecks) GetState(name string) string {
+	return c.GetStateAt(name, time.Time{})
+}
+
+//StatusStateUnknown is the state of a status that was set after the time, what it was at the time is not known
+const StatusStateUnknown = "unknown"
+
+//GetStateAt returns the state the required check had at the time, such as when a PR was merged, a zero time means now
+//the check has passed if either the status or the check run succeeded, otherwise a failure of either of them fails it
+//Github only keeps the latest status of each context so the state of a status set after the time is unknown,
+//a known state of the check run takes precedence over it
+func (c *CommitChecks) GetStateAt(name string, at time.Time) string {
+	var states []string
+	if c.Statuses != nil {
+		if status := c.Statuses.GetStatus(name); status != nil {
+			if !at.IsZero() && status.UpdatedAt.After(at) {
+				states = append(states, StatusStateUnknown)
+			} else {
+				states = append(states, status.State)
+			}
+		}
+	}
+	if checkRun := c.getCheckRunAt(name, at); checkRun != nil {
+		if !at.IsZero() && checkRun.CompletedAt.After(at) {
+			states = append(states, StatusStatePending)
+		} else {
+			states = append(states, checkRun.GetState())
+		}
+	}
+
+	result := ""
+	for _, state := range states {
+		switch {
+		case state == StatusStateSuccess:
+			return state
+		case state == StatusStateFailure || state == StatusStateError:
+			result = state
+		case result == "" || result == StatusStateUnknown:
+			result = state
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRunListFromGithubJSON(t *testing.T) {
+	jsonAsString := `{"total_count":2,"check_runs":[{"id":4,"head_sha":"6dcb09b5","name":"build","status":"completed","conclusion":"success"},{"id":5,"head_sha":"6dcb09b5","name":"test","status":"in_progress","conclusion":null}]}`
+
+	var target CheckRunList
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, target.TotalCount)
+	assert.EqualValues(t, "build", target.CheckRuns[0].Name)
+	assert.EqualValues(t, "", target.CheckRuns[1].Conclusion)
+}
+
+func TestCheckRunGetState(t *testing.T) {
+	assert.EqualValues(t, StatusStatePending, (&CheckRun{Status: CheckRunStatusQueued}).GetState())
+	assert.EqualValues(t, StatusStatePending, (&CheckRun{Status: CheckRunStatusInProgress}).GetState())
+	assert.EqualValues(t, StatusStateSuccess, (&CheckRun{Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionSuccess}).GetState())
+	assert.EqualValues(t, StatusStateSuccess, (&CheckRun{Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionSkipped}).GetState())
+	assert.EqualValues(t, StatusStateFailure, (&CheckRun{Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionTimedOut}).GetState())
+	assert.EqualValues(t, StatusStateFailure, (&CheckRun{Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionCancelled}).GetState())
+}
+
+func TestCommitChecksGetState(t *testing.T) {
+	checks := CommitChecks{
+		Statuses: &CombinedStatus{Statuses: []CommitStatus{
+			{Context: "ci/build", State: StatusStateSuccess},
+			{Context: "ci/test", State: StatusStateFailure},
+			{Context: "ci/deploy", State: StatusStatePending},
+		}},
+		CheckRuns: []CheckRun{
+			{ID: 1, Name: "ci/test", Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionSuccess},
+			{ID: 3, Name: "lint", Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionSuccess},
+			{ID: 2, Name: "lint", Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionFailure},
+			{ID: 4, Name: "ci/deploy", Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionFailure},
+		},
+	}
+
+	assert.EqualValues(t, StatusStateSuccess, checks.GetState("ci/build"))
+	//either a status or a check run is enough
+	assert.EqualValues(t, StatusStateSuccess, checks.GetState("ci/test"))
+	//the check run was run again and passed
+	assert.EqualValues(t, StatusStateSuccess, checks.GetState("lint"))
+	assert.EqualValues(t, int64(3), checks.GetCheckRun("lint").ID)
+	assert.EqualValues(t, StatusStateFailure, checks.GetState("ci/deploy"))
+	assert.EqualValues(t, "", checks.GetState("ci/missing"))
+	assert.Nil(t, checks.GetCheckRun("ci/missing"))
+
+	assert.EqualValues(t, "", (&CommitChecks{}).GetState("ci/build"))
+}
+
+func TestCommitChecksGetStateAt(t *testing.T) {
+	mergedAt := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	checks := CommitChecks{
+		Statuses: &CombinedStatus{Statuses: []CommitStatus{
+			{Context: "ci/build", State: StatusStateSuccess, UpdatedAt: mergedAt.Add(-time.Minute)},
+			{Context: "ci/test", State: StatusStateSuccess, UpdatedAt: mergedAt.Add(time.Minute)},
+			{Context: "e2e", State: StatusStateSuccess, UpdatedAt: mergedAt.Add(time.Minute)},
+		}},
+		CheckRuns: []CheckRun{
+			{ID: 1, Name: "lint", Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionFailure, StartedAt: mergedAt.Add(-time.Hour), CompletedAt: mergedAt.Add(-time.Minute)},
+			{ID: 2, Name: "lint", Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionSuccess, StartedAt: mergedAt.Add(time.Hour), CompletedAt: mergedAt.Add(2 * time.Hour)},
+			{ID: 3, Name: "e2e", Status: CheckRunStatusCompleted, Conclusion: CheckRunConclusionSuccess, StartedAt: mergedAt.Add(-time.Minute), CompletedAt: mergedAt.Add(time.Minute)},
+		},
+	}
+
+	assert.EqualValues(t, StatusStateSuccess, checks.GetStateAt("ci/build", mergedAt))
+	//the status was set again after the merge so what it was at the merge is not known
+	assert.EqualValues(t, StatusStateUnknown, checks.GetStateAt("ci/test", mergedAt))
+	assert.EqualValues(t, StatusStateSuccess, checks.GetState("ci/test"))
+	//the check run was only run again after the merge
+	assert.EqualValues(t, StatusStateFailure, checks.GetStateAt("lint", mergedAt))
+	assert.EqualValues(t, StatusStateSuccess, checks.GetState("lint"))
+	//the check run was still running when the PR was merged, which is known unlike the state of the status
+	assert.EqualValues(t, StatusStatePending, checks.GetStateAt("e2e", mergedAt))
+}
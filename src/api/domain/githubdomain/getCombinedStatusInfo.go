@@ -0,0 +1,27 @@
+package githubdomain
+
+//the states of a status check, the combined state is pending until every status has succeeded
+const (
+	StatusStatePending = "pending"
+	StatusStateSuccess = "success"
+	StatusStateFailure = "failure"
+	StatusStateError   = "error"
+)
+
+//CombinedStatus stores the latest status of each context on a commit
+type CombinedStatus struct {
+	State      string         `json:"state"`
+	SHA        string         `json:"sha"`
+	TotalCount int            `json:"total_count"`
+	Statuses   []CommitStatus `json:"statuses"`
+}
+
+//GetStatus returns the status of the given context, nil if the commit has no status for it
+func (s *CombinedStatus) GetStatus(context string) *CommitStatus {
+	for counter := range s.Statuses {
+		if s.Statuses[counter].Context == context {
+			return &s.Statuses[counter]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusStateConstants(t *testing.T) {
+	assert.EqualValues(t, "pending", StatusStatePending)
+	assert.EqualValues(t, "success", StatusStateSuccess)
+	assert.EqualValues(t, "failure", StatusStateFailure)
+	assert.EqualValues(t, "error", StatusStateError)
+}
+
+func TestCombinedStatusFromGithubJSON(t *testing.T) {
+	jsonAsString := `{"state":"failure","sha":"6dcb09b5","total_count":2,"statuses":[{"id":1,"state":"success","context":"ci/build"},{"id":2,"state":"failure","context":"ci/test"}]}`
+
+	var target CombinedStatus
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, StatusStateFailure, target.State)
+	assert.EqualValues(t, 2, target.TotalCount)
+
+	assert.EqualValues(t, StatusStateSuccess, target.GetStatus("ci/build").State)
+	assert.EqualValues(t, StatusStateFailure, target.GetStatus("ci/test").State)
+	assert.Nil(t, target.GetStatus("ci/lint"))
+}
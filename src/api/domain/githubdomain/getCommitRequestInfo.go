@@ -1,6 +1,10 @@
 package githubdomain
 
-import "time"
+import (
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/messagedomain"
+)
 
 //GetCommitInfo returns information about a commit
 type GetCommitInfo struct {
@@ -10,16 +14,62 @@ type GetCommitInfo struct {
 	Author        GitUser                       `json:"author"`
 	Committer     GitUser                       `json:"committer"`
 	Parents       []Parent                      `json:"parents"`
-	IsMergeCommit bool                          `json:"ismergecommit"` //not set by github, calculated later in code
-	PRForMerge    *GetSinglePullRequestResponse `json:"pull"`          //not set by github
+	Stats         *CommitStats                  `json:"stats,omitempty"`          //only set by github for a single commit
+	Files         []ChangedFile                 `json:"files,omitempty"`          //only set by github for a single commit
+	IsMergeCommit bool                          `json:"ismergecommit"`            //not set by github, calculated later in code
+	PRForMerge    *GetSinglePullRequestResponse `json:"pull"`                     //not set by github
+	ParsedMessage *messagedomain.CommitMessage  `json:"parsed_message,omitempty"` //not set by github, parsed from the message
+	Relations     []CommitRelation              `json:"relations,omitempty"`      //not set by github, worked out from the message
+}
+
+//the ways a commit can be related to an earlier commit
+const (
+	RelationRevert     = "revert"
+	RelationCherryPick = "cherry_pick"
+)
+
+//CommitRelation links a commit to the earlier commit it reverts or was cherry picked from along with the PR that commit was merged by
+//the earlier commit may not be found, such as when it was cherry picked from a fork, and may not have been merged by a PR
+type CommitRelation struct {
+	Type       string `json:"type"`
+	SHA        string `json:"sha"`
+	Found      bool   `json:"found"`
+	Message    string `json:"message,omitempty"`
+	PullNumber int64  `json:"pull_number,omitempty"`
+	PullTitle  string `json:"pull_title,omitempty"`
+}
+
+//SetParsedMessage splits the message of the commit into its parts
+func (c *GetCommitInfo) SetParsedMessage() {
+	c.ParsedMessage = messagedomain.ParseCommitMessage(c.Commit.Message)
 }
 
+//the reasons github gives for the outcome of verifying the signature of a commit, there are others for each way a signature can be bad
+const (
+	VerificationReasonValid    = "valid"
+	VerificationReasonUnsigned = "unsigned"
+)
+
 //DetailedCommitInfo has more detailed info about the commit
 type DetailedCommitInfo struct {
-	URL       string     `json:"url"`
-	Author    CommitUser `json:"author"`
-	Committer CommitUser `json:"committer"`
-	Message   string     `json:"message"`
+	URL          string              `json:"url"`
+	Author       CommitUser          `json:"author"`
+	Committer    CommitUser          `json:"committer"`
+	Message      string              `json:"message"`
+	Verification *CommitVerification `json:"verification,omitempty"` //not set for commits recorded from a push event
+}
+
+//CommitVerification is the outcome of github verifying the GPG, SSH or S/MIME signature of the commit
+type CommitVerification struct {
+	Verified  bool   `json:"verified"`
+	Reason    string `json:"reason"`
+	Signature string `json:"signature"`
+	Payload   string `json:"payload"`
+}
+
+//IsSigned returns true if the commit has a signature, even one github couldn't verify
+func (v *CommitVerification) IsSigned() bool {
+	return v.Reason != VerificationReasonUnsigned
 }
 
 //CommitUser has info about the user doing the commit
@@ -156,5 +156,55 @@ func TestGetCommitInfo(t *testing.T) {
 	assert.EqualValues(t, getCommitInfo.Author, target.Author)
 	assert.EqualValues(t, getCommitInfo.Committer, target.Committer)
 	assert.EqualValues(t, getCommitInfo.Parents, target.Parents)
+	assert.Nil(t, target.Stats)
+	assert.Nil(t, target.Files)
 
 }
+
+func TestGetCommitInfoWithFilesFromGithubJSON(t *testing.T) {
+	jsonAsString := `{"sha":"AABCDEF123456","stats":{"additions":104,"deletions":4,"total":108},"files":[{"filename":"file1.txt","status":"modified","additions":10,"deletions":2,"changes":12}]}`
+
+	var target GetCommitInfo
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, &CommitStats{Additions: 104, Deletions: 4, Total: 108}, target.Stats)
+	assert.EqualValues(t, 1, len(target.Files))
+	assert.EqualValues(t, "file1.txt", target.Files[0].Filename)
+	assert.EqualValues(t, 12, target.Files[0].Changes)
+}
+
+func TestCommitVerificationFromGithubJSON(t *testing.T) {
+	jsonAsString := `{"sha":"AABCDEF123456","commit":{"message":"signed","verification":{"verified":true,"reason":"valid","signature":"-----BEGIN PGP SIGNATURE-----","payload":"tree 123"}}}`
+
+	var target GetCommitInfo
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.True(t, target.Commit.Verification.Verified)
+	assert.EqualValues(t, VerificationReasonValid, target.Commit.Verification.Reason)
+	assert.EqualValues(t, "-----BEGIN PGP SIGNATURE-----", target.Commit.Verification.Signature)
+	assert.True(t, target.Commit.Verification.IsSigned())
+
+	assert.False(t, (&CommitVerification{Reason: VerificationReasonUnsigned}).IsSigned())
+	assert.True(t, (&CommitVerification{Reason: "unknown_key", Signature: "-----BEGIN PGP SIGNATURE-----"}).IsSigned())
+
+	bytes, err := json.Marshal(DetailedCommitInfo{Message: "pushed"})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(bytes), "verification")
+}
+
+func TestGetCommitInfoSetParsedMessage(t *testing.T) {
+	commit := GetCommitInfo{SHA: "ABC"}
+	bytes, err := json.Marshal(commit)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(bytes), "parsed_message")
+
+	commit.Commit.Message = "fix(api): handle empty repos\n\nSigned-off-by: Bob <bob@example.com>"
+	commit.SetParsedMessage()
+	assert.EqualValues(t, "fix", commit.ParsedMessage.Type)
+	assert.EqualValues(t, "api", commit.ParsedMessage.Scope)
+	assert.True(t, commit.ParsedMessage.IsSignedOffBy("bob@example.com"))
+
+	bytes, err = json.Marshal(commit)
+	assert.Nil(t, err)
+	assert.Contains(t, string(bytes), `"parsed_message":{"header":"fix(api): handle empty repos","conventional":true,"type":"fix","scope":"api"`)
+}
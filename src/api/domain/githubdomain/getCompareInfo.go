@@ -0,0 +1,14 @@
+package githubdomain
+
+//GetCompareCommitsResponse stores the result of comparing two refs in a repo
+//Status is one of "identical", "ahead", "behind" or "diverged" and describes head relative to base
+type GetCompareCommitsResponse struct {
+	URL             string          `json:"url"`
+	Status          string          `json:"status"`
+	AheadBy         int             `json:"ahead_by"`
+	BehindBy        int             `json:"behind_by"`
+	TotalCommits    int             `json:"total_commits"`
+	BaseCommit      GetCommitInfo   `json:"base_commit"`
+	MergeBaseCommit GetCommitInfo   `json:"merge_base_commit"`
+	Commits         []GetCommitInfo `json:"commits"`
+}
@@ -0,0 +1,53 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCompareCommitsResponse(t *testing.T) {
+	compare := GetCompareCommitsResponse{
+		URL:             "https://api.github.com/repos/myowner/myrepo/compare/main...ABCDEF",
+		Status:          "behind",
+		AheadBy:         0,
+		BehindBy:        3,
+		TotalCommits:    0,
+		BaseCommit:      GetCommitInfo{SHA: "BASE123"},
+		MergeBaseCommit: GetCommitInfo{SHA: "ABCDEF"},
+		Commits:         []GetCommitInfo{},
+	}
+
+	bytes, err := json.Marshal(compare)
+	assert.Nil(t, err)
+	assert.NotNil(t, bytes)
+
+	var target GetCompareCommitsResponse
+
+	err = json.Unmarshal(bytes, &target)
+	assert.Nil(t, err)
+	assert.NotNil(t, &target)
+	assert.EqualValues(t, compare.URL, target.URL)
+	assert.EqualValues(t, compare.Status, target.Status)
+	assert.EqualValues(t, compare.AheadBy, target.AheadBy)
+	assert.EqualValues(t, compare.BehindBy, target.BehindBy)
+	assert.EqualValues(t, compare.TotalCommits, target.TotalCommits)
+	assert.EqualValues(t, compare.BaseCommit.SHA, target.BaseCommit.SHA)
+	assert.EqualValues(t, compare.MergeBaseCommit.SHA, target.MergeBaseCommit.SHA)
+	assert.EqualValues(t, 0, len(target.Commits))
+}
+
+func TestGetCompareCommitsResponseFromGithubJSON(t *testing.T) {
+	jsonAsString := `{"url":"some URL","status":"ahead","ahead_by":1,"behind_by":0,"total_commits":1,"base_commit":{"sha":"BASE123"},"merge_base_commit":{"sha":"BASE123"},"commits":[{"sha":"AABCDEF123456","commit":{"message":"some commit message"},"parents":[{"sha":"BASE123"}]}]}`
+
+	var target GetCompareCommitsResponse
+
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "ahead", target.Status)
+	assert.EqualValues(t, 1, target.AheadBy)
+	assert.EqualValues(t, 1, len(target.Commits))
+	assert.EqualValues(t, "AABCDEF123456", target.Commits[0].SHA)
+	assert.EqualValues(t, "some commit message", target.Commits[0].Commit.Message)
+}
@@ -0,0 +1,49 @@
+package githubdomain
+
+import "time"
+
+//the states of a deployment status, a successful deployment becomes inactive once a later one replaces it
+const (
+	DeploymentStateSuccess    = "success"
+	DeploymentStateFailure    = "failure"
+	DeploymentStateError      = "error"
+	DeploymentStateInactive   = "inactive"
+	DeploymentStateInProgress = "in_progress"
+	DeploymentStateQueued     = "queued"
+	DeploymentStatePending    = "pending"
+)
+
+//Deployment stores information about a single deployment of a repo to an environment
+type Deployment struct {
+	ID          int64     `json:"id"`
+	SHA         string    `json:"sha"`
+	Ref         string    `json:"ref"`
+	Task        string    `json:"task"`
+	Environment string    `json:"environment"`
+	Description string    `json:"description"`
+	Creator     GitUser   `json:"creator"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+//DeploymentStatus stores a single status reported for a deployment
+type DeploymentStatus struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"state"`
+	Description string    `json:"description"`
+	Environment string    `json:"environment"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+//Release stores information about a single release of a repo
+type Release struct {
+	ID              int64     `json:"id"`
+	TagName         string    `json:"tag_name"`
+	TargetCommitish string    `json:"target_commitish"`
+	Name            string    `json:"name"`
+	Draft           bool      `json:"draft"`
+	Prerelease      bool      `json:"prerelease"`
+	Author          GitUser   `json:"author"`
+	CreatedAt       time.Time `json:"created_at"`
+	PublishedAt     time.Time `json:"published_at"`
+}
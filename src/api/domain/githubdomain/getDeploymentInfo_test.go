@@ -0,0 +1,39 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentConstants(t *testing.T) {
+	assert.EqualValues(t, "success", DeploymentStateSuccess)
+	assert.EqualValues(t, "failure", DeploymentStateFailure)
+	assert.EqualValues(t, "error", DeploymentStateError)
+	assert.EqualValues(t, "inactive", DeploymentStateInactive)
+	assert.EqualValues(t, "in_progress", DeploymentStateInProgress)
+	assert.EqualValues(t, "queued", DeploymentStateQueued)
+	assert.EqualValues(t, "pending", DeploymentStatePending)
+}
+
+func TestDeploymentUnmarshal(t *testing.T) {
+	var deployment Deployment
+	err := json.Unmarshal([]byte(`{"id":1,"sha":"AAA111","ref":"main","environment":"production","creator":{"login":"deployer"},"created_at":"2020-03-02T10:00:00Z"}`), &deployment)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, deployment.ID)
+	assert.EqualValues(t, "AAA111", deployment.SHA)
+	assert.EqualValues(t, "production", deployment.Environment)
+	assert.EqualValues(t, "deployer", deployment.Creator.Login)
+	assert.EqualValues(t, 2020, deployment.CreatedAt.Year())
+}
+
+func TestReleaseUnmarshal(t *testing.T) {
+	var release Release
+	err := json.Unmarshal([]byte(`{"id":2,"tag_name":"v1.0.1","target_commitish":"main","prerelease":true,"published_at":"2020-03-02T10:00:00Z"}`), &release)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "v1.0.1", release.TagName)
+	assert.True(t, release.Prerelease)
+	assert.False(t, release.Draft)
+	assert.EqualValues(t, 2, release.PublishedAt.Day())
+}
@@ -9,6 +9,8 @@ type GetSinglePullRequestResponse struct {
 	Number            int64     `json:"number"`
 	State             string    `json:"state"`
 	Title             string    `json:"title"`
+	Body              string    `json:"body"`
+	Labels            []Label   `json:"labels"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 	ClosedAt          time.Time `json:"closed_at"`
@@ -17,17 +19,32 @@ type GetSinglePullRequestResponse struct {
 	User              GitUser   `json:"user"`
 	Assignee          GitUser   `json:"assignee"`
 	Base              RepoBase  `json:"base"`
+	Head              RepoBase  `json:"head"`
 	AuthorAssociation string    `json:"author_association"`
 	Draft             bool      `json:"draft"`
 	Merged            bool      `json:"merged"`
 	MergeableState    string    `json:"mergeable_state"`
 	MergedBy          GitUser   `json:"merged_by"`
 	Commits           int64     `json:"commits"`
+	Additions         int64     `json:"additions"`     //only set by github for a single PR
+	Deletions         int64     `json:"deletions"`     //only set by github for a single PR
+	ChangedFiles      int64     `json:"changed_files"` //only set by github for a single PR
 }
 
-//RepoBase stores info about the base of the repo
+//HasDetails returns true if the PR was fetched on its own rather than read from a list of PRs
+//only a single PR says who merged it and how many commits and lines it has, every PR has at least one commit
+func (pr *GetSinglePullRequestResponse) HasDetails() bool {
+	return pr.Commits > 0 || pr.ChangedFiles > 0
+}
+
+//RepoBase stores info about the base or head branch of a PR
 type RepoBase struct {
 	Label string `json:"label"`
 	Ref   string `json:"ref"`
 	SHA   string `json:"sha"`
 }
+
+//Label stores the name of a label given to a PR
+type Label struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,37 @@
+package githubdomain
+
+import "time"
+
+//the states a review can be in, webhooks send these in lower case
+const (
+	ReviewStateApproved         = "APPROVED"
+	ReviewStateChangesRequested = "CHANGES_REQUESTED"
+	ReviewStateCommented        = "COMMENTED"
+	ReviewStateDismissed        = "DISMISSED"
+	ReviewStatePending          = "PENDING"
+)
+
+//PullRequestReview stores information about a single review of a PR
+type PullRequestReview struct {
+	ID                int64     `json:"id"`
+	User              GitUser   `json:"user"`
+	Body              string    `json:"body"`
+	State             string    `json:"state"`
+	HTMLURL           string    `json:"html_url"`
+	PullRequestURL    string    `json:"pull_request_url"`
+	CommitID          string    `json:"commit_id"`
+	SubmittedAt       time.Time `json:"submitted_at"`
+	AuthorAssociation string    `json:"author_association"`
+}
+
+//PullRequestReviewComment is a comment left on a line of the changes of a PR, usually as part of a review
+type PullRequestReviewComment struct {
+	ID                  int64     `json:"id"`
+	PullRequestReviewID int64     `json:"pull_request_review_id"`
+	User                GitUser   `json:"user"`
+	Body                string    `json:"body"`
+	Path                string    `json:"path"`
+	CommitID            string    `json:"commit_id"`
+	InReplyToID         int64     `json:"in_reply_to_id,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
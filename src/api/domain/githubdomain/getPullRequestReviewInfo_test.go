@@ -0,0 +1,67 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewStateConstants(t *testing.T) {
+	assert.EqualValues(t, "APPROVED", ReviewStateApproved)
+	assert.EqualValues(t, "CHANGES_REQUESTED", ReviewStateChangesRequested)
+	assert.EqualValues(t, "COMMENTED", ReviewStateCommented)
+	assert.EqualValues(t, "DISMISSED", ReviewStateDismissed)
+	assert.EqualValues(t, "PENDING", ReviewStatePending)
+}
+
+func TestPullRequestReview(t *testing.T) {
+	review := PullRequestReview{
+		ID: 80,
+		User: GitUser{
+			Login:     "A Second Login ID",
+			ID:        8767,
+			Type:      "User",
+			SiteAdmin: false,
+		},
+		Body:              "Looks good",
+		State:             ReviewStateApproved,
+		HTMLURL:           "https://github.com/myuser/myrepo/pull/9#pullrequestreview-80",
+		PullRequestURL:    "https://api.github.com/repos/myuser/myrepo/pulls/9",
+		CommitID:          "ABCDEF1234567890",
+		SubmittedAt:       time.Now().UTC(),
+		AuthorAssociation: "MEMBER",
+	}
+
+	bytes, err := json.Marshal(review)
+	assert.Nil(t, err)
+	assert.NotNil(t, bytes)
+
+	var target PullRequestReview
+
+	err = json.Unmarshal(bytes, &target)
+	assert.Nil(t, err)
+	assert.NotNil(t, &target)
+	assert.EqualValues(t, review.ID, target.ID)
+	assert.EqualValues(t, review.User, target.User)
+	assert.EqualValues(t, review.Body, target.Body)
+	assert.EqualValues(t, review.State, target.State)
+	assert.EqualValues(t, review.HTMLURL, target.HTMLURL)
+	assert.EqualValues(t, review.PullRequestURL, target.PullRequestURL)
+	assert.EqualValues(t, review.CommitID, target.CommitID)
+	assert.EqualValues(t, review.SubmittedAt, target.SubmittedAt)
+	assert.EqualValues(t, review.AuthorAssociation, target.AuthorAssociation)
+}
+
+func TestPullRequestReviewComment(t *testing.T) {
+	var comment PullRequestReviewComment
+	err := json.Unmarshal([]byte(`{"id":10,"pull_request_review_id":80,"user":{"login":"reviewer"},"body":"Why not reuse the helper?","path":"src/a.go","commit_id":"ABCDEF","in_reply_to_id":9,"created_at":"2020-03-02T09:00:00Z"}`), &comment)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, comment.ID)
+	assert.EqualValues(t, 80, comment.PullRequestReviewID)
+	assert.EqualValues(t, "reviewer", comment.User.Login)
+	assert.EqualValues(t, "src/a.go", comment.Path)
+	assert.EqualValues(t, 9, comment.InReplyToID)
+	assert.EqualValues(t, time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC), comment.CreatedAt)
+}
@@ -56,6 +56,8 @@ func TestGetPullRequestInfoResponse(t *testing.T) {
 		Number:         9,
 		State:          "open",
 		Title:          "Title of the PR",
+		Body:           "Fixes the login page",
+		Labels:         []Label{{Name: "bug"}},
 		CreatedAt:      time.Now().AddDate(0, 0, -1).UTC(),
 		UpdatedAt:      time.Now().AddDate(0, -1, 0).UTC(),
 		ClosedAt:       time.Now().AddDate(0, -1, 0).UTC(),
@@ -64,6 +66,10 @@ func TestGetPullRequestInfoResponse(t *testing.T) {
 		User:           gitUser1,
 		Assignee:       gitUser2,
 		Base:           repoBase,
+		Head:           RepoBase{Label: "myuser:feature", Ref: "feature", SHA: "FEDCBA654321"},
+		Additions:      100,
+		Deletions:      3,
+		ChangedFiles:   5,
 	}
 
 	bytes, err := json.Marshal(getPRInfoResponse)
@@ -83,6 +89,8 @@ func TestGetPullRequestInfoResponse(t *testing.T) {
 	assert.EqualValues(t, getPRInfoResponse.Number, target.Number)
 	assert.EqualValues(t, getPRInfoResponse.State, target.State)
 	assert.EqualValues(t, getPRInfoResponse.Title, target.Title)
+	assert.EqualValues(t, "Fixes the login page", target.Body)
+	assert.EqualValues(t, "bug", target.Labels[0].Name)
 	assert.EqualValues(t, getPRInfoResponse.CreatedAt, target.CreatedAt)
 	assert.EqualValues(t, getPRInfoResponse.UpdatedAt, target.UpdatedAt)
 	assert.EqualValues(t, getPRInfoResponse.ClosedAt, target.ClosedAt)
@@ -91,6 +99,10 @@ func TestGetPullRequestInfoResponse(t *testing.T) {
 	assert.EqualValues(t, getPRInfoResponse.User, target.User)
 	assert.EqualValues(t, getPRInfoResponse.Assignee, target.Assignee)
 	assert.EqualValues(t, getPRInfoResponse.Base, target.Base)
+	assert.EqualValues(t, getPRInfoResponse.Head, target.Head)
+	assert.EqualValues(t, 100, target.Additions)
+	assert.EqualValues(t, 3, target.Deletions)
+	assert.EqualValues(t, 5, target.ChangedFiles)
 
 }
 
@@ -108,3 +120,13 @@ func TestMultiplePullRequestResponse(t *testing.T) {
 	assert.EqualValues(t, "some URL", target[0].URL)
 
 }
+
+func TestPullRequestHasDetails(t *testing.T) {
+	var listed GetSinglePullRequestResponse
+	assert.Nil(t, json.Unmarshal([]byte(`{"number": 9, "state": "closed"}`), &listed))
+	assert.False(t, listed.HasDetails())
+
+	var single GetSinglePullRequestResponse
+	assert.Nil(t, json.Unmarshal([]byte(`{"number": 9, "state": "closed", "commits": 1, "changed_files": 2}`), &single))
+	assert.True(t, single.HasDetails())
+}
@@ -0,0 +1,30 @@
+package githubdomain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+//RepoContent stores a single file held in a repo
+type RepoContent struct {
+	Type     string `json:"type"`
+	Encoding string `json:"encoding"`
+	Size     int64  `json:"size"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+	SHA      string `json:"sha"`
+}
+
+//DecodedContent returns the contents of the file, Github base64 encodes them over several lines
+func (c *RepoContent) DecodedContent() (string, error) {
+	if c.Encoding != "base64" {
+		return "", fmt.Errorf("unsupported encoding %s", c.Encoding)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.NewReplacer("\n", "", "\r", "").Replace(c.Content))
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
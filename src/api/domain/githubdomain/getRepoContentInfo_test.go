@@ -0,0 +1,32 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoContentDecodedContent(t *testing.T) {
+	//Github splits the encoded content over lines of 60 characters
+	jsonAsString := `{"type":"file","encoding":"base64","size":38,"name":"CODEOWNERS","path":".github/CODEOWNERS","content":"KiBAbXl1c2VyCi9kb2NzLyBAbXlvcmcvd3JpdGVycwpzcmMvKi5nbyBhbm90\naGVy\n","sha":"3d21ec53"}`
+
+	var target RepoContent
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, ".github/CODEOWNERS", target.Path)
+
+	content, err := target.DecodedContent()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "* @myuser\n/docs/ @myorg/writers\nsrc/*.go another", content)
+}
+
+func TestRepoContentDecodedContentErrors(t *testing.T) {
+	content, err := (&RepoContent{Encoding: "none", Content: "text"}).DecodedContent()
+	assert.EqualValues(t, "", content)
+	assert.EqualValues(t, "unsupported encoding none", err.Error())
+
+	content, err = (&RepoContent{Encoding: "base64", Content: "not base64!"}).DecodedContent()
+	assert.EqualValues(t, "", content)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,41 @@
+package githubdomain
+
+import "time"
+
+//GetRepoInfo stores information about a single repository
+type GetRepoInfo struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Owner         GitUser   `json:"owner"`
+	Private       bool      `json:"private"`
+	HTMLURL       string    `json:"html_url"`
+	Description   string    `json:"description"`
+	Fork          bool      `json:"fork"`
+	URL           string    `json:"url"`
+	DefaultBranch string    `json:"default_branch"`
+	Archived      bool      `json:"archived"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	PushedAt      time.Time `json:"pushed_at"`
+}
+
+//the permission levels a collaborator can have on a repo, maintain and triage are reported as write and read
+const (
+	PermissionAdmin = "admin"
+	PermissionWrite = "write"
+	PermissionRead  = "read"
+	PermissionNone  = "none"
+)
+
+//CollaboratorPermission stores the permission level a user has on a repo
+type CollaboratorPermission struct {
+	Permission string  `json:"permission"`
+	RoleName   string  `json:"role_name"`
+	User       GitUser `json:"user"`
+}
+
+//IsAdmin returns true if the user can administer the repo, which lets them merge past unenforced branch protection
+func (p *CollaboratorPermission) IsAdmin() bool {
+	return p.Permission == PermissionAdmin
+}
@@ -0,0 +1,81 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRepoInfo(t *testing.T) {
+	repoInfo := GetRepoInfo{
+		ID:       1296269,
+		Name:     "myrepo",
+		FullName: "myowner/myrepo",
+		Owner: GitUser{
+			Login:     "myowner",
+			ID:        1,
+			Type:      "User",
+			SiteAdmin: false,
+		},
+		Private:       true,
+		HTMLURL:       "https://github.com/myowner/myrepo",
+		Description:   "some description",
+		Fork:          false,
+		URL:           "https://api.github.com/repos/myowner/myrepo",
+		DefaultBranch: "main",
+		Archived:      false,
+		CreatedAt:     time.Now().AddDate(-1, 0, 0).UTC(),
+		UpdatedAt:     time.Now().AddDate(0, -1, 0).UTC(),
+		PushedAt:      time.Now().AddDate(0, 0, -1).UTC(),
+	}
+
+	bytes, err := json.Marshal(repoInfo)
+	assert.Nil(t, err)
+	assert.NotNil(t, bytes)
+
+	var target GetRepoInfo
+
+	err = json.Unmarshal(bytes, &target)
+	assert.Nil(t, err)
+	assert.NotNil(t, &target)
+	assert.EqualValues(t, repoInfo.ID, target.ID)
+	assert.EqualValues(t, repoInfo.Name, target.Name)
+	assert.EqualValues(t, repoInfo.FullName, target.FullName)
+	assert.EqualValues(t, repoInfo.Owner, target.Owner)
+	assert.EqualValues(t, repoInfo.Private, target.Private)
+	assert.EqualValues(t, repoInfo.HTMLURL, target.HTMLURL)
+	assert.EqualValues(t, repoInfo.Description, target.Description)
+	assert.EqualValues(t, repoInfo.Fork, target.Fork)
+	assert.EqualValues(t, repoInfo.URL, target.URL)
+	assert.EqualValues(t, repoInfo.DefaultBranch, target.DefaultBranch)
+	assert.EqualValues(t, repoInfo.Archived, target.Archived)
+	assert.EqualValues(t, repoInfo.CreatedAt, target.CreatedAt)
+	assert.EqualValues(t, repoInfo.UpdatedAt, target.UpdatedAt)
+	assert.EqualValues(t, repoInfo.PushedAt, target.PushedAt)
+}
+
+func TestGetRepoInfoFromGithubJSON(t *testing.T) {
+	jsonAsString := `{"id":1296269,"name":"myrepo","full_name":"myowner/myrepo","owner":{"login":"myowner","id":1,"type":"Organization","site_admin":false},"private":false,"default_branch":"develop","archived":true}`
+
+	var target GetRepoInfo
+
+	err := json.Unmarshal([]byte(jsonAsString), &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "myrepo", target.Name)
+	assert.EqualValues(t, "myowner/myrepo", target.FullName)
+	assert.EqualValues(t, "myowner", target.Owner.Login)
+	assert.EqualValues(t, "develop", target.DefaultBranch)
+	assert.EqualValues(t, true, target.Archived)
+}
+
+func TestCollaboratorPermissionFromGithubJSON(t *testing.T) {
+	var target CollaboratorPermission
+	err := json.Unmarshal([]byte(`{"permission":"admin","role_name":"admin","user":{"login":"octocat","site_admin":false}}`), &target)
+	assert.Nil(t, err)
+	assert.True(t, target.IsAdmin())
+	assert.EqualValues(t, "octocat", target.User.Login)
+
+	assert.False(t, (&CollaboratorPermission{Permission: PermissionWrite, RoleName: "maintain"}).IsAdmin())
+}
@@ -1,5 +1,13 @@
 package githubdomain
 
+import (
+	"net/http"
+	"strings"
+)
+
+//MessageBranchNotProtected is the message Github returns when asked for the protection of a branch that isn't protected
+const MessageBranchNotProtected = "Branch not protected"
+
 //GithubErrorResponse holds details about the errors received back from Github
 type GithubErrorResponse struct {
 	StatusCode       int           `json:"status_code"`
@@ -12,6 +20,12 @@ func (r GithubErrorResponse) Error() string {
 	return r.Message
 }
 
+//IsBranchNotProtected returns true if Github couldn't find the protection of a branch because it isn't protected
+//Github also says not found when the token can't read the protection, which says nothing about the branch
+func (r GithubErrorResponse) IsBranchNotProtected() bool {
+	return r.StatusCode == http.StatusNotFound && strings.EqualFold(r.Message, MessageBranchNotProtected)
+}
+
 //GithubError holds details about a single error from Github
 type GithubError struct {
 	Resource string `json:"resource"`
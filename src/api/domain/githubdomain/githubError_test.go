@@ -2,6 +2,7 @@ package githubdomain
 
 import (
 	"encoding/json"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -70,3 +71,10 @@ func TestError(t *testing.T) {
 
 	assert.EqualValues(t, ghErrorResponse.Message, ghErrorResponse.Error())
 }
+
+func TestGithubErrorResponseIsBranchNotProtected(t *testing.T) {
+	assert.True(t, GithubErrorResponse{StatusCode: http.StatusNotFound, Message: "Branch not protected"}.IsBranchNotProtected())
+	//a token without admin access to the repo can't find the protection either
+	assert.False(t, GithubErrorResponse{StatusCode: http.StatusNotFound, Message: "Not Found"}.IsBranchNotProtected())
+	assert.False(t, GithubErrorResponse{StatusCode: http.StatusForbidden, Message: "Branch not protected"}.IsBranchNotProtected())
+}
@@ -0,0 +1,121 @@
+package githubdomain
+
+import (
+	"strings"
+	"time"
+)
+
+//the webhook events that are understood, passed by Github in the X-GitHub-Event header
+const (
+	WebhookEventPing              = "ping"
+	WebhookEventPush              = "push"
+	WebhookEventPullRequest       = "pull_request"
+	WebhookEventPullRequestReview = "pull_request_review"
+)
+
+//WebhookDelivery describes the outcome of receiving a single webhook delivery
+type WebhookDelivery struct {
+	ID     string `json:"id"`
+	Event  string `json:"event"`
+	Status string `json:"status"`
+}
+
+//WebhookRepository holds the repository details sent with every webhook event
+//push events send timestamps as numbers rather than strings so only the common fields are kept
+type WebhookRepository struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	FullName      string  `json:"full_name"`
+	Owner         GitUser `json:"owner"`
+	Private       bool    `json:"private"`
+	HTMLURL       string  `json:"html_url"`
+	DefaultBranch string  `json:"default_branch"`
+}
+
+//PushEventUser identifies the author, committer or pusher in a push event
+type PushEventUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+//PushEventCommit holds details about a single commit in a push event
+type PushEventCommit struct {
+	ID        string        `json:"id"`
+	TreeID    string        `json:"tree_id"`
+	Distinct  bool          `json:"distinct"`
+	Message   string        `json:"message"`
+	Timestamp time.Time     `json:"timestamp"`
+	URL       string        `json:"url"`
+	Author    PushEventUser `json:"author"`
+	Committer PushEventUser `json:"committer"`
+	Added     []string      `json:"added"`
+	Removed   []string      `json:"removed"`
+	Modified  []string      `json:"modified"`
+}
+
+//PushEvent is sent by Github when commits are pushed to a branch or tag
+type PushEvent struct {
+	Ref        string            `json:"ref"`
+	Before     string            `json:"before"`
+	After      string            `json:"after"`
+	Created    bool              `json:"created"`
+	Deleted    bool              `json:"deleted"`
+	Forced     bool              `json:"forced"`
+	Commits    []PushEventCommit `json:"commits"`
+	HeadCommit *PushEventCommit  `json:"head_commit"`
+	Repository WebhookRepository `json:"repository"`
+	Pusher     PushEventUser     `json:"pusher"`
+	Sender     GitUser           `json:"sender"`
+}
+
+//PullRequestEvent is sent by Github when a PR is opened, edited, closed etc.
+type PullRequestEvent struct {
+	Action      string                       `json:"action"`
+	Number      int64                        `json:"number"`
+	PullRequest GetSinglePullRequestResponse `json:"pull_request"`
+	Repository  WebhookRepository            `json:"repository"`
+	Sender      GitUser                      `json:"sender"`
+}
+
+//PullRequestReviewEvent is sent by Github when a review of a PR is submitted, edited or dismissed
+type PullRequestReviewEvent struct {
+	Action      string                       `json:"action"`
+	Review      PullRequestReview            `json:"review"`
+	PullRequest GetSinglePullRequestResponse `json:"pull_request"`
+	Repository  WebhookRepository            `json:"repository"`
+	Sender      GitUser                      `json:"sender"`
+}
+
+//Branch returns the name of the branch pushed to, or an empty string if a tag was pushed
+func (e *PushEvent) Branch() string {
+	if !strings.HasPrefix(e.Ref, "refs/heads/") {
+		return ""
+	}
+	return strings.TrimPrefix(e.Ref, "refs/heads/")
+}
+
+//ToCommitInfo converts the pushed commit into the same shape as a commit returned by the commits API
+//the parents aren't sent in a push event so these are left empty
+func (c *PushEventCommit) ToCommitInfo() GetCommitInfo {
+	return GetCommitInfo{
+		URL: c.URL,
+		SHA: c.ID,
+		Commit: DetailedCommitInfo{
+			URL: c.URL,
+			Author: CommitUser{
+				Name:  c.Author.Name,
+				Email: c.Author.Email,
+				Date:  c.Timestamp,
+			},
+			Committer: CommitUser{
+				Name:  c.Committer.Name,
+				Email: c.Committer.Email,
+				Date:  c.Timestamp,
+			},
+			Message: c.Message,
+		},
+		Author:    GitUser{Login: c.Author.Username},
+		Committer: GitUser{Login: c.Committer.Username},
+	}
+}
@@ -0,0 +1,116 @@
+package githubdomain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookConstants(t *testing.T) {
+	assert.EqualValues(t, "ping", WebhookEventPing)
+	assert.EqualValues(t, "push", WebhookEventPush)
+	assert.EqualValues(t, "pull_request", WebhookEventPullRequest)
+	assert.EqualValues(t, "pull_request_review", WebhookEventPullRequestReview)
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	delivery := WebhookDelivery{
+		ID:     "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+		Event:  "push",
+		Status: "processed",
+	}
+
+	bytes, err := json.Marshal(delivery)
+	assert.Nil(t, err)
+
+	var target WebhookDelivery
+
+	err = json.Unmarshal(bytes, &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, delivery, target)
+}
+
+func TestPushEvent(t *testing.T) {
+	var event PushEvent
+
+	err := json.Unmarshal(testutils.GetMockDataPushEventPayload(), &event)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "refs/heads/main", event.Ref)
+	assert.EqualValues(t, "main", event.Branch())
+	assert.EqualValues(t, "ABCDEF123456768", event.Before)
+	assert.EqualValues(t, "AABCDEF123456", event.After)
+	assert.EqualValues(t, 1, len(event.Commits))
+	assert.EqualValues(t, "AABCDEF123456", event.Commits[0].ID)
+	assert.EqualValues(t, "someuser", event.Commits[0].Author.Username)
+	assert.EqualValues(t, []string{"README.md"}, event.Commits[0].Modified)
+	assert.NotNil(t, event.HeadCommit)
+	assert.EqualValues(t, "myuser/myrepo", event.Repository.FullName)
+	assert.EqualValues(t, "myuser", event.Repository.Owner.Login)
+	assert.EqualValues(t, "main", event.Repository.DefaultBranch)
+	assert.EqualValues(t, "someuser", event.Pusher.Name)
+	assert.EqualValues(t, "someuser", event.Sender.Login)
+}
+
+func TestPushEventBranchForTag(t *testing.T) {
+	event := PushEvent{Ref: "refs/tags/v1.0.0"}
+	assert.EqualValues(t, "", event.Branch())
+}
+
+func TestPushEventCommitToCommitInfo(t *testing.T) {
+	timestamp := time.Now().UTC()
+	pushCommit := PushEventCommit{
+		ID:        "AABCDEF123456",
+		Message:   "some commit message",
+		Timestamp: timestamp,
+		URL:       "https://github.com/myuser/myrepo/commit/AABCDEF123456",
+		Author:    PushEventUser{Name: "some name", Email: "email@email.com", Username: "someuser"},
+		Committer: PushEventUser{Name: "GitHub", Email: "noreply@github.com", Username: "web-flow"},
+	}
+
+	commitInfo := pushCommit.ToCommitInfo()
+	assert.EqualValues(t, "AABCDEF123456", commitInfo.SHA)
+	assert.EqualValues(t, pushCommit.URL, commitInfo.URL)
+	assert.EqualValues(t, "some commit message", commitInfo.Commit.Message)
+	assert.EqualValues(t, "some name", commitInfo.Commit.Author.Name)
+	assert.EqualValues(t, "email@email.com", commitInfo.Commit.Author.Email)
+	assert.EqualValues(t, timestamp, commitInfo.Commit.Author.Date)
+	assert.EqualValues(t, "GitHub", commitInfo.Commit.Committer.Name)
+	assert.EqualValues(t, "someuser", commitInfo.Author.Login)
+	assert.EqualValues(t, "web-flow", commitInfo.Committer.Login)
+	assert.EqualValues(t, 0, len(commitInfo.Parents))
+}
+
+func TestPullRequestEvent(t *testing.T) {
+	var event PullRequestEvent
+
+	err := json.Unmarshal(testutils.GetMockDataPullRequestMergedEventPayload(), &event)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "closed", event.Action)
+	assert.EqualValues(t, 9, event.Number)
+	assert.EqualValues(t, 9, event.PullRequest.Number)
+	assert.EqualValues(t, true, event.PullRequest.Merged)
+	assert.EqualValues(t, "AABCDEF123456", event.PullRequest.MergeCommitSHA)
+	assert.EqualValues(t, "main", event.PullRequest.Base.Ref)
+	assert.EqualValues(t, "FEDCBA654321", event.PullRequest.Head.SHA)
+	assert.EqualValues(t, "A Second Login ID", event.PullRequest.MergedBy.Login)
+	assert.EqualValues(t, "myuser/myrepo", event.Repository.FullName)
+	assert.EqualValues(t, "A Second Login ID", event.Sender.Login)
+}
+
+func TestPullRequestReviewEvent(t *testing.T) {
+	var event PullRequestReviewEvent
+
+	err := json.Unmarshal(testutils.GetMockDataPullRequestReviewEventPayload(), &event)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "submitted", event.Action)
+	assert.EqualValues(t, 80, event.Review.ID)
+	assert.EqualValues(t, "approved", event.Review.State)
+	assert.EqualValues(t, "FEDCBA654321", event.Review.CommitID)
+	assert.EqualValues(t, "A Second Login ID", event.Review.User.Login)
+	assert.EqualValues(t, 9, event.PullRequest.Number)
+	assert.EqualValues(t, true, event.PullRequest.ClosedAt.IsZero())
+	assert.EqualValues(t, "myuser/myrepo", event.Repository.FullName)
+}
@@ -0,0 +1,95 @@
+//Package identitydomain works out which person is behind a commit or PR, whatever login, name or email they used
+package identitydomain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//mailmapLine matches a line of a mailmap file, a proper name and or email followed optionally by the name and email used in commits
+var mailmapLine = regexp.MustCompile(`^([^<]*)<([^>]*)>\s*(?:([^<]*)<([^>]*)>)?\s*$`)
+
+//mailmapEntry replaces the name and email used in commits with the proper ones, an empty proper value leaves it as it is
+//an entry without a commit name applies to every commit using the email
+type mailmapEntry struct {
+	properName  string
+	properEmail string
+	commitName  string
+	commitEmail string
+}
+
+//Mailmap holds the entries of a file in the format git uses for .mailmap
+type Mailmap struct {
+	entries []mailmapEntry
+}
+
+//NewMailmap creates a mailmap without any entries
+func NewMailmap() *Mailmap {
+	return &Mailmap{}
+}
+
+//ParseMailmap reads the entries of a mailmap file, blank lines and comments starting with # are ignored
+//each line takes one of the forms git supports:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func ParseMailmap(content string) (*Mailmap, error) {
+	mailmap := NewMailmap()
+	for counter, line := range strings.Split(content, "\n") {
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		matches := mailmapLine.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid mailmap entry on line %d: %s", counter+1, line)
+		}
+		entry := mailmapEntry{properName: strings.TrimSpace(matches[1]), commitEmail: strings.ToLower(strings.TrimSpace(matches[2]))}
+		if matches[4] != "" {
+			entry.properEmail = strings.TrimSpace(matches[2])
+			entry.commitName = strings.TrimSpace(matches[3])
+			entry.commitEmail = strings.ToLower(strings.TrimSpace(matches[4]))
+		}
+		if entry.commitEmail == "" {
+			return nil, fmt.Errorf("invalid mailmap entry on line %d: an email is required", counter+1)
+		}
+		mailmap.entries = append(mailmap.entries, entry)
+	}
+	return mailmap, nil
+}
+
+//Lookup returns the proper name and email for those used in a commit, as in git an entry matching both
+//the name and email is used before one matching only the email, and the last matching entry wins
+func (m *Mailmap) Lookup(name string, email string) (string, string) {
+	var match *mailmapEntry
+	for counter := range m.entries {
+		entry := &m.entries[counter]
+		if entry.commitEmail != strings.ToLower(email) {
+			continue
+		}
+		if entry.commitName != "" && !strings.EqualFold(entry.commitName, name) {
+			continue
+		}
+		if match == nil || entry.commitName != "" || match.commitName == "" {
+			match = entry
+		}
+	}
+
+	if match == nil {
+		return name, email
+	}
+	if match.properName != "" {
+		name = match.properName
+	}
+	if match.properEmail != "" {
+		email = match.properEmail
+	}
+	return name, email
+}
@@ -0,0 +1,66 @@
+package identitydomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMailmap = `# people who have changed their email
+Jane Doe <jane@example.com>
+<jane@example.com> <jane@old.example.com>
+Joe Bloggs <joe@example.com> <joe@laptop.local>   # a comment after the entry
+Joe Bloggs <joe@example.com> Build Server <ci@example.com>
+Octocat <583231+octocat@users.noreply.github.com> <OCTO@example.com>
+`
+
+func TestParseMailmap(t *testing.T) {
+	mailmap, err := ParseMailmap(testMailmap)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, len(mailmap.entries))
+	assert.EqualValues(t, mailmapEntry{properName: "Jane Doe", commitEmail: "jane@example.com"}, mailmap.entries[0])
+	assert.EqualValues(t, mailmapEntry{properEmail: "jane@example.com", commitEmail: "jane@old.example.com"}, mailmap.entries[1])
+	assert.EqualValues(t, mailmapEntry{properName: "Joe Bloggs", properEmail: "joe@example.com", commitName: "Build Server", commitEmail: "ci@example.com"}, mailmap.entries[3])
+	assert.EqualValues(t, "octo@example.com", mailmap.entries[4].commitEmail)
+}
+
+func TestParseMailmapInvalid(t *testing.T) {
+	mailmap, err := ParseMailmap("Jane Doe jane@example.com")
+	assert.Nil(t, mailmap)
+	assert.EqualValues(t, "invalid mailmap entry on line 1: Jane Doe jane@example.com", err.Error())
+
+	_, err = ParseMailmap("\nJane Doe <>")
+	assert.EqualValues(t, "invalid mailmap entry on line 2: an email is required", err.Error())
+}
+
+func TestMailmapLookup(t *testing.T) {
+	mailmap, _ := ParseMailmap(testMailmap)
+
+	name, email := mailmap.Lookup("jane", "Jane@Example.com")
+	assert.EqualValues(t, "Jane Doe", name)
+	assert.EqualValues(t, "Jane@Example.com", email)
+
+	name, email = mailmap.Lookup("Jane", "jane@old.example.com")
+	assert.EqualValues(t, "Jane", name)
+	assert.EqualValues(t, "jane@example.com", email)
+
+	name, email = mailmap.Lookup("build server", "ci@example.com")
+	assert.EqualValues(t, "Joe Bloggs", name)
+	assert.EqualValues(t, "joe@example.com", email)
+
+	name, email = mailmap.Lookup("Release Bot", "ci@example.com")
+	assert.EqualValues(t, "Release Bot", name)
+	assert.EqualValues(t, "ci@example.com", email)
+
+	name, email = NewMailmap().Lookup("Someone", "someone@example.com")
+	assert.EqualValues(t, "Someone", name)
+	assert.EqualValues(t, "someone@example.com", email)
+}
+
+func TestMailmapLookupPrefersNameMatch(t *testing.T) {
+	mailmap, _ := ParseMailmap("Shared <team@example.com> Alice <ci@example.com>\nEveryone <ci@example.com>")
+	name, _ := mailmap.Lookup("Alice", "ci@example.com")
+	assert.EqualValues(t, "Shared", name)
+	name, _ = mailmap.Lookup("Bob", "ci@example.com")
+	assert.EqualValues(t, "Everyone", name)
+}
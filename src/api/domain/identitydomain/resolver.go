@@ -0,0 +1,91 @@
+package identitydomain
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//noreplyEmail matches the private emails Github gives its users, such as 583231+octocat@users.noreply.github.com
+//older accounts use the form without the ID, octocat@users.noreply.github.com
+var noreplyEmail = regexp.MustCompile(`(?i)^(?:\d+\+)?([a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\[bot\])?)@users\.noreply\.github\.com$`)
+
+//NoreplyLogin returns the login within a Github noreply email, empty if it isn't one
+func NoreplyLogin(email string) string {
+	matches := noreplyEmail.FindStringSubmatch(strings.TrimSpace(email))
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+//Identity is the person behind a commit or PR, the ID is their Github login if it is known and their email otherwise
+type Identity struct {
+	ID    string `json:"id"`
+	Login string `json:"login,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+//Resolver works out the identity behind the login, name and email of a commit or PR
+type Resolver struct {
+	mutex        sync.Mutex
+	mailmap      *Mailmap
+	loginByEmail map[string]string
+}
+
+//NewResolver creates a resolver that uses the mailmap before anything else
+func NewResolver(mailmap *Mailmap) *Resolver {
+	if mailmap == nil {
+		mailmap = NewMailmap()
+	}
+	return &Resolver{mailmap: mailmap, loginByEmail: make(map[string]string)}
+}
+
+//Observe records that the email was used with the login, such as by a commit Github linked to an account
+//the first login seen for an email is kept
+func (r *Resolver) Observe(login string, email string) {
+	if login == "" || email == "" {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	email = strings.ToLower(email)
+	if _, ok := r.loginByEmail[email]; !ok {
+		r.loginByEmail[email] = login
+	}
+}
+
+//Resolve returns the identity behind the login, name and email, any of which can be empty
+//the mailmap gives the proper name and email, then a missing login is found from a Github noreply email
+//or an observed use of the email with a login
+func (r *Resolver) Resolve(login string, name string, email string) Identity {
+	properName, properEmail := r.mailmap.Lookup(name, email)
+
+	if login == "" {
+		login = r.findLogin(properEmail)
+	}
+	if login == "" {
+		login = r.findLogin(email)
+	}
+
+	ID := strings.ToLower(login)
+	if ID == "" {
+		ID = strings.ToLower(properEmail)
+	}
+	if ID == "" {
+		ID = strings.ToLower(properName)
+	}
+	return Identity{ID: ID, Login: login, Name: properName, Email: properEmail}
+}
+
+//findLogin returns the login known for the email, empty if there isn't one
+func (r *Resolver) findLogin(email string) string {
+	if login := NoreplyLogin(email); login != "" {
+		return login
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.loginByEmail[strings.ToLower(email)]
+}
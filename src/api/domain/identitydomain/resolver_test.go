@@ -0,0 +1,41 @@
+package identitydomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoreplyLogin(t *testing.T) {
+	assert.EqualValues(t, "octocat", NoreplyLogin("583231+octocat@users.noreply.github.com"))
+	assert.EqualValues(t, "Octo-Cat", NoreplyLogin("Octo-Cat@Users.NoReply.GitHub.com"))
+	assert.EqualValues(t, "dependabot[bot]", NoreplyLogin("49699333+dependabot[bot]@users.noreply.github.com"))
+	assert.EqualValues(t, "", NoreplyLogin("octocat@github.com"))
+	assert.EqualValues(t, "", NoreplyLogin(""))
+}
+
+func TestResolve(t *testing.T) {
+	mailmap, err := ParseMailmap(testMailmap)
+	assert.Nil(t, err)
+	resolver := NewResolver(mailmap)
+	resolver.Observe("JaneD", "JANE@example.com")
+	resolver.Observe("someoneelse", "jane@example.com")
+
+	//a login given by Github is always used
+	assert.EqualValues(t, Identity{ID: "octocat", Login: "Octocat", Name: "The Octocat", Email: "x@example.com"}, resolver.Resolve("Octocat", "The Octocat", "x@example.com"))
+	//the mailmap gives the proper email, which has been seen with a login
+	assert.EqualValues(t, Identity{ID: "janed", Login: "JaneD", Name: "Jane", Email: "jane@example.com"}, resolver.Resolve("", "Jane", "jane@old.example.com"))
+	//the mailmap gives a noreply email
+	assert.EqualValues(t, "octocat", resolver.Resolve("", "octo", "octo@example.com").ID)
+	//the commit uses a noreply email
+	assert.EqualValues(t, "hubot", resolver.Resolve("", "Hubot", "hubot@users.noreply.github.com").ID)
+	//nothing links the email to a login
+	assert.EqualValues(t, Identity{ID: "joe@example.com", Name: "Joe Bloggs", Email: "joe@example.com"}, resolver.Resolve("", "Joe", "joe@laptop.local"))
+	//only a name is known
+	assert.EqualValues(t, "mystery", resolver.Resolve("", "Mystery", "").ID)
+}
+
+func TestResolveWithoutMailmap(t *testing.T) {
+	resolver := NewResolver(nil)
+	assert.EqualValues(t, "jane@example.com", resolver.Resolve("", "Jane", "Jane@Example.com").ID)
+}
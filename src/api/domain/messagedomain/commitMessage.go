@@ -0,0 +1,172 @@
+//Package messagedomain interprets commit messages written in the Conventional Commits format along with their git trailers
+package messagedomain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+)
+
+//the trailers git and Github add to a commit message
+const (
+	TrailerCoAuthoredBy = "Co-authored-by"
+	TrailerSignedOffBy  = "Signed-off-by"
+	TrailerReviewedBy   = "Reviewed-by"
+)
+
+var (
+	//conventionalHeader matches a header such as feat(api)!: add an endpoint
+	conventionalHeader = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+	//trailerLine matches a trailer such as Signed-off-by: Some Name <some@email.com>
+	trailerLine = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|BREAKING CHANGE): (.*)$`)
+	//revertLine matches the line git adds to a revert, such as This reverts commit 1a2b3c4d
+	revertLine = regexp.MustCompile(`(?i)\bThis reverts commit ([0-9a-f]{7,40})\b`)
+	//cherryPickLine matches the line git cherry-pick -x adds, such as (cherry picked from commit 1a2b3c4d)
+	cherryPickLine = regexp.MustCompile(`(?i)\(cherry picked from commit ([0-9a-f]{7,40})\)`)
+)
+
+//Trailer is a key and value at the end of a commit message
+type Trailer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+//CommitMessage is a commit message split into its parts
+//the type and scope are only set when the header follows Conventional Commits, otherwise the subject is the whole header
+//the SHAs of the commits it reverts or was cherry picked from are taken from the lines git adds to the message
+type CommitMessage struct {
+	Header           string    `json:"header"`
+	Conventional     bool      `json:"conventional"`
+	Type             string    `json:"type,omitempty"`
+	Scope            string    `json:"scope,omitempty"`
+	Breaking         bool      `json:"breaking"`
+	Subject          string    `json:"subject"`
+	Body             string    `json:"body,omitempty"`
+	Trailers         []Trailer `json:"trailers"`
+	Reverts          []string  `json:"reverts,omitempty"`
+	CherryPickedFrom []string  `json:"cherry_picked_from,omitempty"`
+}
+
+//ParseCommitMessage splits the message into its header, body and trailers
+//the trailers are the last paragraph of the message when every line of it is a trailer
+//a change is breaking if the header has a ! after the type or there is a BREAKING CHANGE trailer
+func ParseCommitMessage(message string) *CommitMessage {
+	lines := strings.Split(strings.TrimSpace(strings.Replace(message, "\r\n", "\n", -1)), "\n")
+	result := &CommitMessage{Header: strings.TrimSpace(lines[0]), Trailers: make([]Trailer, 0)}
+	result.Subject = result.Header
+
+	if match := conventionalHeader.FindStringSubmatch(result.Header); match != nil {
+		result.Conventional = true
+		result.Type = strings.ToLower(match[1])
+		result.Scope = strings.TrimSpace(match[2])
+		result.Breaking = match[3] == "!"
+		result.Subject = strings.TrimSpace(match[4])
+	}
+
+	body := lines[1:]
+	start := len(body)
+	for start > 0 && strings.TrimSpace(body[start-1]) != "" {
+		start--
+	}
+	//the trailers have to be separated from the header by a blank line
+	if start > 0 && start < len(body) {
+		trailers := make([]Trailer, 0)
+		for _, line := range body[start:] {
+			match := trailerLine.FindStringSubmatch(strings.TrimSpace(line))
+			if match == nil {
+				trailers = nil
+				break
+			}
+			trailers = append(trailers, Trailer{Key: match[1], Value: strings.TrimSpace(match[2])})
+		}
+		if trailers != nil {
+			result.Trailers = trailers
+			body = body[:start]
+		}
+	}
+	result.Body = strings.TrimSpace(strings.Join(body, "\n"))
+
+	if len(result.TrailerValues("BREAKING CHANGE")) > 0 || len(result.TrailerValues("BREAKING-CHANGE")) > 0 {
+		result.Breaking = true
+	}
+	result.Reverts = findSHAs(revertLine, message)
+	result.CherryPickedFrom = findSHAs(cherryPickLine, message)
+	return result
+}
+
+//IsRevert returns true if the message says which commit it reverts
+func (m *CommitMessage) IsRevert() bool {
+	return len(m.Reverts) > 0
+}
+
+//IsCherryPick returns true if the message says which commit it was cherry picked from
+func (m *CommitMessage) IsCherryPick() bool {
+	return len(m.CherryPickedFrom) > 0
+}
+
+//findSHAs returns the lower cased SHAs matched by the pattern in the order they are first found, each SHA is only returned once
+func findSHAs(pattern *regexp.Regexp, message string) []string {
+	var result []string
+	for _, match := range pattern.FindAllStringSubmatch(message, -1) {
+		SHA := strings.ToLower(match[1])
+		if !containsFold(result, SHA) {
+			result = append(result, SHA)
+		}
+	}
+	return result
+}
+
+//TrailerValues returns the values of the trailers with the key, the key isn't case sensitive
+func (m *CommitMessage) TrailerValues(key string) []string {
+	var result []string
+	for _, trailer := range m.Trailers {
+		if strings.EqualFold(trailer.Key, key) {
+			result = append(result, trailer.Value)
+		}
+	}
+	return result
+}
+
+//IsSignedOffBy returns true if the message has a Signed-off-by trailer for the email, as the DCO requires
+func (m *CommitMessage) IsSignedOffBy(email string) bool {
+	if email == "" {
+		return false
+	}
+	for _, value := range m.TrailerValues(TrailerSignedOffBy) {
+		if strings.Contains(strings.ToLower(value), "<"+strings.ToLower(email)+">") {
+			return true
+		}
+	}
+	return false
+}
+
+//Check returns how the message breaks the rules, a message keeping to the rules returns nothing
+//the email of the author of the commit is needed to check the sign-off
+func (m *CommitMessage) Check(rules *policydomain.CommitMessageRules, authorEmail string) []string {
+	var result []string
+	if rules.Format == policydomain.MessageFormatConventional {
+		if !m.Conventional {
+			result = append(result, "the message doesn't follow conventional commits, such as feat(api): add an endpoint")
+		} else if len(rules.Types) > 0 && !containsFold(rules.Types, m.Type) {
+			result = append(result, fmt.Sprintf("the type %s isn't one of %s", m.Type, strings.Join(rules.Types, ", ")))
+		}
+	}
+	if length := len([]rune(m.Header)); rules.MaxSubjectLength > 0 && length > rules.MaxSubjectLength {
+		result = append(result, fmt.Sprintf("the subject is %d characters, more than the limit of %d", length, rules.MaxSubjectLength))
+	}
+	if rules.RequireSignOff && !m.IsSignedOffBy(authorEmail) {
+		result = append(result, fmt.Sprintf("the message isn't signed off by the author %s", authorEmail))
+	}
+	return result
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package messagedomain
+
+import (
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitMessageConventional(t *testing.T) {
+	message := ParseCommitMessage("feat(api)!: remove the v1 endpoints\r\n\r\nThe v1 endpoints were deprecated last year.\r\nUse v2 instead.\r\n\r\nReviewed-by: Alice <alice@example.com>\r\nSigned-off-by: Bob <bob@example.com>\r\n")
+	assert.True(t, message.Conventional)
+	assert.EqualValues(t, "feat(api)!: remove the v1 endpoints", message.Header)
+	assert.EqualValues(t, "feat", message.Type)
+	assert.EqualValues(t, "api", message.Scope)
+	assert.True(t, message.Breaking)
+	assert.EqualValues(t, "remove the v1 endpoints", message.Subject)
+	assert.EqualValues(t, "The v1 endpoints were deprecated last year.\nUse v2 instead.", message.Body)
+	assert.EqualValues(t, []Trailer{{Key: "Reviewed-by", Value: "Alice <alice@example.com>"}, {Key: "Signed-off-by", Value: "Bob <bob@example.com>"}}, message.Trailers)
+	assert.EqualValues(t, []string{"Alice <alice@example.com>"}, message.TrailerValues("reviewed-by"))
+}
+
+func TestParseCommitMessageNotConventional(t *testing.T) {
+	message := ParseCommitMessage("Merge pull request #9 from myuser/feature")
+	assert.False(t, message.Conventional)
+	assert.EqualValues(t, "", message.Type)
+	assert.EqualValues(t, "Merge pull request #9 from myuser/feature", message.Subject)
+	assert.EqualValues(t, "", message.Body)
+	assert.EqualValues(t, 0, len(message.Trailers))
+
+	//a colon without a space after it isn't conventional
+	message = ParseCommitMessage("fix:no space")
+	assert.False(t, message.Conventional)
+
+	message = ParseCommitMessage("")
+	assert.EqualValues(t, "", message.Header)
+}
+
+func TestParseCommitMessageTrailers(t *testing.T) {
+	//only trailers
+	message := ParseCommitMessage("Fix: handle empty repos\n\nCo-authored-by: Carol <carol@example.com>")
+	assert.EqualValues(t, "fix", message.Type)
+	assert.EqualValues(t, "", message.Body)
+	assert.EqualValues(t, []string{"Carol <carol@example.com>"}, message.TrailerValues(TrailerCoAuthoredBy))
+
+	//a last paragraph that isn't all trailers is part of the body
+	message = ParseCommitMessage("fix: handle empty repos\n\nSee: the issue\nfor more detail")
+	assert.EqualValues(t, "See: the issue\nfor more detail", message.Body)
+	assert.EqualValues(t, 0, len(message.Trailers))
+
+	//trailers have to be separated from the header
+	message = ParseCommitMessage("fix: handle empty repos\nSigned-off-by: Bob <bob@example.com>")
+	assert.EqualValues(t, "Signed-off-by: Bob <bob@example.com>", message.Body)
+	assert.EqualValues(t, 0, len(message.Trailers))
+
+	message = ParseCommitMessage("fix: tighten validation\n\nBREAKING CHANGE: empty owners are rejected")
+	assert.True(t, message.Breaking)
+	assert.EqualValues(t, []string{"empty owners are rejected"}, message.TrailerValues("BREAKING CHANGE"))
+}
+
+func TestParseCommitMessageRevert(t *testing.T) {
+	message := ParseCommitMessage("Revert \"feat: add an endpoint\"\n\nThis reverts commit 1A2B3C4D5E6F7A8B9C0D1E2F3A4B5C6D7E8F9A0B.")
+	assert.True(t, message.IsRevert())
+	assert.False(t, message.IsCherryPick())
+	assert.EqualValues(t, []string{"1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b"}, message.Reverts)
+
+	//reverting a revert and a squashed message listing the same revert twice
+	message = ParseCommitMessage("Reapply the endpoint\n\nThis reverts commit abc1234.\n\n* This reverts commit def5678.\n* This reverts commit abc1234.")
+	assert.EqualValues(t, []string{"abc1234", "def5678"}, message.Reverts)
+
+	//too short to be a SHA
+	assert.False(t, ParseCommitMessage("This reverts commit abc").IsRevert())
+}
+
+func TestParseCommitMessageCherryPick(t *testing.T) {
+	message := ParseCommitMessage("fix: handle empty repos\n\n(cherry picked from commit 0123456789abcdef0123456789abcdef01234567)\n\nSigned-off-by: Bob <bob@example.com>")
+	assert.True(t, message.IsCherryPick())
+	assert.False(t, message.IsRevert())
+	assert.EqualValues(t, []string{"0123456789abcdef0123456789abcdef01234567"}, message.CherryPickedFrom)
+	assert.EqualValues(t, 1, len(message.Trailers))
+
+	assert.False(t, ParseCommitMessage("fix: handle empty repos").IsCherryPick())
+}
+
+func TestIsSignedOffBy(t *testing.T) {
+	message := ParseCommitMessage("fix: something\n\nSigned-off-by: Bob <Bob@Example.com>")
+	assert.True(t, message.IsSignedOffBy("bob@example.com"))
+	assert.False(t, message.IsSignedOffBy("alice@example.com"))
+	assert.False(t, message.IsSignedOffBy(""))
+}
+
+func TestCheck(t *testing.T) {
+	rules := &policydomain.CommitMessageRules{Format: policydomain.MessageFormatConventional, Types: []string{"feat", "fix"}, MaxSubjectLength: 30, RequireSignOff: true}
+
+	message := ParseCommitMessage("fix: something\n\nSigned-off-by: Bob <bob@example.com>")
+	assert.EqualValues(t, 0, len(message.Check(rules, "bob@example.com")))
+
+	message = ParseCommitMessage("chore: tidy up the code in the handlers")
+	assert.EqualValues(t, []string{
+		"the type chore isn't one of feat, fix",
+		"the subject is 39 characters, more than the limit of 30",
+		"the message isn't signed off by the author bob@example.com",
+	}, message.Check(rules, "bob@example.com"))
+
+	message = ParseCommitMessage("Tidy up")
+	assert.EqualValues(t, []string{"the message doesn't follow conventional commits, such as feat(api): add an endpoint"}, message.Check(&policydomain.CommitMessageRules{Format: policydomain.MessageFormatConventional}, ""))
+	assert.EqualValues(t, 0, len(message.Check(&policydomain.CommitMessageRules{Format: policydomain.MessageFormatAny}, "")))
+}
@@ -0,0 +1,103 @@
+package metricsdomain
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/identitydomain"
+)
+
+//githubBotType is the type Github gives to the accounts of apps such as dependabot
+const githubBotType = "Bot"
+
+//IsBot determines if the account belongs to an app, Github gives these the Bot type and a login ending in [bot]
+//commits made by an app without a linked account are recognised by their email, such as 1+app[bot]@users.noreply.github.com
+func IsBot(login string, userType string, email string) bool {
+	return userType == githubBotType || strings.HasSuffix(strings.ToLower(login), "[bot]") || strings.Contains(strings.ToLower(email), "[bot]@")
+}
+
+//ContributorActivity counts what a single person did in a repo over a period
+//the ID is that of their identity, their Github login or their email address if it isn't linked to an account
+type ContributorActivity struct {
+	ID                 string   `json:"id"`
+	Login              string   `json:"login,omitempty"`
+	Names              []string `json:"names"`
+	Emails             []string `json:"emails"`
+	Commits            int      `json:"commits"`
+	PullRequestsOpened int      `json:"pull_requests_opened"`
+	PullRequestsMerged int      `json:"pull_requests_merged"`
+	ReviewsGiven       int      `json:"reviews_given"`
+	ApprovalsGiven     int      `json:"approvals_given"`
+}
+
+//total returns the count of everything the person did, used to rank the contributors
+func (a *ContributorActivity) total() int {
+	return a.Commits + a.PullRequestsOpened + a.PullRequestsMerged + a.ReviewsGiven
+}
+
+//addIdentity records a name or email the person was seen using, each is only recorded once
+func addIdentity(identities []string, identity string) []string {
+	if identity == "" {
+		return identities
+	}
+	for _, existing := range identities {
+		if strings.EqualFold(existing, identity) {
+			return identities
+		}
+	}
+	return append(identities, identity)
+}
+
+//Contributors gathers the activity of each person, keyed on the ID of their identity
+type Contributors struct {
+	activity map[string]*ContributorActivity
+}
+
+//NewContributors creates an empty set of contributors
+func NewContributors() *Contributors {
+	return &Contributors{activity: make(map[string]*ContributorActivity)}
+}
+
+//Get returns the activity of the person, recording the name and email they used
+func (c *Contributors) Get(identity identitydomain.Identity) *ContributorActivity {
+	activity, ok := c.activity[identity.ID]
+	if !ok {
+		activity = &ContributorActivity{ID: identity.ID, Names: make([]string, 0), Emails: make([]string, 0)}
+		c.activity[identity.ID] = activity
+	}
+	if activity.Login == "" {
+		activity.Login = identity.Login
+	}
+	activity.Names = addIdentity(activity.Names, identity.Name)
+	activity.Emails = addIdentity(activity.Emails, strings.ToLower(identity.Email))
+	return activity
+}
+
+//List returns the activity of each person, the most active first
+func (c *Contributors) List() []ContributorActivity {
+	result := make([]ContributorActivity, 0, len(c.activity))
+	for _, activity := range c.activity {
+		result = append(result, *activity)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].total() != result[j].total() {
+			return result[i].total() > result[j].total()
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+//ContributorActivityReport holds who wrote and who reviewed the code of a repo over a period
+//commits are those on the branch, PRs are counted when opened and when merged and reviews when submitted
+type ContributorActivityReport struct {
+	Owner        string                `json:"owner"`
+	Repo         string                `json:"repo"`
+	Branch       string                `json:"branch"`
+	FromDate     time.Time             `json:"from_date"`
+	ToDate       time.Time             `json:"to_date"`
+	DataAsOf     time.Time             `json:"data_as_of"`
+	IncludeBots  bool                  `json:"include_bots"`
+	Contributors []ContributorActivity `json:"contributors"`
+}
@@ -0,0 +1,49 @@
+package metricsdomain
+
+import (
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/identitydomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBot(t *testing.T) {
+	assert.True(t, IsBot("dependabot[bot]", "Bot", ""))
+	assert.True(t, IsBot("renovate[bot]", "User", ""))
+	assert.True(t, IsBot("", "", "49699333+dependabot[bot]@users.noreply.github.com"))
+	assert.False(t, IsBot("octocat", "User", "octocat@github.com"))
+}
+
+func TestContributorsMergesIdentities(t *testing.T) {
+	contributors := NewContributors()
+
+	contributors.Get(identitydomain.Identity{ID: "octocat", Name: "The Octocat", Email: "Octo@example.com"}).Commits++
+	contributors.Get(identitydomain.Identity{ID: "octocat", Login: "Octocat", Name: "Octo Cat", Email: "octo@example.com"}).Commits++
+	contributors.Get(identitydomain.Identity{ID: "octocat", Login: "Octocat"}).ReviewsGiven++
+	contributors.Get(identitydomain.Identity{ID: "jane@example.com", Name: "Jane", Email: "jane@example.com"}).Commits++
+	contributors.Get(identitydomain.Identity{ID: "jane@example.com", Name: "Jane Doe", Email: "JANE@example.com"}).PullRequestsOpened++
+
+	list := contributors.List()
+	assert.EqualValues(t, 2, len(list))
+	assert.EqualValues(t, "octocat", list[0].ID)
+	assert.EqualValues(t, "Octocat", list[0].Login)
+	assert.EqualValues(t, 2, list[0].Commits)
+	assert.EqualValues(t, 1, list[0].ReviewsGiven)
+	assert.EqualValues(t, []string{"The Octocat", "Octo Cat"}, list[0].Names)
+	assert.EqualValues(t, []string{"octo@example.com"}, list[0].Emails)
+
+	assert.EqualValues(t, "jane@example.com", list[1].ID)
+	assert.EqualValues(t, "", list[1].Login)
+	assert.EqualValues(t, []string{"Jane", "Jane Doe"}, list[1].Names)
+	assert.EqualValues(t, 1, list[1].Commits)
+	assert.EqualValues(t, 1, list[1].PullRequestsOpened)
+}
+
+func TestContributorsListOrdersTiesByID(t *testing.T) {
+	contributors := NewContributors()
+	contributors.Get(identitydomain.Identity{ID: "zed"}).Commits++
+	contributors.Get(identitydomain.Identity{ID: "amy"}).Commits++
+	list := contributors.List()
+	assert.EqualValues(t, "amy", list[0].ID)
+	assert.EqualValues(t, "zed", list[1].ID)
+}
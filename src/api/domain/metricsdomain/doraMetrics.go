@@ -0,0 +1,160 @@
+package metricsdomain
+
+import (
+	"math"
+	"regexp"
+	"time"
+)
+
+//where the deployments are read from, a repo that doesn't use Github deployments can use its releases
+const (
+	DeploymentSourceDeployments = "deployments"
+	DeploymentSourceReleases    = "releases"
+)
+
+//the outcome of a deployment
+const (
+	DeploymentSucceeded = "succeeded"
+	DeploymentFailed    = "failed"
+)
+
+//hotfixPattern matches a commit message mentioning a hotfix, such as a merge of a hotfix/ branch
+var hotfixPattern = regexp.MustCompile(`(?i)\bhot-?fix`)
+
+//IsHotfixCommit determines if the commit message mentions a hotfix
+func IsHotfixCommit(message string) bool {
+	return hotfixPattern.MatchString(message)
+}
+
+//Deployment is a single deployment to production, either a Github deployment or a release
+//the commits are those deployed for the first time, the changes since the previous successful deployment
+type Deployment struct {
+	ID            string          `json:"id"`
+	SHA           string          `json:"sha,omitempty"`
+	Ref           string          `json:"ref"`
+	DeployedAt    time.Time       `json:"deployed_at"`
+	Outcome       string          `json:"outcome"`
+	Commits       int             `json:"commits"`
+	RevertCommits []string        `json:"revert_commits,omitempty"`
+	HotfixCommits []string        `json:"hotfix_commits,omitempty"`
+	ChangeFailure bool            `json:"change_failure"`
+	RestoredAt    *time.Time      `json:"restored_at,omitempty"`
+	LeadTimes     []time.Duration `json:"-"`
+}
+
+//IsRemediation determines if the deployment reverted or hotfixed an earlier one
+func (d *Deployment) IsRemediation() bool {
+	return len(d.RevertCommits) > 0 || len(d.HotfixCommits) > 0
+}
+
+//DORAMetrics holds the four DORA metrics of a repo over a period along with the definitions used to work them out
+type DORAMetrics struct {
+	Owner              string            `json:"owner"`
+	Repo               string            `json:"repo"`
+	Source             string            `json:"source"`
+	Environment        string            `json:"environment,omitempty"`
+	FromDate           time.Time         `json:"from_date"`
+	ToDate             time.Time         `json:"to_date"`
+	DataAsOf           time.Time         `json:"data_as_of"`
+	TotalDeployments   int               `json:"total_deployments"`
+	FailedDeployments  int               `json:"failed_deployments"`
+	DeploymentsPerWeek float64           `json:"deployments_per_week"`
+	LeadTimeForChanges DurationStats     `json:"lead_time_for_changes"`
+	ChangeFailures     int               `json:"change_failures"`
+	ChangeFailureRate  float64           `json:"change_failure_rate"`
+	TimeToRestore      DurationStats     `json:"time_to_restore"`
+	Definitions        map[string]string `json:"definitions"`
+	Deployments        []Deployment      `json:"deployments"`
+}
+
+//NewDORAMetrics works out the DORA metrics from the deployments in the period, oldest first
+//a failed deployment is a change failure restored by the next successful deployment, a successful deployment
+//is a change failure if the next one reverts or hotfixes it and is restored by that deployment
+func NewDORAMetrics(owner string, repo string, source string, environment string, fromDate time.Time, toDate time.Time, deployments []Deployment, asOf time.Time) *DORAMetrics {
+	if deployments == nil {
+		deployments = make([]Deployment, 0)
+	}
+	markChangeFailures(deployments)
+
+	metrics := &DORAMetrics{
+		Owner:       owner,
+		Repo:        repo,
+		Source:      source,
+		Environment: environment,
+		FromDate:    fromDate,
+		ToDate:      toDate,
+		DataAsOf:    asOf,
+		Definitions: getDORADefinitions(source),
+		Deployments: deployments,
+	}
+
+	var leadTimes, restoreTimes []time.Duration
+	succeeded := 0
+	for counter := range deployments {
+		deployment := &deployments[counter]
+		metrics.TotalDeployments++
+		if deployment.Outcome == DeploymentFailed {
+			metrics.FailedDeployments++
+		} else {
+			succeeded++
+			leadTimes = append(leadTimes, deployment.LeadTimes...)
+		}
+		if deployment.ChangeFailure {
+			metrics.ChangeFailures++
+			if deployment.RestoredAt != nil {
+				restoreTimes = append(restoreTimes, deployment.RestoredAt.Sub(deployment.DeployedAt))
+			}
+		}
+	}
+
+	weeks := toDate.Sub(fromDate).Hours() / (24 * 7)
+	if weeks > 0 {
+		metrics.DeploymentsPerWeek = math.Round(float64(succeeded)/weeks*100) / 100
+	}
+	if metrics.TotalDeployments > 0 {
+		metrics.ChangeFailureRate = math.Round(float64(metrics.ChangeFailures)/float64(metrics.TotalDeployments)*100) / 100
+	}
+	metrics.LeadTimeForChanges = NewDurationStats(leadTimes)
+	metrics.TimeToRestore = NewDurationStats(restoreTimes)
+	return metrics
+}
+
+//markChangeFailures finds the deployments that caused a failure in production and when each was restored
+func markChangeFailures(deployments []Deployment) {
+	lastSucceeded := -1
+	for counter := range deployments {
+		deployment := &deployments[counter]
+		if deployment.Outcome == DeploymentFailed {
+			deployment.ChangeFailure = true
+			continue
+		}
+
+		//the first successful deployment after a failure restores it
+		for previous := lastSucceeded + 1; previous < counter; previous++ {
+			if deployments[previous].RestoredAt == nil {
+				deployments[previous].RestoredAt = &deployment.DeployedAt
+			}
+		}
+		if deployment.IsRemediation() && lastSucceeded >= 0 && !deployments[lastSucceeded].ChangeFailure {
+			deployments[lastSucceeded].ChangeFailure = true
+			deployments[lastSucceeded].RestoredAt = &deployment.DeployedAt
+		}
+		lastSucceeded = counter
+	}
+}
+
+//getDORADefinitions describes how each metric is worked out for the source of the deployments
+func getDORADefinitions(source string) map[string]string {
+	deployment := "a Github deployment to the environment whose latest status is success, or was before it became inactive"
+	if source == DeploymentSourceReleases {
+		deployment = "a published release that isn't a draft or pre-release"
+	}
+	return map[string]string{
+		"deployment":            deployment,
+		"deployment_frequency":  "successful deployments in the period divided by the number of weeks in the period",
+		"lead_time_for_changes": "time from each commit being made to the first successful deployment containing it, the commits of a deployment are those since the previous successful deployment",
+		"change_failure":        "a deployment that failed, or a successful deployment followed by one containing a revert commit or a commit mentioning a hotfix",
+		"change_failure_rate":   "change failures divided by all the deployments in the period, both successful and failed",
+		"time_to_restore":       "time from a change failure being deployed to the next successful deployment",
+	}
+}
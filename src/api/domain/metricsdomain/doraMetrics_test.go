@@ -0,0 +1,61 @@
+package metricsdomain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsHotfixCommit(t *testing.T) {
+	assert.True(t, IsHotfixCommit("Merge pull request #12 from myuser/hotfix/login"))
+	assert.True(t, IsHotfixCommit("Hot-fix the login page"))
+	assert.True(t, IsHotfixCommit("[HOTFIX] login"))
+	assert.False(t, IsHotfixCommit("Fix the photo upload"))
+}
+
+func TestNewDORAMetrics(t *testing.T) {
+	fromDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	toDate := fromDate.AddDate(0, 0, 14)
+	deployments := []Deployment{
+		{ID: "1", DeployedAt: *timeAt(2, 10), Outcome: DeploymentSucceeded, LeadTimes: []time.Duration{time.Hour, 3 * time.Hour}},
+		{ID: "2", DeployedAt: *timeAt(3, 10), Outcome: DeploymentSucceeded, LeadTimes: []time.Duration{2 * time.Hour}},
+		{ID: "3", DeployedAt: *timeAt(3, 14), Outcome: DeploymentSucceeded, RevertCommits: []string{"BBB222"}, LeadTimes: []time.Duration{time.Hour}},
+		{ID: "4", DeployedAt: *timeAt(5, 10), Outcome: DeploymentFailed},
+		{ID: "5", DeployedAt: *timeAt(5, 11), Outcome: DeploymentFailed},
+		{ID: "6", DeployedAt: *timeAt(5, 13), Outcome: DeploymentSucceeded, HotfixCommits: []string{"CCC333"}},
+	}
+
+	metrics := NewDORAMetrics("myuser", "myrepo", DeploymentSourceDeployments, "production", fromDate, toDate, deployments, toDate)
+	assert.EqualValues(t, 6, metrics.TotalDeployments)
+	assert.EqualValues(t, 2, metrics.FailedDeployments)
+	assert.EqualValues(t, 2, metrics.DeploymentsPerWeek)
+	assert.EqualValues(t, 4, metrics.LeadTimeForChanges.Count)
+	assert.EqualValues(t, 1, metrics.LeadTimeForChanges.P50)
+	assert.EqualValues(t, 3, metrics.LeadTimeForChanges.P90)
+
+	assert.False(t, metrics.Deployments[0].ChangeFailure)
+	assert.True(t, metrics.Deployments[1].ChangeFailure)
+	assert.True(t, timeAt(3, 14).Equal(*metrics.Deployments[1].RestoredAt))
+	assert.True(t, metrics.Deployments[3].ChangeFailure)
+	assert.True(t, timeAt(5, 13).Equal(*metrics.Deployments[3].RestoredAt))
+	assert.True(t, timeAt(5, 13).Equal(*metrics.Deployments[4].RestoredAt))
+	//the hotfix remediates the third deployment, the last one to succeed
+	assert.True(t, metrics.Deployments[2].ChangeFailure)
+	assert.False(t, metrics.Deployments[5].ChangeFailure)
+
+	assert.EqualValues(t, 4, metrics.ChangeFailures)
+	assert.EqualValues(t, 0.67, metrics.ChangeFailureRate)
+	assert.EqualValues(t, 4, metrics.TimeToRestore.Count)
+	assert.EqualValues(t, 3, metrics.TimeToRestore.P50)
+	assert.Contains(t, metrics.Definitions["deployment"], "Github deployment")
+}
+
+func TestNewDORAMetricsNoDeployments(t *testing.T) {
+	fromDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	metrics := NewDORAMetrics("myuser", "myrepo", DeploymentSourceReleases, "", fromDate, fromDate.AddDate(0, 0, 7), nil, fromDate)
+	assert.EqualValues(t, 0, metrics.TotalDeployments)
+	assert.EqualValues(t, 0, metrics.ChangeFailureRate)
+	assert.EqualValues(t, 0, metrics.DeploymentsPerWeek)
+	assert.Contains(t, metrics.Definitions["deployment"], "release")
+}
@@ -0,0 +1,64 @@
+//Package metricsdomain holds the metrics worked out from the history of a repo
+package metricsdomain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+//DurationStats summarises a set of durations, each given in hours
+type DurationStats struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50_hours"`
+	P90   float64 `json:"p90_hours"`
+	Mean  float64 `json:"mean_hours"`
+}
+
+//NewDurationStats works out the percentiles and mean of the durations, all are zero if there are no durations
+func NewDurationStats(durations []time.Duration) DurationStats {
+	stats := DurationStats{Count: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	hours := make([]float64, 0, len(durations))
+	total := 0.0
+	for _, duration := range durations {
+		hours = append(hours, duration.Hours())
+		total += duration.Hours()
+	}
+	sort.Float64s(hours)
+
+	stats.P50 = roundHours(Percentile(hours, 50))
+	stats.P90 = roundHours(Percentile(hours, 90))
+	stats.Mean = roundHours(total / float64(len(hours)))
+	return stats
+}
+
+//Percentile returns the nearest rank percentile of the sorted values, zero if there are no values
+func Percentile(sorted []float64, percentile float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+//roundHours rounds to two decimal places, anything finer than a minute isn't useful
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+//WeekStart returns the start of the week, Monday at midnight UTC, the time falls in
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,37 @@
+package metricsdomain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.EqualValues(t, 5, Percentile(values, 50))
+	assert.EqualValues(t, 9, Percentile(values, 90))
+	assert.EqualValues(t, 1, Percentile(values, 0))
+	assert.EqualValues(t, 10, Percentile(values, 100))
+	assert.EqualValues(t, 0, Percentile(nil, 50))
+	assert.EqualValues(t, 7, Percentile([]float64{7}, 90))
+}
+
+func TestNewDurationStats(t *testing.T) {
+	stats := NewDurationStats([]time.Duration{3 * time.Hour, time.Hour, 90 * time.Minute, 20 * time.Hour})
+	assert.EqualValues(t, 4, stats.Count)
+	assert.EqualValues(t, 1.5, stats.P50)
+	assert.EqualValues(t, 20, stats.P90)
+	assert.EqualValues(t, 6.38, stats.Mean)
+
+	empty := NewDurationStats(nil)
+	assert.EqualValues(t, DurationStats{}, empty)
+}
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)
+	assert.True(t, monday.Equal(WeekStart(time.Date(2020, 3, 2, 15, 4, 5, 0, time.UTC))))
+	assert.True(t, monday.Equal(WeekStart(time.Date(2020, 3, 8, 23, 59, 0, 0, time.UTC))))
+	assert.True(t, monday.Equal(WeekStart(time.Date(2020, 3, 5, 10, 0, 0, 0, time.UTC))))
+	assert.True(t, monday.AddDate(0, 0, 7).Equal(WeekStart(time.Date(2020, 3, 9, 0, 0, 0, 0, time.UTC))))
+}
@@ -0,0 +1,182 @@
+package metricsdomain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+)
+
+//PullRequestTimings records when each stage of a single PR was reached, stages not yet reached are left out
+type PullRequestTimings struct {
+	Number        int64      `json:"number"`
+	Title         string     `json:"title"`
+	Author        string     `json:"author"`
+	AuthorID      string     `json:"author_id"`
+	BaseRef       string     `json:"base_ref"`
+	State         string     `json:"state"`
+	CreatedAt     time.Time  `json:"created_at"`
+	FirstReviewAt *time.Time `json:"first_review_at,omitempty"`
+	ApprovedAt    *time.Time `json:"approved_at,omitempty"`
+	MergedAt      *time.Time `json:"merged_at,omitempty"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty"`
+	Additions     int64      `json:"additions"`
+	Deletions     int64      `json:"deletions"`
+	ChangedFiles  int64      `json:"changed_files"`
+	Size          string     `json:"size"`
+}
+
+//SetSize records the lines and files changed by the PR along with the size they make it
+func (t *PullRequestTimings) SetSize(additions int64, deletions int64, changedFiles int64) {
+	t.Additions = additions
+	t.Deletions = deletions
+	t.ChangedFiles = changedFiles
+	t.Size = SizeOf(t.ChangedLines())
+}
+
+//ChangedLines returns the number of lines the PR adds and deletes
+func (t *PullRequestTimings) ChangedLines() int64 {
+	return t.Additions + t.Deletions
+}
+
+//TimeToFirstReview returns how long the PR waited for its first review, false if it hasn't been reviewed
+func (t *PullRequestTimings) TimeToFirstReview() (time.Duration, bool) {
+	return durationSinceCreated(t.CreatedAt, t.FirstReviewAt)
+}
+
+//TimeToApproval returns how long the PR waited for its first approval, false if it hasn't been approved
+func (t *PullRequestTimings) TimeToApproval() (time.Duration, bool) {
+	return durationSinceCreated(t.CreatedAt, t.ApprovedAt)
+}
+
+//TimeToMerge returns how long the PR took to be merged, false if it hasn't been merged
+func (t *PullRequestTimings) TimeToMerge() (time.Duration, bool) {
+	return durationSinceCreated(t.CreatedAt, t.MergedAt)
+}
+
+//OpenDuration returns how long the PR was open, a PR that is still open is measured up to the given time
+func (t *PullRequestTimings) OpenDuration(asOf time.Time) time.Duration {
+	if duration, ok := durationSinceCreated(t.CreatedAt, t.ClosedAt); ok {
+		return duration
+	}
+	return asOf.Sub(t.CreatedAt)
+}
+
+//durationSinceCreated returns the time from creation to the stage, false if the stage hasn't been reached
+func durationSinceCreated(createdAt time.Time, reachedAt *time.Time) (time.Duration, bool) {
+	if reachedAt == nil {
+		return 0, false
+	}
+	return reachedAt.Sub(createdAt), true
+}
+
+//CycleTimeStats summarises the time taken by each stage of a set of PRs
+type CycleTimeStats struct {
+	PullRequests      int           `json:"pull_requests"`
+	Merged            int           `json:"merged"`
+	TimeToFirstReview DurationStats `json:"time_to_first_review"`
+	TimeToApproval    DurationStats `json:"time_to_approval"`
+	TimeToMerge       DurationStats `json:"time_to_merge"`
+	OpenDuration      DurationStats `json:"open_duration"`
+}
+
+//NewCycleTimeStats summarises the PRs, open PRs are measured up to the given time
+func NewCycleTimeStats(timings []PullRequestTimings, asOf time.Time) CycleTimeStats {
+	var firstReview, approval, merge, open []time.Duration
+	for counter := range timings {
+		timing := &timings[counter]
+		if duration, ok := timing.TimeToFirstReview(); ok {
+			firstReview = append(firstReview, duration)
+		}
+		if duration, ok := timing.TimeToApproval(); ok {
+			approval = append(approval, duration)
+		}
+		if duration, ok := timing.TimeToMerge(); ok {
+			merge = append(merge, duration)
+		}
+		open = append(open, timing.OpenDuration(asOf))
+	}
+
+	return CycleTimeStats{
+		PullRequests:      len(timings),
+		Merged:            len(merge),
+		TimeToFirstReview: NewDurationStats(firstReview),
+		TimeToApproval:    NewDurationStats(approval),
+		TimeToMerge:       NewDurationStats(merge),
+		OpenDuration:      NewDurationStats(open),
+	}
+}
+
+//WeeklyCycleTimeStats summarises the PRs created in the week starting on the Monday
+type WeeklyCycleTimeStats struct {
+	WeekStart time.Time `json:"week_start"`
+	CycleTimeStats
+}
+
+//PullRequestMetrics holds the cycle time and review latency of the PRs created in a period
+//the PRs can be limited to a single author or base branch
+type PullRequestMetrics struct {
+	Owner        string                 `json:"owner"`
+	Repo         string                 `json:"repo"`
+	FromDate     time.Time              `json:"from_date"`
+	ToDate       time.Time              `json:"to_date"`
+	Author       string                 `json:"author,omitempty"`
+	BaseRef      string                 `json:"base_ref,omitempty"`
+	DataAsOf     time.Time              `json:"data_as_of"`
+	Overall      CycleTimeStats         `json:"overall"`
+	Weekly       []WeeklyCycleTimeStats `json:"weekly"`
+	Sizes        SizeDistribution       `json:"sizes"`
+	Teams        []TeamCycleTimeStats   `json:"teams,omitempty"`
+	PullRequests []PullRequestTimings   `json:"pull_requests"`
+}
+
+//TeamCycleTimeStats summarises the PRs written by the members of a team
+type TeamCycleTimeStats struct {
+	Team string `json:"team"`
+	CycleTimeStats
+}
+
+//NewPullRequestMetrics summarises the PRs overall and for each week they were created in, oldest week first
+//weeks without any PRs are included so the trend can be charted
+func NewPullRequestMetrics(owner string, repo string, fromDate time.Time, toDate time.Time, timings []PullRequestTimings, asOf time.Time) *PullRequestMetrics {
+	sort.Slice(timings, func(i, j int) bool {
+		return timings[i].CreatedAt.Before(timings[j].CreatedAt)
+	})
+
+	metrics := &PullRequestMetrics{
+		Owner:        owner,
+		Repo:         repo,
+		FromDate:     fromDate,
+		ToDate:       toDate,
+		DataAsOf:     asOf,
+		Overall:      NewCycleTimeStats(timings, asOf),
+		Weekly:       make([]WeeklyCycleTimeStats, 0),
+		PullRequests: timings,
+	}
+
+	for week := WeekStart(fromDate); week.Before(toDate); week = week.AddDate(0, 0, 7) {
+		var weekTimings []PullRequestTimings
+		for _, timing := range timings {
+			if WeekStart(timing.CreatedAt).Equal(week) {
+				weekTimings = append(weekTimings, timing)
+			}
+		}
+		metrics.Weekly = append(metrics.Weekly, WeeklyCycleTimeStats{WeekStart: week, CycleTimeStats: NewCycleTimeStats(weekTimings, asOf)})
+	}
+	return metrics
+}
+
+//SetTeams breaks the PRs down by the team of their author, a person in several teams counts towards each of them
+func (m *PullRequestMetrics) SetTeams(directory *teamdomain.Directory) {
+	teamTimings := make(map[string][]PullRequestTimings)
+	for _, timing := range m.PullRequests {
+		for _, team := range directory.TeamsOf(timing.AuthorID) {
+			teamTimings[team] = append(teamTimings[team], timing)
+		}
+	}
+
+	m.Teams = make([]TeamCycleTimeStats, 0)
+	for _, team := range directory.TeamNames() {
+		m.Teams = append(m.Teams, TeamCycleTimeStats{Team: team, CycleTimeStats: NewCycleTimeStats(teamTimings[team], m.DataAsOf)})
+	}
+}
@@ -0,0 +1,97 @@
+package metricsdomain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func timeAt(day int, hour int) *time.Time {
+	t := time.Date(2020, 3, day, hour, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func getTestTimings() []PullRequestTimings {
+	return []PullRequestTimings{
+		{Number: 2, CreatedAt: *timeAt(10, 9), FirstReviewAt: timeAt(10, 10), ApprovedAt: timeAt(10, 12), MergedAt: timeAt(10, 13), ClosedAt: timeAt(10, 13)},
+		{Number: 1, CreatedAt: *timeAt(3, 9), FirstReviewAt: timeAt(3, 11), ApprovedAt: timeAt(4, 9), MergedAt: timeAt(4, 10), ClosedAt: timeAt(4, 10)},
+		{Number: 3, CreatedAt: *timeAt(11, 9), FirstReviewAt: timeAt(11, 13)},
+	}
+}
+
+func TestPullRequestTimings(t *testing.T) {
+	timings := getTestTimings()
+
+	duration, ok := timings[1].TimeToFirstReview()
+	assert.True(t, ok)
+	assert.EqualValues(t, 2*time.Hour, duration)
+	duration, ok = timings[1].TimeToApproval()
+	assert.True(t, ok)
+	assert.EqualValues(t, 24*time.Hour, duration)
+	duration, ok = timings[1].TimeToMerge()
+	assert.True(t, ok)
+	assert.EqualValues(t, 25*time.Hour, duration)
+	assert.EqualValues(t, 25*time.Hour, timings[1].OpenDuration(*timeAt(20, 0)))
+
+	_, ok = timings[2].TimeToApproval()
+	assert.False(t, ok)
+	_, ok = timings[2].TimeToMerge()
+	assert.False(t, ok)
+	assert.EqualValues(t, 24*time.Hour, timings[2].OpenDuration(*timeAt(12, 9)))
+}
+
+func TestNewCycleTimeStats(t *testing.T) {
+	stats := NewCycleTimeStats(getTestTimings(), *timeAt(12, 9))
+	assert.EqualValues(t, 3, stats.PullRequests)
+	assert.EqualValues(t, 2, stats.Merged)
+	assert.EqualValues(t, 3, stats.TimeToFirstReview.Count)
+	assert.EqualValues(t, 2, stats.TimeToFirstReview.P50)
+	assert.EqualValues(t, 4, stats.TimeToFirstReview.P90)
+	assert.EqualValues(t, 2, stats.TimeToApproval.Count)
+	assert.EqualValues(t, 3, stats.TimeToApproval.P50)
+	assert.EqualValues(t, 2, stats.TimeToMerge.Count)
+	assert.EqualValues(t, 25, stats.TimeToMerge.P90)
+	assert.EqualValues(t, 3, stats.OpenDuration.Count)
+	assert.EqualValues(t, 24, stats.OpenDuration.P50)
+}
+
+func TestNewPullRequestMetrics(t *testing.T) {
+	fromDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)
+	metrics := NewPullRequestMetrics("myuser", "myrepo", fromDate, toDate, getTestTimings(), *timeAt(12, 9))
+
+	assert.EqualValues(t, 3, metrics.Overall.PullRequests)
+	assert.EqualValues(t, 1, metrics.PullRequests[0].Number)
+	assert.EqualValues(t, 3, metrics.PullRequests[2].Number)
+
+	//the 1st of March 2020 is a Sunday so its week starts in February
+	assert.EqualValues(t, 3, len(metrics.Weekly))
+	assert.True(t, time.Date(2020, 2, 24, 0, 0, 0, 0, time.UTC).Equal(metrics.Weekly[0].WeekStart))
+	assert.EqualValues(t, 0, metrics.Weekly[0].PullRequests)
+	assert.EqualValues(t, 1, metrics.Weekly[1].PullRequests)
+	assert.EqualValues(t, 25, metrics.Weekly[1].TimeToMerge.P50)
+	assert.EqualValues(t, 2, metrics.Weekly[2].PullRequests)
+	assert.EqualValues(t, 1, metrics.Weekly[2].Merged)
+}
+
+func TestPullRequestMetricsSetTeams(t *testing.T) {
+	fromDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)
+	timings := getTestTimings()
+	timings[0].AuthorID = "alice"
+	timings[1].AuthorID = "alice"
+	timings[2].AuthorID = "bob"
+	metrics := NewPullRequestMetrics("myuser", "myrepo", fromDate, toDate, timings, *timeAt(12, 9))
+
+	metrics.SetTeams(&teamdomain.Directory{Teams: []teamdomain.Team{{Name: "payments", Members: []string{"alice"}}}})
+	assert.EqualValues(t, 2, len(metrics.Teams))
+	assert.EqualValues(t, "payments", metrics.Teams[0].Team)
+	assert.EqualValues(t, 2, metrics.Teams[0].PullRequests)
+	assert.EqualValues(t, 2, metrics.Teams[0].Merged)
+	assert.EqualValues(t, 2, metrics.Teams[0].TimeToFirstReview.P90)
+	assert.EqualValues(t, teamdomain.Unassigned, metrics.Teams[1].Team)
+	assert.EqualValues(t, 1, metrics.Teams[1].PullRequests)
+	assert.EqualValues(t, 4, metrics.Teams[1].TimeToFirstReview.P50)
+}
@@ -0,0 +1,84 @@
+package metricsdomain
+
+import "sort"
+
+//the size of a PR by the number of lines it adds and deletes
+const (
+	SizeXS = "XS"
+	SizeS  = "S"
+	SizeM  = "M"
+	SizeL  = "L"
+	SizeXL = "XL"
+)
+
+//sizeLimits gives the most lines changed by a PR of each size, anything bigger is XL
+var sizeLimits = []struct {
+	size     string
+	maxLines int64
+}{
+	{SizeXS, 9},
+	{SizeS, 49},
+	{SizeM, 249},
+	{SizeL, 999},
+}
+
+//SizeOf returns the size of a PR changing the number of lines
+func SizeOf(changedLines int64) string {
+	for _, limit := range sizeLimits {
+		if changedLines <= limit.maxLines {
+			return limit.size
+		}
+	}
+	return SizeXL
+}
+
+//SizeBucket counts the PRs of a single size, the largest size has no upper limit
+type SizeBucket struct {
+	Size         string `json:"size"`
+	MaxLines     int64  `json:"max_lines,omitempty"`
+	PullRequests int    `json:"pull_requests"`
+}
+
+//SizeDistribution summarises how many lines a set of PRs changed
+//PRs changing at least the large change limit are listed so they can be followed up, there is no limit when it is zero
+type SizeDistribution struct {
+	PullRequests      int          `json:"pull_requests"`
+	P50Lines          float64      `json:"p50_lines"`
+	P90Lines          float64      `json:"p90_lines"`
+	Buckets           []SizeBucket `json:"buckets"`
+	LargeChangeLines  int64        `json:"large_change_lines,omitempty"`
+	LargePullRequests []int64      `json:"large_pull_requests"`
+}
+
+//NewSizeDistribution counts the PRs of each size and works out the percentiles of the lines changed
+func NewSizeDistribution(timings []PullRequestTimings, largeChangeLines int64) SizeDistribution {
+	distribution := SizeDistribution{
+		PullRequests:      len(timings),
+		Buckets:           make([]SizeBucket, 0, len(sizeLimits)+1),
+		LargeChangeLines:  largeChangeLines,
+		LargePullRequests: make([]int64, 0),
+	}
+	for _, limit := range sizeLimits {
+		distribution.Buckets = append(distribution.Buckets, SizeBucket{Size: limit.size, MaxLines: limit.maxLines})
+	}
+	distribution.Buckets = append(distribution.Buckets, SizeBucket{Size: SizeXL})
+
+	lines := make([]float64, 0, len(timings))
+	for counter := range timings {
+		changedLines := timings[counter].ChangedLines()
+		lines = append(lines, float64(changedLines))
+		for bucket := range distribution.Buckets {
+			if distribution.Buckets[bucket].Size == SizeOf(changedLines) {
+				distribution.Buckets[bucket].PullRequests++
+			}
+		}
+		if largeChangeLines > 0 && changedLines >= largeChangeLines {
+			distribution.LargePullRequests = append(distribution.LargePullRequests, timings[counter].Number)
+		}
+	}
+	sort.Float64s(lines)
+
+	distribution.P50Lines = Percentile(lines, 50)
+	distribution.P90Lines = Percentile(lines, 90)
+	return distribution
+}
@@ -0,0 +1,64 @@
+package metricsdomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizeConstants(t *testing.T) {
+	assert.EqualValues(t, "XS", SizeXS)
+	assert.EqualValues(t, "S", SizeS)
+	assert.EqualValues(t, "M", SizeM)
+	assert.EqualValues(t, "L", SizeL)
+	assert.EqualValues(t, "XL", SizeXL)
+}
+
+func TestSizeOf(t *testing.T) {
+	assert.EqualValues(t, SizeXS, SizeOf(0))
+	assert.EqualValues(t, SizeXS, SizeOf(9))
+	assert.EqualValues(t, SizeS, SizeOf(10))
+	assert.EqualValues(t, SizeM, SizeOf(249))
+	assert.EqualValues(t, SizeL, SizeOf(250))
+	assert.EqualValues(t, SizeL, SizeOf(999))
+	assert.EqualValues(t, SizeXL, SizeOf(1000))
+}
+
+func TestPullRequestTimingsSetSize(t *testing.T) {
+	timing := PullRequestTimings{}
+	timing.SetSize(40, 15, 3)
+	assert.EqualValues(t, 40, timing.Additions)
+	assert.EqualValues(t, 15, timing.Deletions)
+	assert.EqualValues(t, 3, timing.ChangedFiles)
+	assert.EqualValues(t, 55, timing.ChangedLines())
+	assert.EqualValues(t, SizeM, timing.Size)
+}
+
+func TestNewSizeDistribution(t *testing.T) {
+	timings := make([]PullRequestTimings, 4)
+	for counter, lines := range []int64{5, 60, 1500, 700} {
+		timings[counter].Number = int64(counter + 1)
+		timings[counter].SetSize(lines, 0, 1)
+	}
+
+	distribution := NewSizeDistribution(timings, 500)
+	assert.EqualValues(t, 4, distribution.PullRequests)
+	assert.EqualValues(t, 60, distribution.P50Lines)
+	assert.EqualValues(t, 1500, distribution.P90Lines)
+	assert.EqualValues(t, []SizeBucket{
+		{Size: SizeXS, MaxLines: 9, PullRequests: 1},
+		{Size: SizeS, MaxLines: 49},
+		{Size: SizeM, MaxLines: 249, PullRequests: 1},
+		{Size: SizeL, MaxLines: 999, PullRequests: 1},
+		{Size: SizeXL, PullRequests: 1},
+	}, distribution.Buckets)
+	assert.EqualValues(t, []int64{3, 4}, distribution.LargePullRequests)
+
+	distribution = NewSizeDistribution(timings, 0)
+	assert.EqualValues(t, 0, len(distribution.LargePullRequests))
+
+	distribution = NewSizeDistribution(nil, 500)
+	assert.EqualValues(t, 0, distribution.PullRequests)
+	assert.EqualValues(t, 0, distribution.P90Lines)
+	assert.EqualValues(t, 5, len(distribution.Buckets))
+}
@@ -0,0 +1,99 @@
+package policydomain
+
+import (
+	"path"
+	"strings"
+)
+
+//codeOwnersRule assigns the owners to the files matching the pattern
+type codeOwnersRule struct {
+	pattern string
+	owners  []string
+}
+
+//CodeOwners holds the rules of a CODEOWNERS file in the order they appear
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+//ParseCodeOwners reads the rules from the content of a CODEOWNERS file, blank lines and comments are ignored
+func ParseCodeOwners(content string) *CodeOwners {
+	codeOwners := &CodeOwners{}
+	for _, line := range strings.Split(content, "\n") {
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		codeOwners.rules = append(codeOwners.rules, codeOwnersRule{pattern: fields[0], owners: fields[1:]})
+	}
+	return codeOwners
+}
+
+//OwnersFor returns the owners of the file, as in Github the last matching rule wins
+//a rule without any owners means the file has no owners
+func (c *CodeOwners) OwnersFor(filename string) []string {
+	for counter := len(c.rules) - 1; counter >= 0; counter-- {
+		if matchesCodeOwnersPattern(c.rules[counter].pattern, filename) {
+			return c.rules[counter].owners
+		}
+	}
+	return nil
+}
+
+//matchesCodeOwnersPattern matches the file against a pattern that follows the gitignore rules
+//a leading slash anchors the pattern to the root of the repo, a trailing slash matches everything in the directory
+//a pattern without a slash matches the file or directory at any depth and a pattern ending in /* only matches
+//the files directly within the directory
+func matchesCodeOwnersPattern(pattern string, filename string) bool {
+	filename = strings.TrimPrefix(filename, "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	exact := strings.HasSuffix(pattern, "/*")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" || pattern == "*" && !directory {
+		return true
+	}
+
+	parts := strings.Split(filename, "/")
+	patternParts := strings.Split(pattern, "/")
+	if !anchored {
+		//an unanchored pattern can match any directory or the file itself
+		for start := range parts {
+			if matchesPathParts(patternParts, parts[start:], directory, exact) {
+				return true
+			}
+		}
+		return false
+	}
+	return matchesPathParts(patternParts, parts, directory, exact)
+}
+
+//matchesPathParts matches the parts of the path from the start, any remaining parts of the path are
+//files within a matched directory, a directory only pattern needs at least one of these and an exact pattern none
+func matchesPathParts(patternParts []string, parts []string, directory bool, exact bool) bool {
+	if len(patternParts) == 0 {
+		if exact {
+			return len(parts) == 0
+		}
+		return !directory || len(parts) > 0
+	}
+	if patternParts[0] == "**" {
+		for skip := 0; skip <= len(parts); skip++ {
+			if matchesPathParts(patternParts[1:], parts[skip:], directory, exact) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(patternParts[0], parts[0]); !matched {
+		return false
+	}
+	return matchesPathParts(patternParts[1:], parts[1:], directory, exact)
+}
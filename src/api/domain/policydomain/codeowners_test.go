@@ -0,0 +1,46 @@
+package policydomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCodeOwners = `# the default owners
+*       @myorg/everyone
+
+*.js    @js-owner
+/build/ @build-owner
+docs/*  docs@example.com
+apps/   @apps-owner
+/scripts/**/deploy.sh @ops-owner @myorg/ops
+/vendor/
+`
+
+func TestParseCodeOwnersIgnoresCommentsAndBlankLines(t *testing.T) {
+	codeOwners := ParseCodeOwners(testCodeOwners)
+	assert.EqualValues(t, 7, len(codeOwners.rules))
+	assert.EqualValues(t, "*", codeOwners.rules[0].pattern)
+	assert.EqualValues(t, []string{"@myorg/everyone"}, codeOwners.rules[0].owners)
+}
+
+func TestOwnersForLastMatchingRuleWins(t *testing.T) {
+	codeOwners := ParseCodeOwners(testCodeOwners)
+
+	assert.EqualValues(t, []string{"@myorg/everyone"}, codeOwners.OwnersFor("README.md"))
+	assert.EqualValues(t, []string{"@js-owner"}, codeOwners.OwnersFor("src/web/app.js"))
+	assert.EqualValues(t, []string{"@build-owner"}, codeOwners.OwnersFor("build/logs/out.js"))
+	assert.EqualValues(t, []string{"@myorg/everyone"}, codeOwners.OwnersFor("src/build/main.go"))
+	assert.EqualValues(t, []string{"docs@example.com"}, codeOwners.OwnersFor("docs/guide.md"))
+	assert.EqualValues(t, []string{"@myorg/everyone"}, codeOwners.OwnersFor("src/docs/guide.md"))
+	assert.EqualValues(t, []string{"@myorg/everyone"}, codeOwners.OwnersFor("docs/api/guide.md"))
+	assert.EqualValues(t, []string{"@apps-owner"}, codeOwners.OwnersFor("src/apps/main.go"))
+	assert.EqualValues(t, []string{"@ops-owner", "@myorg/ops"}, codeOwners.OwnersFor("scripts/deploy.sh"))
+	assert.EqualValues(t, []string{"@ops-owner", "@myorg/ops"}, codeOwners.OwnersFor("scripts/prod/eu/deploy.sh"))
+	assert.EqualValues(t, 0, len(codeOwners.OwnersFor("vendor/lib/lib.go")))
+}
+
+func TestOwnersForNoRules(t *testing.T) {
+	codeOwners := ParseCodeOwners("")
+	assert.Nil(t, codeOwners.OwnersFor("main.go"))
+}
@@ -0,0 +1,93 @@
+package policydomain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+//PathFilter limits a report to the commits changing a file matching any of the patterns, an empty filter matches everything
+//the patterns follow the same rules as a CODEOWNERS file, such as services/payments/** or *.go
+type PathFilter []string
+
+//NewPathFilter checks each pattern is a valid glob, blank patterns are ignored
+func NewPathFilter(patterns []string) (PathFilter, error) {
+	var filter PathFilter
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		for _, part := range strings.Split(pattern, "/") {
+			if _, err := path.Match(part, ""); err != nil {
+				return nil, fmt.Errorf("invalid path pattern %s", pattern)
+			}
+		}
+		filter = append(filter, pattern)
+	}
+	return filter, nil
+}
+
+//IsEmpty returns true if the filter matches everything
+func (f PathFilter) IsEmpty() bool {
+	return len(f) == 0
+}
+
+//Matches returns true if the file matches any of the patterns
+func (f PathFilter) Matches(filename string) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	for _, pattern := range f {
+		if matchesCodeOwnersPattern(pattern, filename) {
+			return true
+		}
+	}
+	return false
+}
+
+//MatchesAny returns true if any of the files matches any of the patterns
+func (f PathFilter) MatchesAny(filenames []string) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	for _, filename := range filenames {
+		if f.Matches(filename) {
+			return true
+		}
+	}
+	return false
+}
+
+//GithubPaths returns the paths to ask Github for the commits of, one for each pattern, along with whether the
+//commits Github returns only change matching files, if not the files of each commit have to be checked
+//nothing is returned when a pattern can match anywhere in the repo, so all commits have to be checked
+func (f PathFilter) GithubPaths() ([]string, bool) {
+	var paths []string
+	exact := true
+	for _, pattern := range f {
+		trimmed := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/"), "/**")
+		if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+			//a pattern without a slash matches at any depth
+			return nil, false
+		}
+
+		var literal []string
+		for _, part := range strings.Split(trimmed, "/") {
+			if strings.ContainsAny(part, "*?[\\") {
+				break
+			}
+			literal = append(literal, part)
+		}
+		if len(literal) == 0 {
+			return nil, false
+		}
+
+		prefix := strings.Join(literal, "/")
+		if prefix != trimmed {
+			exact = false
+		}
+		paths = append(paths, prefix)
+	}
+	return paths, exact
+}
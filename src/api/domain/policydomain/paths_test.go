@@ -0,0 +1,57 @@
+package policydomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPathFilter(t *testing.T) {
+	filter, err := NewPathFilter([]string{" services/payments/** ", "", "*.go"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, PathFilter{"services/payments/**", "*.go"}, filter)
+	assert.False(t, filter.IsEmpty())
+
+	filter, err = NewPathFilter(nil)
+	assert.Nil(t, err)
+	assert.True(t, filter.IsEmpty())
+
+	filter, err = NewPathFilter([]string{"services/[payments/**"})
+	assert.Nil(t, filter)
+	assert.EqualValues(t, "invalid path pattern services/[payments/**", err.Error())
+}
+
+func TestPathFilterMatches(t *testing.T) {
+	filter := PathFilter{"services/payments/**", "/docs/*.md"}
+	assert.True(t, filter.Matches("services/payments/api/handler.go"))
+	assert.True(t, filter.Matches("docs/README.md"))
+	assert.False(t, filter.Matches("docs/guides/setup.md"))
+	assert.False(t, filter.Matches("services/billing/handler.go"))
+	assert.True(t, PathFilter{}.Matches("anything.go"))
+
+	assert.True(t, filter.MatchesAny([]string{"go.mod", "services/payments/main.go"}))
+	assert.False(t, filter.MatchesAny([]string{"go.mod"}))
+	assert.False(t, filter.MatchesAny(nil))
+	assert.True(t, PathFilter{}.MatchesAny(nil))
+}
+
+func TestPathFilterGithubPaths(t *testing.T) {
+	tests := []struct {
+		filter PathFilter
+		paths  []string
+		exact  bool
+	}{
+		{PathFilter{"services/payments/**"}, []string{"services/payments"}, true},
+		{PathFilter{"/docs", "src/main.go"}, []string{"docs", "src/main.go"}, true},
+		{PathFilter{"services/payments/**", "services/*/api/**"}, []string{"services/payments", "services"}, false},
+		{PathFilter{"services/payments/*"}, []string{"services/payments"}, false},
+		{PathFilter{"services/payments/**", "*.go"}, nil, false},
+		{PathFilter{"docs/"}, nil, false},
+		{PathFilter{"**/*.go"}, nil, false},
+	}
+	for _, test := range tests {
+		paths, exact := test.filter.GithubPaths()
+		assert.EqualValues(t, test.paths, paths, test.filter)
+		assert.EqualValues(t, test.exact, exact, test.filter)
+	}
+}
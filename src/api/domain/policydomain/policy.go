@@ -0,0 +1,193 @@
+//Package policydomain holds the review rules a PR must meet before it is merged
+package policydomain
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+//the formats a commit message can be required to follow
+const (
+	MessageFormatAny          = "any"
+	MessageFormatConventional = "conventional"
+)
+
+//DefaultTicketPattern finds ticket keys in the format used by Jira, such as PAY-123
+const DefaultTicketPattern = `\b[A-Z][A-Z0-9]+-[0-9]+\b`
+
+//ReviewPolicy defines the reviews and status checks a PR needs before it can be merged
+//a PR changing at least the large change lines is flagged as being hard to review, zero turns this off
+//enforce admins is only used to audit the branch protection of the repo, admins are otherwise treated like anyone else
+//the merges bypassing the policy are only looked for when enabled as the permission of each merger is looked up
+type ReviewPolicy struct {
+	RequiredApprovals      int                `yaml:"required_approvals" json:"required_approvals"`
+	RequireCodeOwnerReview bool               `yaml:"require_code_owner_review" json:"require_code_owner_review"`
+	DismissStaleApprovals  bool               `yaml:"dismiss_stale_approvals" json:"dismiss_stale_approvals"`
+	RequiredStatusChecks   []string           `yaml:"required_status_checks" json:"required_status_checks"`
+	EnforceAdmins          bool               `yaml:"enforce_admins" json:"enforce_admins"`
+	LargeChangeLines       int64              `yaml:"large_change_lines" json:"large_change_lines"`
+	CommitMessages         CommitMessageRules `yaml:"commit_messages" json:"commit_messages"`
+	Tickets                TicketRules        `yaml:"tickets" json:"tickets"`
+	Signatures             SignatureRules     `yaml:"signatures" json:"signatures"`
+	RubberStamps           RubberStampRules   `yaml:"rubber_stamps" json:"rubber_stamps"`
+	DetectBypassMerges     bool               `yaml:"detect_bypass_merges" json:"detect_bypass_merges"`
+}
+
+//BranchProtectionBaseline is the protection the default branch of a repo is expected to have
+//it is the part of the review policy Github can enforce itself, the branch can be stricter than the baseline
+type BranchProtectionBaseline struct {
+	RequiredApprovals      int      `json:"required_approvals"`
+	DismissStaleApprovals  bool     `json:"dismiss_stale_approvals"`
+	RequireCodeOwnerReview bool     `json:"require_code_owner_review"`
+	EnforceAdmins          bool     `json:"enforce_admins"`
+	RequiredStatusChecks   []string `json:"required_status_checks"`
+}
+
+//GetBranchProtectionBaseline returns the branch protection the policy expects
+func (p *ReviewPolicy) GetBranchProtectionBaseline() BranchProtectionBaseline {
+	return BranchProtectionBaseline{
+		RequiredApprovals:      p.RequiredApprovals,
+		DismissStaleApprovals:  p.DismissStaleApprovals,
+		RequireCodeOwnerReview: p.RequireCodeOwnerReview,
+		EnforceAdmins:          p.EnforceAdmins,
+		RequiredStatusChecks:   p.RequiredStatusChecks,
+	}
+}
+
+//CommitMessageRules defines what the message of each commit must look like, the zero value allows any message
+//the types are only checked for a conventional message and an empty list of types allows any type
+//a zero max subject length doesn't limit the length and a sign-off must be by the author of the commit, as the DCO requires
+type CommitMessageRules struct {
+	Format           string   `yaml:"format" json:"format,omitempty"`
+	Types            []string `yaml:"types" json:"types,omitempty"`
+	MaxSubjectLength int      `yaml:"max_subject_length" json:"max_subject_length,omitempty"`
+	RequireSignOff   bool     `yaml:"require_sign_off" json:"require_sign_off,omitempty"`
+}
+
+//IsEmpty returns true if the rules allow any message
+func (r *CommitMessageRules) IsEmpty() bool {
+	return r.Format != MessageFormatConventional && r.MaxSubjectLength == 0 && !r.RequireSignOff
+}
+
+//TicketRules defines the issue tracker tickets each change must reference, the zero value doesn't require any
+//the keys are found with the patterns, a pattern with a group uses what the first group matched as the key
+//a ticket must exist and be in one of the allowed states, any state is allowed when none are given
+type TicketRules struct {
+	Required      bool     `yaml:"required" json:"required"`
+	Patterns      []string `yaml:"patterns" json:"patterns,omitempty"`
+	AllowedStates []string `yaml:"allowed_states" json:"allowed_states,omitempty"`
+}
+
+//CompilePatterns returns the patterns used to find the ticket keys, the default pattern is used when none are given
+func (r *TicketRules) CompilePatterns() ([]*regexp.Regexp, error) {
+	patterns := r.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{DefaultTicketPattern}
+	}
+
+	result := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, compiled)
+	}
+	return result, nil
+}
+
+//IsAllowedState returns true if a ticket in the state meets the rules, the state isn't case sensitive
+func (r *TicketRules) IsAllowedState(state string) bool {
+	if len(r.AllowedStates) == 0 {
+		return true
+	}
+	for _, allowed := range r.AllowedStates {
+		if strings.EqualFold(allowed, state) {
+			return true
+		}
+	}
+	return false
+}
+
+//SignatureRules defines whether each commit must be signed with a signature Github has verified
+//commits by the exempt authors, such as bots that can't sign their commits, don't need to be signed
+type SignatureRules struct {
+	Required bool     `yaml:"required" json:"required"`
+	Exempt   []string `yaml:"exempt" json:"exempt,omitempty"`
+}
+
+//IsExempt returns true if the author, given by their Github login or commit email, doesn't need to sign their commits
+//the authors aren't case sensitive and are matched exactly as bot logins such as dependabot[bot] aren't valid patterns
+func (r *SignatureRules) IsExempt(login string, email string) bool {
+	for _, exempt := range r.Exempt {
+		if (login != "" && strings.EqualFold(exempt, login)) || (email != "" && strings.EqualFold(exempt, email)) {
+			return true
+		}
+	}
+	return false
+}
+
+//RubberStampRules defines when an approval looks like a rubber stamp rather than a real review, approvals are only checked when enabled
+//the signals are an approval within the min review seconds of the PR being opened, a PR changing at least the large change lines
+//and the approver leaving fewer than the min review comments on the changes, a zero threshold turns its signal off
+//an approval is suspicious once at least the min signals are seen, zero is taken as one, so by default no single signal is enough
+type RubberStampRules struct {
+	Enabled           bool  `yaml:"enabled" json:"enabled"`
+	MinReviewSeconds  int64 `yaml:"min_review_seconds" json:"min_review_seconds"`
+	LargeChangeLines  int64 `yaml:"large_change_lines" json:"large_change_lines"`
+	MinReviewComments int   `yaml:"min_review_comments" json:"min_review_comments"`
+	MinSignals        int   `yaml:"min_signals" json:"min_signals"`
+}
+
+//CountSignals returns how many of the signals are turned on
+func (r *RubberStampRules) CountSignals() int {
+	result := 0
+	if r.MinReviewSeconds > 0 {
+		result++
+	}
+	if r.LargeChangeLines > 0 {
+		result++
+	}
+	if r.MinReviewComments > 0 {
+		result++
+	}
+	return result
+}
+
+//RepoPolicy overrides the default policy for the repos matching the pattern, such as myorg/* or myorg/myrepo
+//an override replaces the whole of the default policy
+type RepoPolicy struct {
+	Repo         string `yaml:"repo" json:"repo"`
+	ReviewPolicy `yaml:",inline"`
+}
+
+//Policies holds the default policy along with the overrides for particular repos
+type Policies struct {
+	Default ReviewPolicy `yaml:"default" json:"default"`
+	Repos   []RepoPolicy `yaml:"repos" json:"repos"`
+}
+
+//NewDefaultPolicies returns the policies used when none are configured
+//a single approval is needed, approvals of earlier commits don't count, a PR changing 1000 lines or more is large
+//and any commit message is allowed, once enabled an approval is a rubber stamp if it has two of the signals
+func NewDefaultPolicies() *Policies {
+	return &Policies{Default: ReviewPolicy{
+		RequiredApprovals:     1,
+		DismissStaleApprovals: true,
+		LargeChangeLines:      1000,
+		CommitMessages:        CommitMessageRules{Format: MessageFormatAny},
+		RubberStamps:          RubberStampRules{MinReviewSeconds: 300, LargeChangeLines: 500, MinReviewComments: 1, MinSignals: 2},
+	}}
+}
+
+//ForRepo returns the policy of the repo, the first override matching the repo is used and the default otherwise
+func (p *Policies) ForRepo(owner string, repo string) ReviewPolicy {
+	name := strings.ToLower(owner + "/" + repo)
+	for _, repoPolicy := range p.Repos {
+		if matched, _ := path.Match(strings.ToLower(repoPolicy.Repo), name); matched {
+			return repoPolicy.ReviewPolicy
+		}
+	}
+	return p.Default
+}
@@ -0,0 +1,87 @@
+package policydomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultPolicies(t *testing.T) {
+	policies := NewDefaultPolicies()
+	assert.EqualValues(t, 1, policies.Default.RequiredApprovals)
+	assert.True(t, policies.Default.DismissStaleApprovals)
+	assert.False(t, policies.Default.RequireCodeOwnerReview)
+	assert.EqualValues(t, 1000, policies.Default.LargeChangeLines)
+	assert.EqualValues(t, MessageFormatAny, policies.Default.CommitMessages.Format)
+	assert.True(t, policies.Default.CommitMessages.IsEmpty())
+	assert.False(t, policies.Default.RubberStamps.Enabled)
+	assert.EqualValues(t, 2, policies.Default.RubberStamps.MinSignals)
+	assert.EqualValues(t, 0, len(policies.Repos))
+}
+
+func TestRubberStampRulesCountSignals(t *testing.T) {
+	assert.EqualValues(t, 0, (&RubberStampRules{Enabled: true, MinSignals: 1}).CountSignals())
+	assert.EqualValues(t, 2, (&RubberStampRules{MinReviewSeconds: 60, MinReviewComments: 1}).CountSignals())
+	assert.EqualValues(t, 3, NewDefaultPolicies().Default.RubberStamps.CountSignals())
+}
+
+func TestCommitMessageRulesIsEmpty(t *testing.T) {
+	assert.True(t, (&CommitMessageRules{}).IsEmpty())
+	assert.True(t, (&CommitMessageRules{Format: MessageFormatAny, Types: []string{"feat"}}).IsEmpty())
+	assert.False(t, (&CommitMessageRules{Format: MessageFormatConventional}).IsEmpty())
+	assert.False(t, (&CommitMessageRules{MaxSubjectLength: 72}).IsEmpty())
+	assert.False(t, (&CommitMessageRules{RequireSignOff: true}).IsEmpty())
+}
+
+func TestTicketRulesCompilePatterns(t *testing.T) {
+	patterns, err := (&TicketRules{}).CompilePatterns()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(patterns))
+	assert.EqualValues(t, "PAY-123", patterns[0].FindString("fix: PAY-123 handle refunds"))
+	assert.EqualValues(t, "", patterns[0].FindString("support UTF8 and pay-123"))
+
+	patterns, err = (&TicketRules{Patterns: []string{`#(\d+)`, `OPS-\d+`}}).CompilePatterns()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(patterns))
+
+	patterns, err = (&TicketRules{Patterns: []string{`(`}}).CompilePatterns()
+	assert.Nil(t, patterns)
+	assert.NotNil(t, err)
+}
+
+func TestTicketRulesIsAllowedState(t *testing.T) {
+	assert.True(t, (&TicketRules{}).IsAllowedState("Done"))
+	rules := &TicketRules{AllowedStates: []string{"In Progress", "In Review"}}
+	assert.True(t, rules.IsAllowedState("in review"))
+	assert.False(t, rules.IsAllowedState("Done"))
+	assert.False(t, rules.IsAllowedState(""))
+}
+
+func TestSignatureRulesIsExempt(t *testing.T) {
+	rules := &SignatureRules{Required: true, Exempt: []string{"dependabot[bot]", "ci@example.com"}}
+	assert.True(t, rules.IsExempt("Dependabot[bot]", ""))
+	assert.True(t, rules.IsExempt("", "CI@example.com"))
+	assert.False(t, rules.IsExempt("dependabotb", "someone@example.com"))
+	assert.False(t, rules.IsExempt("", ""))
+	assert.False(t, (&SignatureRules{}).IsExempt("dependabot[bot]", ""))
+}
+
+func TestGetBranchProtectionBaseline(t *testing.T) {
+	policy := ReviewPolicy{RequiredApprovals: 2, DismissStaleApprovals: true, EnforceAdmins: true, RequiredStatusChecks: []string{"ci/build"}, LargeChangeLines: 500}
+	assert.EqualValues(t, BranchProtectionBaseline{RequiredApprovals: 2, DismissStaleApprovals: true, EnforceAdmins: true, RequiredStatusChecks: []string{"ci/build"}}, policy.GetBranchProtectionBaseline())
+}
+
+func TestForRepo(t *testing.T) {
+	policies := &Policies{
+		Default: ReviewPolicy{RequiredApprovals: 1},
+		Repos: []RepoPolicy{
+			{Repo: "myorg/payments", ReviewPolicy: ReviewPolicy{RequiredApprovals: 3}},
+			{Repo: "myorg/*", ReviewPolicy: ReviewPolicy{RequiredApprovals: 2, RequiredStatusChecks: []string{"ci/build"}}},
+		},
+	}
+
+	assert.EqualValues(t, 3, policies.ForRepo("MyOrg", "Payments").RequiredApprovals)
+	assert.EqualValues(t, 2, policies.ForRepo("myorg", "web").RequiredApprovals)
+	assert.EqualValues(t, []string{"ci/build"}, policies.ForRepo("myorg", "web").RequiredStatusChecks)
+	assert.EqualValues(t, 1, policies.ForRepo("otherorg", "web").RequiredApprovals)
+}
@@ -0,0 +1,170 @@
+package reportdomain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+)
+
+//the settings the branch protection of a repo is compared against the baseline on
+const (
+	ProtectionSettingProtected     = "protected"
+	ProtectionSettingApprovals     = "required_approvals"
+	ProtectionSettingDismissStale  = "dismiss_stale_approvals"
+	ProtectionSettingCodeOwners    = "require_code_owner_review"
+	ProtectionSettingEnforceAdmins = "enforce_admins"
+	ProtectionSettingStatusChecks  = "required_status_checks"
+)
+
+//BranchProtectionReport compares the protection of the default branch of a repo against the baseline of its policy
+//the default branch should always be protected, the other settings are only checked when the baseline asks for them
+//the repo has drifted from the baseline if any setting doesn't meet it
+type BranchProtectionReport struct {
+	Owner      string                                `json:"owner"`
+	Repo       string                                `json:"repo"`
+	Branch     string                                `json:"branch"`
+	Baseline   policydomain.BranchProtectionBaseline `json:"baseline"`
+	Protected  bool                                  `json:"protected"`
+	Protection *githubdomain.BranchProtection        `json:"protection,omitempty"`
+	Compliant  bool                                  `json:"compliant"`
+	Settings   []RequirementResult                   `json:"settings"`
+	Drift      []string                              `json:"drift"`
+	Error      string                                `json:"error,omitempty"`
+}
+
+//NewBranchProtectionReport compares the protection of the branch against the baseline, a nil protection means the branch isn't protected
+func NewBranchProtectionReport(owner string, repo string, branch string, protection *githubdomain.BranchProtection, baseline policydomain.BranchProtectionBaseline) *BranchProtectionReport {
+	report := &BranchProtectionReport{
+		Owner:      owner,
+		Repo:       repo,
+		Branch:     branch,
+		Baseline:   baseline,
+		Protected:  protection != nil,
+		Protection: protection,
+		Compliant:  true,
+		Settings:   make([]RequirementResult, 0),
+		Drift:      make([]string, 0),
+	}
+	if protection == nil {
+		report.addSetting(ProtectionSettingProtected, false, fmt.Sprintf("the branch %s isn't protected", branch))
+		protection = &githubdomain.BranchProtection{}
+	} else {
+		report.addSetting(ProtectionSettingProtected, true, fmt.Sprintf("the branch %s is protected", branch))
+	}
+
+	approvals := protection.GetRequiredApprovals()
+	report.addSetting(ProtectionSettingApprovals, approvals >= baseline.RequiredApprovals,
+		fmt.Sprintf("%d required approvals, the baseline is %d", approvals, baseline.RequiredApprovals))
+	if baseline.DismissStaleApprovals {
+		report.addEnabledSetting(ProtectionSettingDismissStale, protection.IsDismissingStaleReviews(), "stale approvals are dismissed", "stale approvals aren't dismissed")
+	}
+	if baseline.RequireCodeOwnerReview {
+		report.addEnabledSetting(ProtectionSettingCodeOwners, protection.IsRequiringCodeOwnerReviews(), "code owner reviews are required", "code owner reviews aren't required")
+	}
+	if baseline.EnforceAdmins {
+		report.addEnabledSetting(ProtectionSettingEnforceAdmins, protection.IsEnforcedForAdmins(), "admins have to follow the protection", "admins can bypass the protection")
+	}
+	if len(baseline.RequiredStatusChecks) > 0 {
+		var missing []string
+		for _, check := range baseline.RequiredStatusChecks {
+			if !containsString(protection.GetRequiredStatusChecks(), check) {
+				missing = append(missing, check)
+			}
+		}
+		if len(missing) > 0 {
+			report.addSetting(ProtectionSettingStatusChecks, false, "required status checks missing: "+strings.Join(missing, ", "))
+		} else {
+			report.addSetting(ProtectionSettingStatusChecks, true, "every status check of the baseline is required")
+		}
+	}
+	return report
+}
+
+//NewUnknownBranchProtectionReport returns the report of a branch whose protection couldn't be read
+//nothing is known about the protection so it is neither compliant nor said to be unprotected
+func NewUnknownBranchProtectionReport(owner string, repo string, branch string, message string) *BranchProtectionReport {
+	return &BranchProtectionReport{
+		Owner:    owner,
+		Repo:     repo,
+		Branch:   branch,
+		Settings: make([]RequirementResult, 0),
+		Drift:    make([]string, 0),
+		Error:    message,
+	}
+}
+
+//IsUnknown returns true if the protection of the branch couldn't be read
+func (r *BranchProtectionReport) IsUnknown() bool {
+	return r.Error != ""
+}
+
+//addSetting records the outcome of comparing a setting, the repo has drifted from the baseline once a setting doesn't meet it
+func (r *BranchProtectionReport) addSetting(name string, satisfied bool, detail string) {
+	r.Settings = append(r.Settings, RequirementResult{Name: name, Satisfied: satisfied, Detail: detail})
+	if !satisfied {
+		r.Drift = append(r.Drift, detail)
+	}
+	r.Compliant = len(r.Drift) == 0
+}
+
+//addEnabledSetting records the outcome of a setting the baseline needs to be turned on
+func (r *BranchProtectionReport) addEnabledSetting(name string, enabled bool, enabledDetail string, disabledDetail string) {
+	if enabled {
+		r.addSetting(name, true, enabledDetail)
+	} else {
+		r.addSetting(name, false, disabledDetail)
+	}
+}
+
+//OrgBranchProtectionReport compares the branch protection of every repo in an organisation against their baselines
+//the drift by setting counts the repos that don't meet the baseline for each setting, repos whose protection
+//couldn't be read are counted as unknown
+type OrgBranchProtectionReport struct {
+	Owner            string                   `json:"owner"`
+	TotalRepos       int                      `json:"total_repos"`
+	TotalCompliant   int                      `json:"total_compliant"`
+	TotalWithDrift   int                      `json:"total_with_drift"`
+	TotalUnprotected int                      `json:"total_unprotected"`
+	TotalUnknown     int                      `json:"total_unknown"`
+	DriftBySetting   map[string]int           `json:"drift_by_setting"`
+	Repos            []BranchProtectionReport `json:"repos"`
+}
+
+//NewOrgBranchProtectionReport returns an empty report for the organisation
+func NewOrgBranchProtectionReport(owner string) *OrgBranchProtectionReport {
+	return &OrgBranchProtectionReport{Owner: owner, DriftBySetting: make(map[string]int), Repos: make([]BranchProtectionReport, 0)}
+}
+
+//AddRepo adds the report of a repo and counts it towards the totals of the organisation
+func (r *OrgBranchProtectionReport) AddRepo(report *BranchProtectionReport) {
+	r.Repos = append(r.Repos, *report)
+	r.TotalRepos++
+	if report.IsUnknown() {
+		r.TotalUnknown++
+		return
+	}
+	if report.Compliant {
+		r.TotalCompliant++
+	} else {
+		r.TotalWithDrift++
+	}
+	if !report.Protected {
+		r.TotalUnprotected++
+	}
+	for _, setting := range report.Settings {
+		if !setting.Satisfied {
+			r.DriftBySetting[setting.Name]++
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package reportdomain
+
+import (
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestBaseline() policydomain.BranchProtectionBaseline {
+	return policydomain.BranchProtectionBaseline{RequiredApprovals: 2, DismissStaleApprovals: true, RequireCodeOwnerReview: true, EnforceAdmins: true, RequiredStatusChecks: []string{"ci/build", "ci/test"}}
+}
+
+func TestNewBranchProtectionReportCompliant(t *testing.T) {
+	protection := &githubdomain.BranchProtection{
+		RequiredStatusChecks:       &githubdomain.RequiredStatusChecks{Contexts: []string{"ci/test", "ci/build", "ci/lint"}},
+		EnforceAdmins:              &githubdomain.ProtectionSetting{Enabled: true},
+		RequiredPullRequestReviews: &githubdomain.RequiredPullRequestReviews{DismissStaleReviews: true, RequireCodeOwnerReviews: true, RequiredApprovingReviewCount: 3},
+	}
+
+	report := NewBranchProtectionReport("myorg", "myrepo", "main", protection, getTestBaseline())
+	assert.True(t, report.Protected)
+	assert.True(t, report.Compliant)
+	assert.EqualValues(t, 0, len(report.Drift))
+	assert.EqualValues(t, 6, len(report.Settings))
+	assert.EqualValues(t, ProtectionSettingApprovals, report.Settings[1].Name)
+	assert.EqualValues(t, "3 required approvals, the baseline is 2", report.Settings[1].Detail)
+}
+
+func TestNewBranchProtectionReportDrift(t *testing.T) {
+	protection := &githubdomain.BranchProtection{
+		RequiredStatusChecks:       &githubdomain.RequiredStatusChecks{Contexts: []string{"ci/build"}},
+		RequiredPullRequestReviews: &githubdomain.RequiredPullRequestReviews{RequireCodeOwnerReviews: true, RequiredApprovingReviewCount: 1},
+	}
+
+	report := NewBranchProtectionReport("myorg", "myrepo", "main", protection, getTestBaseline())
+	assert.True(t, report.Protected)
+	assert.False(t, report.Compliant)
+	assert.EqualValues(t, []string{
+		"1 required approvals, the baseline is 2",
+		"stale approvals aren't dismissed",
+		"admins can bypass the protection",
+		"required status checks missing: ci/test",
+	}, report.Drift)
+}
+
+func TestNewBranchProtectionReportNotProtected(t *testing.T) {
+	report := NewBranchProtectionReport("myorg", "myrepo", "main", nil, policydomain.BranchProtectionBaseline{RequiredApprovals: 1})
+	assert.False(t, report.Protected)
+	assert.False(t, report.Compliant)
+	assert.Nil(t, report.Protection)
+	assert.EqualValues(t, []string{"the branch main isn't protected", "0 required approvals, the baseline is 1"}, report.Drift)
+
+	//an empty baseline still expects the branch to be protected
+	report = NewBranchProtectionReport("myorg", "myrepo", "main", nil, policydomain.BranchProtectionBaseline{})
+	assert.False(t, report.Compliant)
+	assert.EqualValues(t, []string{"the branch main isn't protected"}, report.Drift)
+}
+
+func TestOrgBranchProtectionReportAddRepo(t *testing.T) {
+	baseline := policydomain.BranchProtectionBaseline{RequiredApprovals: 1, EnforceAdmins: true}
+	report := NewOrgBranchProtectionReport("myorg")
+	report.AddRepo(NewBranchProtectionReport("myorg", "web", "main", &githubdomain.BranchProtection{
+		EnforceAdmins:              &githubdomain.ProtectionSetting{Enabled: true},
+		RequiredPullRequestReviews: &githubdomain.RequiredPullRequestReviews{RequiredApprovingReviewCount: 1},
+	}, baseline))
+	report.AddRepo(NewBranchProtectionReport("myorg", "api", "main", &githubdomain.BranchProtection{
+		RequiredPullRequestReviews: &githubdomain.RequiredPullRequestReviews{RequiredApprovingReviewCount: 1},
+	}, baseline))
+	report.AddRepo(NewBranchProtectionReport("myorg", "docs", "master", nil, baseline))
+
+	assert.EqualValues(t, 3, report.TotalRepos)
+	assert.EqualValues(t, 1, report.TotalCompliant)
+	assert.EqualValues(t, 2, report.TotalWithDrift)
+	assert.EqualValues(t, 1, report.TotalUnprotected)
+	assert.EqualValues(t, map[string]int{ProtectionSettingProtected: 1, ProtectionSettingApprovals: 1, ProtectionSettingEnforceAdmins: 2}, report.DriftBySetting)
+	assert.EqualValues(t, "docs", report.Repos[2].Repo)
+}
+
+func TestOrgBranchProtectionReportAddUnknownRepo(t *testing.T) {
+	report := NewOrgBranchProtectionReport("myorg")
+	report.AddRepo(NewBranchProtectionReport("myorg", "docs", "master", nil, policydomain.BranchProtectionBaseline{}))
+	report.AddRepo(NewUnknownBranchProtectionReport("myorg", "web", "main", "Not Found"))
+
+	assert.EqualValues(t, 2, report.TotalRepos)
+	assert.EqualValues(t, 1, report.TotalUnknown)
+	assert.EqualValues(t, 1, report.TotalUnprotected)
+	assert.EqualValues(t, 1, report.TotalWithDrift)
+	assert.EqualValues(t, map[string]int{ProtectionSettingProtected: 1}, report.DriftBySetting)
+	assert.True(t, report.Repos[1].IsUnknown())
+	assert.False(t, report.Repos[1].Compliant)
+	assert.EqualValues(t, "Not Found", report.Repos[1].Error)
+}
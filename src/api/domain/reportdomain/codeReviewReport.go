@@ -0,0 +1,546 @@
+//Package reportdomain holds the reports and metrics calculated from the Github data
+package reportdomain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+)
+
+//the review status given to each commit on the audited branch
+const (
+	ReviewStatusReviewed              = "reviewed"
+	ReviewStatusReviewedOnOtherBranch = "reviewed_on_other_branch"
+	ReviewStatusNotReviewed           = "not_reviewed"
+)
+
+//the outcome of checking the signature of each commit on the audited branch
+const (
+	SignatureStatusVerified   = "verified"
+	SignatureStatusUnverified = "unverified"
+	SignatureStatusUnsigned   = "unsigned"
+	SignatureStatusExempt     = "exempt"
+)
+
+//the reason given for a signature when Github didn't say whether it was verified
+const signatureReasonUnknown = "unknown"
+
+//the state of the required checks of the PR each reviewed commit was merged in, when the PR was merged
+//the state is unknown when a check was set again after the merge, it is reported but doesn't fail the policy
+const (
+	ChecksStatusPassed  = "passed"
+	ChecksStatusFailing = "failing"
+	ChecksStatusPending = "pending"
+	ChecksStatusUnknown = "unknown"
+)
+
+//the reasons the merge of a PR bypassed the review policy, an unknown permission means the merger may be an admin of the repo
+const (
+	BypassReasonNoApproval        = "merged without a qualifying approval"
+	BypassReasonSiteAdmin         = "merged by a site admin"
+	BypassReasonRepoAdmin         = "merged by a repo admin"
+	BypassReasonPermissionUnknown = "merged by someone whose permission on the repo is unknown"
+)
+
+//CodeReviewReport summarises whether the commits on a branch of a repo were merged via a PR into that branch
+//a report limited to paths only covers the commits changing files matching at least one of the path patterns
+//the tickets referenced by each commit and the signature of each commit are only checked when the policy of the repo requires them
+//the required checks are only checked for reviewed commits, when the policy of the repo has some
+//the approvals of the PRs merging reviewed commits are only checked for rubber stamps when the policy of the repo enables it
+//and the merges of those PRs are only checked for bypassing the policy when the policy of the repo enables it
+type CodeReviewReport struct {
+	Owner                               string               `json:"owner"`
+	Repo                                string               `json:"repo"`
+	Branch                              string               `json:"branch"`
+	FromDate                            time.Time            `json:"from_date"`
+	ToDate                              time.Time            `json:"to_date"`
+	Paths                               []string             `json:"paths,omitempty"`
+	TotalCommits                        int                  `json:"total_commits"`
+	TotalMergeCommits                   int                  `json:"total_merge_commits"`
+	TotalCommitsWithPR                  int                  `json:"total_commits_with_pr"`
+	TotalCommitsReviewedOnOtherBranches int                  `json:"total_commits_reviewed_on_other_branches"`
+	TotalCommitsWithNoPR                int                  `json:"total_commits_with_no_pr"`
+	TicketsChecked                      bool                 `json:"tickets_checked"`
+	TotalCommitsMissingTickets          int                  `json:"total_commits_missing_tickets,omitempty"`
+	TotalCommitsWithInvalidTickets      int                  `json:"total_commits_with_invalid_tickets,omitempty"`
+	SignaturesChecked                   bool                 `json:"signatures_checked"`
+	TotalUnsignedCommits                int                  `json:"total_unsigned_commits,omitempty"`
+	TotalUnverifiedCommits              int                  `json:"total_unverified_commits,omitempty"`
+	ChecksChecked                       bool                 `json:"checks_checked"`
+	TotalCommitsWithFailingChecks       int                  `json:"total_commits_with_failing_checks,omitempty"`
+	TotalCommitsWithPendingChecks       int                  `json:"total_commits_with_pending_checks,omitempty"`
+	TotalCommitsWithUnknownChecks       int                  `json:"total_commits_with_unknown_checks,omitempty"`
+	TotalReverts                        int                  `json:"total_reverts"`
+	TotalCherryPicks                    int                  `json:"total_cherry_picks"`
+	BypassMergesChecked                 bool                 `json:"bypass_merges_checked"`
+	TotalBypassMerges                   int                  `json:"total_bypass_merges,omitempty"`
+	BypassMerges                        []BypassMerge        `json:"bypass_merges,omitempty"`
+	RubberStampsChecked                 bool                 `json:"rubber_stamps_checked"`
+	TotalSuspiciousApprovals            int                  `json:"total_suspicious_approvals,omitempty"`
+	SuspiciousApprovals                 []SuspiciousApproval `json:"suspicious_approvals,omitempty"`
+	Commits                             []CommitReview       `json:"commits"`
+	Teams                               []TeamReviewSummary  `json:"teams,omitempty"`
+	DataFromStore                       bool                 `json:"data_from_store"`
+	DataAsOf                            time.Time            `json:"data_as_of"`
+}
+
+//CommitReview holds the outcome of the audit for a single commit
+type CommitReview struct {
+	SHA             string                        `json:"sha"`
+	Author          string                        `json:"author"`
+	AuthorID        string                        `json:"author_id"`
+	Date            time.Time                     `json:"date"`
+	Message         string                        `json:"message"`
+	IsMergeCommit   bool                          `json:"ismergecommit"`
+	ReviewStatus    string                        `json:"review_status"`
+	PullNumber      int64                         `json:"pull_number,omitempty"`
+	PullTitle       string                        `json:"pull_title,omitempty"`
+	PullBaseRef     string                        `json:"pull_base_ref,omitempty"`
+	PullAuthor      string                        `json:"pull_author,omitempty"`
+	PullAuthorID    string                        `json:"pull_author_id,omitempty"`
+	PullMergedBy    string                        `json:"pull_merged_by,omitempty"`
+	PullMergedByID  string                        `json:"pull_merged_by_id,omitempty"`
+	TicketStatus    string                        `json:"ticket_status,omitempty"`
+	Tickets         []ticketdomain.Reference      `json:"tickets,omitempty"`
+	SignatureStatus string                        `json:"signature_status,omitempty"`
+	SignatureReason string                        `json:"signature_reason,omitempty"`
+	ChecksStatus    string                        `json:"checks_status,omitempty"`
+	ChecksNotPassed []string                      `json:"checks_not_passed,omitempty"`
+	Relations       []githubdomain.CommitRelation `json:"relations,omitempty"`
+}
+
+//BypassMerge is a PR merged into the audited branch in a way that could have got around the review policy
+//an admin can merge without the approvals branch protection asks for unless the protection is enforced for admins
+type BypassMerge struct {
+	PullNumber int64     `json:"pull_number"`
+	PullTitle  string    `json:"pull_title"`
+	PullAuthor string    `json:"pull_author"`
+	MergedBy   string    `json:"merged_by"`
+	MergedByID string    `json:"merged_by_id,omitempty"`
+	MergedAt   time.Time `json:"merged_at"`
+	Approvers  []string  `json:"approvers"`
+	Reasons    []string  `json:"reasons"`
+}
+
+//TeamReviewSummary holds the headline numbers of the report for the commits written by the members of a team
+//a self-merged PR is counted against the team of its author
+type TeamReviewSummary struct {
+	Team                                string `json:"team"`
+	TotalCommits                        int    `json:"total_commits"`
+	TotalCommitsWithPR                  int    `json:"total_commits_with_pr"`
+	TotalCommitsReviewedOnOtherBranches int    `json:"total_commits_reviewed_on_other_branches"`
+	TotalCommitsWithNoPR                int    `json:"total_commits_with_no_pr"`
+	TotalSelfMergedPRs                  int    `json:"total_self_merged_prs"`
+}
+
+//Summary returns the headline numbers of the report on a single line
+func (r *CodeReviewReport) Summary() string {
+	return fmt.Sprintf("#Branch: %s, #Total Commits: %d, #Merged Commits: %d,  #Commits with PRs: %d, #Commits reviewed on other branches: %d, #Commits with No PRs: %d",
+		r.Branch, r.TotalCommits, r.TotalMergeCommits, r.TotalCommitsWithPR, r.TotalCommitsReviewedOnOtherBranches, r.TotalCommitsWithNoPR)
+}
+
+//Text returns the report as plain text
+//the summary comes first followed by a section for each category of commit that needs attention
+func (r *CodeReviewReport) Text() string {
+	var sb strings.Builder
+	sb.WriteString(r.Summary())
+	if len(r.Paths) > 0 {
+		sb.WriteString("\n#Paths: ")
+		sb.WriteString(strings.Join(r.Paths, ", "))
+	}
+	if r.TicketsChecked {
+		sb.WriteString(fmt.Sprintf("\n#Commits with no ticket: %d, #Commits with invalid tickets: %d", r.TotalCommitsMissingTickets, r.TotalCommitsWithInvalidTickets))
+	}
+	if r.SignaturesChecked {
+		sb.WriteString(fmt.Sprintf("\n#Unsigned commits: %d, #Commits with unverified signatures: %d", r.TotalUnsignedCommits, r.TotalUnverifiedCommits))
+	}
+	if r.ChecksChecked {
+		sb.WriteString(fmt.Sprintf("\n#Commits merged with failing checks: %d, #Commits merged with pending checks: %d, #Commits with checks changed after the merge: %d",
+			r.TotalCommitsWithFailingChecks, r.TotalCommitsWithPendingChecks, r.TotalCommitsWithUnknownChecks))
+	}
+	if r.BypassMergesChecked {
+		sb.WriteString(fmt.Sprintf("\n#Bypass merges: %d", r.TotalBypassMerges))
+	}
+	if r.RubberStampsChecked {
+		sb.WriteString(fmt.Sprintf("\n#Suspicious approvals: %d", r.TotalSuspiciousApprovals))
+	}
+	if r.DataFromStore || !r.DataAsOf.IsZero() {
+		sb.WriteString("\n")
+		sb.WriteString(r.Freshness())
+	}
+
+	writeTeamSection(&sb, r.Teams)
+	writeCommitSection(&sb, "Commits reviewed only on other branches:", r.CommitsWithStatus(ReviewStatusReviewedOnOtherBranch))
+	writeCommitSection(&sb, "Commits with no PR:", r.CommitsWithStatus(ReviewStatusNotReviewed))
+	writeCommitSection(&sb, "Commits with no ticket:", r.CommitsWithTicketStatus(ticketdomain.StatusMissing))
+	writeCommitDetailSection(&sb, "Commits with invalid tickets:", r.CommitsWithTicketStatus(ticketdomain.StatusInvalid), func(commit *CommitReview) string {
+		return strings.Join(ticketdomain.Problems(commit.Tickets), "; ")
+	})
+	writeCommitDetailSection(&sb, "Reverts:", r.CommitsWithRelation(githubdomain.RelationRevert), func(commit *CommitReview) string {
+		return "reverts " + commit.describeRelations(githubdomain.RelationRevert)
+	})
+	writeCommitDetailSection(&sb, "Cherry-picks:", r.CommitsWithRelation(githubdomain.RelationCherryPick), func(commit *CommitReview) string {
+		return "cherry picked from " + commit.describeRelations(githubdomain.RelationCherryPick)
+	})
+	writeBypassSection(&sb, r.BypassMerges)
+	writeSuspiciousApprovalSection(&sb, r.SuspiciousApprovals)
+	writeCommitSection(&sb, "Unsigned commits:", r.CommitsWithSignature(SignatureStatusUnsigned))
+	writeCommitDetailSection(&sb, "Commits with unverified signatures:", r.CommitsWithSignature(SignatureStatusUnverified), func(commit *CommitReview) string {
+		return commit.SignatureReason
+	})
+	writeCommitDetailSection(&sb, "Commits merged with failing checks:", r.CommitsWithChecksStatus(ChecksStatusFailing), func(commit *CommitReview) string {
+		return strings.Join(commit.ChecksNotPassed, ", ")
+	})
+	writeCommitDetailSection(&sb, "Commits merged with pending checks:", r.CommitsWithChecksStatus(ChecksStatusPending), func(commit *CommitReview) string {
+		return strings.Join(commit.ChecksNotPassed, ", ")
+	})
+	writeCommitDetailSection(&sb, "Commits with checks changed after the merge, their state at the merge is unknown:", r.CommitsWithChecksStatus(ChecksStatusUnknown), func(commit *CommitReview) string {
+		return strings.Join(commit.ChecksNotPassed, ", ")
+	})
+
+	return sb.String()
+}
+
+//Freshness describes how up to date the data behind the report is
+//data read from the store is as fresh as the last sync, which isn't known if the repo has never been synced
+func (r *CodeReviewReport) Freshness() string {
+	asOf := "unknown"
+	if !r.DataAsOf.IsZero() {
+		asOf = r.DataAsOf.UTC().Format(time.RFC3339)
+	}
+	if r.DataFromStore {
+		return fmt.Sprintf("#Data as of: %s (read from the data store)", asOf)
+	}
+	return fmt.Sprintf("#Data as of: %s", asOf)
+}
+
+//CommitsWithStatus returns the commits in the report with the given review status
+func (r *CodeReviewReport) CommitsWithStatus(reviewStatus string) []CommitReview {
+	var result []CommitReview
+	for _, commit := range r.Commits {
+		if commit.ReviewStatus == reviewStatus {
+			result = append(result, commit)
+		}
+	}
+	return result
+}
+
+//CommitsWithTicketStatus returns the commits in the report with the given outcome of checking their tickets
+func (r *CodeReviewReport) CommitsWithTicketStatus(ticketStatus string) []CommitReview {
+	var result []CommitReview
+	for _, commit := range r.Commits {
+		if commit.TicketStatus == ticketStatus {
+			result = append(result, commit)
+		}
+	}
+	return result
+}
+
+//SetTickets records the tickets referenced by the commit and counts it towards the totals of the report
+func (r *CodeReviewReport) SetTickets(commit *CommitReview, references []ticketdomain.Reference) {
+	r.TicketsChecked = true
+	commit.Tickets = references
+	commit.TicketStatus = ticketdomain.GetStatus(references)
+	switch commit.TicketStatus {
+	case ticketdomain.StatusMissing:
+		r.TotalCommitsMissingTickets++
+	case ticketdomain.StatusInvalid:
+		r.TotalCommitsWithInvalidTickets++
+	}
+}
+
+//CommitsWithSignature returns the commits in the report with the given outcome of checking their signature
+func (r *CodeReviewReport) CommitsWithSignature(signatureStatus string) []CommitReview {
+	var result []CommitReview
+	for _, commit := range r.Commits {
+		if commit.SignatureStatus == signatureStatus {
+			result = append(result, commit)
+		}
+	}
+	return result
+}
+
+//SetSignature records whether the commit was signed and Github verified the signature and counts it towards the totals of the report
+//a commit without any verification is counted as unverified as Github didn't say whether it was signed
+func (r *CodeReviewReport) SetSignature(commit *CommitReview, verification *githubdomain.CommitVerification, exempt bool) {
+	r.SignaturesChecked = true
+	switch {
+	case exempt:
+		commit.SignatureStatus = SignatureStatusExempt
+	case verification == nil:
+		commit.SignatureStatus = SignatureStatusUnverified
+		commit.SignatureReason = signatureReasonUnknown
+	case !verification.IsSigned():
+		commit.SignatureStatus = SignatureStatusUnsigned
+	case !verification.Verified:
+		commit.SignatureStatus = SignatureStatusUnverified
+		commit.SignatureReason = verification.Reason
+	default:
+		commit.SignatureStatus = SignatureStatusVerified
+	}
+
+	switch commit.SignatureStatus {
+	case SignatureStatusUnsigned:
+		r.TotalUnsignedCommits++
+	case SignatureStatusUnverified:
+		r.TotalUnverifiedCommits++
+	}
+}
+
+//CommitsWithChecksStatus returns the commits in the report with the given state of the required checks of their PR
+func (r *CodeReviewReport) CommitsWithChecksStatus(checksStatus string) []CommitReview {
+	var result []CommitReview
+	for _, commit := range r.Commits {
+		if commit.ChecksStatus == checksStatus {
+			result = append(result, commit)
+		}
+	}
+	return result
+}
+
+//SetChecks records the state of the required checks on the head commit of the PR when it was merged and counts it towards the totals of the report
+//a check the head commit had neither a status nor a check run for is counted as pending, as Github shows it as expected
+//a failing check outweighs a pending one, which outweighs a check that was set again after the merge so its state at the merge is unknown
+func (r *CodeReviewReport) SetChecks(commit *CommitReview, checks *githubdomain.CommitChecks, requiredChecks []string, mergedAt time.Time) {
+	r.ChecksChecked = true
+	commit.ChecksStatus = ChecksStatusPassed
+	commit.ChecksNotPassed = nil
+	for _, check := range requiredChecks {
+		state := checks.GetStateAt(check, mergedAt)
+		switch state {
+		case githubdomain.StatusStateSuccess:
+			continue
+		case githubdomain.StatusStateFailure, githubdomain.StatusStateError:
+			commit.ChecksStatus = ChecksStatusFailing
+		case githubdomain.StatusStateUnknown:
+			if commit.ChecksStatus == ChecksStatusPassed {
+				commit.ChecksStatus = ChecksStatusUnknown
+			}
+		case "":
+			state = "missing"
+			fallthrough
+		default:
+			if commit.ChecksStatus == ChecksStatusPassed || commit.ChecksStatus == ChecksStatusUnknown {
+				commit.ChecksStatus = ChecksStatusPending
+			}
+		}
+		commit.ChecksNotPassed = append(commit.ChecksNotPassed, fmt.Sprintf("%s (%s)", check, state))
+	}
+
+	switch commit.ChecksStatus {
+	case ChecksStatusFailing:
+		r.TotalCommitsWithFailingChecks++
+	case ChecksStatusPending:
+		r.TotalCommitsWithPendingChecks++
+	case ChecksStatusUnknown:
+		r.TotalCommitsWithUnknownChecks++
+	}
+}
+
+//CommitsWithRelation returns the commits in the report that revert, or were cherry picked from, an earlier commit
+func (r *CodeReviewReport) CommitsWithRelation(relationType string) []CommitReview {
+	var result []CommitReview
+	for _, commit := range r.Commits {
+		if commit.HasRelation(relationType) {
+			result = append(result, commit)
+		}
+	}
+	return result
+}
+
+//SetRelations records the earlier commits the commit reverts or was cherry picked from and counts it towards the totals of the report
+func (r *CodeReviewReport) SetRelations(commit *CommitReview, relations []githubdomain.CommitRelation) {
+	commit.Relations = relations
+	if commit.HasRelation(githubdomain.RelationRevert) {
+		r.TotalReverts++
+	}
+	if commit.HasRelation(githubdomain.RelationCherryPick) {
+		r.TotalCherryPicks++
+	}
+}
+
+//AddBypassMerge records a PR merged by bypassing the review policy, each PR is only recorded once however many of its commits are on the branch
+func (r *CodeReviewReport) AddBypassMerge(bypass BypassMerge) {
+	for _, existing := range r.BypassMerges {
+		if existing.PullNumber == bypass.PullNumber {
+			return
+		}
+	}
+	r.BypassMerges = append(r.BypassMerges, bypass)
+	r.TotalBypassMerges++
+}
+
+//AddSuspiciousApproval records an approval that looks like a rubber stamp, each approval of a PR is only recorded once however many of its commits are on the branch
+func (r *CodeReviewReport) AddSuspiciousApproval(approval SuspiciousApproval) {
+	for _, existing := range r.SuspiciousApprovals {
+		if existing.PullNumber == approval.PullNumber && existing.Approver == approval.Approver {
+			return
+		}
+	}
+	r.SuspiciousApprovals = append(r.SuspiciousApprovals, approval)
+	r.TotalSuspiciousApprovals++
+}
+
+//SetTeams breaks the report down by the teams in the directory, a person in several teams counts towards each of them
+func (r *CodeReviewReport) SetTeams(directory *teamdomain.Directory) {
+	summaries := make(map[string]*TeamReviewSummary)
+	r.Teams = make([]TeamReviewSummary, 0)
+	for _, name := range directory.TeamNames() {
+		r.Teams = append(r.Teams, TeamReviewSummary{Team: name})
+	}
+	for counter := range r.Teams {
+		summaries[r.Teams[counter].Team] = &r.Teams[counter]
+	}
+
+	selfMerged := make(map[int64]bool)
+	for counter := range r.Commits {
+		commit := &r.Commits[counter]
+		for _, team := range directory.TeamsOf(commit.AuthorID) {
+			summary := summaries[team]
+			summary.TotalCommits++
+			switch commit.ReviewStatus {
+			case ReviewStatusReviewed:
+				summary.TotalCommitsWithPR++
+			case ReviewStatusReviewedOnOtherBranch:
+				summary.TotalCommitsReviewedOnOtherBranches++
+			default:
+				summary.TotalCommitsWithNoPR++
+			}
+		}
+
+		if commit.IsSelfMerged() && !selfMerged[commit.PullNumber] {
+			selfMerged[commit.PullNumber] = true
+			for _, team := range directory.TeamsOf(commit.PullAuthorID) {
+				summaries[team].TotalSelfMergedPRs++
+			}
+		}
+	}
+}
+
+//Text returns a single line with the headline numbers of the team
+func (s *TeamReviewSummary) Text() string {
+	return fmt.Sprintf("%s: #Total Commits: %d, #Commits with PRs: %d, #Commits reviewed on other branches: %d, #Commits with No PRs: %d, #Self-merged PRs: %d",
+		s.Team, s.TotalCommits, s.TotalCommitsWithPR, s.TotalCommitsReviewedOnOtherBranches, s.TotalCommitsWithNoPR, s.TotalSelfMergedPRs)
+}
+
+func writeTeamSection(sb *strings.Builder, teams []TeamReviewSummary) {
+	if len(teams) == 0 {
+		return
+	}
+
+	sb.WriteString("\n\nBy team:")
+	for counter := range teams {
+		sb.WriteString("\n")
+		sb.WriteString(teams[counter].Text())
+	}
+}
+
+func writeBypassSection(sb *strings.Builder, bypassMerges []BypassMerge) {
+	if len(bypassMerges) == 0 {
+		return
+	}
+
+	sb.WriteString("\n\nBypass merges:")
+	for counter := range bypassMerges {
+		sb.WriteString("\n")
+		sb.WriteString(bypassMerges[counter].Text())
+	}
+}
+
+func writeSuspiciousApprovalSection(sb *strings.Builder, approvals []SuspiciousApproval) {
+	if len(approvals) == 0 {
+		return
+	}
+
+	sb.WriteString("\n\nSuspicious approvals:")
+	for counter := range approvals {
+		sb.WriteString("\n")
+		sb.WriteString(approvals[counter].Text())
+	}
+}
+
+func writeCommitSection(sb *strings.Builder, heading string, commits []CommitReview) {
+	if len(commits) == 0 {
+		return
+	}
+
+	sb.WriteString("\n\n")
+	sb.WriteString(heading)
+	for _, commit := range commits {
+		sb.WriteString("\n")
+		sb.WriteString(commit.Text())
+	}
+}
+
+//writeCommitDetailSection lists the commits along with the detail of what is wrong with each of them
+func writeCommitDetailSection(sb *strings.Builder, heading string, commits []CommitReview, detail func(commit *CommitReview) string) {
+	if len(commits) == 0 {
+		return
+	}
+
+	sb.WriteString("\n\n")
+	sb.WriteString(heading)
+	for counter := range commits {
+		sb.WriteString("\n")
+		sb.WriteString(commits[counter].Text())
+		sb.WriteString(": ")
+		sb.WriteString(detail(&commits[counter]))
+	}
+}
+
+//Text returns a single line describing the commit
+func (c *CommitReview) Text() string {
+	line := fmt.Sprintf("%s %s %s %s", c.SHA, c.Date.UTC().Format(time.RFC3339), c.Author, firstLine(c.Message))
+	if c.PullNumber > 0 {
+		line = fmt.Sprintf("%s (PR #%d into %s)", line, c.PullNumber, c.PullBaseRef)
+	}
+	return line
+}
+
+//Text returns a single line describing the PR along with why its merge bypassed the review policy
+func (b *BypassMerge) Text() string {
+	return fmt.Sprintf("PR #%d %s merged by %s at %s: %s", b.PullNumber, b.PullTitle, b.MergedBy, b.MergedAt.UTC().Format(time.RFC3339), strings.Join(b.Reasons, ", "))
+}
+
+//HasRelation returns true if the commit reverts, or was cherry picked from, an earlier commit
+func (c *CommitReview) HasRelation(relationType string) bool {
+	for _, relation := range c.Relations {
+		if relation.Type == relationType {
+			return true
+		}
+	}
+	return false
+}
+
+//describeRelations lists the earlier commits related to the commit along with the PR each came from
+func (c *CommitReview) describeRelations(relationType string) string {
+	var described []string
+	for _, relation := range c.Relations {
+		if relation.Type != relationType {
+			continue
+		}
+		switch {
+		case !relation.Found:
+			described = append(described, shortSHA(relation.SHA)+" (not found)")
+		case relation.PullNumber > 0:
+			described = append(described, fmt.Sprintf("%s (PR #%d)", shortSHA(relation.SHA), relation.PullNumber))
+		default:
+			described = append(described, shortSHA(relation.SHA)+" (no PR)")
+		}
+	}
+	return strings.Join(described, ", ")
+}
+
+//IsSelfMerged returns true if the PR the commit came from was merged by its own author
+func (c *CommitReview) IsSelfMerged() bool {
+	return c.PullAuthorID != "" && c.PullAuthorID == c.PullMergedByID
+}
+
+//firstLine returns the subject line of a commit message
+func firstLine(message string) string {
+	return strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+}
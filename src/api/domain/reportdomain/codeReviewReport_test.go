@@ -0,0 +1,347 @@
+package reportdomain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestCodeReviewReport() CodeReviewReport {
+	commitDate, _ := time.Parse(time.RFC3339, "2019-12-09T15:00:04Z")
+	return CodeReviewReport{
+		Owner:                               "myowner",
+		Repo:                                "myrepo",
+		Branch:                              "main",
+		TotalCommits:                        3,
+		TotalMergeCommits:                   1,
+		TotalCommitsWithPR:                  1,
+		TotalCommitsReviewedOnOtherBranches: 1,
+		TotalCommitsWithNoPR:                1,
+		Commits: []CommitReview{
+			{SHA: "AAA111", Author: "some name", Date: commitDate, Message: "reviewed commit", IsMergeCommit: true, ReviewStatus: ReviewStatusReviewed, PullNumber: 9, PullTitle: "a PR", PullBaseRef: "main"},
+			{SHA: "BBB222", Author: "some name", Date: commitDate, Message: "develop commit\n\nwith a body", ReviewStatus: ReviewStatusReviewedOnOtherBranch, PullNumber: 10, PullTitle: "another PR", PullBaseRef: "develop"},
+			{SHA: "CCC333", Author: "another name", Date: commitDate, Message: "pushed straight to main", ReviewStatus: ReviewStatusNotReviewed},
+		},
+	}
+}
+
+func TestConstants(t *testing.T) {
+	assert.EqualValues(t, "reviewed", ReviewStatusReviewed)
+	assert.EqualValues(t, "reviewed_on_other_branch", ReviewStatusReviewedOnOtherBranch)
+	assert.EqualValues(t, "not_reviewed", ReviewStatusNotReviewed)
+}
+
+func TestCodeReviewReportJSON(t *testing.T) {
+	report := getTestCodeReviewReport()
+
+	bytes, err := json.Marshal(report)
+	assert.Nil(t, err)
+	assert.NotNil(t, bytes)
+
+	var target CodeReviewReport
+
+	err = json.Unmarshal(bytes, &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, report.Owner, target.Owner)
+	assert.EqualValues(t, report.Repo, target.Repo)
+	assert.EqualValues(t, report.Branch, target.Branch)
+	assert.EqualValues(t, report.TotalCommits, target.TotalCommits)
+	assert.EqualValues(t, report.TotalMergeCommits, target.TotalMergeCommits)
+	assert.EqualValues(t, report.TotalCommitsWithPR, target.TotalCommitsWithPR)
+	assert.EqualValues(t, report.TotalCommitsReviewedOnOtherBranches, target.TotalCommitsReviewedOnOtherBranches)
+	assert.EqualValues(t, report.TotalCommitsWithNoPR, target.TotalCommitsWithNoPR)
+	assert.EqualValues(t, report.Commits, target.Commits)
+	assert.EqualValues(t, report.DataFromStore, target.DataFromStore)
+	assert.EqualValues(t, report.DataAsOf, target.DataAsOf)
+}
+
+func TestCodeReviewReportSummary(t *testing.T) {
+	report := getTestCodeReviewReport()
+	assert.EqualValues(t, "#Branch: main, #Total Commits: 3, #Merged Commits: 1,  #Commits with PRs: 1, #Commits reviewed on other branches: 1, #Commits with No PRs: 1", report.Summary())
+}
+
+func TestCodeReviewReportCommitsWithStatus(t *testing.T) {
+	report := getTestCodeReviewReport()
+	commits := report.CommitsWithStatus(ReviewStatusNotReviewed)
+	assert.EqualValues(t, 1, len(commits))
+	assert.EqualValues(t, "CCC333", commits[0].SHA)
+	assert.EqualValues(t, 0, len(report.CommitsWithStatus("unknown")))
+}
+
+func TestCodeReviewReportText(t *testing.T) {
+	report := getTestCodeReviewReport()
+	expected := report.Summary() + `
+
+Commits reviewed only on other branches:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop)
+
+Commits with no PR:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main`
+	assert.EqualValues(t, expected, report.Text())
+}
+
+func TestCodeReviewReportTextAllReviewed(t *testing.T) {
+	report := getTestCodeReviewReport()
+	report.Commits = report.Commits[:1]
+	assert.EqualValues(t, report.Summary(), report.Text())
+}
+
+func TestCodeReviewReportTextForPaths(t *testing.T) {
+	report := getTestCodeReviewReport()
+	report.Commits = report.Commits[:1]
+	report.Paths = []string{"services/payments/", "*.go"}
+	assert.EqualValues(t, report.Summary()+"\n#Paths: services/payments/, *.go", report.Text())
+}
+
+func TestCodeReviewReportSetTickets(t *testing.T) {
+	report := getTestCodeReviewReport()
+	report.SetTickets(&report.Commits[0], []ticketdomain.Reference{{Key: "PAY-12", Source: ticketdomain.SourcePullTitle, Valid: true}})
+	report.SetTickets(&report.Commits[1], nil)
+	report.SetTickets(&report.Commits[2], []ticketdomain.Reference{
+		{Key: "PAY-13", Source: ticketdomain.SourceCommitMessage, Status: "Done", Problem: "the ticket PAY-13 is Done, expected one of In Progress"},
+		{Key: "UTF-8", Source: ticketdomain.SourceCommitMessage, Problem: "the ticket UTF-8 doesn't exist"},
+	})
+
+	assert.True(t, report.TicketsChecked)
+	assert.EqualValues(t, ticketdomain.StatusValid, report.Commits[0].TicketStatus)
+	assert.EqualValues(t, 1, report.TotalCommitsMissingTickets)
+	assert.EqualValues(t, 1, report.TotalCommitsWithInvalidTickets)
+	assert.EqualValues(t, "BBB222", report.CommitsWithTicketStatus(ticketdomain.StatusMissing)[0].SHA)
+
+	report.Commits = report.Commits[1:]
+	expected := report.Summary() + `
+#Commits with no ticket: 1, #Commits with invalid tickets: 1
+
+Commits reviewed only on other branches:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop)
+
+Commits with no PR:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main
+
+Commits with no ticket:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop)
+
+Commits with invalid tickets:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main: the ticket PAY-13 is Done, expected one of In Progress; the ticket UTF-8 doesn't exist`
+	assert.EqualValues(t, expected, report.Text())
+}
+
+func TestCodeReviewReportSetSignature(t *testing.T) {
+	report := getTestCodeReviewReport()
+	report.Commits = append(report.Commits, CommitReview{SHA: "DDD444", Author: "dependabot", Date: report.Commits[0].Date, Message: "bump yaml", ReviewStatus: ReviewStatusReviewed, PullNumber: 11, PullBaseRef: "main"})
+	report.SetSignature(&report.Commits[0], &githubdomain.CommitVerification{Verified: true, Reason: githubdomain.VerificationReasonValid}, false)
+	report.SetSignature(&report.Commits[1], &githubdomain.CommitVerification{Reason: "unknown_key", Signature: "sig"}, false)
+	report.SetSignature(&report.Commits[2], &githubdomain.CommitVerification{Reason: githubdomain.VerificationReasonUnsigned}, false)
+	report.SetSignature(&report.Commits[3], nil, true)
+
+	assert.True(t, report.SignaturesChecked)
+	assert.EqualValues(t, SignatureStatusVerified, report.Commits[0].SignatureStatus)
+	assert.EqualValues(t, SignatureStatusUnverified, report.Commits[1].SignatureStatus)
+	assert.EqualValues(t, "unknown_key", report.Commits[1].SignatureReason)
+	assert.EqualValues(t, SignatureStatusUnsigned, report.Commits[2].SignatureStatus)
+	assert.EqualValues(t, SignatureStatusExempt, report.Commits[3].SignatureStatus)
+	assert.EqualValues(t, 1, report.TotalUnsignedCommits)
+	assert.EqualValues(t, 1, report.TotalUnverifiedCommits)
+
+	expected := report.Summary() + `
+#Unsigned commits: 1, #Commits with unverified signatures: 1
+
+Commits reviewed only on other branches:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop)
+
+Commits with no PR:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main
+
+Unsigned commits:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main
+
+Commits with unverified signatures:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop): unknown_key`
+	assert.EqualValues(t, expected, report.Text())
+
+	commit := CommitReview{SHA: "EEE555"}
+	report.SetSignature(&commit, nil, false)
+	assert.EqualValues(t, SignatureStatusUnverified, commit.SignatureStatus)
+	assert.EqualValues(t, "unknown", commit.SignatureReason)
+}
+
+func TestCodeReviewReportSetRelations(t *testing.T) {
+	report := getTestCodeReviewReport()
+	report.SetRelations(&report.Commits[0], []githubdomain.CommitRelation{{Type: githubdomain.RelationRevert, SHA: "0123456789abcdef", Found: true, PullNumber: 7}})
+	report.SetRelations(&report.Commits[1], []githubdomain.CommitRelation{
+		{Type: githubdomain.RelationCherryPick, SHA: "fedcba9876543210", Found: true},
+		{Type: githubdomain.RelationCherryPick, SHA: "abc1234"},
+	})
+	report.SetRelations(&report.Commits[2], nil)
+
+	assert.EqualValues(t, 1, report.TotalReverts)
+	assert.EqualValues(t, 1, report.TotalCherryPicks)
+	assert.True(t, report.Commits[0].HasRelation(githubdomain.RelationRevert))
+	assert.False(t, report.Commits[0].HasRelation(githubdomain.RelationCherryPick))
+	assert.EqualValues(t, "BBB222", report.CommitsWithRelation(githubdomain.RelationCherryPick)[0].SHA)
+
+	expected := report.Summary() + `
+
+Commits reviewed only on other branches:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop)
+
+Commits with no PR:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main
+
+Reverts:
+AAA111 2019-12-09T15:00:04Z some name reviewed commit (PR #9 into main): reverts 0123456 (PR #7)
+
+Cherry-picks:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop): cherry picked from fedcba9 (no PR), abc1234 (not found)`
+	assert.EqualValues(t, expected, report.Text())
+}
+
+func TestCodeReviewReportSetChecks(t *testing.T) {
+	report := getTestCodeReviewReport()
+	mergedAt := report.Commits[0].Date
+	checks := &githubdomain.CommitChecks{
+		Statuses: &githubdomain.CombinedStatus{Statuses: []githubdomain.CommitStatus{
+			{Context: "ci/build", State: githubdomain.StatusStateSuccess},
+			{Context: "ci/test", State: githubdomain.StatusStateFailure},
+			{Context: "ci/release", State: githubdomain.StatusStateFailure, UpdatedAt: mergedAt.Add(time.Minute)},
+		}},
+		CheckRuns: []githubdomain.CheckRun{{ID: 1, Name: "lint", Status: githubdomain.CheckRunStatusInProgress}},
+	}
+	report.Commits = append(report.Commits, CommitReview{SHA: "DDD444", Author: "some name", Date: mergedAt, Message: "released commit", IsMergeCommit: true, ReviewStatus: ReviewStatusReviewed, PullNumber: 11, PullTitle: "a release", PullBaseRef: "main"})
+	report.SetChecks(&report.Commits[0], checks, []string{"ci/build", "lint", "ci/test"}, mergedAt)
+	report.SetChecks(&report.Commits[1], checks, []string{"ci/build", "lint", "ci/deploy"}, mergedAt)
+	report.SetChecks(&report.Commits[2], checks, []string{"ci/build"}, mergedAt)
+	report.SetChecks(&report.Commits[3], checks, []string{"ci/build", "ci/release"}, mergedAt)
+
+	assert.True(t, report.ChecksChecked)
+	assert.EqualValues(t, ChecksStatusFailing, report.Commits[0].ChecksStatus)
+	assert.EqualValues(t, []string{"lint (pending)", "ci/test (failure)"}, report.Commits[0].ChecksNotPassed)
+	assert.EqualValues(t, ChecksStatusPending, report.Commits[1].ChecksStatus)
+	assert.EqualValues(t, []string{"lint (pending)", "ci/deploy (missing)"}, report.Commits[1].ChecksNotPassed)
+	assert.EqualValues(t, ChecksStatusPassed, report.Commits[2].ChecksStatus)
+	assert.EqualValues(t, 0, len(report.Commits[2].ChecksNotPassed))
+	assert.EqualValues(t, 1, report.TotalCommitsWithFailingChecks)
+	assert.EqualValues(t, 1, report.TotalCommitsWithPendingChecks)
+	//the status failed after the merge, which isn't counted against the merge
+	assert.EqualValues(t, ChecksStatusUnknown, report.Commits[3].ChecksStatus)
+	assert.EqualValues(t, []string{"ci/release (unknown)"}, report.Commits[3].ChecksNotPassed)
+	assert.EqualValues(t, 1, report.TotalCommitsWithUnknownChecks)
+
+	expected := report.Summary() + `
+#Commits merged with failing checks: 1, #Commits merged with pending checks: 1, #Commits with checks changed after the merge: 1
+
+Commits reviewed only on other branches:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop)
+
+Commits with no PR:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main
+
+Commits merged with failing checks:
+AAA111 2019-12-09T15:00:04Z some name reviewed commit (PR #9 into main): lint (pending), ci/test (failure)
+
+Commits merged with pending checks:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop): lint (pending), ci/deploy (missing)
+
+Commits with checks changed after the merge, their state at the merge is unknown:
+DDD444 2019-12-09T15:00:04Z some name released commit (PR #11 into main): ci/release (unknown)`
+	assert.EqualValues(t, expected, report.Text())
+}
+
+func TestCodeReviewReportAddBypassMerge(t *testing.T) {
+	report := getTestCodeReviewReport()
+	mergedAt := report.Commits[0].Date
+	report.AddBypassMerge(BypassMerge{PullNumber: 9, PullTitle: "reviewed PR", MergedBy: "admin1", MergedAt: mergedAt, Reasons: []string{BypassReasonNoApproval, BypassReasonRepoAdmin}})
+	report.AddBypassMerge(BypassMerge{PullNumber: 9, PullTitle: "reviewed PR", MergedBy: "admin1", MergedAt: mergedAt, Reasons: []string{BypassReasonNoApproval}})
+	report.AddBypassMerge(BypassMerge{PullNumber: 12, PullTitle: "hotfix", MergedBy: "octocat", MergedAt: mergedAt, Reasons: []string{BypassReasonSiteAdmin}})
+
+	assert.EqualValues(t, 2, report.TotalBypassMerges)
+	assert.EqualValues(t, 2, len(report.BypassMerges))
+
+	expected := report.Summary() + `
+
+Commits reviewed only on other branches:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop)
+
+Commits with no PR:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main
+
+Bypass merges:
+PR #9 reviewed PR merged by admin1 at 2019-12-09T15:00:04Z: merged without a qualifying approval, merged by a repo admin
+PR #12 hotfix merged by octocat at 2019-12-09T15:00:04Z: merged by a site admin`
+	assert.EqualValues(t, expected, report.Text())
+}
+
+func TestCodeReviewReportAddSuspiciousApproval(t *testing.T) {
+	report := getTestCodeReviewReport()
+	report.RubberStampsChecked = true
+	approvedAt := report.Commits[0].Date
+	report.AddSuspiciousApproval(SuspiciousApproval{PullNumber: 9, PullTitle: "reviewed PR", Approver: "bob", ApprovedAt: approvedAt, Signals: []string{"approved 30s after the PR was opened", "no review comments"}})
+	report.AddSuspiciousApproval(SuspiciousApproval{PullNumber: 9, PullTitle: "reviewed PR", Approver: "bob", ApprovedAt: approvedAt, Signals: []string{"no review comments"}})
+	report.AddSuspiciousApproval(SuspiciousApproval{PullNumber: 9, PullTitle: "reviewed PR", Approver: "carol", ApprovedAt: approvedAt, Signals: []string{"the PR changes 2000 lines"}})
+
+	assert.EqualValues(t, 2, report.TotalSuspiciousApprovals)
+
+	expected := report.Summary() + `
+#Suspicious approvals: 2
+
+Commits reviewed only on other branches:
+BBB222 2019-12-09T15:00:04Z some name develop commit (PR #10 into develop)
+
+Commits with no PR:
+CCC333 2019-12-09T15:00:04Z another name pushed straight to main
+
+Suspicious approvals:
+PR #9 reviewed PR approved by bob at 2019-12-09T15:00:04Z: approved 30s after the PR was opened, no review comments
+PR #9 reviewed PR approved by carol at 2019-12-09T15:00:04Z: the PR changes 2000 lines`
+	assert.EqualValues(t, expected, report.Text())
+}
+
+func TestCodeReviewReportFreshness(t *testing.T) {
+	report := getTestCodeReviewReport()
+	assert.EqualValues(t, "#Data as of: unknown", report.Freshness())
+
+	report.DataAsOf, _ = time.Parse(time.RFC3339, "2019-12-10T09:00:00Z")
+	assert.EqualValues(t, "#Data as of: 2019-12-10T09:00:00Z", report.Freshness())
+
+	report.DataFromStore = true
+	assert.EqualValues(t, "#Data as of: 2019-12-10T09:00:00Z (read from the data store)", report.Freshness())
+
+	report.Commits = nil
+	assert.EqualValues(t, report.Summary()+"\n"+report.Freshness(), report.Text())
+}
+
+func TestCommitReviewIsSelfMerged(t *testing.T) {
+	commit := CommitReview{PullAuthorID: "alice", PullMergedByID: "alice"}
+	assert.True(t, commit.IsSelfMerged())
+	commit.PullMergedByID = "bob"
+	assert.False(t, commit.IsSelfMerged())
+	assert.False(t, (&CommitReview{}).IsSelfMerged())
+}
+
+func TestCodeReviewReportSetTeams(t *testing.T) {
+	report := getTestCodeReviewReport()
+	report.Commits[0].AuthorID = "alice"
+	report.Commits[0].PullAuthorID = "alice"
+	report.Commits[0].PullMergedByID = "alice"
+	report.Commits[1].AuthorID = "alice"
+	report.Commits[2].AuthorID = "bob@example.com"
+	report.Commits = append(report.Commits, CommitReview{SHA: "DDD444", AuthorID: "carol", ReviewStatus: ReviewStatusReviewed, PullNumber: 9, PullAuthorID: "alice", PullMergedByID: "alice"})
+	directory := &teamdomain.Directory{Teams: []teamdomain.Team{
+		{Name: "payments", Members: []string{"alice", "bob@example.com"}},
+		{Name: "platform", Members: []string{"alice"}},
+	}}
+
+	report.SetTeams(directory)
+	assert.EqualValues(t, 3, len(report.Teams))
+	assert.EqualValues(t, TeamReviewSummary{Team: "payments", TotalCommits: 3, TotalCommitsWithPR: 1, TotalCommitsReviewedOnOtherBranches: 1, TotalCommitsWithNoPR: 1, TotalSelfMergedPRs: 1}, report.Teams[0])
+	assert.EqualValues(t, TeamReviewSummary{Team: "platform", TotalCommits: 2, TotalCommitsWithPR: 1, TotalCommitsReviewedOnOtherBranches: 1, TotalSelfMergedPRs: 1}, report.Teams[1])
+	assert.EqualValues(t, TeamReviewSummary{Team: teamdomain.Unassigned, TotalCommits: 1, TotalCommitsWithPR: 1}, report.Teams[2])
+
+	assert.EqualValues(t, "payments: #Total Commits: 3, #Commits with PRs: 1, #Commits reviewed on other branches: 1, #Commits with No PRs: 1, #Self-merged PRs: 1", report.Teams[0].Text())
+	assert.Contains(t, report.Text(), "\n\nBy team:\npayments: #Total Commits: 3")
+}
@@ -0,0 +1,125 @@
+package reportdomain
+
+import (
+	"fmt"
+	"strings"
+)
+
+//the name the compliance verdict is published under in Github, branch protection can require it
+const ComplianceContext = "code-review-compliance"
+
+//the verdicts given to a commit
+const (
+	CompliancePass = "pass"
+	ComplianceFail = "fail"
+)
+
+//where the verdict can be published in Github
+const (
+	PublishTargetStatus   = "status"
+	PublishTargetCheckRun = "check_run"
+)
+
+//Github limits the description of a commit status to 140 characters
+const maxComplianceDescription = 140
+
+//CommitCompliance is the code review verdict for a single commit on the audited branch
+//a commit passes only if it was merged via a PR into that branch and its message keeps to the rules of the repo
+//the head commit of an open PR instead passes if the PR could be merged into its base branch now
+type CommitCompliance struct {
+	Owner             string                 `json:"owner"`
+	Repo              string                 `json:"repo"`
+	Branch            string                 `json:"branch"`
+	Commit            CommitReview           `json:"commit"`
+	Verdict           string                 `json:"verdict"`
+	MessageViolations []string               `json:"message_violations,omitempty"`
+	PullRequest       *PullRequestCompliance `json:"pull_request,omitempty"`
+	PublishedTo       string                 `json:"published_to,omitempty"`
+	URL               string                 `json:"url,omitempty"`
+}
+
+//NewCommitCompliance gives the commit its verdict based on its review status
+func NewCommitCompliance(owner string, repo string, branch string, commit CommitReview) *CommitCompliance {
+	verdict := ComplianceFail
+	if commit.ReviewStatus == ReviewStatusReviewed {
+		verdict = CompliancePass
+	}
+	return &CommitCompliance{Owner: owner, Repo: repo, Branch: branch, Commit: commit, Verdict: verdict}
+}
+
+//NewPullRequestHeadCompliance gives the head commit of an open PR its verdict based on the compliance of the PR against its base branch
+func NewPullRequestHeadCompliance(owner string, repo string, commit CommitReview, pullRequest *PullRequestCompliance) *CommitCompliance {
+	verdict := ComplianceFail
+	if pullRequest.Compliant {
+		verdict = CompliancePass
+	}
+	return &CommitCompliance{Owner: owner, Repo: repo, Branch: pullRequest.BaseRef, Commit: commit, Verdict: verdict, PullRequest: pullRequest}
+}
+
+//SetMessageViolations records how the commit message breaks the rules, the commit fails if it breaks any of them
+func (c *CommitCompliance) SetMessageViolations(violations []string) {
+	c.MessageViolations = violations
+	if len(violations) > 0 {
+		c.Verdict = ComplianceFail
+	}
+}
+
+//Passed determines if the commit is compliant
+func (c *CommitCompliance) Passed() bool {
+	return c.Verdict == CompliancePass
+}
+
+//Title returns the verdict as shown in Github, such as code-review-compliance: pass
+func (c *CommitCompliance) Title() string {
+	return fmt.Sprintf("%s: %s", ComplianceContext, c.Verdict)
+}
+
+//Description explains the verdict in a single line that fits in a commit status
+func (c *CommitCompliance) Description() string {
+	var description string
+	switch {
+	case c.PullRequest != nil && c.PullRequest.Compliant:
+		description = fmt.Sprintf("PR #%d into %s meets the review policy", c.PullRequest.PullNumber, c.Branch)
+	case c.PullRequest != nil:
+		description = fmt.Sprintf("PR #%d into %s is missing: %s", c.PullRequest.PullNumber, c.Branch, strings.Join(c.PullRequest.MissingRequirements, ", "))
+	case c.Commit.ReviewStatus == ReviewStatusReviewed:
+		description = fmt.Sprintf("Reviewed in PR #%d into %s", c.Commit.PullNumber, c.Commit.PullBaseRef)
+	case c.Commit.ReviewStatus == ReviewStatusReviewedOnOtherBranch:
+		description = fmt.Sprintf("Only reviewed in PR #%d into %s, no PR into %s", c.Commit.PullNumber, c.Commit.PullBaseRef, c.Branch)
+	default:
+		description = fmt.Sprintf("No PR was merged into %s for this commit", c.Branch)
+	}
+	if len(c.MessageViolations) > 0 {
+		description += ", the commit message breaks the rules"
+	}
+
+	if len([]rune(description)) > maxComplianceDescription {
+		description = string([]rune(description)[:maxComplianceDescription-3]) + "..."
+	}
+	return description
+}
+
+//Summary returns the details of the verdict as markdown, as shown against a check run
+func (c *CommitCompliance) Summary() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s**\n\n", c.Title()))
+	if c.PullRequest != nil {
+		sb.WriteString(fmt.Sprintf("Commit `%s` at the head of a PR into `%s` by %s\n", c.Commit.SHA, c.Branch, c.Commit.Author))
+	} else {
+		sb.WriteString(fmt.Sprintf("Commit `%s` on `%s` by %s\n", c.Commit.SHA, c.Branch, c.Commit.Author))
+	}
+	sb.WriteString(fmt.Sprintf("> %s\n\n", firstLine(c.Commit.Message)))
+	sb.WriteString(c.Description())
+	if c.Commit.PullTitle != "" {
+		sb.WriteString(fmt.Sprintf(": %s", c.Commit.PullTitle))
+	}
+	if c.PullRequest != nil {
+		for _, missing := range c.PullRequest.MissingRequirements {
+			sb.WriteString(fmt.Sprintf("\n- %s", missing))
+		}
+	}
+	for _, violation := range c.MessageViolations {
+		sb.WriteString(fmt.Sprintf("\n- %s", violation))
+	}
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package reportdomain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplianceConstants(t *testing.T) {
+	assert.EqualValues(t, "code-review-compliance", ComplianceContext)
+	assert.EqualValues(t, "pass", CompliancePass)
+	assert.EqualValues(t, "fail", ComplianceFail)
+	assert.EqualValues(t, "status", PublishTargetStatus)
+	assert.EqualValues(t, "check_run", PublishTargetCheckRun)
+}
+
+func TestCommitComplianceReviewed(t *testing.T) {
+	report := getTestCodeReviewReport()
+	compliance := NewCommitCompliance("myowner", "myrepo", "main", report.Commits[0])
+
+	assert.True(t, compliance.Passed())
+	assert.EqualValues(t, CompliancePass, compliance.Verdict)
+	assert.EqualValues(t, "code-review-compliance: pass", compliance.Title())
+	assert.EqualValues(t, "Reviewed in PR #9 into main", compliance.Description())
+	assert.EqualValues(t, "**code-review-compliance: pass**\n\nCommit `AAA111` on `main` by some name\n> reviewed commit\n\nReviewed in PR #9 into main: a PR", compliance.Summary())
+}
+
+func TestCommitComplianceReviewedOnOtherBranch(t *testing.T) {
+	report := getTestCodeReviewReport()
+	compliance := NewCommitCompliance("myowner", "myrepo", "main", report.Commits[1])
+
+	assert.False(t, compliance.Passed())
+	assert.EqualValues(t, "code-review-compliance: fail", compliance.Title())
+	assert.EqualValues(t, "Only reviewed in PR #10 into develop, no PR into main", compliance.Description())
+	assert.Contains(t, compliance.Summary(), "> develop commit\n\n")
+}
+
+func TestCommitComplianceNotReviewed(t *testing.T) {
+	report := getTestCodeReviewReport()
+	compliance := NewCommitCompliance("myowner", "myrepo", "main", report.Commits[2])
+
+	assert.False(t, compliance.Passed())
+	assert.EqualValues(t, "No PR was merged into main for this commit", compliance.Description())
+	assert.True(t, strings.HasSuffix(compliance.Summary(), "No PR was merged into main for this commit"))
+}
+
+func TestCommitComplianceMessageViolations(t *testing.T) {
+	report := getTestCodeReviewReport()
+	compliance := NewCommitCompliance("myowner", "myrepo", "main", report.Commits[0])
+	compliance.SetMessageViolations(nil)
+	assert.True(t, compliance.Passed())
+
+	compliance.SetMessageViolations([]string{"the subject is 80 characters, more than the limit of 72", "the message isn't signed off by the author a@b.com"})
+	assert.False(t, compliance.Passed())
+	assert.EqualValues(t, "Reviewed in PR #9 into main, the commit message breaks the rules", compliance.Description())
+	assert.True(t, strings.HasSuffix(compliance.Summary(), "a PR\n- the subject is 80 characters, more than the limit of 72\n- the message isn't signed off by the author a@b.com"))
+}
+
+func TestCommitComplianceDescriptionTruncated(t *testing.T) {
+	compliance := NewCommitCompliance("myowner", "myrepo", strings.Repeat("b", 200), CommitReview{ReviewStatus: ReviewStatusNotReviewed})
+
+	description := compliance.Description()
+	assert.EqualValues(t, 140, len(description))
+	assert.True(t, strings.HasSuffix(description, "..."))
+}
+
+func TestPullRequestHeadCompliance(t *testing.T) {
+	commit := CommitReview{SHA: "BBB222", Author: "some name", Message: "add a feature", ReviewStatus: ReviewStatusNotReviewed, PullNumber: 12, PullTitle: "a feature", PullBaseRef: "release"}
+	pullRequest := &PullRequestCompliance{PullNumber: 12, BaseRef: "release"}
+	pullRequest.AddRequirement(RequirementApprovals, true, "1 of 1 required approvals")
+
+	compliance := NewPullRequestHeadCompliance("myowner", "myrepo", commit, pullRequest)
+	assert.True(t, compliance.Passed())
+	assert.EqualValues(t, "release", compliance.Branch)
+	assert.EqualValues(t, "PR #12 into release meets the review policy", compliance.Description())
+	assert.EqualValues(t, "**code-review-compliance: pass**\n\nCommit `BBB222` at the head of a PR into `release` by some name\n> add a feature\n\nPR #12 into release meets the review policy: a feature", compliance.Summary())
+
+	pullRequest.AddRequirement(RequirementStatusChecks, false, "required status checks not passing: ci/build")
+	compliance = NewPullRequestHeadCompliance("myowner", "myrepo", commit, pullRequest)
+	assert.False(t, compliance.Passed())
+	assert.EqualValues(t, "PR #12 into release is missing: required status checks not passing: ci/build", compliance.Description())
+	assert.True(t, strings.HasSuffix(compliance.Summary(), "a feature\n- required status checks not passing: ci/build"))
+}
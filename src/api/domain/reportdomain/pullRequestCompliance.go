@@ -0,0 +1,69 @@
+package reportdomain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+)
+
+//the requirements an open PR is evaluated against
+const (
+	RequirementOpen              = "open"
+	RequirementApprovals         = "approvals"
+	RequirementNoChangesRequired = "no_changes_requested"
+	RequirementCodeOwners        = "code_owners"
+	RequirementStatusChecks      = "status_checks"
+	RequirementCommitMessages    = "commit_messages"
+	RequirementTickets           = "tickets"
+	RequirementSignedCommits     = "signed_commits"
+)
+
+//RequirementResult records if the PR meets a single requirement of the policy
+type RequirementResult struct {
+	Name      string `json:"name"`
+	Satisfied bool   `json:"satisfied"`
+	Detail    string `json:"detail"`
+}
+
+//PullRequestCompliance evaluates an open PR against the review policy of its repo
+//the PR is compliant if merging it now would meet every requirement
+type PullRequestCompliance struct {
+	Owner               string                    `json:"owner"`
+	Repo                string                    `json:"repo"`
+	PullNumber          int64                     `json:"pull_number"`
+	Title               string                    `json:"title"`
+	Author              string                    `json:"author"`
+	BaseRef             string                    `json:"base_ref"`
+	HeadSHA             string                    `json:"head_sha"`
+	Policy              policydomain.ReviewPolicy `json:"policy"`
+	Compliant           bool                      `json:"compliant"`
+	Approvers           []string                  `json:"approvers"`
+	StaleApprovers      []string                  `json:"stale_approvers"`
+	Requirements        []RequirementResult       `json:"requirements"`
+	MissingRequirements []string                  `json:"missing_requirements"`
+	Warnings            []string                  `json:"warnings"`
+	Tickets             []ticketdomain.Reference  `json:"tickets,omitempty"`
+	SuspiciousApprovals []SuspiciousApproval      `json:"suspicious_approvals,omitempty"`
+}
+
+//AddRequirement records the result of a requirement, the PR is only compliant while every requirement is satisfied
+func (c *PullRequestCompliance) AddRequirement(name string, satisfied bool, detail string) {
+	c.Requirements = append(c.Requirements, RequirementResult{Name: name, Satisfied: satisfied, Detail: detail})
+	if !satisfied {
+		c.MissingRequirements = append(c.MissingRequirements, detail)
+	}
+	c.Compliant = len(c.MissingRequirements) == 0
+}
+
+//AddWarning records something a reviewer should know about the PR, warnings don't stop the PR being compliant
+func (c *PullRequestCompliance) AddWarning(detail string) {
+	c.Warnings = append(c.Warnings, detail)
+}
+
+//AddSuspiciousApproval records an approval that looks like a rubber stamp and warns about it, it still counts towards the approvals
+func (c *PullRequestCompliance) AddSuspiciousApproval(approval SuspiciousApproval) {
+	c.SuspiciousApprovals = append(c.SuspiciousApprovals, approval)
+	c.AddWarning(fmt.Sprintf("the approval by %s looks like a rubber stamp: %s", approval.Approver, strings.Join(approval.Signals, ", ")))
+}
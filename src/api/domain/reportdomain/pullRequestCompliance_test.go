@@ -0,0 +1,40 @@
+package reportdomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddRequirement(t *testing.T) {
+	compliance := PullRequestCompliance{}
+
+	compliance.AddRequirement(RequirementOpen, true, "the PR is open")
+	assert.True(t, compliance.Compliant)
+	assert.EqualValues(t, 0, len(compliance.MissingRequirements))
+
+	compliance.AddRequirement(RequirementApprovals, false, "1 of 2 required approvals")
+	compliance.AddRequirement(RequirementStatusChecks, true, "all required status checks passed")
+	assert.False(t, compliance.Compliant)
+	assert.EqualValues(t, 3, len(compliance.Requirements))
+	assert.EqualValues(t, RequirementApprovals, compliance.Requirements[1].Name)
+	assert.False(t, compliance.Requirements[1].Satisfied)
+	assert.EqualValues(t, []string{"1 of 2 required approvals"}, compliance.MissingRequirements)
+}
+
+func TestAddWarning(t *testing.T) {
+	compliance := PullRequestCompliance{}
+	compliance.AddRequirement(RequirementOpen, true, "the PR is open")
+	compliance.AddWarning("the PR changes 1200 lines")
+	assert.True(t, compliance.Compliant)
+	assert.EqualValues(t, []string{"the PR changes 1200 lines"}, compliance.Warnings)
+}
+
+func TestAddSuspiciousApproval(t *testing.T) {
+	compliance := PullRequestCompliance{}
+	compliance.AddRequirement(RequirementApprovals, true, "1 of 1 required approvals")
+	compliance.AddSuspiciousApproval(SuspiciousApproval{PullNumber: 9, Approver: "bob", Signals: []string{"approved 30s after the PR was opened", "no review comments"}})
+	assert.True(t, compliance.Compliant)
+	assert.EqualValues(t, 1, len(compliance.SuspiciousApprovals))
+	assert.EqualValues(t, []string{"the approval by bob looks like a rubber stamp: approved 30s after the PR was opened, no review comments"}, compliance.Warnings)
+}
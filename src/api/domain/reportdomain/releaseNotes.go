@@ -0,0 +1,213 @@
+package reportdomain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/messagedomain"
+)
+
+//the sections of the release notes, in the order they are written
+const (
+	SectionBreakingChanges = "Breaking Changes"
+	SectionFeatures        = "Features"
+	SectionBugFixes        = "Bug Fixes"
+	SectionPerformance     = "Performance"
+	SectionDocumentation   = "Documentation"
+	SectionMaintenance     = "Maintenance"
+	SectionOtherChanges    = "Other Changes"
+)
+
+var sectionOrder = []string{SectionBreakingChanges, SectionFeatures, SectionBugFixes, SectionPerformance, SectionDocumentation, SectionMaintenance, SectionOtherChanges}
+
+//sectionLabels gives the section for the PR labels commonly used on Github
+var sectionLabels = map[string]string{
+	"breaking":        SectionBreakingChanges,
+	"breaking-change": SectionBreakingChanges,
+	"breaking change": SectionBreakingChanges,
+	"feature":         SectionFeatures,
+	"enhancement":     SectionFeatures,
+	"bug":             SectionBugFixes,
+	"bugfix":          SectionBugFixes,
+	"fix":             SectionBugFixes,
+	"performance":     SectionPerformance,
+	"documentation":   SectionDocumentation,
+	"docs":            SectionDocumentation,
+	"chore":           SectionMaintenance,
+	"dependencies":    SectionMaintenance,
+	"refactor":        SectionMaintenance,
+}
+
+//sectionTypes gives the section for each conventional commit type
+var sectionTypes = map[string]string{
+	"feat":     SectionFeatures,
+	"fix":      SectionBugFixes,
+	"perf":     SectionPerformance,
+	"docs":     SectionDocumentation,
+	"build":    SectionMaintenance,
+	"chore":    SectionMaintenance,
+	"ci":       SectionMaintenance,
+	"refactor": SectionMaintenance,
+	"style":    SectionMaintenance,
+	"test":     SectionMaintenance,
+}
+
+//ReleaseNotes lists the PRs merged between two refs of a repo grouped into sections
+//commits between the refs that didn't come from a merged PR are listed separately
+type ReleaseNotes struct {
+	Owner             string                `json:"owner"`
+	Repo              string                `json:"repo"`
+	From              string                `json:"from"`
+	To                string                `json:"to"`
+	TotalCommits      int                   `json:"total_commits"`
+	TotalPullRequests int                   `json:"total_pull_requests"`
+	Sections          []ReleaseNotesSection `json:"sections"`
+	CommitsWithoutPR  []ReleaseNoteCommit   `json:"commits_without_pr"`
+}
+
+//ReleaseNotesSection holds the PRs of a single section of the release notes
+type ReleaseNotesSection struct {
+	Title        string        `json:"title"`
+	PullRequests []ReleaseNote `json:"pull_requests"`
+}
+
+//ReleaseNote describes a single merged PR, the type and scope are set when the title follows conventional commits
+type ReleaseNote struct {
+	Number   int64     `json:"number"`
+	Title    string    `json:"title"`
+	Subject  string    `json:"subject"`
+	Type     string    `json:"type,omitempty"`
+	Scope    string    `json:"scope,omitempty"`
+	Breaking bool      `json:"breaking"`
+	Author   string    `json:"author"`
+	Labels   []string  `json:"labels"`
+	MergedAt time.Time `json:"merged_at"`
+	Section  string    `json:"section"`
+}
+
+//ReleaseNoteCommit describes a commit between the refs that didn't come from a merged PR
+type ReleaseNoteCommit struct {
+	SHA     string `json:"sha"`
+	Author  string `json:"author"`
+	Message string `json:"message"`
+}
+
+//NewReleaseNote describes the merged PR and works out the section it belongs in
+//a breaking change always goes in the breaking changes section, otherwise the labels of the PR are used
+//before the conventional commit type of its title and anything left over is an other change
+func NewReleaseNote(pullRequest *githubdomain.GetSinglePullRequestResponse) ReleaseNote {
+	note := ReleaseNote{
+		Number:   pullRequest.Number,
+		Title:    pullRequest.Title,
+		Subject:  pullRequest.Title,
+		Author:   pullRequest.User.Login,
+		Labels:   make([]string, 0),
+		MergedAt: pullRequest.MergedAt,
+	}
+	if title := messagedomain.ParseCommitMessage(pullRequest.Title); title.Conventional {
+		note.Type = title.Type
+		note.Scope = title.Scope
+		note.Breaking = title.Breaking
+		note.Subject = title.Subject
+	}
+	if strings.Contains(pullRequest.Body, "BREAKING CHANGE") {
+		note.Breaking = true
+	}
+
+	labelSections := make(map[string]bool)
+	for _, label := range pullRequest.Labels {
+		note.Labels = append(note.Labels, label.Name)
+		if section, ok := sectionLabels[strings.ToLower(label.Name)]; ok {
+			labelSections[section] = true
+		}
+	}
+
+	note.Section = SectionOtherChanges
+	if note.Breaking || labelSections[SectionBreakingChanges] {
+		note.Breaking = true
+		note.Section = SectionBreakingChanges
+		return note
+	}
+	for _, section := range sectionOrder {
+		if labelSections[section] {
+			note.Section = section
+			return note
+		}
+	}
+	if section, ok := sectionTypes[note.Type]; ok {
+		note.Section = section
+	}
+	return note
+}
+
+//NewReleaseNoteCommit describes a commit that didn't come from a merged PR
+func NewReleaseNoteCommit(commit *githubdomain.GetCommitInfo) ReleaseNoteCommit {
+	author := commit.Author.Login
+	if author == "" {
+		author = commit.Commit.Author.Name
+	}
+	return ReleaseNoteCommit{SHA: commit.SHA, Author: author, Message: firstLine(commit.Commit.Message)}
+}
+
+//SetSections groups the notes into their sections, sections without any PRs are left out
+//the notes keep their order within each section
+func (r *ReleaseNotes) SetSections(notes []ReleaseNote) {
+	r.TotalPullRequests = len(notes)
+	r.Sections = make([]ReleaseNotesSection, 0)
+	for _, title := range sectionOrder {
+		section := ReleaseNotesSection{Title: title}
+		for _, note := range notes {
+			if note.Section == title {
+				section.PullRequests = append(section.PullRequests, note)
+			}
+		}
+		if len(section.PullRequests) > 0 {
+			r.Sections = append(r.Sections, section)
+		}
+	}
+}
+
+//Markdown returns the release notes as Markdown with a heading for each section
+func (r *ReleaseNotes) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s/%s %s...%s\n", r.Owner, r.Repo, r.From, r.To))
+	if len(r.Sections) == 0 && len(r.CommitsWithoutPR) == 0 {
+		sb.WriteString("\nNo changes.\n")
+	}
+
+	for _, section := range r.Sections {
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", section.Title))
+		for _, note := range section.PullRequests {
+			sb.WriteString("- ")
+			sb.WriteString(note.Markdown())
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(r.CommitsWithoutPR) > 0 {
+		sb.WriteString("\n## Commits without a PR\n\n")
+		for _, commit := range r.CommitsWithoutPR {
+			sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", shortSHA(commit.SHA), commit.Message, commit.Author))
+		}
+	}
+	return sb.String()
+}
+
+//Markdown returns a single line describing the PR
+func (n *ReleaseNote) Markdown() string {
+	line := n.Subject
+	if n.Scope != "" {
+		line = fmt.Sprintf("**%s:** %s", n.Scope, line)
+	}
+	return fmt.Sprintf("%s (#%d) @%s", line, n.Number, n.Author)
+}
+
+//shortSHA returns the abbreviated SHA Github shows for a commit
+func shortSHA(SHA string) string {
+	if len(SHA) > 7 {
+		return SHA[:7]
+	}
+	return SHA
+}
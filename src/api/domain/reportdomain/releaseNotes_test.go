@@ -0,0 +1,127 @@
+package reportdomain
+
+import (
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestReleasePR(number int64, title string, labels ...string) *githubdomain.GetSinglePullRequestResponse {
+	pullRequest := &githubdomain.GetSinglePullRequestResponse{Number: number, Title: title, User: githubdomain.GitUser{Login: "author"}}
+	for _, label := range labels {
+		pullRequest.Labels = append(pullRequest.Labels, githubdomain.Label{Name: label})
+	}
+	return pullRequest
+}
+
+func TestNewReleaseNoteConventionalTitle(t *testing.T) {
+	note := NewReleaseNote(getTestReleasePR(12, "feat(api): add release notes"))
+	assert.EqualValues(t, "feat", note.Type)
+	assert.EqualValues(t, "api", note.Scope)
+	assert.EqualValues(t, "add release notes", note.Subject)
+	assert.False(t, note.Breaking)
+	assert.EqualValues(t, SectionFeatures, note.Section)
+	assert.EqualValues(t, "**api:** add release notes (#12) @author", note.Markdown())
+
+	note = NewReleaseNote(getTestReleasePR(13, "Fix: handle empty repos"))
+	assert.EqualValues(t, "fix", note.Type)
+	assert.EqualValues(t, SectionBugFixes, note.Section)
+	assert.EqualValues(t, "handle empty repos (#13) @author", note.Markdown())
+
+	note = NewReleaseNote(getTestReleasePR(14, "ci: run the tests on pushes"))
+	assert.EqualValues(t, SectionMaintenance, note.Section)
+
+	note = NewReleaseNote(getTestReleasePR(15, "Update the README"))
+	assert.EqualValues(t, "", note.Type)
+	assert.EqualValues(t, "Update the README", note.Subject)
+	assert.EqualValues(t, SectionOtherChanges, note.Section)
+
+	note = NewReleaseNote(getTestReleasePR(16, "wip: something"))
+	assert.EqualValues(t, "wip", note.Type)
+	assert.EqualValues(t, SectionOtherChanges, note.Section)
+}
+
+func TestNewReleaseNoteBreaking(t *testing.T) {
+	note := NewReleaseNote(getTestReleasePR(12, "feat(api)!: remove the v1 endpoints"))
+	assert.True(t, note.Breaking)
+	assert.EqualValues(t, SectionBreakingChanges, note.Section)
+
+	pullRequest := getTestReleasePR(13, "fix: tighten validation")
+	pullRequest.Body = "Some detail\n\nBREAKING CHANGE: empty owners are rejected"
+	note = NewReleaseNote(pullRequest)
+	assert.True(t, note.Breaking)
+	assert.EqualValues(t, SectionBreakingChanges, note.Section)
+
+	note = NewReleaseNote(getTestReleasePR(14, "Tidy up", "breaking-change"))
+	assert.True(t, note.Breaking)
+	assert.EqualValues(t, SectionBreakingChanges, note.Section)
+}
+
+func TestNewReleaseNoteLabels(t *testing.T) {
+	//the labels win over the title
+	note := NewReleaseNote(getTestReleasePR(12, "feat: faster lookups", "Performance", "enhancement"))
+	assert.EqualValues(t, []string{"Performance", "enhancement"}, note.Labels)
+	assert.EqualValues(t, SectionFeatures, note.Section)
+
+	note = NewReleaseNote(getTestReleasePR(13, "feat: faster lookups", "performance"))
+	assert.EqualValues(t, SectionPerformance, note.Section)
+
+	note = NewReleaseNote(getTestReleasePR(14, "Bump yaml", "dependencies", "unknown"))
+	assert.EqualValues(t, SectionMaintenance, note.Section)
+
+	note = NewReleaseNote(getTestReleasePR(15, "feat: something", "unknown"))
+	assert.EqualValues(t, SectionFeatures, note.Section)
+}
+
+func TestNewReleaseNoteCommit(t *testing.T) {
+	commit := githubdomain.GetCommitInfo{SHA: "ABCDEF123456"}
+	commit.Commit.Message = "hotfix straight to main\n\nwith a body"
+	commit.Commit.Author.Name = "some name"
+
+	note := NewReleaseNoteCommit(&commit)
+	assert.EqualValues(t, "some name", note.Author)
+	assert.EqualValues(t, "hotfix straight to main", note.Message)
+
+	commit.Author.Login = "somelogin"
+	assert.EqualValues(t, "somelogin", NewReleaseNoteCommit(&commit).Author)
+}
+
+func TestReleaseNotesMarkdown(t *testing.T) {
+	notes := ReleaseNotes{Owner: "myowner", Repo: "myrepo", From: "v1.2.0", To: "v1.3.0"}
+	notes.SetSections([]ReleaseNote{
+		NewReleaseNote(getTestReleasePR(12, "fix: handle empty repos")),
+		NewReleaseNote(getTestReleasePR(10, "feat(api): add release notes")),
+		NewReleaseNote(getTestReleasePR(11, "fix(report): sort by date")),
+	})
+	notes.CommitsWithoutPR = []ReleaseNoteCommit{{SHA: "ABCDEF123456", Author: "some name", Message: "hotfix"}}
+
+	assert.EqualValues(t, 3, notes.TotalPullRequests)
+	assert.EqualValues(t, 2, len(notes.Sections))
+	assert.EqualValues(t, SectionFeatures, notes.Sections[0].Title)
+	assert.EqualValues(t, 12, notes.Sections[1].PullRequests[0].Number)
+
+	expected := `# myowner/myrepo v1.2.0...v1.3.0
+
+## Features
+
+- **api:** add release notes (#10) @author
+
+## Bug Fixes
+
+- handle empty repos (#12) @author
+- **report:** sort by date (#11) @author
+
+## Commits without a PR
+
+- ABCDEF1 hotfix (some name)
+`
+	assert.EqualValues(t, expected, notes.Markdown())
+}
+
+func TestReleaseNotesMarkdownNoChanges(t *testing.T) {
+	notes := ReleaseNotes{Owner: "myowner", Repo: "myrepo", From: "v1.2.0", To: "v1.2.0"}
+	notes.SetSections(nil)
+	assert.EqualValues(t, 0, len(notes.Sections))
+	assert.EqualValues(t, "# myowner/myrepo v1.2.0...v1.2.0\n\nNo changes.\n", notes.Markdown())
+}
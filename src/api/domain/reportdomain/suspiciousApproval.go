@@ -0,0 +1,68 @@
+package reportdomain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+)
+
+//SuspiciousApproval is an approval that looks like a rubber stamp rather than a real review, the signals say why
+type SuspiciousApproval struct {
+	PullNumber     int64     `json:"pull_number"`
+	PullTitle      string    `json:"pull_title"`
+	Approver       string    `json:"approver"`
+	ApprovedAt     time.Time `json:"approved_at"`
+	ReviewSeconds  int64     `json:"review_seconds"`
+	ChangedLines   int64     `json:"changed_lines"`
+	ReviewComments int       `json:"review_comments"`
+	Signals        []string  `json:"signals"`
+}
+
+//NewSuspiciousApproval checks the approval of the PR against the rules, it returns nil unless the approval looks like a rubber stamp
+//the review time runs from when the PR was opened and the review comments are those the approver left on the changes
+//a PR read from a list of PRs doesn't say how many lines it changes so its size is never a signal
+func NewSuspiciousApproval(pullRequest *githubdomain.GetSinglePullRequestResponse, review *githubdomain.PullRequestReview, reviewComments int, rules *policydomain.RubberStampRules) *SuspiciousApproval {
+	approval := &SuspiciousApproval{
+		PullNumber:     pullRequest.Number,
+		PullTitle:      pullRequest.Title,
+		Approver:       review.User.Login,
+		ApprovedAt:     review.SubmittedAt,
+		ChangedLines:   pullRequest.Additions + pullRequest.Deletions,
+		ReviewComments: reviewComments,
+	}
+
+	if !pullRequest.CreatedAt.IsZero() && !review.SubmittedAt.IsZero() {
+		reviewTime := review.SubmittedAt.Sub(pullRequest.CreatedAt)
+		approval.ReviewSeconds = int64(reviewTime.Seconds())
+		if rules.MinReviewSeconds > 0 && approval.ReviewSeconds < rules.MinReviewSeconds {
+			approval.Signals = append(approval.Signals, fmt.Sprintf("approved %s after the PR was opened", reviewTime.Round(time.Second)))
+		}
+	}
+	if rules.LargeChangeLines > 0 && approval.ChangedLines >= rules.LargeChangeLines {
+		approval.Signals = append(approval.Signals, fmt.Sprintf("the PR changes %d lines", approval.ChangedLines))
+	}
+	if rules.MinReviewComments > 0 && reviewComments < rules.MinReviewComments {
+		if reviewComments == 0 {
+			approval.Signals = append(approval.Signals, "no review comments")
+		} else {
+			approval.Signals = append(approval.Signals, fmt.Sprintf("%d review comments, fewer than %d", reviewComments, rules.MinReviewComments))
+		}
+	}
+
+	minSignals := rules.MinSignals
+	if minSignals < 1 {
+		minSignals = 1
+	}
+	if len(approval.Signals) < minSignals {
+		return nil
+	}
+	return approval
+}
+
+//Text returns a single line describing the approval along with why it looks like a rubber stamp
+func (a *SuspiciousApproval) Text() string {
+	return fmt.Sprintf("PR #%d %s approved by %s at %s: %s", a.PullNumber, a.PullTitle, a.Approver, a.ApprovedAt.UTC().Format(time.RFC3339), strings.Join(a.Signals, ", "))
+}
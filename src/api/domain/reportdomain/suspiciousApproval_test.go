@@ -0,0 +1,48 @@
+package reportdomain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSuspiciousApproval(t *testing.T) {
+	rules := &policydomain.RubberStampRules{Enabled: true, MinReviewSeconds: 300, LargeChangeLines: 500, MinReviewComments: 1, MinSignals: 2}
+	opened := time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC)
+	pullRequest := &githubdomain.GetSinglePullRequestResponse{Number: 5, Title: "Add a feature", CreatedAt: opened, Additions: 1800, Deletions: 200}
+	review := &githubdomain.PullRequestReview{User: githubdomain.GitUser{Login: "bob"}, State: githubdomain.ReviewStateApproved, SubmittedAt: opened.Add(30 * time.Second)}
+
+	approval := NewSuspiciousApproval(pullRequest, review, 0, rules)
+	assert.NotNil(t, approval)
+	assert.EqualValues(t, SuspiciousApproval{PullNumber: 5, PullTitle: "Add a feature", Approver: "bob", ApprovedAt: opened.Add(30 * time.Second), ReviewSeconds: 30, ChangedLines: 2000,
+		Signals: []string{"approved 30s after the PR was opened", "the PR changes 2000 lines", "no review comments"}}, *approval)
+	assert.EqualValues(t, "PR #5 Add a feature approved by bob at 2020-03-02T09:00:30Z: approved 30s after the PR was opened, the PR changes 2000 lines, no review comments", approval.Text())
+
+	//a quick approval of a small change with a comment is only one signal
+	pullRequest.Additions, pullRequest.Deletions = 10, 2
+	assert.Nil(t, NewSuspiciousApproval(pullRequest, review, 1, rules))
+
+	//a small change approved quickly without a comment is two
+	approval = NewSuspiciousApproval(pullRequest, review, 0, rules)
+	assert.EqualValues(t, []string{"approved 30s after the PR was opened", "no review comments"}, approval.Signals)
+
+	//a large change reviewed for an hour with too few comments
+	pullRequest.Additions = 700
+	review.SubmittedAt = opened.Add(time.Hour)
+	approval = NewSuspiciousApproval(pullRequest, review, 1, &policydomain.RubberStampRules{LargeChangeLines: 500, MinReviewComments: 3, MinSignals: 2})
+	assert.EqualValues(t, 3600, approval.ReviewSeconds)
+	assert.EqualValues(t, []string{"the PR changes 702 lines", "1 review comments, fewer than 3"}, approval.Signals)
+
+	//a zero min signals is taken as one and the turned off signals are never seen
+	assert.NotNil(t, NewSuspiciousApproval(pullRequest, review, 5, &policydomain.RubberStampRules{LargeChangeLines: 500}))
+	assert.Nil(t, NewSuspiciousApproval(pullRequest, review, 0, &policydomain.RubberStampRules{}))
+
+	//without the time the PR was opened the review time isn't known
+	pullRequest.CreatedAt = time.Time{}
+	approval = NewSuspiciousApproval(pullRequest, review, 0, rules)
+	assert.EqualValues(t, 0, approval.ReviewSeconds)
+	assert.EqualValues(t, []string{"the PR changes 702 lines", "no review comments"}, approval.Signals)
+}
@@ -0,0 +1,70 @@
+//Package scheduledomain holds the reports that are run on a schedule and the history of their runs
+package scheduledomain
+
+import (
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+)
+
+//the reports that can be scheduled
+const (
+	ReportCodeReview = "code_review"
+)
+
+//what to do about runs that were due while the app wasn't running
+const (
+	MissedRunsSkip    = "skip"
+	MissedRunsRunOnce = "run_once"
+	MissedRunsRunAll  = "run_all"
+)
+
+//the outcome of a run
+const (
+	RunStatusSucceeded = "succeeded"
+	RunStatusPartial   = "partial"
+	RunStatusFailed    = "failed"
+	RunStatusSkipped   = "skipped"
+)
+
+//Schedule defines a report to run on a cron expression
+//leaving out the repo runs the report for every repo owned by the organisation, the paths limit the report to the
+//commits changing files matching the patterns, such as services/payments/**
+//each report covers the period from the previous scheduled time up to the scheduled time of the run
+type Schedule struct {
+	Name       string   `yaml:"name" json:"name"`
+	Cron       string   `yaml:"cron" json:"cron"`
+	Timezone   string   `yaml:"timezone" json:"timezone"`
+	Report     string   `yaml:"report" json:"report"`
+	Owner      string   `yaml:"owner" json:"owner"`
+	Repo       string   `yaml:"repo" json:"repo,omitempty"`
+	Paths      []string `yaml:"paths" json:"paths,omitempty"`
+	MissedRuns string   `yaml:"missed_runs" json:"missed_runs"`
+}
+
+//IsOrgWide determines if the report is run for every repo of the owner
+func (s *Schedule) IsOrgWide() bool {
+	return s.Repo == ""
+}
+
+//ScheduleRun records a single run of a schedule along with the reports it produced
+//a run that was missed while the app wasn't running is recorded as skipped unless the schedule says to run it
+type ScheduleRun struct {
+	Schedule           string                          `json:"schedule"`
+	ScheduledFor       time.Time                       `json:"scheduled_for"`
+	FromDate           time.Time                       `json:"from_date"`
+	StartedAt          time.Time                       `json:"started_at"`
+	FinishedAt         time.Time                       `json:"finished_at"`
+	Missed             bool                            `json:"missed"`
+	Status             string                          `json:"status"`
+	Errors             []string                        `json:"errors,omitempty"`
+	NotificationErrors []string                        `json:"notification_errors,omitempty"`
+	Reports            []reportdomain.CodeReviewReport `json:"reports,omitempty"`
+}
+
+//ScheduleStatus describes a schedule along with when it will next run and how it last ran
+type ScheduleStatus struct {
+	Schedule
+	NextRun time.Time    `json:"next_run"`
+	LastRun *ScheduleRun `json:"last_run,omitempty"`
+}
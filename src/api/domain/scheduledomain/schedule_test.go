@@ -0,0 +1,57 @@
+package scheduledomain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstants(t *testing.T) {
+	assert.EqualValues(t, "code_review", ReportCodeReview)
+	assert.EqualValues(t, "skip", MissedRunsSkip)
+	assert.EqualValues(t, "run_once", MissedRunsRunOnce)
+	assert.EqualValues(t, "run_all", MissedRunsRunAll)
+	assert.EqualValues(t, "succeeded", RunStatusSucceeded)
+	assert.EqualValues(t, "partial", RunStatusPartial)
+	assert.EqualValues(t, "failed", RunStatusFailed)
+	assert.EqualValues(t, "skipped", RunStatusSkipped)
+}
+
+func TestScheduleIsOrgWide(t *testing.T) {
+	schedule := Schedule{Owner: "myorg"}
+	assert.True(t, schedule.IsOrgWide())
+
+	schedule.Repo = "myrepo"
+	assert.False(t, schedule.IsOrgWide())
+}
+
+func TestScheduleStatusJSON(t *testing.T) {
+	status := ScheduleStatus{
+		Schedule: Schedule{Name: "monthly", Cron: "0 6 1 * *", Timezone: "Europe/London", Report: ReportCodeReview, Owner: "myorg", MissedRuns: MissedRunsRunOnce},
+		NextRun:  time.Date(2020, 1, 1, 6, 0, 0, 0, time.UTC),
+		LastRun: &ScheduleRun{
+			Schedule:     "monthly",
+			ScheduledFor: time.Date(2019, 12, 1, 6, 0, 0, 0, time.UTC),
+			Status:       RunStatusPartial,
+			Errors:       []string{"myrepo: Requires authentication"},
+			Reports:      []reportdomain.CodeReviewReport{{Owner: "myorg", Repo: "another", TotalCommits: 3}},
+		},
+	}
+
+	bytes, err := json.Marshal(status)
+	assert.Nil(t, err)
+	assert.Contains(t, string(bytes), `"name":"monthly"`)
+	assert.NotContains(t, string(bytes), `"repo":""`)
+
+	var target ScheduleStatus
+	err = json.Unmarshal(bytes, &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, status.Schedule, target.Schedule)
+	assert.EqualValues(t, status.NextRun, target.NextRun)
+	assert.EqualValues(t, status.LastRun.ScheduledFor, target.LastRun.ScheduledFor)
+	assert.EqualValues(t, status.LastRun.Errors, target.LastRun.Errors)
+	assert.EqualValues(t, 3, target.LastRun.Reports[0].TotalCommits)
+}
@@ -0,0 +1,30 @@
+//Package syncdomain holds the state of copying the history of a repo from Github into the data store
+package syncdomain
+
+import "time"
+
+//SyncState records how far the history of a repo has been synced and the outcome of the last sync
+//the cursors are where the next sync carries on from so only newer data is fetched
+type SyncState struct {
+	Owner             string    `json:"owner"`
+	Repo              string    `json:"repo"`
+	Branch            string    `json:"branch"`
+	CommitsCursor     time.Time `json:"commits_cursor"`
+	PullsCursor       time.Time `json:"pulls_cursor"`
+	LastSyncStarted   time.Time `json:"last_sync_started"`
+	LastSyncCompleted time.Time `json:"last_sync_completed"`
+	LastSyncCommits   int       `json:"last_sync_commits"`
+	LastSyncPulls     int       `json:"last_sync_pulls"`
+	LastError         string    `json:"last_error,omitempty"`
+	LastErrorAt       time.Time `json:"last_error_at"`
+}
+
+//IsSynced determines if the repo has ever been synced successfully
+func (s *SyncState) IsSynced() bool {
+	return !s.LastSyncCompleted.IsZero()
+}
+
+//Failed determines if the most recent sync failed
+func (s *SyncState) Failed() bool {
+	return s.LastError != "" && !s.LastErrorAt.Before(s.LastSyncStarted)
+}
@@ -0,0 +1,55 @@
+package syncdomain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncStateJSON(t *testing.T) {
+	state := SyncState{
+		Owner:             "myuser",
+		Repo:              "myrepo",
+		Branch:            "main",
+		CommitsCursor:     time.Now().UTC(),
+		PullsCursor:       time.Now().UTC().Add(-time.Minute),
+		LastSyncStarted:   time.Now().UTC(),
+		LastSyncCompleted: time.Now().UTC(),
+		LastSyncCommits:   3,
+		LastSyncPulls:     2,
+	}
+
+	bytes, err := json.Marshal(state)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(bytes), "last_error\"")
+
+	var target SyncState
+	err = json.Unmarshal(bytes, &target)
+	assert.Nil(t, err)
+	assert.EqualValues(t, state.Owner, target.Owner)
+	assert.EqualValues(t, state.Branch, target.Branch)
+	assert.EqualValues(t, state.CommitsCursor, target.CommitsCursor)
+	assert.EqualValues(t, state.PullsCursor, target.PullsCursor)
+	assert.EqualValues(t, state.LastSyncCommits, target.LastSyncCommits)
+	assert.EqualValues(t, state.LastSyncPulls, target.LastSyncPulls)
+}
+
+func TestSyncStateIsSyncedAndFailed(t *testing.T) {
+	state := SyncState{}
+	assert.False(t, state.IsSynced())
+	assert.False(t, state.Failed())
+
+	state.LastSyncStarted = time.Now().UTC()
+	state.LastError = "Requires authentication"
+	state.LastErrorAt = state.LastSyncStarted.Add(time.Second)
+	assert.False(t, state.IsSynced())
+	assert.True(t, state.Failed())
+
+	//a later sync that worked clears the failure
+	state.LastSyncStarted = state.LastErrorAt.Add(time.Minute)
+	state.LastSyncCompleted = state.LastSyncStarted.Add(time.Second)
+	assert.True(t, state.IsSynced())
+	assert.False(t, state.Failed())
+}
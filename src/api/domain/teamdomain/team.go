@@ -0,0 +1,86 @@
+//Package teamdomain holds the team directory used to break the reports and metrics down by team
+package teamdomain
+
+import (
+	"sort"
+	"strings"
+)
+
+//Unassigned is the team given to the people who don't belong to any team in the directory
+const Unassigned = "unassigned"
+
+//Team lists the people in a team, a member is a Github login or, for people without one, a commit email
+//the members of a Github team in the organisation are added to the team when the directory is synced
+type Team struct {
+	Name          string   `yaml:"name" json:"name"`
+	GithubTeam    string   `yaml:"github_team" json:"github_team,omitempty"`
+	Members       []string `yaml:"members" json:"members"`
+	SyncedMembers []string `yaml:"-" json:"synced_members,omitempty"`
+}
+
+//Directory holds the teams of the organisation
+type Directory struct {
+	Org   string `yaml:"org" json:"org,omitempty"`
+	Teams []Team `yaml:"teams" json:"teams"`
+}
+
+//NewDirectory returns an empty directory, everybody is unassigned
+func NewDirectory() *Directory {
+	return &Directory{Teams: make([]Team, 0)}
+}
+
+//HasTeams returns true if there are any teams in the directory
+func (d *Directory) HasTeams() bool {
+	return len(d.Teams) > 0
+}
+
+//TeamsOf returns the names of the teams the person with the identity belongs to, in the order they are defined
+//a person belonging to no team is unassigned
+func (d *Directory) TeamsOf(identityID string) []string {
+	identityID = strings.ToLower(identityID)
+	var result []string
+	for _, team := range d.Teams {
+		if team.HasMember(identityID) {
+			result = append(result, team.Name)
+		}
+	}
+	if len(result) == 0 {
+		return []string{Unassigned}
+	}
+	return result
+}
+
+//HasMember returns true if the person with the identity is a member of the team
+func (t *Team) HasMember(identityID string) bool {
+	for _, member := range t.AllMembers() {
+		if strings.EqualFold(member, identityID) {
+			return true
+		}
+	}
+	return false
+}
+
+//AllMembers returns the members listed in the directory along with the members synced from Github, sorted without duplicates
+func (t *Team) AllMembers() []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, member := range append(append([]string{}, t.Members...), t.SyncedMembers...) {
+		member = strings.ToLower(strings.TrimSpace(member))
+		if member == "" || seen[member] {
+			continue
+		}
+		seen[member] = true
+		result = append(result, member)
+	}
+	sort.Strings(result)
+	return result
+}
+
+//TeamNames returns the names of the teams followed by unassigned, the order breakdowns are reported in
+func (d *Directory) TeamNames() []string {
+	var result []string
+	for _, team := range d.Teams {
+		result = append(result, team.Name)
+	}
+	return append(result, Unassigned)
+}
@@ -0,0 +1,36 @@
+package teamdomain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDirectory(t *testing.T) {
+	directory := NewDirectory()
+	assert.False(t, directory.HasTeams())
+	assert.EqualValues(t, []string{Unassigned}, directory.TeamsOf("alice"))
+	assert.EqualValues(t, []string{Unassigned}, directory.TeamNames())
+}
+
+func TestTeamsOf(t *testing.T) {
+	directory := &Directory{Teams: []Team{
+		{Name: "payments", Members: []string{"Alice", "bob@example.com"}},
+		{Name: "platform", Members: []string{"carol"}, SyncedMembers: []string{"alice"}},
+	}}
+
+	assert.True(t, directory.HasTeams())
+	assert.EqualValues(t, []string{"payments", "platform"}, directory.TeamsOf("ALICE"))
+	assert.EqualValues(t, []string{"payments"}, directory.TeamsOf("bob@example.com"))
+	assert.EqualValues(t, []string{"platform"}, directory.TeamsOf("carol"))
+	assert.EqualValues(t, []string{Unassigned}, directory.TeamsOf("dave"))
+	assert.EqualValues(t, []string{Unassigned}, directory.TeamsOf(""))
+	assert.EqualValues(t, []string{"payments", "platform", Unassigned}, directory.TeamNames())
+}
+
+func TestAllMembers(t *testing.T) {
+	team := Team{Name: "platform", Members: []string{"Carol", " dave ", ""}, SyncedMembers: []string{"carol", "alice"}}
+	assert.EqualValues(t, []string{"alice", "carol", "dave"}, team.AllMembers())
+	assert.True(t, team.HasMember("Dave"))
+	assert.False(t, team.HasMember("bob"))
+}
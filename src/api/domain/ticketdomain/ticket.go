@@ -0,0 +1,108 @@
+//Package ticketdomain finds the issue tracker tickets referenced by commits and PRs and records whether they meet the rules
+package ticketdomain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+)
+
+//where a ticket key was found
+const (
+	SourceCommitMessage = "commit_message"
+	SourcePullTitle     = "pr_title"
+	SourcePullBody      = "pr_body"
+)
+
+//the outcome of checking the tickets of a change
+const (
+	StatusValid   = "valid"
+	StatusMissing = "missing"
+	StatusInvalid = "invalid"
+)
+
+//Issue is a ticket in the issue tracker
+type Issue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"`
+}
+
+//Reference is a ticket key found in a commit message or PR along with the outcome of looking it up
+//a reference that couldn't be looked up, as no issue tracker is set, is taken to be valid and has no status
+type Reference struct {
+	Key     string `json:"key"`
+	Source  string `json:"source"`
+	Status  string `json:"status,omitempty"`
+	Valid   bool   `json:"valid"`
+	Problem string `json:"problem,omitempty"`
+}
+
+//ExtractKeys returns the ticket keys in the text in the order they are first found, each key is only returned once
+//a pattern with a group uses what the first group matched as the key and keys are upper cased as issue trackers use
+func ExtractKeys(patterns []*regexp.Regexp, text string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			key := match[0]
+			if len(match) > 1 {
+				key = match[1]
+			}
+			if key = strings.ToUpper(strings.TrimSpace(key)); key != "" && !seen[key] {
+				seen[key] = true
+				result = append(result, key)
+			}
+		}
+	}
+	return result
+}
+
+//NewReference checks the ticket the key was found for against the rules, a nil issue means the ticket doesn't exist
+func NewReference(key string, source string, issue *Issue, rules *policydomain.TicketRules) Reference {
+	reference := Reference{Key: key, Source: source}
+	switch {
+	case issue == nil:
+		reference.Problem = fmt.Sprintf("the ticket %s doesn't exist", key)
+	case !rules.IsAllowedState(issue.Status):
+		reference.Status = issue.Status
+		reference.Problem = fmt.Sprintf("the ticket %s is %s, expected one of %s", key, issue.Status, strings.Join(rules.AllowedStates, ", "))
+	default:
+		reference.Status = issue.Status
+		reference.Valid = true
+	}
+	return reference
+}
+
+//NewUnverifiedReference returns a reference that couldn't be looked up, it is taken to be valid
+func NewUnverifiedReference(key string, source string) Reference {
+	return Reference{Key: key, Source: source, Valid: true}
+}
+
+//GetStatus returns the outcome of checking the tickets of a change
+//a change without any references is missing a ticket and one is enough, so a change is only invalid when none of its references are valid
+//this stops text that only looks like a key, such as UTF-8, from failing a change with a real ticket
+func GetStatus(references []Reference) string {
+	if len(references) == 0 {
+		return StatusMissing
+	}
+	for _, reference := range references {
+		if reference.Valid {
+			return StatusValid
+		}
+	}
+	return StatusInvalid
+}
+
+//Problems returns the problems of the references that aren't valid
+func Problems(references []Reference) []string {
+	var result []string
+	for _, reference := range references {
+		if !reference.Valid {
+			result = append(result, reference.Problem)
+		}
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package ticketdomain
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractKeys(t *testing.T) {
+	patterns, _ := (&policydomain.TicketRules{}).CompilePatterns()
+	assert.EqualValues(t, []string{"PAY-12", "OPS-7"}, ExtractKeys(patterns, "PAY-12: handle refunds\n\nAlso fixes OPS-7 and PAY-12"))
+	assert.EqualValues(t, 0, len(ExtractKeys(patterns, "handle refunds")))
+	assert.EqualValues(t, 0, len(ExtractKeys(patterns, "")))
+}
+
+func TestExtractKeysWithGroups(t *testing.T) {
+	patterns := []*regexp.Regexp{regexp.MustCompile(`\[(\w+-\d+)\]`), regexp.MustCompile(`(?i)\bops-\d+\b`)}
+	assert.EqualValues(t, []string{"PAY-12", "OPS-7"}, ExtractKeys(patterns, "[pay-12] handle refunds for ops-7"))
+}
+
+func TestNewReference(t *testing.T) {
+	rules := &policydomain.TicketRules{AllowedStates: []string{"In Progress", "In Review"}}
+
+	reference := NewReference("PAY-12", SourcePullTitle, &Issue{Key: "PAY-12", Status: "In Review"}, rules)
+	assert.EqualValues(t, Reference{Key: "PAY-12", Source: SourcePullTitle, Status: "In Review", Valid: true}, reference)
+
+	reference = NewReference("PAY-13", SourcePullBody, &Issue{Key: "PAY-13", Status: "Done"}, rules)
+	assert.False(t, reference.Valid)
+	assert.EqualValues(t, "Done", reference.Status)
+	assert.EqualValues(t, "the ticket PAY-13 is Done, expected one of In Progress, In Review", reference.Problem)
+
+	reference = NewReference("PAY-14", SourceCommitMessage, nil, rules)
+	assert.False(t, reference.Valid)
+	assert.EqualValues(t, "the ticket PAY-14 doesn't exist", reference.Problem)
+
+	reference = NewUnverifiedReference("PAY-15", SourceCommitMessage)
+	assert.True(t, reference.Valid)
+	assert.EqualValues(t, "", reference.Status)
+}
+
+func TestGetStatus(t *testing.T) {
+	valid := Reference{Key: "PAY-12", Valid: true}
+	invalid := Reference{Key: "UTF-8", Problem: "the ticket UTF-8 doesn't exist"}
+
+	assert.EqualValues(t, StatusMissing, GetStatus(nil))
+	assert.EqualValues(t, StatusValid, GetStatus([]Reference{invalid, valid}))
+	assert.EqualValues(t, StatusInvalid, GetStatus([]Reference{invalid}))
+	assert.EqualValues(t, []string{"the ticket UTF-8 doesn't exist"}, Problems([]Reference{invalid, valid}))
+}
@@ -0,0 +1,163 @@
+package notifications
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v2"
+)
+
+//the types of sink that can be configured
+const (
+	SinkTypeSlack = "slack"
+	SinkTypeTeams = "teams"
+	SinkTypeEmail = "email"
+)
+
+//Config is the layout of the file defining the sinks and the routes that send the reports to them
+type Config struct {
+	Sinks  []SinkConfig  `yaml:"sinks"`
+	Routes []RouteConfig `yaml:"routes"`
+}
+
+//SinkConfig defines a single sink, the password is read from the environment variable named by PasswordEnv
+//so it doesn't need to be kept in the file
+type SinkConfig struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`
+	URL         string   `yaml:"url"`
+	Host        string   `yaml:"host"`
+	Port        int      `yaml:"port"`
+	Username    string   `yaml:"username"`
+	PasswordEnv string   `yaml:"password_env"`
+	From        string   `yaml:"from"`
+	To          []string `yaml:"to"`
+}
+
+//RouteConfig sends the reports of the matching repos to the named sinks
+//by default only reports with unreviewed commits are sent, the templates default to the report as text
+type RouteConfig struct {
+	Repos   []string `yaml:"repos"`
+	Sinks   []string `yaml:"sinks"`
+	When    string   `yaml:"when"`
+	Subject string   `yaml:"subject"`
+	Text    string   `yaml:"text"`
+}
+
+//LoadConfig reads the notifications file and returns a notifier that routes the reports to the sinks
+func LoadConfig(path string) (Notifier, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid notifications file %s: %s", path, err.Error())
+	}
+
+	notifier, err := NewNotifier(&config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifications file %s: %s", path, err.Error())
+	}
+	return notifier, nil
+}
+
+//NewNotifier creates the sinks and routes, every sink a route uses must be defined
+func NewNotifier(config *Config) (Notifier, error) {
+	sinks := make(map[string]Sink)
+	for _, sinkConfig := range config.Sinks {
+		if _, exists := sinks[sinkConfig.Name]; exists {
+			return nil, fmt.Errorf("the sink name %s is used more than once", sinkConfig.Name)
+		}
+		sink, err := newSink(&sinkConfig)
+		if err != nil {
+			return nil, err
+		}
+		sinks[sinkConfig.Name] = sink
+	}
+
+	notifier := &routingNotifier{}
+	for counter, routeConfig := range config.Routes {
+		route, err := newRoute(&routeConfig, sinks)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route %d: %s", counter+1, err.Error())
+		}
+		notifier.routes = append(notifier.routes, route)
+	}
+	return notifier, nil
+}
+
+//newSink creates the sink, checking it has everything it needs
+func newSink(config *SinkConfig) (Sink, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("a name is required for each sink")
+	}
+
+	switch config.Type {
+	case SinkTypeSlack, SinkTypeTeams:
+		webhookURL, err := url.Parse(config.URL)
+		if err != nil || (webhookURL.Scheme != "https" && webhookURL.Scheme != "http") || webhookURL.Host == "" {
+			return nil, fmt.Errorf("a valid url is required for the sink %s", config.Name)
+		}
+		if config.Type == SinkTypeSlack {
+			return NewSlackSink(config.Name, config.URL), nil
+		}
+		return NewTeamsSink(config.Name, config.URL), nil
+	case SinkTypeEmail:
+		if config.Host == "" || config.From == "" || len(config.To) == 0 {
+			return nil, fmt.Errorf("a host, from and to are required for the sink %s", config.Name)
+		}
+		port := config.Port
+		if port == 0 {
+			port = 25
+		}
+		password := ""
+		if config.PasswordEnv != "" {
+			password = os.Getenv(config.PasswordEnv)
+		}
+		return NewEmailSink(config.Name, config.Host, port, config.Username, password, config.From, config.To), nil
+	default:
+		return nil, fmt.Errorf("unknown type %s for the sink %s", config.Type, config.Name)
+	}
+}
+
+//newRoute creates the route, looking up its sinks and parsing its templates
+func newRoute(config *RouteConfig, sinks map[string]Sink) (*route, error) {
+	if len(config.Repos) == 0 || len(config.Sinks) == 0 {
+		return nil, fmt.Errorf("repos and sinks are required")
+	}
+
+	when := config.When
+	if when == "" {
+		when = WhenUnreviewed
+	}
+	if when != WhenUnreviewed && when != WhenAlways {
+		return nil, fmt.Errorf("unknown when %s", config.When)
+	}
+
+	for _, pattern := range config.Repos {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid repo pattern %s", pattern)
+		}
+	}
+
+	result := &route{repos: config.Repos, when: when}
+	for _, name := range config.Sinks {
+		sink, exists := sinks[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown sink %s", name)
+		}
+		result.sinks = append(result.sinks, sink)
+	}
+
+	templates, err := newMessageTemplates(config.Subject, config.Text)
+	if err != nil {
+		return nil, err
+	}
+	result.templates = templates
+	return result, nil
+}
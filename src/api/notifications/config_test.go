@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//writeConfigFile writes the contents to a temporary file, the returned function removes it
+func writeConfigFile(t *testing.T, contents string) (string, func()) {
+	dir, err := ioutil.TempDir("", "notifications")
+	assert.Nil(t, err)
+	path := filepath.Join(dir, "notifications.yaml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	os.Setenv("TEST_SMTP_PASSWORD", "secret")
+	defer os.Unsetenv("TEST_SMTP_PASSWORD")
+
+	path, cleanup := writeConfigFile(t, `
+sinks:
+  - name: team-slack
+    type: slack
+    url: https://hooks.slack.com/services/T000/B000/XXXX
+  - name: team-teams
+    type: teams
+    url: https://example.webhook.office.com/webhookb2/abc
+  - name: compliance
+    type: email
+    host: smtp.example.com
+    port: 587
+    username: reports
+    password_env: TEST_SMTP_PASSWORD
+    from: reports@example.com
+    to: [audit@example.com]
+routes:
+  - repos: ["myorg/*"]
+    sinks: [team-slack, team-teams]
+  - repos: ["*/*"]
+    sinks: [compliance]
+    when: always
+    subject: "Weekly review of {{.Repo}}"
+`)
+	defer cleanup()
+
+	notifier, err := LoadConfig(path)
+	assert.Nil(t, err)
+	routes := notifier.(*routingNotifier).routes
+	assert.EqualValues(t, 2, len(routes))
+	assert.EqualValues(t, WhenUnreviewed, routes[0].when)
+	assert.EqualValues(t, "team-slack", routes[0].sinks[0].Name())
+	assert.EqualValues(t, "team-teams", routes[0].sinks[1].Name())
+	assert.EqualValues(t, WhenAlways, routes[1].when)
+
+	email := routes[1].sinks[0].(*emailSink)
+	assert.EqualValues(t, 587, email.port)
+	assert.EqualValues(t, "secret", email.password)
+
+	message, err := routes[1].templates.render(getTestReport("myorg", "myrepo", 0))
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Weekly review of myrepo", message.Subject)
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	notifier, err := LoadConfig(filepath.Join(os.TempDir(), "does-not-exist", "notifications.yaml"))
+	assert.Nil(t, notifier)
+	assert.NotNil(t, err)
+}
+
+func TestLoadConfigDefaultPort(t *testing.T) {
+	notifier, err := NewNotifier(&Config{Sinks: []SinkConfig{{Name: "compliance", Type: SinkTypeEmail, Host: "localhost", From: "a@example.com", To: []string{"b@example.com"}}}})
+	assert.Nil(t, err)
+	assert.NotNil(t, notifier)
+
+	sink, err := newSink(&SinkConfig{Name: "compliance", Type: SinkTypeEmail, Host: "localhost", From: "a@example.com", To: []string{"b@example.com"}})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 25, sink.(*emailSink).port)
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	slack := "sinks:\n  - name: team-slack\n    type: slack\n    url: https://hooks.slack.com/services/x\n"
+	tests := map[string]string{
+		"unknown field":       "sinks:\n  - name: a\n    type: slack\n    channel: general\n",
+		"no sink name":        "sinks:\n  - type: slack\n    url: https://hooks.slack.com/services/x\n",
+		"duplicate sink name": slack + "  - name: team-slack\n    type: teams\n    url: https://example.com/x\n",
+		"unknown sink type":   "sinks:\n  - name: a\n    type: pager\n",
+		"invalid url":         "sinks:\n  - name: a\n    type: teams\n    url: not a url\n",
+		"incomplete email":    "sinks:\n  - name: a\n    type: email\n    host: localhost\n",
+		"unknown sink":        slack + "routes:\n  - repos: ['*/*']\n    sinks: [other]\n",
+		"no repos":            slack + "routes:\n  - sinks: [team-slack]\n",
+		"bad repo pattern":    slack + "routes:\n  - repos: ['[']\n    sinks: [team-slack]\n",
+		"unknown when":        slack + "routes:\n  - repos: ['*/*']\n    sinks: [team-slack]\n    when: sometimes\n",
+		"bad template":        slack + "routes:\n  - repos: ['*/*']\n    sinks: [team-slack]\n    text: '{{.Repo'\n",
+	}
+
+	for name, contents := range tests {
+		path, cleanup := writeConfigFile(t, contents)
+		notifier, err := LoadConfig(path)
+		assert.Nil(t, notifier, name)
+		assert.NotNil(t, err, name)
+		cleanup()
+	}
+}
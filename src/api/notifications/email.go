@@ -0,0 +1,102 @@
+package notifications
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//smtpTimeout limits how long sending an email can take so an SMTP server that doesn't answer can't hold up the other sinks
+const smtpTimeout = 30 * time.Second
+
+//emailSink sends the message by email through an SMTP server
+type emailSink struct {
+	name     string
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+//NewEmailSink returns a sink that sends email through the SMTP server
+//no authentication is used if the username is empty, Go only allows authentication over TLS or to localhost
+func NewEmailSink(name string, host string, port int, username string, password string, from string, to []string) Sink {
+	return &emailSink{name: name, host: host, port: port, username: username, password: password, from: from, to: to}
+}
+
+func (s *emailSink) Name() string {
+	return s.name
+}
+
+//Send sends the message to each of the recipients, TLS is used when the server offers it as smtp.SendMail does
+func (s *emailSink) Send(message *Message) error {
+	address := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	conn, err := net.DialTimeout("tcp", address, smtpTimeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(s.buildEmail(message)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+//subjectLineBreaks turns the line breaks of a subject into spaces, a line break would end the header
+var subjectLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+//buildEmail returns the email as plain text with the lines ending in CRLF as SMTP expects
+func (s *emailSink) buildEmail(message *Message) []byte {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("From: %s\r\n", s.from))
+	sb.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(s.to, ", ")))
+	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", subjectLineBreaks.Replace(message.Subject)))
+	sb.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Text, "\r\n", "\n"), "\n", "\r\n"))
+	sb.WriteString("\r\n")
+	return []byte(sb.String())
+}
@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//startSMTPServer starts a stand-in SMTP server that accepts a single email, the email is sent on the channel
+//the commands it receives are sent along with the data so the envelope can be checked
+func startSMTPServer(t *testing.T) (string, int, chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	emails := make(chan string, 1)
+
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) {
+			conn.Write([]byte(line + "\r\n"))
+		}
+		var received strings.Builder
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL"), strings.HasPrefix(command, "RCPT"):
+				received.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 end with .")
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil || dataLine == ".\r\n" {
+						break
+					}
+					received.WriteString(dataLine)
+				}
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 bye")
+				emails <- received.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	assert.Nil(t, err)
+	portNumber, err := strconv.Atoi(port)
+	assert.Nil(t, err)
+	return host, portNumber, emails
+}
+
+func TestEmailSink(t *testing.T) {
+	host, port, emails := startSMTPServer(t)
+
+	sink := NewEmailSink("compliance", host, port, "", "", "reports@example.com", []string{"audit@example.com", "lead@example.com"})
+	assert.EqualValues(t, "compliance", sink.Name())
+	assert.Nil(t, sink.Send(&Message{Subject: "myorg/myrepo", Text: "first line\nsecond line"}))
+
+	email := <-emails
+	assert.Contains(t, email, "MAIL FROM:<reports@example.com>")
+	assert.Contains(t, email, "RCPT TO:<audit@example.com>")
+	assert.Contains(t, email, "RCPT TO:<lead@example.com>")
+	assert.Contains(t, email, "From: reports@example.com\r\n")
+	assert.Contains(t, email, "To: audit@example.com, lead@example.com\r\n")
+	assert.Contains(t, email, "Subject: myorg/myrepo\r\n")
+	assert.Contains(t, email, "Content-Type: text/plain; charset=UTF-8\r\n")
+	assert.Contains(t, email, "\r\n\r\nfirst line\r\nsecond line\r\n")
+}
+
+func TestEmailSinkUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	sink := NewEmailSink("compliance", "127.0.0.1", port, "", "", "reports@example.com", []string{"audit@example.com"})
+	assert.NotNil(t, sink.Send(&Message{Subject: "subject", Text: "text"}))
+}
+
+func TestBuildEmailSubjectOnOneLine(t *testing.T) {
+	sink := &emailSink{from: "reports@example.com", to: []string{"audit@example.com"}}
+	email := string(sink.buildEmail(&Message{Subject: "first\nsecond\rthird\r\nfourth", Text: "text"}))
+	assert.Contains(t, email, "Subject: first second third fourth\r\n")
+}
@@ -0,0 +1,102 @@
+//Package notifications tells people about the outcome of the reports through sinks such as Slack, Teams and email
+//which sinks are told about which repos is set by routing rules
+package notifications
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+)
+
+//when a route sends a notification, a report has unreviewed commits when some have no PR or were only reviewed on other branches
+const (
+	WhenUnreviewed = "unreviewed"
+	WhenAlways     = "always"
+)
+
+//Message is a notification ready to be sent to a sink
+type Message struct {
+	Subject string
+	Text    string
+}
+
+//Sink is somewhere a notification can be sent
+type Sink interface {
+	Name() string
+	Send(message *Message) error
+}
+
+//Notifier sends notifications about the reports
+type Notifier interface {
+	Notify(report *reportdomain.CodeReviewReport) error
+}
+
+//disabledNotifier is used when no notifications have been configured, nothing is sent
+type disabledNotifier struct{}
+
+//NewDisabledNotifier returns a notifier that doesn't send anything
+func NewDisabledNotifier() Notifier {
+	return &disabledNotifier{}
+}
+
+func (n *disabledNotifier) Notify(report *reportdomain.CodeReviewReport) error {
+	return nil
+}
+
+//route sends the reports of the matching repos to its sinks
+type route struct {
+	repos     []string
+	when      string
+	sinks     []Sink
+	templates *messageTemplates
+}
+
+//matches determines if the route applies to the report
+//the repos are given as owner/repo patterns, such as myorg/* for every repo of the organisation
+func (r *route) matches(report *reportdomain.CodeReviewReport) bool {
+	if r.when == WhenUnreviewed && report.TotalCommitsWithNoPR == 0 && report.TotalCommitsReviewedOnOtherBranches == 0 {
+		return false
+	}
+
+	fullName := strings.ToLower(fmt.Sprintf("%s/%s", report.Owner, report.Repo))
+	for _, pattern := range r.repos {
+		if matched, _ := path.Match(strings.ToLower(pattern), fullName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+//routingNotifier sends each report to the sinks of every route that matches it
+type routingNotifier struct {
+	routes []*route
+}
+
+//Notify sends the report to the sinks of each matching route
+//a failure to send to one sink doesn't stop the others, all the failures are returned together
+func (n *routingNotifier) Notify(report *reportdomain.CodeReviewReport) error {
+	var failures []string
+	for _, route := range n.routes {
+		if !route.matches(report) {
+			continue
+		}
+
+		message, err := route.templates.render(report)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		for _, sink := range route.sinks {
+			if err := sink.Send(message); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s", sink.Name(), err.Error()))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("unable to send notifications: %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+//recordingSink keeps the messages sent to it, failing if it has been given an error
+type recordingSink struct {
+	name     string
+	err      error
+	messages []Message
+}
+
+func (s *recordingSink) Name() string {
+	return s.name
+}
+
+func (s *recordingSink) Send(message *Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, *message)
+	return nil
+}
+
+func getTestReport(owner string, repo string, commitsWithNoPR int) *reportdomain.CodeReviewReport {
+	return &reportdomain.CodeReviewReport{
+		Owner:                owner,
+		Repo:                 repo,
+		Branch:               "main",
+		FromDate:             time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC),
+		ToDate:               time.Date(2020, 1, 13, 0, 0, 0, 0, time.UTC),
+		TotalCommits:         4,
+		TotalCommitsWithNoPR: commitsWithNoPR,
+	}
+}
+
+func TestConstants(t *testing.T) {
+	assert.EqualValues(t, "unreviewed", WhenUnreviewed)
+	assert.EqualValues(t, "always", WhenAlways)
+	assert.EqualValues(t, "slack", SinkTypeSlack)
+	assert.EqualValues(t, "teams", SinkTypeTeams)
+	assert.EqualValues(t, "email", SinkTypeEmail)
+}
+
+func TestDisabledNotifier(t *testing.T) {
+	assert.Nil(t, NewDisabledNotifier().Notify(getTestReport("myorg", "myrepo", 1)))
+}
+
+func TestRouteMatches(t *testing.T) {
+	route := route{repos: []string{"MyOrg/*", "myuser/myrepo"}, when: WhenUnreviewed}
+
+	assert.True(t, route.matches(getTestReport("myorg", "another", 1)))
+	assert.True(t, route.matches(getTestReport("MyUser", "MyRepo", 2)))
+	assert.False(t, route.matches(getTestReport("myuser", "another", 1)))
+	assert.False(t, route.matches(getTestReport("myorg", "another", 0)))
+
+	//a commit only reviewed on another branch didn't get a review on its way into the branch
+	report := getTestReport("myorg", "another", 0)
+	report.TotalCommitsReviewedOnOtherBranches = 1
+	assert.True(t, route.matches(report))
+
+	route.when = WhenAlways
+	assert.True(t, route.matches(getTestReport("myorg", "another", 0)))
+}
+
+func TestRoutingNotifierSendsToMatchingRoutes(t *testing.T) {
+	slack := &recordingSink{name: "slack"}
+	email := &recordingSink{name: "email"}
+	templates, err := newMessageTemplates("{{.Owner}}/{{.Repo}}", "{{.TotalCommitsWithNoPR}} unreviewed")
+	assert.Nil(t, err)
+
+	notifier := &routingNotifier{routes: []*route{
+		{repos: []string{"myorg/*"}, when: WhenUnreviewed, sinks: []Sink{slack}, templates: templates},
+		{repos: []string{"*/*"}, when: WhenAlways, sinks: []Sink{email}, templates: templates},
+	}}
+
+	assert.Nil(t, notifier.Notify(getTestReport("myorg", "myrepo", 2)))
+	assert.Nil(t, notifier.Notify(getTestReport("myorg", "another", 0)))
+
+	assert.EqualValues(t, []Message{{Subject: "myorg/myrepo", Text: "2 unreviewed"}}, slack.messages)
+	assert.EqualValues(t, 2, len(email.messages))
+	assert.EqualValues(t, "myorg/another", email.messages[1].Subject)
+}
+
+func TestRoutingNotifierCarriesOnAfterFailure(t *testing.T) {
+	broken := &recordingSink{name: "broken", err: errors.New("connection refused")}
+	working := &recordingSink{name: "working"}
+	templates, err := newMessageTemplates("", "")
+	assert.Nil(t, err)
+
+	notifier := &routingNotifier{routes: []*route{
+		{repos: []string{"*/*"}, when: WhenUnreviewed, sinks: []Sink{broken, working}, templates: templates},
+	}}
+
+	err = notifier.Notify(getTestReport("myorg", "myrepo", 1))
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "unable to send notifications: broken: connection refused", err.Error())
+	assert.EqualValues(t, 1, len(working.messages))
+}
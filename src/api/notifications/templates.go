@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+)
+
+//the templates used when a route doesn't give its own, the report is passed to each template
+const (
+	defaultSubjectTemplate = `{{.Owner}}/{{.Repo}}: {{.TotalCommitsWithNoPR}} commits with no PR between {{.FromDate.Format "2006-01-02"}} and {{.ToDate.Format "2006-01-02"}}`
+	defaultTextTemplate    = `{{.Text}}`
+)
+
+//messageTemplates turn a report into a message
+type messageTemplates struct {
+	subject *template.Template
+	text    *template.Template
+}
+
+//newMessageTemplates parses the templates, the defaults are used for any that are empty
+func newMessageTemplates(subject string, text string) (*messageTemplates, error) {
+	if subject == "" {
+		subject = defaultSubjectTemplate
+	}
+	if text == "" {
+		text = defaultTextTemplate
+	}
+
+	subjectTemplate, err := template.New("subject").Option("missingkey=error").Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject template: %s", err.Error())
+	}
+	textTemplate, err := template.New("text").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid text template: %s", err.Error())
+	}
+	return &messageTemplates{subject: subjectTemplate, text: textTemplate}, nil
+}
+
+//render creates the message for the report
+func (t *messageTemplates) render(report *reportdomain.CodeReviewReport) (*Message, error) {
+	var subject, text bytes.Buffer
+	if err := t.subject.Execute(&subject, report); err != nil {
+		return nil, fmt.Errorf("unable to render the subject template: %s", err.Error())
+	}
+	if err := t.text.Execute(&text, report); err != nil {
+		return nil, fmt.Errorf("unable to render the text template: %s", err.Error())
+	}
+	return &Message{Subject: subject.String(), Text: text.String()}, nil
+}
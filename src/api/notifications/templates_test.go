@@ -0,0 +1,54 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultTemplates(t *testing.T) {
+	templates, err := newMessageTemplates("", "")
+	assert.Nil(t, err)
+
+	report := getTestReport("myorg", "myrepo", 3)
+	message, err := templates.render(report)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "myorg/myrepo: 3 commits with no PR between 2020-01-06 and 2020-01-13", message.Subject)
+	assert.EqualValues(t, report.Text(), message.Text)
+}
+
+func TestCustomTemplates(t *testing.T) {
+	templates, err := newMessageTemplates("Unreviewed commits in {{.Repo}}", "{{.Summary}}\n{{range .Commits}}{{.SHA}} {{.Author}}\n{{end}}")
+	assert.Nil(t, err)
+
+	report := getTestReport("myorg", "myrepo", 1)
+	report.Commits = append(report.Commits, reportdomain.CommitReview{SHA: "AAA111", Author: "some name"})
+	message, err := templates.render(report)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Unreviewed commits in myrepo", message.Subject)
+	assert.EqualValues(t, report.Summary()+"\nAAA111 some name\n", message.Text)
+}
+
+func TestInvalidTemplates(t *testing.T) {
+	templates, err := newMessageTemplates("{{.Repo", "")
+	assert.Nil(t, templates)
+	assert.NotNil(t, err)
+
+	templates, err = newMessageTemplates("", "{{end}}")
+	assert.Nil(t, templates)
+	assert.NotNil(t, err)
+
+	//fields that don't exist are only found when the template is run
+	templates, err = newMessageTemplates("{{.Unknown}}", "")
+	assert.Nil(t, err)
+	message, err := templates.render(getTestReport("myorg", "myrepo", 1))
+	assert.Nil(t, message)
+	assert.NotNil(t, err)
+
+	templates, err = newMessageTemplates("", "{{.Unknown}}")
+	assert.Nil(t, err)
+	message, err = templates.render(getTestReport("myorg", "myrepo", 1))
+	assert.Nil(t, message)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,74 @@
+package notifications
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+)
+
+//slackMessage is the body of a message sent to a Slack compatible incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+//teamsMessage is the body of a message card sent to a Microsoft Teams incoming webhook
+type teamsMessage struct {
+	Type    string `json:"@type"`
+	Context string `json:"@context"`
+	Summary string `json:"summary"`
+	Title   string `json:"title"`
+	Text    string `json:"text"`
+}
+
+//webhookSink posts the message to an incoming webhook
+type webhookSink struct {
+	name   string
+	URL    string
+	format func(message *Message) interface{}
+}
+
+//NewSlackSink returns a sink that posts to a Slack compatible incoming webhook
+func NewSlackSink(name string, URL string) Sink {
+	return &webhookSink{name: name, URL: URL, format: func(message *Message) interface{} {
+		return slackMessage{Text: fmt.Sprintf("*%s*\n%s", message.Subject, message.Text)}
+	}}
+}
+
+//NewTeamsSink returns a sink that posts a message card to a Microsoft Teams incoming webhook
+//Teams treats the text as markdown so it is sent as preformatted text to keep the layout of the report
+func NewTeamsSink(name string, URL string) Sink {
+	return &webhookSink{name: name, URL: URL, format: func(message *Message) interface{} {
+		return teamsMessage{
+			Type:    "MessageCard",
+			Context: "http://schema.org/extensions",
+			Summary: message.Subject,
+			Title:   message.Subject,
+			Text:    fmt.Sprintf("```\n%s\n```", message.Text),
+		}
+	}}
+}
+
+func (s *webhookSink) Name() string {
+	return s.name
+}
+
+//Send posts the message, anything other than a 2xx response is treated as a failure
+func (s *webhookSink) Send(message *Message) error {
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+
+	response, err := restclient.Post(s.URL, s.format(message), headers)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned status %d", response.StatusCode)
+	}
+	return nil
+}
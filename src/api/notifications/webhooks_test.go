@@ -0,0 +1,71 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//startWebhookServer starts a stand-in webhook that replies with the status code, the bodies it receives are sent on the channel
+func startWebhookServer(statusCode int) (*httptest.Server, chan []byte) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method == http.MethodPost && r.Header.Get("Content-Type") == "application/json" {
+			bodies <- body
+		}
+		w.WriteHeader(statusCode)
+	}))
+	return server, bodies
+}
+
+func TestSlackSink(t *testing.T) {
+	server, bodies := startWebhookServer(http.StatusOK)
+	defer server.Close()
+
+	sink := NewSlackSink("team-slack", server.URL)
+	assert.EqualValues(t, "team-slack", sink.Name())
+	assert.Nil(t, sink.Send(&Message{Subject: "myorg/myrepo", Text: "2 commits with no PR"}))
+
+	var body slackMessage
+	assert.Nil(t, json.Unmarshal(<-bodies, &body))
+	assert.EqualValues(t, "*myorg/myrepo*\n2 commits with no PR", body.Text)
+}
+
+func TestTeamsSink(t *testing.T) {
+	server, bodies := startWebhookServer(http.StatusOK)
+	defer server.Close()
+
+	sink := NewTeamsSink("team-teams", server.URL)
+	assert.EqualValues(t, "team-teams", sink.Name())
+	assert.Nil(t, sink.Send(&Message{Subject: "myorg/myrepo", Text: "2 commits with no PR"}))
+
+	var body map[string]string
+	assert.Nil(t, json.Unmarshal(<-bodies, &body))
+	assert.EqualValues(t, "MessageCard", body["@type"])
+	assert.EqualValues(t, "http://schema.org/extensions", body["@context"])
+	assert.EqualValues(t, "myorg/myrepo", body["summary"])
+	assert.EqualValues(t, "myorg/myrepo", body["title"])
+	assert.EqualValues(t, "```\n2 commits with no PR\n```", body["text"])
+}
+
+func TestWebhookSinkErrorStatus(t *testing.T) {
+	server, _ := startWebhookServer(http.StatusForbidden)
+	defer server.Close()
+
+	err := NewSlackSink("team-slack", server.URL).Send(&Message{Subject: "subject", Text: "text"})
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "webhook returned status 403", err.Error())
+}
+
+func TestWebhookSinkUnreachable(t *testing.T) {
+	server, _ := startWebhookServer(http.StatusOK)
+	server.Close()
+
+	err := NewTeamsSink("team-teams", server.URL).Send(&Message{Subject: "subject", Text: "text"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,57 @@
+package githubprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+)
+
+//information needed to publish results against a commit in Github
+const (
+	urlCreateCommitStatus = "https://api.github.com/repos/%s/%s/statuses/%s"
+	urlCreateCheckRun     = "https://api.github.com/repos/%s/%s/check-runs"
+)
+
+//CreateCommitStatus sets the status of the commit, the latest status for each context is the one shown by Github
+func CreateCommitStatus(accessToken string, owner string, repo string, sha string, request githubdomain.CreateCommitStatusRequest) (*githubdomain.CommitStatus, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlCreateCommitStatus, owner, repo, sha)
+	headers := getCommonHeader(accessToken)
+
+	bytes, err := postDataToGithubAPI(URL, headers, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result githubdomain.CommitStatus
+
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
+		return nil, getUnmarshalBodyError()
+	}
+	return &result, nil
+}
+
+//CreateCheckRun creates a check run against the commit in the request
+//Github only allows check runs to be created with a Github App installation token
+func CreateCheckRun(accessToken string, owner string, repo string, request githubdomain.CreateCheckRunRequest) (*githubdomain.CheckRun, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlCreateCheckRun, owner, repo)
+	headers := getCommonHeader(accessToken)
+	headers.Set(headerAccept, headerChecksAPI)
+
+	bytes, err := postDataToGithubAPI(URL, headers, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result githubdomain.CheckRun
+
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
+		return nil, getUnmarshalBodyError()
+	}
+	return &result, nil
+}
@@ -0,0 +1,133 @@
+package githubprovider
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantsForCommitStatus(t *testing.T) {
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/statuses/%s", urlCreateCommitStatus)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/check-runs", urlCreateCheckRun)
+	assert.EqualValues(t, "application/vnd.github.antiope-preview+json", headerChecksAPI)
+}
+
+func TestCreateCommitStatusErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/statuses/AAA111",
+		HTTPMethod: http.MethodPost,
+		Err:        errors.New("invalid rest client response"),
+	})
+	response, err := CreateCommitStatus("", "myuser", "myrepo", "AAA111", githubdomain.CreateCommitStatusRequest{})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
+	assert.EqualValues(t, "invalid rest client response", err.Message)
+}
+
+func TestCreateCommitStatusInvalidState(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/statuses/AAA111",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Validation Failed","errors":[{"resource":"Status","code":"custom","field":"state","message":"state is not included in the list"}]}`)),
+		},
+	})
+	response, err := CreateCommitStatus("", "myuser", "myrepo", "AAA111", githubdomain.CreateCommitStatusRequest{State: "maybe"})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, err.StatusCode)
+	assert.EqualValues(t, "Validation Failed", err.Message)
+	assert.EqualValues(t, "state", err.Errors[0].Field)
+}
+
+func TestCreateCommitStatusErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/statuses/AAA111",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"id": 1}]`)),
+		},
+	})
+	response, err := CreateCommitStatus("", "myuser", "myrepo", "AAA111", githubdomain.CreateCommitStatusRequest{})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestCreateCommitStatusNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/statuses/AAA111",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":1,"state":"success","description":"Reviewed in PR #12","context":"code-review-compliance"}`)),
+		},
+	})
+	response, err := CreateCommitStatus("", "myuser", "myrepo", "AAA111", githubdomain.CreateCommitStatusRequest{State: githubdomain.CommitStatusSuccess})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, response.ID)
+	assert.EqualValues(t, githubdomain.CommitStatusSuccess, response.State)
+}
+
+func TestCreateCheckRunForbidden(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/check-runs",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusForbidden,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "You must authenticate via a GitHub App."}`)),
+		},
+	})
+	response, err := CreateCheckRun("", "myuser", "myrepo", githubdomain.CreateCheckRunRequest{HeadSHA: "AAA111"})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusForbidden, err.StatusCode)
+	assert.EqualValues(t, "You must authenticate via a GitHub App.", err.Message)
+}
+
+func TestCreateCheckRunErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/check-runs",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"id": 1}]`)),
+		},
+	})
+	response, err := CreateCheckRun("", "myuser", "myrepo", githubdomain.CreateCheckRunRequest{HeadSHA: "AAA111"})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestCreateCheckRunNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/check-runs",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":4,"head_sha":"AAA111","name":"code-review-compliance","status":"completed","conclusion":"failure","html_url":"https://github.com/myuser/myrepo/runs/4"}`)),
+		},
+	})
+	response, err := CreateCheckRun("", "myuser", "myrepo", githubdomain.CreateCheckRunRequest{HeadSHA: "AAA111"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, response.ID)
+	assert.EqualValues(t, githubdomain.CheckRunConclusionFailure, response.Conclusion)
+	assert.EqualValues(t, "https://github.com/myuser/myrepo/runs/4", response.HTMLURL)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
@@ -14,12 +16,28 @@ import (
 const (
 	urlGetRepoCommits            = "https://api.github.com/repos/%s/%s/commits"
 	urlGetRepoSingleCommit       = "https://api.github.com/repos/%s/%s/commits/%s"
-	urlGetRepoCommitsInDateRange = "https://api.github.com/repos/%s/%s/commits?since=%s&until=%s"
+	urlGetRepoCommitsInDateRange = "https://api.github.com/repos/%s/%s/commits?sha=%s&since=%s&until=%s&per_page=%d"
+	urlGetRepoCommitsOnBranch    = "https://api.github.com/repos/%s/%s/commits?sha=%s&per_page=%d"
+	urlGetRepoCommitsSince       = "https://api.github.com/repos/%s/%s/commits?sha=%s&since=%s&per_page=%d"
+	urlGetCombinedStatus         = "https://api.github.com/repos/%s/%s/commits/%s/status"
+	urlGetCheckRuns              = "https://api.github.com/repos/%s/%s/commits/%s/check-runs?per_page=%d"
 )
 
-//GetRepoCommits returns commits for the given repo
-func GetRepoCommits(accessToken string, owner string, repo string) ([]githubdomain.GetCommitInfo, *githubdomain.GithubErrorResponse) {
-	URL := fmt.Sprintf(urlGetRepoCommits, owner, repo)
+//withPath limits the commits Github returns to those changing the file or directory, an empty path leaves the URL as it is
+func withPath(URL string, path string) string {
+	if path == "" {
+		return URL
+	}
+	separator := "?"
+	if strings.Contains(URL, "?") {
+		separator = "&"
+	}
+	return URL + separator + "path=" + url.QueryEscape(path)
+}
+
+//GetRepoCommits returns commits for the given repo, limited to those changing the path if one is given
+func GetRepoCommits(accessToken string, owner string, repo string, path string) ([]githubdomain.GetCommitInfo, *githubdomain.GithubErrorResponse) {
+	URL := withPath(fmt.Sprintf(urlGetRepoCommits, owner, repo), path)
 	headers := getCommonHeader(accessToken)
 
 	bytes, err := getDataFromGithubAPI(URL, headers)
@@ -37,22 +55,47 @@ func GetRepoCommits(accessToken string, owner string, repo string) ([]githubdoma
 	return result, nil
 }
 
-//GetRepoCommitsInDateRange returns commits for the given repo
-func GetRepoCommitsInDateRange(accessToken string, owner string, repo string, fromDate time.Time, toDate time.Time) ([]githubdomain.GetCommitInfo, *githubdomain.GithubErrorResponse) {
-	URL := fmt.Sprintf(urlGetRepoCommitsInDateRange, owner, repo, fromDate.UTC().Format(FmtGithubDate), toDate.UTC().Format(FmtGithubDate))
+//GetRepoCommitsInDateRange returns every commit on the given branch of the repo between the dates, following all the pages of results
+//the commits are limited to those changing the path if one is given
+func GetRepoCommitsInDateRange(accessToken string, owner string, repo string, branch string, fromDate time.Time, toDate time.Time, path string) ([]githubdomain.GetCommitInfo, *githubdomain.GithubErrorResponse) {
+	URL := withPath(fmt.Sprintf(urlGetRepoCommitsInDateRange, owner, repo, url.QueryEscape(branch), fromDate.UTC().Format(FmtGithubDate), toDate.UTC().Format(FmtGithubDate), perPage), path)
 	headers := getCommonHeader(accessToken)
 
-	bytes, err := getDataFromGithubAPI(URL, headers)
-
+	result := make([]githubdomain.GetCommitInfo, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.GetCommitInfo
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return result, nil
+}
 
-	var result []githubdomain.GetCommitInfo
+//GetRepoCommitsSince returns every commit on the given branch since the date, following all the pages of results
+//a zero date returns the whole history of the branch
+func GetRepoCommitsSince(accessToken string, owner string, repo string, branch string, since time.Time) ([]githubdomain.GetCommitInfo, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetRepoCommitsOnBranch, owner, repo, url.QueryEscape(branch), perPage)
+	if !since.IsZero() {
+		URL = fmt.Sprintf(urlGetRepoCommitsSince, owner, repo, url.QueryEscape(branch), since.UTC().Format(FmtGithubDate), perPage)
+	}
+	headers := getCommonHeader(accessToken)
 
-	if err := json.Unmarshal(bytes, &result); err != nil {
-		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
-		return nil, getUnmarshalBodyError()
+	result := make([]githubdomain.GetCommitInfo, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.GetCommitInfo
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return result, nil
 }
@@ -77,3 +120,41 @@ func GetRepoSingleCommit(accessToken string, owner string, repo string, sha stri
 	}
 	return &result, nil
 }
+
+//GetCombinedStatus returns the latest status of each context on the commit
+func GetCombinedStatus(accessToken string, owner string, repo string, ref string) (*githubdomain.CombinedStatus, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetCombinedStatus, owner, repo, ref)
+
+	bytes, err := getDataFromGithubAPI(URL, getCommonHeader(accessToken))
+	if err != nil {
+		return nil, err
+	}
+
+	var result githubdomain.CombinedStatus
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
+		return nil, getUnmarshalBodyError()
+	}
+	return &result, nil
+}
+
+//GetCheckRuns returns every check run on the commit, following all the pages of results
+func GetCheckRuns(accessToken string, owner string, repo string, ref string) ([]githubdomain.CheckRun, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetCheckRuns, owner, repo, ref, perPage)
+	headers := getCommonHeader(accessToken)
+	headers.Set(headerAccept, headerChecksAPI)
+
+	result := make([]githubdomain.CheckRun, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page githubdomain.CheckRunList
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page.CheckRuns...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
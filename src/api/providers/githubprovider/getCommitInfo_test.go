@@ -15,6 +15,10 @@ import (
 func TestConstantsForCommits(t *testing.T) {
 	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/commits", urlGetRepoCommits)
 	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/commits/%s", urlGetRepoSingleCommit)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/commits?sha=%s&per_page=%d", urlGetRepoCommitsOnBranch)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/commits?sha=%s&since=%s&per_page=%d", urlGetRepoCommitsSince)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/commits/%s/status", urlGetCombinedStatus)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/commits/%s/check-runs?per_page=%d", urlGetCheckRuns)
 }
 
 func TestGetRepoCommitsErrorFromGithub(t *testing.T) {
@@ -24,7 +28,7 @@ func TestGetRepoCommitsErrorFromGithub(t *testing.T) {
 		HTTPMethod: http.MethodGet,
 		Err:        errors.New("invalid rest client response"),
 	})
-	response, err := GetRepoCommits("", "myuser", "myrepo")
+	response, err := GetRepoCommits("", "myuser", "myrepo", "")
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, "invalid rest client response", err.Message)
@@ -41,7 +45,7 @@ func TestGetRepoCommitsErrorResponseBody(t *testing.T) {
 			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "123"}`)),
 		},
 	})
-	response, err := GetRepoCommits("", "myuser", "myrepo")
+	response, err := GetRepoCommits("", "myuser", "myrepo", "")
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
@@ -59,7 +63,7 @@ func TestGetRepoCommitsNoError(t *testing.T) {
 			Body:       ioutil.NopCloser(strings.NewReader(`[{"url":"http://www.github.com","sha":"AABCDEF123456","commit":{"url":"http://www.github.com","author":{"name":"some name","email":"email@email.com","date":"2019-12-09T15:00:04.061358Z"},"committer":{"name":"some committer","email":"someemail@email.com","date":"2019-12-09T15:00:04.061358Z"},"message":"some commit message"},"author":{"login":"some loing id","id":9876,"type":"user","site_admin":true},"committer":{"login":"login id","id":12345,"type":"user","site_admin":false},"parents":[{"url":"http://test.com","sha":"ABCDEF123456768"},{"url":"http://test12.com","sha":"ABFGGG"}]}]`)),
 		},
 	})
-	response, err := GetRepoCommits("", "myuser", "myrepo")
+	response, err := GetRepoCommits("", "myuser", "myrepo", "")
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
 	assert.EqualValues(t, len(response), 1)
@@ -72,18 +76,42 @@ func TestGetRepoCommitsNoError(t *testing.T) {
 
 }
 
+func TestWithPath(t *testing.T) {
+	assert.EqualValues(t, "https://api.github.com/repos/myuser/myrepo/commits", withPath("https://api.github.com/repos/myuser/myrepo/commits", ""))
+	assert.EqualValues(t, "https://api.github.com/repos/myuser/myrepo/commits?path=services%2Fpayments",
+		withPath("https://api.github.com/repos/myuser/myrepo/commits", "services/payments"))
+	assert.EqualValues(t, "https://api.github.com/repos/myuser/myrepo/commits?sha=main&path=docs%2FREADME+notes.md",
+		withPath("https://api.github.com/repos/myuser/myrepo/commits?sha=main", "docs/README notes.md"))
+}
+
+func TestGetRepoCommitsForPath(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?path=services%2Fpayments",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"AABCDEF123456"}]`)),
+		},
+	})
+	response, err := GetRepoCommits("", "myuser", "myrepo", "services/payments")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(response))
+	assert.EqualValues(t, "AABCDEF123456", response[0].SHA)
+}
+
 func TestGetRepoCommitsDateRangeErrorFromGithub(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(FmtGithubDate) + "&until=" + toDate.UTC().Format(FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=master&since=" + fromDate.UTC().Format(FmtGithubDate) + "&until=" + toDate.UTC().Format(FmtGithubDate) + "&per_page=100"
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
 		HTTPMethod: http.MethodGet,
 		Err:        errors.New("invalid rest client response"),
 	})
-	response, err := GetRepoCommitsInDateRange("", "myuser", "myrepo", fromDate, toDate)
+	response, err := GetRepoCommitsInDateRange("", "myuser", "myrepo", "master", fromDate, toDate, "")
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, "invalid rest client response", err.Message)
@@ -94,7 +122,7 @@ func TestGetRepoCommitsDateRangeErrorResponseBody(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(FmtGithubDate) + "&until=" + toDate.UTC().Format(FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=master&since=" + fromDate.UTC().Format(FmtGithubDate) + "&until=" + toDate.UTC().Format(FmtGithubDate) + "&per_page=100"
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -104,19 +132,49 @@ func TestGetRepoCommitsDateRangeErrorResponseBody(t *testing.T) {
 			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "123"}`)),
 		},
 	})
-	response, err := GetRepoCommitsInDateRange("", "myuser", "myrepo", fromDate, toDate)
+	response, err := GetRepoCommitsInDateRange("", "myuser", "myrepo", "master", fromDate, toDate, "")
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
 	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
 }
 
+func TestGetRepoCommitsDateRangeFollowsPages(t *testing.T) {
+	restclient.FlushMockups()
+	fromDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+	secondPage := "https://api.github.com/repositories/1/commits?sha=master&since=2020-03-01T00:00:00Z&until=2020-03-31T00:00:00Z&per_page=100&page=2"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=master&since=2020-03-01T00:00:00Z&until=2020-03-31T00:00:00Z&per_page=100&path=docs",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"SHA1"},{"sha":"SHA2"}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"SHA3"}]`)),
+		},
+	})
+
+	response, err := GetRepoCommitsInDateRange("", "myuser", "myrepo", "master", fromDate, toDate, "docs")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(response))
+	assert.EqualValues(t, "SHA3", response[2].SHA)
+}
+
 func TestGetRepoCommitsDateRangeNoError(t *testing.T) {
 
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(FmtGithubDate) + "&until=" + toDate.UTC().Format(FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=master&since=" + fromDate.UTC().Format(FmtGithubDate) + "&until=" + toDate.UTC().Format(FmtGithubDate) + "&per_page=100"
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -127,7 +185,7 @@ func TestGetRepoCommitsDateRangeNoError(t *testing.T) {
 		},
 	})
 
-	response, err := GetRepoCommitsInDateRange("", "myuser", "myrepo", fromDate, toDate)
+	response, err := GetRepoCommitsInDateRange("", "myuser", "myrepo", "master", fromDate, toDate, "")
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
 	assert.EqualValues(t, len(response), 1)
@@ -140,6 +198,99 @@ func TestGetRepoCommitsDateRangeNoError(t *testing.T) {
 
 }
 
+func TestGetRepoCommitsSinceFollowsPages(t *testing.T) {
+	restclient.FlushMockups()
+	since := time.Now().UTC().AddDate(0, -1, 0)
+	firstPage := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + since.Format(FmtGithubDate) + "&per_page=100"
+	secondPage := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + since.Format(FmtGithubDate) + "&per_page=100&page=2"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        firstPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next", <` + secondPage + `>; rel="last"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"AABCDEF123456"},{"sha":"AABCDEF123457"}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"AABCDEF123458"}]`)),
+		},
+	})
+
+	response, err := GetRepoCommitsSince("", "myuser", "myrepo", "main", since)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(response))
+	assert.EqualValues(t, "AABCDEF123456", response[0].SHA)
+	assert.EqualValues(t, "AABCDEF123458", response[2].SHA)
+}
+
+func TestGetRepoCommitsSinceWholeHistory(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[]`)),
+		},
+	})
+
+	response, err := GetRepoCommitsSince("", "myuser", "myrepo", "main", time.Time{})
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+	assert.EqualValues(t, 0, len(response))
+}
+
+func TestGetRepoCommitsSinceEscapesBranch(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=release%2F1.0%26hotfix&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"AABCDEF123456"}]`)),
+		},
+	})
+
+	response, err := GetRepoCommitsSince("", "myuser", "myrepo", "release/1.0&hotfix", time.Time{})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(response))
+}
+
+func TestGetRepoCommitsSinceErrors(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Err:        errors.New("invalid rest client response"),
+	})
+
+	response, err := GetRepoCommitsSince("", "myuser", "myrepo", "main", time.Time{})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "invalid rest client response", err.Message)
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"sha":"AABCDEF123456"}`)),
+		},
+	})
+
+	response, err = GetRepoCommitsSince("", "myuser", "myrepo", "main", time.Time{})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
 func TestGetRepoSingleCommitErrorFromGithub(t *testing.T) {
 
 	restclient.FlushMockups()
@@ -195,3 +346,109 @@ func TestGetRepoSingleCommitNoError(t *testing.T) {
 	assert.EqualValues(t, "http://www.github.com", response.Commit.URL)
 
 }
+
+func TestGetCombinedStatusErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AAA111/status",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+	response, err := GetCombinedStatus("", "myuser", "myrepo", "AAA111")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetCombinedStatusErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AAA111/status",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[]`)),
+		},
+	})
+	response, err := GetCombinedStatus("", "myuser", "myrepo", "AAA111")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetCombinedStatusNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AAA111/status",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"state":"success","sha":"AAA111","total_count":1,"statuses":[{"id":1,"state":"success","context":"ci/build"}]}`)),
+		},
+	})
+	response, err := GetCombinedStatus("", "myuser", "myrepo", "AAA111")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "success", response.State)
+	assert.EqualValues(t, "ci/build", response.Statuses[0].Context)
+}
+
+func TestGetCheckRunsErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AAA111/check-runs?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+	response, err := GetCheckRuns("", "myuser", "myrepo", "AAA111")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetCheckRunsErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AAA111/check-runs?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[]`)),
+		},
+	})
+	response, err := GetCheckRuns("", "myuser", "myrepo", "AAA111")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetCheckRunsFollowsPages(t *testing.T) {
+	restclient.FlushMockups()
+	firstPage := "https://api.github.com/repos/myuser/myrepo/commits/AAA111/check-runs?per_page=100"
+	secondPage := firstPage + "&page=2"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        firstPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"total_count":2,"check_runs":[{"id":1,"name":"build","status":"completed","conclusion":"success"}]}`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"total_count":2,"check_runs":[{"id":2,"name":"test","status":"queued"}]}`)),
+		},
+	})
+
+	response, err := GetCheckRuns("", "myuser", "myrepo", "AAA111")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, "build", response[0].Name)
+	assert.EqualValues(t, "queued", response[1].Status)
+}
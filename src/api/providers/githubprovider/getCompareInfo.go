@@ -0,0 +1,79 @@
+package githubprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+)
+
+//information needed to compare two refs in Github
+const (
+	urlGetCompareCommits      = "https://api.github.com/repos/%s/%s/compare/%s...%s"
+	urlGetCompareCommitsPaged = "https://api.github.com/repos/%s/%s/compare/%s...%s?per_page=%d"
+	errorMissingCompareRef    = "a base and a head ref are needed to compare"
+)
+
+//GetCompareCommits compares the head ref against the base ref of the repo
+//refs can be branch names, tags or commit SHAs, they are escaped as they are part of the path
+func GetCompareCommits(accessToken string, owner string, repo string, base string, head string) (*githubdomain.GetCompareCommitsResponse, *githubdomain.GithubErrorResponse) {
+	if err := checkCompareRefs(base, head); err != nil {
+		return nil, err
+	}
+	URL := fmt.Sprintf(urlGetCompareCommits, owner, repo, url.PathEscape(base), url.PathEscape(head))
+	headers := getCommonHeader(accessToken)
+
+	bytes, err := getDataFromGithubAPI(URL, headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result githubdomain.GetCompareCommitsResponse
+
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
+		return nil, getUnmarshalBodyError()
+	}
+	return &result, nil
+}
+
+//GetCompareAllCommits compares the head ref against the base ref of the repo, following all the pages of commits
+//a single comparison only lists the first 250 commits so this is used when every commit between the refs is needed
+func GetCompareAllCommits(accessToken string, owner string, repo string, base string, head string) (*githubdomain.GetCompareCommitsResponse, *githubdomain.GithubErrorResponse) {
+	if err := checkCompareRefs(base, head); err != nil {
+		return nil, err
+	}
+	URL := fmt.Sprintf(urlGetCompareCommitsPaged, owner, repo, url.PathEscape(base), url.PathEscape(head), perPage)
+	headers := getCommonHeader(accessToken)
+
+	var result *githubdomain.GetCompareCommitsResponse
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page githubdomain.GetCompareCommitsResponse
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		if result == nil {
+			result = &page
+		} else {
+			result.Commits = append(result.Commits, page.Commits...)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//checkCompareRefs returns a bad request error unless both refs are given
+func checkCompareRefs(base string, head string) *githubdomain.GithubErrorResponse {
+	if strings.TrimSpace(base) == "" || strings.TrimSpace(head) == "" {
+		return &githubdomain.GithubErrorResponse{StatusCode: http.StatusBadRequest, Message: errorMissingCompareRef}
+	}
+	return nil
+}
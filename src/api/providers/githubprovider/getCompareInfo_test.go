@@ -0,0 +1,164 @@
+package githubprovider
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantsForCompare(t *testing.T) {
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/compare/%s...%s", urlGetCompareCommits)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/compare/%s...%s?per_page=%d", urlGetCompareCommitsPaged)
+}
+
+func TestGetCompareCommitsErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/main...ABC",
+		HTTPMethod: http.MethodGet,
+		Err:        errors.New("invalid rest client response"),
+	})
+	response, err := GetCompareCommits("", "myuser", "myrepo", "main", "ABC")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "invalid rest client response", err.Message)
+}
+
+func TestGetCompareCommitsErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/main...ABC",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"status": "ahead"}]`)),
+		},
+	})
+	response, err := GetCompareCommits("", "myuser", "myrepo", "main", "ABC")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetCompareCommitsNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/main...ABC",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"url":"some URL","status":"behind","ahead_by":0,"behind_by":2,"total_commits":0,"base_commit":{"sha":"BASE123"},"merge_base_commit":{"sha":"ABC"},"commits":[]}`)),
+		},
+	})
+	response, err := GetCompareCommits("", "myuser", "myrepo", "main", "ABC")
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+	assert.EqualValues(t, "behind", response.Status)
+	assert.EqualValues(t, 2, response.BehindBy)
+	assert.EqualValues(t, "ABC", response.MergeBaseCommit.SHA)
+}
+
+func TestGetCompareAllCommitsErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/v1.0.0...v1.1.0?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Err:        errors.New("invalid rest client response"),
+	})
+	response, err := GetCompareAllCommits("", "myuser", "myrepo", "v1.0.0", "v1.1.0")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "invalid rest client response", err.Message)
+}
+
+func TestGetCompareAllCommitsErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/v1.0.0...v1.1.0?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"status": "ahead"}]`)),
+		},
+	})
+	response, err := GetCompareAllCommits("", "myuser", "myrepo", "v1.0.0", "v1.1.0")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetCompareAllCommitsFollowsPages(t *testing.T) {
+	restclient.FlushMockups()
+	firstPage := "https://api.github.com/repos/myuser/myrepo/compare/v1.0.0...v1.1.0?per_page=100"
+	secondPage := "https://api.github.com/repos/myuser/myrepo/compare/v1.0.0...v1.1.0?per_page=100&page=2"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        firstPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next", <` + secondPage + `>; rel="last"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"ahead","ahead_by":3,"total_commits":3,"commits":[{"sha":"SHA1"},{"sha":"SHA2"}]}`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"ahead","ahead_by":3,"total_commits":3,"commits":[{"sha":"SHA3"}]}`)),
+		},
+	})
+
+	response, err := GetCompareAllCommits("", "myuser", "myrepo", "v1.0.0", "v1.1.0")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "ahead", response.Status)
+	assert.EqualValues(t, 3, response.TotalCommits)
+	assert.EqualValues(t, 3, len(response.Commits))
+	assert.EqualValues(t, "SHA3", response.Commits[2].SHA)
+}
+
+func TestGetCompareCommitsEscapesRefs(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/release%2F1.0...fix%23123?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"ahead","ahead_by":1,"total_commits":1,"commits":[{"sha":"SHA1"}]}`)),
+		},
+	})
+	response, err := GetCompareAllCommits("", "myuser", "myrepo", "release/1.0", "fix#123")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(response.Commits))
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/main...feature%2Fa%20b",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"behind","behind_by":1}`)),
+		},
+	})
+	single, err := GetCompareCommits("", "myuser", "myrepo", "main", "feature/a b")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "behind", single.Status)
+}
+
+func TestGetCompareCommitsMissingRef(t *testing.T) {
+	restclient.FlushMockups()
+	response, err := GetCompareAllCommits("", "myuser", "myrepo", "", "v1.1.0")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode)
+	assert.EqualValues(t, "a base and a head ref are needed to compare", err.Message)
+
+	response, err = GetCompareCommits("", "myuser", "myrepo", "main", " ")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode)
+}
@@ -0,0 +1,31 @@
+package githubprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+)
+
+//information needed to get the files held in a repo from Github
+const (
+	urlGetRepoContent = "https://api.github.com/repos/%s/%s/contents/%s?ref=%s"
+)
+
+//GetRepoContent returns the file at the path as of the given ref, a branch name or a commit SHA
+func GetRepoContent(accessToken string, owner string, repo string, path string, ref string) (*githubdomain.RepoContent, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetRepoContent, owner, repo, path, ref)
+
+	bytes, err := getDataFromGithubAPI(URL, getCommonHeader(accessToken))
+	if err != nil {
+		return nil, err
+	}
+
+	var result githubdomain.RepoContent
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
+		return nil, getUnmarshalBodyError()
+	}
+	return &result, nil
+}
@@ -0,0 +1,62 @@
+package githubprovider
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantsForContent(t *testing.T) {
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/contents/%s?ref=%s", urlGetRepoContent)
+}
+
+func TestGetRepoContentNotFound(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/contents/.github/CODEOWNERS?ref=main",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+	response, err := GetRepoContent("", "myuser", "myrepo", ".github/CODEOWNERS", "main")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetRepoContentErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/contents/docs?ref=main",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"type":"file","name":"README.md"}]`)),
+		},
+	})
+	response, err := GetRepoContent("", "myuser", "myrepo", "docs", "main")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetRepoContentNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/contents/CODEOWNERS?ref=main",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"type":"file","encoding":"base64","name":"CODEOWNERS","path":"CODEOWNERS","content":"KiBAbXl1c2Vy\n"}`)),
+		},
+	})
+	response, err := GetRepoContent("", "myuser", "myrepo", "CODEOWNERS", "main")
+	assert.Nil(t, err)
+	content, errDecode := response.DecodedContent()
+	assert.Nil(t, errDecode)
+	assert.EqualValues(t, "* @myuser", content)
+}
@@ -0,0 +1,93 @@
+package githubprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+)
+
+//information needed to get the deployments and releases of a repo from Github
+const (
+	urlGetDeployments        = "https://api.github.com/repos/%s/%s/deployments?environment=%s&per_page=%d"
+	urlGetDeploymentStatuses = "https://api.github.com/repos/%s/%s/deployments/%d/statuses?per_page=%d"
+	urlGetReleases           = "https://api.github.com/repos/%s/%s/releases?per_page=%d"
+)
+
+//GetDeploymentsSince returns the deployments to the environment created since the given date, newest first
+//the deployments before the date are also returned up to the first one isBase accepts, such as the last one that succeeded,
+//so the changes in the first deployment can be found, a nil isBase accepts the latest deployment before the date
+func GetDeploymentsSince(accessToken string, owner string, repo string, environment string, since time.Time, isBase func(deployment *githubdomain.Deployment) bool) ([]githubdomain.Deployment, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetDeployments, owner, repo, url.QueryEscape(environment), perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.Deployment, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.Deployment
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+
+		for counter := range page {
+			result = append(result, page[counter])
+			if page[counter].CreatedAt.Before(since) && (isBase == nil || isBase(&page[counter])) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetDeploymentStatuses returns the statuses reported for the deployment, newest first
+func GetDeploymentStatuses(accessToken string, owner string, repo string, deploymentID int64) ([]githubdomain.DeploymentStatus, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetDeploymentStatuses, owner, repo, deploymentID, perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.DeploymentStatus, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.DeploymentStatus
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetReleasesSince returns the releases created since the given date, newest first, drafts are included
+//the releases before the date are also returned up to the latest published one, neither a draft nor a pre-release,
+//so the changes in the first release can be found
+func GetReleasesSince(accessToken string, owner string, repo string, since time.Time) ([]githubdomain.Release, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetReleases, owner, repo, perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.Release, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.Release
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+
+		for _, release := range page {
+			result = append(result, release)
+			if !release.Draft && !release.Prerelease && release.CreatedAt.Before(since) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package githubprovider
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func addDeploymentMock(URL string, statusCode int, header http.Header, body string) {
+	restclient.AddMockup(restclient.Mock{
+		URL:        URL,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: statusCode,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		},
+	})
+}
+
+func TestConstantsForDeployments(t *testing.T) {
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/deployments?environment=%s&per_page=%d", urlGetDeployments)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/deployments/%d/statuses?per_page=%d", urlGetDeploymentStatuses)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/releases?per_page=%d", urlGetReleases)
+}
+
+func TestGetDeploymentsSinceErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/deployments?environment=production&per_page=100", http.StatusNotFound, nil, `{"message": "Not Found"}`)
+
+	response, err := GetDeploymentsSince("", "myuser", "myrepo", "production", time.Time{}, nil)
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetDeploymentsSinceErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/deployments?environment=production&per_page=100", http.StatusOK, nil, `{"id":1}`)
+
+	response, err := GetDeploymentsSince("", "myuser", "myrepo", "production", time.Time{}, nil)
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetDeploymentsSinceStopsAfterFirstOlderDeployment(t *testing.T) {
+	secondPage := "https://api.github.com/repositories/1/deployments?environment=production&per_page=100&page=2"
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/deployments?environment=production&per_page=100", http.StatusOK,
+		http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+		`[{"id":3,"created_at":"2020-03-10T10:00:00Z"},{"id":2,"created_at":"2020-03-05T10:00:00Z"}]`)
+	addDeploymentMock(secondPage, http.StatusOK, nil,
+		`[{"id":1,"created_at":"2020-02-20T10:00:00Z"},{"id":0,"created_at":"2020-02-10T10:00:00Z"}]`)
+
+	response, err := GetDeploymentsSince("", "myuser", "myrepo", "production", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(response))
+	assert.EqualValues(t, 1, response[2].ID)
+}
+
+func TestGetDeploymentsSinceStopsAtBaseDeployment(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/deployments?environment=production&per_page=100", http.StatusOK, nil,
+		`[{"id":3,"created_at":"2020-03-10T10:00:00Z"},{"id":2,"created_at":"2020-02-25T10:00:00Z"},{"id":1,"created_at":"2020-02-20T10:00:00Z"},{"id":0,"created_at":"2020-02-10T10:00:00Z"}]`)
+
+	isBase := func(deployment *githubdomain.Deployment) bool {
+		return deployment.ID == 1
+	}
+	response, err := GetDeploymentsSince("", "myuser", "myrepo", "production", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), isBase)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(response))
+	assert.EqualValues(t, 1, response[2].ID)
+}
+
+func TestGetDeploymentsSinceEscapesEnvironment(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/deployments?environment=eu+prod%26test&per_page=100", http.StatusOK, nil,
+		`[{"id":1,"created_at":"2020-02-20T10:00:00Z"}]`)
+
+	response, err := GetDeploymentsSince("", "myuser", "myrepo", "eu prod&test", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(response))
+}
+
+func TestGetDeploymentStatusesErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/deployments/3/statuses?per_page=100", http.StatusNotFound, nil, `{"message": "Not Found"}`)
+
+	response, err := GetDeploymentStatuses("", "myuser", "myrepo", 3)
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetDeploymentStatusesErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/deployments/3/statuses?per_page=100", http.StatusOK, nil, `{"id":1}`)
+
+	response, err := GetDeploymentStatuses("", "myuser", "myrepo", 3)
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetDeploymentStatusesNoError(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/deployments/3/statuses?per_page=100", http.StatusOK, nil,
+		`[{"id":2,"state":"success","created_at":"2020-03-10T10:05:00Z"},{"id":1,"state":"in_progress","created_at":"2020-03-10T10:00:00Z"}]`)
+
+	response, err := GetDeploymentStatuses("", "myuser", "myrepo", 3)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, "success", response[0].State)
+}
+
+func TestGetReleasesSinceErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/releases?per_page=100", http.StatusUnauthorized, nil, `{"message": "Bad credentials"}`)
+
+	response, err := GetReleasesSince("", "myuser", "myrepo", time.Time{})
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusUnauthorized, err.StatusCode)
+}
+
+func TestGetReleasesSinceErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/releases?per_page=100", http.StatusOK, nil, `{"id":1}`)
+
+	response, err := GetReleasesSince("", "myuser", "myrepo", time.Time{})
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetReleasesSinceStopsAfterFirstOlderRelease(t *testing.T) {
+	restclient.FlushMockups()
+	addDeploymentMock("https://api.github.com/repos/myuser/myrepo/releases?per_page=100", http.StatusOK, nil, `[
+		{"id":4,"tag_name":"v1.2.0","created_at":"2020-03-10T10:00:00Z"},
+		{"id":3,"tag_name":"v1.1.1","draft":true,"created_at":"2020-02-25T10:00:00Z"},
+		{"id":2,"tag_name":"v1.1.0","created_at":"2020-02-20T10:00:00Z"},
+		{"id":1,"tag_name":"v1.0.0","created_at":"2020-02-10T10:00:00Z"}
+	]`)
+
+	response, err := GetReleasesSince("", "myuser", "myrepo", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(response))
+	assert.EqualValues(t, "v1.1.0", response[2].TagName)
+}
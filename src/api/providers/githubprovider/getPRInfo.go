@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
 )
 
 //information needed to get PR data from Github
 const (
-	urlGetRepoPRs          = "https://api.github.com/repos/%s/%s/pulls?state=%s"
+	urlGetRepoPRs          = "https://api.github.com/repos/%s/%s/pulls?state=%s&per_page=%d"
 	urlGetRepoSinglePR     = "https://api.github.com/repos/%s/%s/pulls/%s"
-	urlGetRepoPRForCommits = "https://api.github.com/repos/%s/%s/commits/%s/pulls"
+	urlGetRepoPRForCommits = "https://api.github.com/repos/%s/%s/commits/%s/pulls?per_page=%d"
+	urlGetRepoPRsByUpdated = "https://api.github.com/repos/%s/%s/pulls?state=all&sort=updated&direction=desc&per_page=%d"
+	urlGetPRFiles          = "https://api.github.com/repos/%s/%s/pulls/%s/files?per_page=%d"
+	urlGetPRCommits        = "https://api.github.com/repos/%s/%s/pulls/%s/commits?per_page=%d"
 )
 
 //GetRepoSinglePR returns the given PR for a repo
@@ -41,31 +45,32 @@ func GetRepoSinglePR(accessToken string, owner string, repo string, pullNumber s
 	return &result, nil
 }
 
-//getRepoPRsFromURL is used to return more than one pull request
+//getRepoPRsFromURL is used to return more than one pull request, following all the pages of results
 func getRepoPRsFromURL(URL string, headers http.Header) ([]githubdomain.GetSinglePullRequestResponse, *githubdomain.GithubErrorResponse) {
 
-	bytes, err := getDataFromGithubAPI(URL, headers)
-
+	result := make([]githubdomain.GetSinglePullRequestResponse, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		//now we have a page of the response, unmarshal it back into the correct object to return
+		var page []githubdomain.GetSinglePullRequestResponse
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	//now we have the response, unmarshal it back into the correct object to return
-	var result []githubdomain.GetSinglePullRequestResponse
-	if err := json.Unmarshal(bytes, &result); err != nil {
-		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
-		return nil, getUnmarshalBodyError()
-	}
 	return result, nil
 
 }
 
-//GetRepoPRs returns all of the PRs in the given repo
+//GetRepoPRs returns all of the PRs in the given repo, following all the pages of results
 func GetRepoPRs(accessToken string, owner string, repo string, state string) ([]githubdomain.GetSinglePullRequestResponse, *githubdomain.GithubErrorResponse) {
 
 	//need to construct the URL to call and also the headers to send
 	//these vary depending on the API call being made as described in the githubdomain API documentation
-	URL := fmt.Sprintf(urlGetRepoPRs, owner, repo, state)
+	URL := fmt.Sprintf(urlGetRepoPRs, owner, repo, state, perPage)
 
 	headers := getCommonHeader(accessToken)
 	headers.Set(headerAccept, headerPRDraftAPI)
@@ -73,12 +78,12 @@ func GetRepoPRs(accessToken string, owner string, repo string, state string) ([]
 	return getRepoPRsFromURL(URL, headers)
 }
 
-//GetSingleCommitPR returns all the PRs associated with a single commit SHA
+//GetSingleCommitPR returns all the PRs associated with a single commit SHA, following all the pages of results
 func GetSingleCommitPR(accessToken string, owner string, repo string, SHA string) ([]githubdomain.GetSinglePullRequestResponse, *githubdomain.GithubErrorResponse) {
 
 	//construct the URL and headers
 	//these can vary depending on the end point being called
-	URL := fmt.Sprintf(urlGetRepoPRForCommits, owner, repo, SHA)
+	URL := fmt.Sprintf(urlGetRepoPRForCommits, owner, repo, SHA, perPage)
 
 	headers := getCommonHeader(accessToken)
 	headers.Set(headerAccept, headerPRForCommitDraftAPI)
@@ -87,3 +92,74 @@ func GetSingleCommitPR(accessToken string, owner string, repo string, SHA string
 	return getRepoPRsFromURL(URL, headers)
 
 }
+
+//GetRepoPRsUpdatedSince returns every PR in the repo updated since the given date, most recently updated first
+//the PRs are requested in order of update so paging stops at the first PR older than the date
+func GetRepoPRsUpdatedSince(accessToken string, owner string, repo string, since time.Time) ([]githubdomain.GetSinglePullRequestResponse, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetRepoPRsByUpdated, owner, repo, perPage)
+
+	headers := getCommonHeader(accessToken)
+	headers.Set(headerAccept, headerPRDraftAPI)
+
+	result := make([]githubdomain.GetSinglePullRequestResponse, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.GetSinglePullRequestResponse
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+
+		for _, pullRequest := range page {
+			if pullRequest.UpdatedAt.Before(since) {
+				return false, nil
+			}
+			result = append(result, pullRequest)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetPRFiles returns every file changed by the PR, following all the pages of results
+//Github lists at most 3000 files for a PR
+func GetPRFiles(accessToken string, owner string, repo string, pullNumber string) ([]githubdomain.ChangedFile, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetPRFiles, owner, repo, pullNumber, perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.ChangedFile, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.ChangedFile
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetPRCommits returns every commit of the PR, oldest first, following all the pages of results
+//Github lists at most 250 commits for a PR
+func GetPRCommits(accessToken string, owner string, repo string, pullNumber string) ([]githubdomain.GetCommitInfo, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetPRCommits, owner, repo, pullNumber, perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.GetCommitInfo, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.GetCommitInfo
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
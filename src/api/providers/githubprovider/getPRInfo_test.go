@@ -26,8 +26,11 @@ func TestConstants(t *testing.T) {
 	assert.EqualValues(t, "Accept", headerAccept)
 	assert.EqualValues(t, "application/vnd.github.shadow-cat-preview+json", headerPRDraftAPI)
 	assert.EqualValues(t, "application/vnd.github.groot-preview+json", headerPRForCommitDraftAPI)
-	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/pulls?state=%s", urlGetRepoPRs)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/pulls?state=%s&per_page=%d", urlGetRepoPRs)
 	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/pulls/%s", urlGetRepoSinglePR)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/pulls?state=all&sort=updated&direction=desc&per_page=%d", urlGetRepoPRsByUpdated)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/pulls/%s/files?per_page=%d", urlGetPRFiles)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/pulls/%s/commits?per_page=%d", urlGetPRCommits)
 
 }
 
@@ -39,7 +42,7 @@ func TestGetAuthorizationHeader(t *testing.T) {
 func TestGetRepoPRsErrorRestclient(t *testing.T) {
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/pulls?state=all",
+		URL:        "https://api.github.com/repos/test/user1/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Err:        errors.New("invalid rest client response"),
 	})
@@ -54,7 +57,7 @@ func TestGetRepoPRsErrorInvalidResponseBody(t *testing.T) {
 	restclient.FlushMockups()
 	invalidCloser, _ := os.Open("-asf3")
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/pulls?state=all",
+		URL:        "https://api.github.com/repos/test/user1/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
@@ -73,7 +76,7 @@ func TestGetRepoPRsErrorInvalidErrorInterface(t *testing.T) {
 	restclient.FlushMockups()
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/pulls?state=all",
+		URL:        "https://api.github.com/repos/test/user1/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusUnauthorized,
@@ -92,7 +95,7 @@ func TestGetRepoPRsUnauthorized(t *testing.T) {
 	restclient.FlushMockups()
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/pulls?state=all",
+		URL:        "https://api.github.com/repos/test/user1/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusUnauthorized,
@@ -111,7 +114,7 @@ func TestGetRepoPRsInvalidResponse(t *testing.T) {
 	restclient.FlushMockups()
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/pulls?state=all",
+		URL:        "https://api.github.com/repos/test/user1/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
@@ -130,7 +133,7 @@ func TestGetRepoPRsNoError(t *testing.T) {
 	restclient.FlushMockups()
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/pulls?state=all",
+		URL:        "https://api.github.com/repos/test/user1/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
@@ -315,7 +318,7 @@ func TestGetGetSingleCommitPRErrorFromGithub(t *testing.T) {
 	restclient.FlushMockups()
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls",
+		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Err:        errors.New("invalid rest client response"),
 	})
@@ -330,7 +333,7 @@ func TestGetGetSingleCommitPRErrorResponseBody(t *testing.T) {
 	restclient.FlushMockups()
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls",
+		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
@@ -348,7 +351,7 @@ func TestGetGetSingleCommitPRNoError(t *testing.T) {
 	restclient.FlushMockups()
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls",
+		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
@@ -362,3 +365,209 @@ func TestGetGetSingleCommitPRNoError(t *testing.T) {
 	assert.EqualValues(t, 123456, response[0].ID)
 	//the JSON is tested elswhere so not doing a full set of assertions here
 }
+
+func TestGetSingleCommitPRFollowsPages(t *testing.T) {
+	secondPage := "https://api.github.com/repositories/1/commits/sha123/pulls?per_page=100&page=2"
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"number":9,"base":{"ref":"develop"}}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"number":10,"base":{"ref":"main"}}]`)),
+		},
+	})
+
+	response, err := GetSingleCommitPR("", "test", "user1", "sha123")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, 9, response[0].Number)
+	assert.EqualValues(t, 10, response[1].Number)
+}
+
+func TestGetRepoPRsUpdatedSinceStopsAtOlderPR(t *testing.T) {
+	restclient.FlushMockups()
+	firstPage := "https://api.github.com/repos/myuser/myrepo/pulls?state=all&sort=updated&direction=desc&per_page=100"
+	secondPage := firstPage + "&page=2"
+	restclient.AddMockup(restclient.Mock{
+		URL:        firstPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"number":12,"updated_at":"2019-12-09T15:00:00Z"},{"number":11,"updated_at":"2019-12-08T15:00:00Z"}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + firstPage + `&page=3>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"number":10,"updated_at":"2019-12-07T15:00:00Z"},{"number":9,"updated_at":"2019-12-01T15:00:00Z"}]`)),
+		},
+	})
+
+	response, err := GetRepoPRsUpdatedSince("", "myuser", "myrepo", time.Date(2019, 12, 5, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(response))
+	assert.EqualValues(t, 12, response[0].Number)
+	assert.EqualValues(t, 10, response[2].Number)
+}
+
+func TestGetRepoPRsUpdatedSinceErrors(t *testing.T) {
+	URL := "https://api.github.com/repos/myuser/myrepo/pulls?state=all&sort=updated&direction=desc&per_page=100"
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        URL,
+		HTTPMethod: http.MethodGet,
+		Err:        errors.New("invalid rest client response"),
+	})
+
+	response, err := GetRepoPRsUpdatedSince("", "myuser", "myrepo", time.Time{})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "invalid rest client response", err.Message)
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        URL,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"number":12}`)),
+		},
+	})
+
+	response, err = GetRepoPRsUpdatedSince("", "myuser", "myrepo", time.Time{})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetPRFilesErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/files?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+	response, err := GetPRFiles("", "myuser", "myrepo", "9")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetPRFilesErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/files?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"filename": "a.go"}`)),
+		},
+	})
+	response, err := GetPRFiles("", "myuser", "myrepo", "9")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetPRFilesFollowsPages(t *testing.T) {
+	secondPage := "https://api.github.com/repositories/1/pulls/9/files?per_page=100&page=2"
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/files?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"filename":"src/a.go","status":"modified","changes":3}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"filename":"docs/b.md","status":"added","changes":10}]`)),
+		},
+	})
+
+	response, err := GetPRFiles("", "myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, "src/a.go", response[0].Filename)
+	assert.EqualValues(t, "docs/b.md", response[1].Filename)
+}
+
+func TestGetPRCommitsErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/commits?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+	response, err := GetPRCommits("", "myuser", "myrepo", "9")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetPRCommitsErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/commits?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"sha": "SHA1"}`)),
+		},
+	})
+	response, err := GetPRCommits("", "myuser", "myrepo", "9")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetPRCommitsFollowsPages(t *testing.T) {
+	secondPage := "https://api.github.com/repositories/1/pulls/9/commits?per_page=100&page=2"
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/commits?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"SHA1","commit":{"message":"feat: first"}}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"SHA2","commit":{"message":"fix: second"}}]`)),
+		},
+	})
+
+	response, err := GetPRCommits("", "myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, "feat: first", response[0].Commit.Message)
+	assert.EqualValues(t, "SHA2", response[1].SHA)
+}
@@ -0,0 +1,54 @@
+package githubprovider
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+)
+
+//information needed to get the reviews of a PR from Github
+const (
+	urlGetPRReviews        = "https://api.github.com/repos/%s/%s/pulls/%s/reviews?per_page=%d"
+	urlGetPRReviewComments = "https://api.github.com/repos/%s/%s/pulls/%s/comments?per_page=%d"
+)
+
+//GetPRReviews returns the reviews of the given PR, oldest first, following all the pages of results
+func GetPRReviews(accessToken string, owner string, repo string, pullNumber string) ([]githubdomain.PullRequestReview, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetPRReviews, owner, repo, pullNumber, perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.PullRequestReview, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.PullRequestReview
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetPRReviewComments returns the comments left on the lines of the changes of the PR, oldest first, following all the pages of results
+func GetPRReviewComments(accessToken string, owner string, repo string, pullNumber string) ([]githubdomain.PullRequestReviewComment, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetPRReviewComments, owner, repo, pullNumber, perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.PullRequestReviewComment, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.PullRequestReviewComment
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
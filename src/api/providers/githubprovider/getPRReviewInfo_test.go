@@ -0,0 +1,157 @@
+package githubprovider
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantsForPRReviews(t *testing.T) {
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/pulls/%s/reviews?per_page=%d", urlGetPRReviews)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/pulls/%s/comments?per_page=%d", urlGetPRReviewComments)
+}
+
+func TestGetPRReviewsErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Err:        errors.New("invalid rest client response"),
+	})
+	response, err := GetPRReviews("", "myuser", "myrepo", "9")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
+	assert.EqualValues(t, "invalid rest client response", err.Message)
+}
+
+func TestGetPRReviewsErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": 123}`)),
+		},
+	})
+	response, err := GetPRReviews("", "myuser", "myrepo", "9")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetPRReviewsNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataPRReviewsResponseStatusCode(),
+			Body:       testutils.GetMockDataPRReviewsResponseMessage(),
+		},
+	})
+	response, err := GetPRReviews("", "myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+	assert.EqualValues(t, 1, len(response))
+	assert.EqualValues(t, 80, response[0].ID)
+	assert.EqualValues(t, githubdomain.ReviewStateApproved, response[0].State)
+	assert.EqualValues(t, "FEDCBA654321", response[0].CommitID)
+}
+
+func TestGetPRReviewsFollowsPages(t *testing.T) {
+	secondPage := "https://api.github.com/repositories/1/pulls/9/reviews?per_page=100&page=2"
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"id":1,"user":{"login":"reviewer"},"state":"CHANGES_REQUESTED","commit_id":"ABC"}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"id":2,"user":{"login":"reviewer"},"state":"APPROVED","commit_id":"DEF"}]`)),
+		},
+	})
+
+	response, err := GetPRReviews("", "myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, githubdomain.ReviewStateChangesRequested, response[0].State)
+	assert.EqualValues(t, githubdomain.ReviewStateApproved, response[1].State)
+	assert.EqualValues(t, "DEF", response[1].CommitID)
+}
+
+func TestGetPRReviewCommentsErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/comments?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+	response, err := GetPRReviewComments("", "myuser", "myrepo", "9")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetPRReviewCommentsErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/comments?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": 123}`)),
+		},
+	})
+	response, err := GetPRReviewComments("", "myuser", "myrepo", "9")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetPRReviewCommentsFollowsPages(t *testing.T) {
+	secondPage := "https://api.github.com/repositories/1/pulls/9/comments?per_page=100&page=2"
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/comments?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"id":1,"pull_request_review_id":80,"user":{"login":"reviewer"},"body":"Needs a test"}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"id":2,"user":{"login":"author"},"body":"Added one","in_reply_to_id":1}]`)),
+		},
+	})
+
+	response, err := GetPRReviewComments("", "myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, "reviewer", response[0].User.Login)
+	assert.EqualValues(t, 1, response[1].InReplyToID)
+}
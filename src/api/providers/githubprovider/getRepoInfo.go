@@ -0,0 +1,95 @@
+package githubprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+)
+
+//information needed to get repository data from Github
+const (
+	urlGetRepo     = "https://api.github.com/repos/%s/%s"
+	urlGetOrgRepos = "https://api.github.com/orgs/%s/repos?per_page=%d"
+
+	urlGetBranchProtection       = "https://api.github.com/repos/%s/%s/branches/%s/protection"
+	urlGetCollaboratorPermission = "https://api.github.com/repos/%s/%s/collaborators/%s/permission"
+)
+
+//GetRepo returns the metadata for the given repo, including its default branch
+func GetRepo(accessToken string, owner string, repo string) (*githubdomain.GetRepoInfo, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetRepo, owner, repo)
+	headers := getCommonHeader(accessToken)
+
+	bytes, err := getDataFromGithubAPI(URL, headers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result githubdomain.GetRepoInfo
+
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
+		return nil, getUnmarshalBodyError()
+	}
+	return &result, nil
+}
+
+//GetOrgRepos returns every repo owned by the organisation, following all the pages of results
+func GetOrgRepos(accessToken string, org string) ([]githubdomain.GetRepoInfo, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetOrgRepos, org, perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.GetRepoInfo, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.GetRepoInfo
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetBranchProtection returns the protection rules of the branch
+//Github returns not found for a branch that isn't protected and only returns the rules to a token with admin access to the repo
+func GetBranchProtection(accessToken string, owner string, repo string, branch string) (*githubdomain.BranchProtection, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetBranchProtection, owner, repo, branch)
+	headers := getCommonHeader(accessToken)
+	headers.Set(headerAccept, headerBranchProtectionAPI)
+
+	bytes, err := getDataFromGithubAPI(URL, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var result githubdomain.BranchProtection
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
+		return nil, getUnmarshalBodyError()
+	}
+	return &result, nil
+}
+
+//GetCollaboratorPermission returns the permission level the user has on the repo now
+func GetCollaboratorPermission(accessToken string, owner string, repo string, username string) (*githubdomain.CollaboratorPermission, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetCollaboratorPermission, owner, repo, username)
+
+	bytes, err := getDataFromGithubAPI(URL, getCommonHeader(accessToken))
+	if err != nil {
+		return nil, err
+	}
+
+	var result githubdomain.CollaboratorPermission
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		log.Println(fmt.Sprintf(errorUnmarshallingResponse, err.Error()))
+		return nil, getUnmarshalBodyError()
+	}
+	return &result, nil
+}
@@ -0,0 +1,240 @@
+package githubprovider
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantsForRepo(t *testing.T) {
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s", urlGetRepo)
+	assert.EqualValues(t, "https://api.github.com/orgs/%s/repos?per_page=%d", urlGetOrgRepos)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/branches/%s/protection", urlGetBranchProtection)
+	assert.EqualValues(t, "https://api.github.com/repos/%s/%s/collaborators/%s/permission", urlGetCollaboratorPermission)
+}
+
+func TestGetRepoErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Err:        errors.New("invalid rest client response"),
+	})
+	response, err := GetRepo("", "myuser", "myrepo")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
+	assert.EqualValues(t, "invalid rest client response", err.Message)
+}
+
+func TestGetRepoNotFound(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found","documentation_url": "https://developer.github.com/v3/repos/#get"}`)),
+		},
+	})
+	response, err := GetRepo("", "myuser", "myrepo")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+	assert.EqualValues(t, "Not Found", err.Message)
+}
+
+func TestGetRepoErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"id": 123}]`)),
+		},
+	})
+	response, err := GetRepo("", "myuser", "myrepo")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetRepoNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":1296269,"name":"myrepo","full_name":"myuser/myrepo","owner":{"login":"myuser","id":1,"type":"User","site_admin":false},"private":false,"default_branch":"main"}`)),
+		},
+	})
+	response, err := GetRepo("", "myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+	assert.EqualValues(t, 1296269, response.ID)
+	assert.EqualValues(t, "myuser/myrepo", response.FullName)
+	assert.EqualValues(t, "main", response.DefaultBranch)
+}
+
+func TestGetOrgReposErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/repos?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found","documentation_url": "https://developer.github.com/v3/repos/#list-organization-repositories"}`)),
+		},
+	})
+	response, err := GetOrgRepos("", "myorg")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+	assert.EqualValues(t, "Not Found", err.Message)
+}
+
+func TestGetOrgReposErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/repos?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": 123}`)),
+		},
+	})
+	response, err := GetOrgRepos("", "myorg")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetOrgReposFollowsPages(t *testing.T) {
+	secondPage := "https://api.github.com/organizations/1/repos?per_page=100&page=2"
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/repos?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next", <` + secondPage + `>; rel="last"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"name":"myrepo","default_branch":"main"},{"name":"old","archived":true}]`)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        secondPage,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"name":"another","default_branch":"develop"}]`)),
+		},
+	})
+
+	response, err := GetOrgRepos("", "myorg")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(response))
+	assert.EqualValues(t, "myrepo", response[0].Name)
+	assert.True(t, response[1].Archived)
+	assert.EqualValues(t, "develop", response[2].DefaultBranch)
+}
+
+func TestGetBranchProtectionNotProtected(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/branches/main/protection",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Branch not protected"}`)),
+		},
+	})
+	response, err := GetBranchProtection("", "myuser", "myrepo", "main")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+	assert.EqualValues(t, "Branch not protected", err.Message)
+}
+
+func TestGetBranchProtectionErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/branches/main/protection",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[]`)),
+		},
+	})
+	response, err := GetBranchProtection("", "myuser", "myrepo", "main")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetBranchProtectionNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/branches/main/protection",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"enforce_admins":{"enabled":true},"required_pull_request_reviews":{"required_approving_review_count":1}}`)),
+		},
+	})
+	response, err := GetBranchProtection("", "myuser", "myrepo", "main")
+	assert.Nil(t, err)
+	assert.True(t, response.IsEnforcedForAdmins())
+	assert.EqualValues(t, 1, response.GetRequiredApprovals())
+}
+
+func TestGetCollaboratorPermissionErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/collaborators/octocat/permission",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+	response, err := GetCollaboratorPermission("", "myuser", "myrepo", "octocat")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetCollaboratorPermissionErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/collaborators/octocat/permission",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[]`)),
+		},
+	})
+	response, err := GetCollaboratorPermission("", "myuser", "myrepo", "octocat")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetCollaboratorPermissionNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/collaborators/octocat/permission",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"permission":"admin","user":{"login":"octocat"}}`)),
+		},
+	})
+	response, err := GetCollaboratorPermission("", "myuser", "myrepo", "octocat")
+	assert.Nil(t, err)
+	assert.True(t, response.IsAdmin())
+}
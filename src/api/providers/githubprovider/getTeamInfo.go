@@ -0,0 +1,33 @@
+package githubprovider
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+)
+
+//information needed to get the teams of an organisation from Github
+const (
+	urlGetTeamMembers = "https://api.github.com/orgs/%s/teams/%s/members?per_page=%d"
+)
+
+//GetTeamMembers returns every member of the team, including the members of its child teams
+func GetTeamMembers(accessToken string, org string, teamSlug string) ([]githubdomain.GitUser, *githubdomain.GithubErrorResponse) {
+	URL := fmt.Sprintf(urlGetTeamMembers, org, teamSlug, perPage)
+	headers := getCommonHeader(accessToken)
+
+	result := make([]githubdomain.GitUser, 0)
+	err := getAllPagesFromGithubAPI(URL, headers, func(bytes []byte) (bool, error) {
+		var page []githubdomain.GitUser
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return false, err
+		}
+		result = append(result, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package githubprovider
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantsForTeams(t *testing.T) {
+	assert.EqualValues(t, "https://api.github.com/orgs/%s/teams/%s/members?per_page=%d", urlGetTeamMembers)
+}
+
+func TestGetTeamMembersErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/teams/writers/members?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+	response, err := GetTeamMembers("", "myorg", "writers")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestGetTeamMembersErrorResponseBody(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/teams/writers/members?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"login":"one"}`)),
+		},
+	})
+	response, err := GetTeamMembers("", "myorg", "writers")
+	assert.Nil(t, response)
+	assert.EqualValues(t, "error when trying to unmarshal github response", err.Message)
+}
+
+func TestGetTeamMembersNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/teams/writers/members?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"login":"writer1","id":1,"type":"User"},{"login":"writer2","id":2,"type":"User"}]`)),
+		},
+	})
+	response, err := GetTeamMembers("", "myorg", "writers")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, "writer2", response[1].Login)
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
 	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
@@ -21,6 +22,13 @@ const (
 	headerAccept              = "Accept"
 	headerPRDraftAPI          = "application/vnd.github.shadow-cat-preview+json"
 	headerPRForCommitDraftAPI = "application/vnd.github.groot-preview+json"
+	headerChecksAPI           = "application/vnd.github.antiope-preview+json"
+	headerBranchProtectionAPI = "application/vnd.github.luke-cage-preview+json"
+
+	//lists are split into pages, the link header holds the URL of the next page
+	headerLink = "Link"
+	relNext    = `rel="next"`
+	perPage    = 100
 
 	FmtGithubDate              = "2006-01-02T15:04:05.999Z"
 	errorUnmarshallingResponse = "error when trying to unmarshal successful response: %s"
@@ -44,10 +52,58 @@ func getCommonHeader(accessToken string) http.Header {
 //otherwise the response body is converted into bytes which can be unmarshalled into the relevant
 //struct by the calling function
 func getDataFromGithubAPI(URL string, headers http.Header) ([]byte, *githubdomain.GithubErrorResponse) {
+	bytes, _, err := getPageFromGithubAPI(URL, headers)
+	return bytes, err
+}
+
+//getPageFromGithubAPI calls the Github API in the same way as getDataFromGithubAPI but also returns
+//the URL of the next page of results, this is empty when there are no more pages
+func getPageFromGithubAPI(URL string, headers http.Header) ([]byte, string, *githubdomain.GithubErrorResponse) {
 	//the basic approach to calling Github to retrieve commit and PR data is the same irrespective
 	//of the Github API being called and the data being returned
 	//as a result, have put this logic into a common function
 	response, err := restclient.Get(URL, headers)
+	bytes, errResponse := readGithubResponse(response, err)
+	if errResponse != nil {
+		return nil, "", errResponse
+	}
+
+	//all good so can return the body to be unmarshalled
+	return bytes, getNextPageURL(response.Header.Get(headerLink)), nil
+}
+
+//getAllPagesFromGithubAPI calls the Github API for the URL and then for each following page of results
+//decodePage unmarshals each page in turn and returns false to stop before the remaining pages are read
+func getAllPagesFromGithubAPI(URL string, headers http.Header, decodePage func(bytes []byte) (bool, error)) *githubdomain.GithubErrorResponse {
+	for URL != "" {
+		bytes, nextURL, err := getPageFromGithubAPI(URL, headers)
+		if err != nil {
+			return err
+		}
+
+		more, errDecode := decodePage(bytes)
+		if errDecode != nil {
+			log.Println(fmt.Sprintf(errorUnmarshallingResponse, errDecode.Error()))
+			return getUnmarshalBodyError()
+		}
+		if !more {
+			return nil
+		}
+		URL = nextURL
+	}
+	return nil
+}
+
+//postDataToGithubAPI sends the body as json to the Github API as indicated by the URL
+//the response body is returned as bytes in the same way as getDataFromGithubAPI
+func postDataToGithubAPI(URL string, headers http.Header, body interface{}) ([]byte, *githubdomain.GithubErrorResponse) {
+	response, err := restclient.Post(URL, body, headers)
+	return readGithubResponse(response, err)
+}
+
+//readGithubResponse returns the body of a successful response from Github
+//an error is returned if Github couldn't be called or responded with an error
+func readGithubResponse(response *http.Response, err error) ([]byte, *githubdomain.GithubErrorResponse) {
 	if err != nil {
 		log.Println(fmt.Sprintf("error when calling Github API: %s", err.Error()))
 		return nil, &githubdomain.GithubErrorResponse{StatusCode: http.StatusInternalServerError, Message: err.Error()}
@@ -69,8 +125,6 @@ func getDataFromGithubAPI(URL string, headers http.Header) ([]byte, *githubdomai
 		errResponse.StatusCode = response.StatusCode
 		return nil, &errResponse
 	}
-
-	//all good so can return the body to be unmarshalled
 	return bytes, nil
 }
 
@@ -79,3 +133,16 @@ func getUnmarshalBodyError() *githubdomain.GithubErrorResponse {
 	return &githubdomain.GithubErrorResponse{StatusCode: http.StatusInternalServerError,
 		Message: "error when trying to unmarshal github response"}
 }
+
+//getNextPageURL returns the URL of the next page from the link header, an empty string is returned if there isn't one
+//the header is of the form <https://api.github.com/...&page=2>; rel="next", <https://api.github.com/...&page=5>; rel="last"
+func getNextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 || strings.TrimSpace(sections[1]) != relNext {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(sections[0]), "<>")
+	}
+	return ""
+}
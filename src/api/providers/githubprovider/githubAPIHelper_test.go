@@ -0,0 +1,15 @@
+package githubprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNextPageURL(t *testing.T) {
+	assert.EqualValues(t, "", getNextPageURL(""))
+	assert.EqualValues(t, "https://api.github.com/repos/myuser/myrepo/commits?page=2",
+		getNextPageURL(`<https://api.github.com/repos/myuser/myrepo/commits?page=2>; rel="next", <https://api.github.com/repos/myuser/myrepo/commits?page=5>; rel="last"`))
+	assert.EqualValues(t, "", getNextPageURL(`<https://api.github.com/repos/myuser/myrepo/commits?page=1>; rel="prev", <https://api.github.com/repos/myuser/myrepo/commits?page=1>; rel="first"`))
+	assert.EqualValues(t, "", getNextPageURL(`rubbish`))
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+//mergedPulls holds the PRs that merged the commits on the audited branch along with their reviews, each PR is only looked up once
+type mergedPulls struct {
+	owner   string
+	repo    string
+	pulls   map[int64]*githubdomain.GetSinglePullRequestResponse
+	reviews map[int64][]githubdomain.PullRequestReview
+}
+
+func newMergedPulls(owner string, repo string) *mergedPulls {
+	return &mergedPulls{
+		owner:   owner,
+		repo:    repo,
+		pulls:   make(map[int64]*githubdomain.GetSinglePullRequestResponse),
+		reviews: make(map[int64][]githubdomain.PullRequestReview),
+	}
+}
+
+//get returns the PR along with its reviews
+func (m *mergedPulls) get(pullNumber int64) (*githubdomain.GetSinglePullRequestResponse, []githubdomain.PullRequestReview, errors.APIError) {
+	if pullRequest, ok := m.pulls[pullNumber]; ok {
+		return pullRequest, m.reviews[pullNumber], nil
+	}
+
+	number := strconv.FormatInt(pullNumber, 10)
+	pullRequest, err := RepositoryService.GetRepoSinglePR(m.owner, m.repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	reviews, err := RepositoryService.GetPRReviews(m.owner, m.repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	m.pulls[pullNumber] = pullRequest
+	m.reviews[pullNumber] = reviews
+	return pullRequest, reviews, nil
+}
+
+//permissionUnknown is used when the permission of a merger couldn't be read
+const permissionUnknown = "unknown"
+
+//bypassChecker finds the PRs merged into the audited branch in a way that could have got around the review policy
+//each PR and the permission of each merger are only checked once
+type bypassChecker struct {
+	pulls       *mergedPulls
+	policy      *policydomain.ReviewPolicy
+	bypasses    map[int64]*reportdomain.BypassMerge
+	permissions map[string]string
+}
+
+func newBypassChecker(pulls *mergedPulls, policy *policydomain.ReviewPolicy) *bypassChecker {
+	return &bypassChecker{
+		pulls:       pulls,
+		policy:      policy,
+		bypasses:    make(map[int64]*reportdomain.BypassMerge),
+		permissions: make(map[string]string),
+	}
+}
+
+//check returns the merged PR along with how its merge bypassed the review policy, the bypass is nil when it didn't
+//a merge is a bypass when there was no qualifying approval while the policy needs one, or the merger is a site admin or an admin of the repo
+//Github only says what permission the merger has now, not what they had when they merged
+func (c *bypassChecker) check(pullNumber int64) (*githubdomain.GetSinglePullRequestResponse, *reportdomain.BypassMerge, errors.APIError) {
+	pullRequest, reviews, err := c.pulls.get(pullNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bypassMerge, ok := c.bypasses[pullNumber]; ok {
+		return pullRequest, bypassMerge, nil
+	}
+
+	approvers, _, _ := getLatestReviews(reviews, pullRequest, c.policy.DismissStaleApprovals)
+	var reasons []string
+	if c.policy.RequiredApprovals > 0 && len(approvers) == 0 {
+		reasons = append(reasons, reportdomain.BypassReasonNoApproval)
+	}
+	if pullRequest.MergedBy.SiteAdmin {
+		reasons = append(reasons, reportdomain.BypassReasonSiteAdmin)
+	}
+	if pullRequest.MergedBy.Login != "" {
+		switch c.getPermission(pullRequest.MergedBy.Login) {
+		case githubdomain.PermissionAdmin:
+			reasons = append(reasons, reportdomain.BypassReasonRepoAdmin)
+		case permissionUnknown:
+			reasons = append(reasons, reportdomain.BypassReasonPermissionUnknown)
+		}
+	}
+
+	var bypassMerge *reportdomain.BypassMerge
+	if len(reasons) > 0 {
+		bypassMerge = &reportdomain.BypassMerge{
+			PullNumber: pullRequest.Number,
+			PullTitle:  pullRequest.Title,
+			PullAuthor: pullRequest.User.Login,
+			MergedBy:   pullRequest.MergedBy.Login,
+			MergedAt:   pullRequest.MergedAt,
+			Approvers:  approvers,
+			Reasons:    reasons,
+		}
+	}
+	c.bypasses[pullNumber] = bypassMerge
+	return pullRequest, bypassMerge, nil
+}
+
+//getPermission returns the permission the user has on the repo, a user who isn't a collaborator has none
+//the permission is unknown when Github won't say, such as when the token can't push to the repo, rather than failing the report
+func (c *bypassChecker) getPermission(login string) string {
+	if permission, ok := c.permissions[login]; ok {
+		return permission
+	}
+
+	collaborator, errProvider := githubprovider.GetCollaboratorPermission(config.GetGithubAccessToken(), c.pulls.owner, c.pulls.repo, login)
+	permission := githubdomain.PermissionNone
+	switch {
+	case errProvider == nil:
+		permission = collaborator.Permission
+	case errProvider.StatusCode != http.StatusNotFound:
+		log.Println("unable to read the permission of", login, "on", c.pulls.owner+"/"+c.pulls.repo, errProvider.Message)
+		permission = permissionUnknown
+	}
+	c.permissions[login] = permission
+	return permission
+}
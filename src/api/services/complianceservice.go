@@ -0,0 +1,387 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/messagedomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+type complianceService struct{}
+
+type complianceServiceInterface interface {
+	GetPRCompliance(owner string, repo string, pullNumber string) (*reportdomain.PullRequestCompliance, errors.APIError)
+}
+
+const (
+	errorDecodingCodeOwners = "unable to decode the CODEOWNERS file"
+)
+
+//the places Github looks for the CODEOWNERS file, the first one found is used
+var codeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+//ComplianceService defines the compliance service to use
+var ComplianceService complianceServiceInterface
+
+func init() {
+	ComplianceService = &complianceService{}
+}
+
+//ResetComplianceService calls the init function again
+func ResetComplianceService() {
+	ComplianceService = &complianceService{}
+}
+
+//GetPRCompliance evaluates an open PR against the review policy of its repo to determine if merging it now would be compliant
+//the approvals, changes requested, code owners, status checks, commit messages, signatures and tickets are each checked and anything missing is listed
+//an approval that looks like a rubber stamp still counts but is warned about, when the policy enables the check
+func (s *complianceService) GetPRCompliance(owner string, repo string, pullNumber string) (*reportdomain.PullRequestCompliance, errors.APIError) {
+	var err errors.APIError
+	owner, repo, pullNumber, err = validateSinglePRInputs(owner, repo, pullNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequest, err := RepositoryService.GetRepoSinglePR(owner, repo, pullNumber)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := RepositoryService.GetPRReviews(owner, repo, pullNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := ReviewPolicies.ForRepo(owner, repo)
+	compliance := &reportdomain.PullRequestCompliance{
+		Owner:               owner,
+		Repo:                repo,
+		PullNumber:          pullRequest.Number,
+		Title:               pullRequest.Title,
+		Author:              pullRequest.User.Login,
+		BaseRef:             pullRequest.Base.Ref,
+		HeadSHA:             pullRequest.Head.SHA,
+		Policy:              policy,
+		Compliant:           true,
+		Requirements:        make([]reportdomain.RequirementResult, 0),
+		MissingRequirements: make([]string, 0),
+		Warnings:            make([]string, 0),
+	}
+
+	addOpenRequirement(compliance, pullRequest)
+	addLargeChangeWarning(compliance, pullRequest, policy.LargeChangeLines)
+
+	approvers, staleApprovers, changesRequestedBy := getLatestReviews(reviews, pullRequest, policy.DismissStaleApprovals)
+	compliance.Approvers = approvers
+	compliance.StaleApprovers = staleApprovers
+	compliance.AddRequirement(reportdomain.RequirementApprovals, len(approvers) >= policy.RequiredApprovals,
+		fmt.Sprintf("%d of %d required approvals", len(approvers), policy.RequiredApprovals))
+	if policy.RubberStamps.Enabled {
+		approvals, err := findSuspiciousApprovals(owner, repo, pullRequest, reviews, approvers, &policy.RubberStamps)
+		if err != nil {
+			return nil, err
+		}
+		for _, approval := range approvals {
+			compliance.AddSuspiciousApproval(approval)
+		}
+	}
+	if len(changesRequestedBy) > 0 {
+		compliance.AddRequirement(reportdomain.RequirementNoChangesRequired, false, "changes requested by "+strings.Join(changesRequestedBy, ", "))
+	} else {
+		compliance.AddRequirement(reportdomain.RequirementNoChangesRequired, true, "no changes requested")
+	}
+
+	if policy.RequireCodeOwnerReview {
+		if err := addCodeOwnersRequirement(compliance, pullRequest, approvers); err != nil {
+			return nil, err
+		}
+	}
+	if len(policy.RequiredStatusChecks) > 0 {
+		if err := addStatusChecksRequirement(compliance, policy.RequiredStatusChecks); err != nil {
+			return nil, err
+		}
+	}
+	if !policy.CommitMessages.IsEmpty() || policy.Signatures.Required {
+		commits, errProvider := githubprovider.GetPRCommits(config.GetGithubAccessToken(), owner, repo, pullNumber)
+		if errProvider != nil {
+			return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+		}
+		if !policy.CommitMessages.IsEmpty() {
+			addCommitMessagesRequirement(compliance, commits, &policy.CommitMessages)
+		}
+		if policy.Signatures.Required {
+			addSignedCommitsRequirement(compliance, commits, &policy.Signatures)
+		}
+	}
+	if policy.Tickets.Required {
+		if err := addTicketsRequirement(compliance, pullRequest, &policy.Tickets); err != nil {
+			return nil, err
+		}
+	}
+	return compliance, nil
+}
+
+//addTicketsRequirement checks the title or body of the PR references a ticket that exists and is in an allowed state
+func addTicketsRequirement(compliance *reportdomain.PullRequestCompliance, pullRequest *githubdomain.GetSinglePullRequestResponse, rules *policydomain.TicketRules) errors.APIError {
+	checker, err := newTicketChecker(rules)
+	if err != nil {
+		return err
+	}
+	references, err := checker.checkPullRequest(pullRequest)
+	if err != nil {
+		return err
+	}
+	compliance.Tickets = references
+
+	switch ticketdomain.GetStatus(references) {
+	case ticketdomain.StatusMissing:
+		compliance.AddRequirement(reportdomain.RequirementTickets, false, "the PR doesn't reference a ticket in its title or body")
+	case ticketdomain.StatusInvalid:
+		compliance.AddRequirement(reportdomain.RequirementTickets, false, "no valid ticket referenced: "+strings.Join(ticketdomain.Problems(references), "; "))
+	default:
+		var keys []string
+		for _, reference := range references {
+			if reference.Valid {
+				keys = append(keys, reference.Key)
+			}
+		}
+		compliance.AddRequirement(reportdomain.RequirementTickets, true, "references the tickets "+strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+//addCommitMessagesRequirement checks the message of each commit of the PR keeps to the rules
+//merge commits are left out as their messages are written by git or Github
+func addCommitMessagesRequirement(compliance *reportdomain.PullRequestCompliance, commits []githubdomain.GetCommitInfo, rules *policydomain.CommitMessageRules) {
+	var broken []string
+	for counter := range commits {
+		commit := &commits[counter]
+		if isMergeCommit(commit) {
+			continue
+		}
+		for _, violation := range messagedomain.ParseCommitMessage(commit.Commit.Message).Check(rules, commit.Commit.Author.Email) {
+			broken = append(broken, fmt.Sprintf("%s %s", commit.SHA, violation))
+		}
+	}
+
+	if len(broken) > 0 {
+		compliance.AddRequirement(reportdomain.RequirementCommitMessages, false, "commit messages break the rules: "+strings.Join(broken, "; "))
+	} else {
+		compliance.AddRequirement(reportdomain.RequirementCommitMessages, true, "every commit message keeps to the rules")
+	}
+}
+
+//addSignedCommitsRequirement checks each commit of the PR has a signature Github has verified, unless its author is exempt
+func addSignedCommitsRequirement(compliance *reportdomain.PullRequestCompliance, commits []githubdomain.GetCommitInfo, rules *policydomain.SignatureRules) {
+	var unsigned []string
+	for counter := range commits {
+		commit := &commits[counter]
+		verification := commit.Commit.Verification
+		switch {
+		case rules.IsExempt(commit.Author.Login, commit.Commit.Author.Email):
+		case verification == nil || !verification.IsSigned():
+			unsigned = append(unsigned, commit.SHA+" (unsigned)")
+		case !verification.Verified:
+			unsigned = append(unsigned, fmt.Sprintf("%s (%s)", commit.SHA, verification.Reason))
+		}
+	}
+
+	if len(unsigned) > 0 {
+		compliance.AddRequirement(reportdomain.RequirementSignedCommits, false, "commits without a verified signature: "+strings.Join(unsigned, ", "))
+	} else {
+		compliance.AddRequirement(reportdomain.RequirementSignedCommits, true, "every commit has a verified signature")
+	}
+}
+
+//addLargeChangeWarning flags a PR changing so many lines that it is hard to review properly
+func addLargeChangeWarning(compliance *reportdomain.PullRequestCompliance, pullRequest *githubdomain.GetSinglePullRequestResponse, largeChangeLines int64) {
+	changedLines := pullRequest.Additions + pullRequest.Deletions
+	if largeChangeLines > 0 && changedLines >= largeChangeLines {
+		compliance.AddWarning(fmt.Sprintf("the PR changes %d lines in %d files, large changes are hard to review", changedLines, pullRequest.ChangedFiles))
+	}
+}
+
+//addOpenRequirement checks the PR can still be merged, a closed or draft PR can't be
+func addOpenRequirement(compliance *reportdomain.PullRequestCompliance, pullRequest *githubdomain.GetSinglePullRequestResponse) {
+	switch {
+	case pullRequest.State != "open":
+		compliance.AddRequirement(reportdomain.RequirementOpen, false, "the PR is "+pullRequest.State)
+	case pullRequest.Draft:
+		compliance.AddRequirement(reportdomain.RequirementOpen, false, "the PR is a draft")
+	default:
+		compliance.AddRequirement(reportdomain.RequirementOpen, true, "the PR is open")
+	}
+}
+
+//getLatestReviews returns who currently approves the PR and who has requested changes based on the latest review of each user
+//comments don't change the state of an earlier review, the author of the PR can't review it
+//when stale approvals are dismissed an approval of an earlier commit doesn't count and is returned separately
+func getLatestReviews(reviews []githubdomain.PullRequestReview, pullRequest *githubdomain.GetSinglePullRequestResponse, dismissStaleApprovals bool) ([]string, []string, []string) {
+	var reviewers []string
+	latest := make(map[string]githubdomain.PullRequestReview)
+	for _, review := range reviews {
+		if review.State == githubdomain.ReviewStateCommented || review.State == githubdomain.ReviewStatePending {
+			continue
+		}
+		if strings.EqualFold(review.User.Login, pullRequest.User.Login) {
+			continue
+		}
+		if _, ok := latest[review.User.Login]; !ok {
+			reviewers = append(reviewers, review.User.Login)
+		}
+		latest[review.User.Login] = review
+	}
+
+	approvers := make([]string, 0)
+	staleApprovers := make([]string, 0)
+	changesRequestedBy := make([]string, 0)
+	for _, reviewer := range reviewers {
+		review := latest[reviewer]
+		switch {
+		case review.State == githubdomain.ReviewStateChangesRequested:
+			changesRequestedBy = append(changesRequestedBy, reviewer)
+		case review.State != githubdomain.ReviewStateApproved:
+		case dismissStaleApprovals && review.CommitID != pullRequest.Head.SHA:
+			staleApprovers = append(staleApprovers, reviewer)
+		default:
+			approvers = append(approvers, reviewer)
+		}
+	}
+	return approvers, staleApprovers, changesRequestedBy
+}
+
+//addCodeOwnersRequirement checks each file changed by the PR is approved by one of its code owners
+//the code owners are read from the base branch, as Github does, and a repo without a CODEOWNERS file meets the requirement
+//owners given as an email address can't be matched to an approver so their files are never approved
+func addCodeOwnersRequirement(compliance *reportdomain.PullRequestCompliance, pullRequest *githubdomain.GetSinglePullRequestResponse, approvers []string) errors.APIError {
+	codeOwners, err := getCodeOwners(compliance.Owner, compliance.Repo, pullRequest.Base.Ref)
+	if err != nil {
+		return err
+	}
+	if codeOwners == nil {
+		compliance.AddRequirement(reportdomain.RequirementCodeOwners, true, "the repo has no CODEOWNERS file")
+		return nil
+	}
+
+	files, err := RepositoryService.GetPRFiles(compliance.Owner, compliance.Repo, fmt.Sprint(pullRequest.Number))
+	if err != nil {
+		return err
+	}
+
+	approvedBy := make(map[string]bool)
+	for _, approver := range approvers {
+		approvedBy[strings.ToLower(approver)] = true
+	}
+	teamMembers := make(map[string][]string)
+
+	var unapproved []string
+	for _, file := range files {
+		owners := codeOwners.OwnersFor(file.Filename)
+		if len(owners) == 0 {
+			continue
+		}
+		approved, err := isApprovedByCodeOwner(owners, approvedBy, teamMembers)
+		if err != nil {
+			return err
+		}
+		if !approved {
+			unapproved = append(unapproved, file.Filename)
+		}
+	}
+
+	if len(unapproved) > 0 {
+		compliance.AddRequirement(reportdomain.RequirementCodeOwners, false, "no approval from the code owners of "+strings.Join(unapproved, ", "))
+	} else {
+		compliance.AddRequirement(reportdomain.RequirementCodeOwners, true, "approved by the code owners of every changed file")
+	}
+	return nil
+}
+
+//getCodeOwners reads the CODEOWNERS file of the repo at the given ref, nil if the repo doesn't have one
+func getCodeOwners(owner string, repo string, ref string) (*policydomain.CodeOwners, errors.APIError) {
+	for _, location := range codeOwnersLocations {
+		content, errProvider := githubprovider.GetRepoContent(config.GetGithubAccessToken(), owner, repo, location, ref)
+		if errProvider != nil && errProvider.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if errProvider != nil {
+			return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+		}
+
+		decoded, errDecode := content.DecodedContent()
+		if errDecode != nil {
+			return nil, errors.NewInternalServerError(errorDecodingCodeOwners)
+		}
+		return policydomain.ParseCodeOwners(decoded), nil
+	}
+	return nil, nil
+}
+
+//isApprovedByCodeOwner determines if one of the owners approved, an owner is a user such as @octocat or a team such as @myorg/writers
+//the members of each team are only read from Github once
+func isApprovedByCodeOwner(owners []string, approvedBy map[string]bool, teamMembers map[string][]string) (bool, errors.APIError) {
+	for _, owner := range owners {
+		if !strings.HasPrefix(owner, "@") {
+			continue
+		}
+		owner = strings.ToLower(strings.TrimPrefix(owner, "@"))
+		if !strings.Contains(owner, "/") {
+			if approvedBy[owner] {
+				return true, nil
+			}
+			continue
+		}
+
+		members, ok := teamMembers[owner]
+		if !ok {
+			team := strings.SplitN(owner, "/", 2)
+			users, errProvider := githubprovider.GetTeamMembers(config.GetGithubAccessToken(), team[0], team[1])
+			if errProvider != nil {
+				return false, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+			}
+			for _, user := range users {
+				members = append(members, strings.ToLower(user.Login))
+			}
+			teamMembers[owner] = members
+		}
+		for _, member := range members {
+			if approvedBy[member] {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+//addStatusChecksRequirement checks each required check has succeeded on the head commit of the PR, as either a commit status or a check run
+func addStatusChecksRequirement(compliance *reportdomain.PullRequestCompliance, requiredChecks []string) errors.APIError {
+	checks, err := getCommitChecks(compliance.Owner, compliance.Repo, compliance.HeadSHA)
+	if err != nil {
+		return err
+	}
+
+	var failing []string
+	for _, check := range requiredChecks {
+		switch state := checks.GetState(check); state {
+		case "":
+			failing = append(failing, check+" (missing)")
+		case githubdomain.StatusStateSuccess:
+		default:
+			failing = append(failing, fmt.Sprintf("%s (%s)", check, state))
+		}
+	}
+
+	if len(failing) > 0 {
+		compliance.AddRequirement(reportdomain.RequirementStatusChecks, false, "required status checks not passed: "+strings.Join(failing, ", "))
+	} else {
+		compliance.AddRequirement(reportdomain.RequirementStatusChecks, true, "every required status check passed")
+	}
+	return nil
+}
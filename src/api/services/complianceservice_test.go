@@ -0,0 +1,336 @@
+package services
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testComplianceReviews = `[
+		{"id":1,"user":{"login":"reviewer1"},"state":"APPROVED","commit_id":"HEAD2"},
+		{"id":2,"user":{"login":"reviewer2"},"state":"APPROVED","commit_id":"HEAD1"},
+		{"id":3,"user":{"login":"reviewer3"},"state":"CHANGES_REQUESTED","commit_id":"HEAD1"},
+		{"id":4,"user":{"login":"reviewer3"},"state":"COMMENTED","commit_id":"HEAD2"},
+		{"id":5,"user":{"login":"author"},"state":"APPROVED","commit_id":"HEAD2"}
+	]`
+	//base64 of "* @myuser/writers\ndocs/ @docs-owner\n"
+	testComplianceCodeOwners = "KiBAbXl1c2VyL3dyaXRlcnMKZG9jcy8gQGRvY3Mtb3duZXIK"
+)
+
+//addComplianceMock mocks a GET of the URL returning the status code and body
+func addComplianceMock(url string, statusCode int, body string) {
+	restclient.AddMockup(restclient.Mock{
+		URL:        url,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		},
+	})
+}
+
+//addPRComplianceMocks mocks PR 9 of myuser/myrepo along with its reviews
+func addPRComplianceMocks(state string, reviews string) {
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusOK,
+		`{"number":9,"state":"`+state+`","title":"Add a feature","user":{"login":"author"},"base":{"ref":"main","sha":"BASE"},"head":{"ref":"feature","sha":"HEAD2"}}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100", http.StatusOK, reviews)
+}
+
+//setupPolicy makes the policy the default, the returned function puts the defaults back
+func setupPolicy(policy policydomain.ReviewPolicy) func() {
+	SetReviewPolicies(&policydomain.Policies{Default: policy})
+	return func() {
+		SetReviewPolicies(policydomain.NewDefaultPolicies())
+	}
+}
+
+func TestGetPRComplianceInvalidInputs(t *testing.T) {
+	ResetComplianceService()
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "abc")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+}
+
+func TestGetPRComplianceErrorGettingPR(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusNotFound, `{"message":"Not Found"}`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetPRComplianceErrorGettingReviews(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	addPRComplianceMocks("open", testComplianceReviews)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100", http.StatusUnauthorized, `{"message":"Bad credentials"}`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+}
+
+func TestGetPRComplianceDefaultPolicy(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	addPRComplianceMocks("open", testComplianceReviews)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.False(t, result.Compliant)
+	assert.EqualValues(t, 9, result.PullNumber)
+	assert.EqualValues(t, "author", result.Author)
+	assert.EqualValues(t, "HEAD2", result.HeadSHA)
+	assert.EqualValues(t, []string{"reviewer1"}, result.Approvers)
+	assert.EqualValues(t, []string{"reviewer2"}, result.StaleApprovers)
+	assert.EqualValues(t, 3, len(result.Requirements))
+	assert.True(t, result.Requirements[0].Satisfied)
+	assert.True(t, result.Requirements[1].Satisfied)
+	assert.EqualValues(t, "1 of 1 required approvals", result.Requirements[1].Detail)
+	assert.EqualValues(t, []string{"changes requested by reviewer3"}, result.MissingRequirements)
+	assert.EqualValues(t, 0, len(result.Warnings))
+}
+
+func TestGetPRComplianceLargeChange(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	addPRComplianceMocks("open", `[{"id":1,"user":{"login":"reviewer1"},"state":"APPROVED","commit_id":"HEAD2"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusOK,
+		`{"number":9,"state":"open","user":{"login":"author"},"head":{"sha":"HEAD2"},"additions":900,"deletions":100,"changed_files":12}`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.True(t, result.Compliant)
+	assert.EqualValues(t, []string{"the PR changes 1000 lines in 12 files, large changes are hard to review"}, result.Warnings)
+}
+
+func TestGetPRComplianceRubberStamps(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 1,
+		RubberStamps: policydomain.RubberStampRules{Enabled: true, MinReviewSeconds: 300, LargeChangeLines: 500, MinReviewComments: 1, MinSignals: 2}})()
+	//reviewer1 approved a large change 30 seconds after it was opened, reviewer2 took an hour and left a comment
+	addPRComplianceMocks("open", `[
+		{"id":1,"user":{"login":"reviewer1"},"state":"APPROVED","commit_id":"HEAD2","submitted_at":"2020-03-02T09:00:30Z"},
+		{"id":2,"user":{"login":"reviewer2"},"state":"APPROVED","commit_id":"HEAD2","submitted_at":"2020-03-02T10:00:00Z"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusOK,
+		`{"number":9,"state":"open","title":"Add a feature","user":{"login":"author"},"head":{"sha":"HEAD2"},"created_at":"2020-03-02T09:00:00Z","additions":1800,"deletions":200}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/comments?per_page=100", http.StatusOK,
+		`[{"id":1,"user":{"login":"Reviewer2"},"body":"Needs a test"},{"id":2,"user":{"login":"author"},"body":"Added one","in_reply_to_id":1}]`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.True(t, result.Compliant)
+	assert.EqualValues(t, []string{"reviewer1", "reviewer2"}, result.Approvers)
+	assert.EqualValues(t, 1, len(result.SuspiciousApprovals))
+	assert.EqualValues(t, "reviewer1", result.SuspiciousApprovals[0].Approver)
+	assert.EqualValues(t, 30, result.SuspiciousApprovals[0].ReviewSeconds)
+	assert.EqualValues(t, []string{"the approval by reviewer1 looks like a rubber stamp: approved 30s after the PR was opened, the PR changes 2000 lines, no review comments"}, result.Warnings)
+
+	addPRComplianceMocks("open", `[{"id":1,"user":{"login":"reviewer1"},"state":"APPROVED","commit_id":"HEAD2"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/comments?per_page=100", http.StatusForbidden, `{"message":"Forbidden"}`)
+	result, err = ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+}
+
+func TestGetPRComplianceCommitMessages(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{CommitMessages: policydomain.CommitMessageRules{Format: policydomain.MessageFormatConventional, Types: []string{"feat", "fix"}, MaxSubjectLength: 20}})()
+	addPRComplianceMocks("open", `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/commits?per_page=100", http.StatusOK, `[
+		{"sha":"SHA1","commit":{"message":"feat: add a feature"},"parents":[{"sha":"BASE"}]},
+		{"sha":"SHA2","commit":{"message":"Merge branch 'main' into feature"},"parents":[{"sha":"SHA1"},{"sha":"MAIN"}]},
+		{"sha":"SHA3","commit":{"message":"chore: tidy up the feature"},"parents":[{"sha":"SHA2"}]}
+	]`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.False(t, result.Compliant)
+	assert.EqualValues(t, []string{"commit messages break the rules: SHA3 the type chore isn't one of feat, fix; SHA3 the subject is 26 characters, more than the limit of 20"}, result.MissingRequirements)
+
+	addPRComplianceMocks("open", `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/commits?per_page=100", http.StatusOK, `[{"sha":"SHA1","commit":{"message":"fix: a bug"},"parents":[{"sha":"BASE"}]}]`)
+	result, err = ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.True(t, result.Compliant)
+	assert.EqualValues(t, reportdomain.RequirementCommitMessages, result.Requirements[len(result.Requirements)-1].Name)
+	assert.EqualValues(t, "every commit message keeps to the rules", result.Requirements[len(result.Requirements)-1].Detail)
+
+	addPRComplianceMocks("open", `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/commits?per_page=100", http.StatusNotFound, `{"message":"Not Found"}`)
+	result, err = ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetPRComplianceSignedCommits(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{Signatures: policydomain.SignatureRules{Required: true, Exempt: []string{"dependabot[bot]"}}})()
+	addPRComplianceMocks("open", `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/commits?per_page=100", http.StatusOK, `[
+		{"sha":"SHA1","commit":{"message":"add a feature","verification":{"verified":true,"reason":"valid"}}},
+		{"sha":"SHA2","commit":{"message":"tidy up","verification":{"verified":false,"reason":"unsigned"}}},
+		{"sha":"SHA3","commit":{"message":"fix it","verification":{"verified":false,"reason":"unknown_key","signature":"sig"}}},
+		{"sha":"SHA4","author":{"login":"dependabot[bot]"},"commit":{"message":"bump yaml","verification":{"verified":false,"reason":"unsigned"}}}
+	]`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.False(t, result.Compliant)
+	assert.EqualValues(t, reportdomain.RequirementSignedCommits, result.Requirements[len(result.Requirements)-1].Name)
+	assert.EqualValues(t, []string{"commits without a verified signature: SHA2 (unsigned), SHA3 (unknown_key)"}, result.MissingRequirements)
+
+	addPRComplianceMocks("open", `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/commits?per_page=100", http.StatusOK, `[{"sha":"SHA1","commit":{"message":"add a feature","verification":{"verified":true,"reason":"valid"}}}]`)
+	result, err = ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.True(t, result.Compliant)
+	assert.EqualValues(t, "every commit has a verified signature", result.Requirements[len(result.Requirements)-1].Detail)
+}
+
+func TestGetPRComplianceTickets(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	_, cleanup := setupIssueTracker()
+	defer cleanup()
+	defer setupPolicy(policydomain.ReviewPolicy{Tickets: policydomain.TicketRules{Required: true, AllowedStates: []string{"In Progress"}}})()
+
+	addPRComplianceMocks("open", `[]`)
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.False(t, result.Compliant)
+	assert.EqualValues(t, reportdomain.RequirementTickets, result.Requirements[len(result.Requirements)-1].Name)
+	assert.EqualValues(t, []string{"the PR doesn't reference a ticket in its title or body"}, result.MissingRequirements)
+
+	addPRComplianceMocks("open", `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusOK,
+		`{"number":9,"state":"open","title":"OPS-7: Fix the build","body":"Also see OPS-8","user":{"login":"author"},"head":{"sha":"HEAD2"}}`)
+	result, err = ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"no valid ticket referenced: the ticket OPS-7 is Done, expected one of In Progress; the ticket OPS-8 doesn't exist"}, result.MissingRequirements)
+	assert.EqualValues(t, 2, len(result.Tickets))
+
+	addPRComplianceMocks("open", `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusOK,
+		`{"number":9,"state":"open","title":"Handle refunds","body":"Closes PAY-12","user":{"login":"author"},"head":{"sha":"HEAD2"}}`)
+	result, err = ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.True(t, result.Compliant)
+	assert.EqualValues(t, "references the tickets PAY-12", result.Requirements[len(result.Requirements)-1].Detail)
+	assert.EqualValues(t, ticketdomain.SourcePullBody, result.Tickets[0].Source)
+}
+
+func TestGetPRComplianceClosedAndUnapproved(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 2})()
+	addPRComplianceMocks("closed", `[{"id":1,"user":{"login":"reviewer1"},"state":"APPROVED","commit_id":"HEAD1"}]`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.False(t, result.Compliant)
+	//stale approvals count when they aren't dismissed
+	assert.EqualValues(t, []string{"reviewer1"}, result.Approvers)
+	assert.EqualValues(t, []string{"the PR is closed", "1 of 2 required approvals"}, result.MissingRequirements)
+}
+
+func TestGetPRComplianceCodeOwnersAndStatusChecks(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 1, RequireCodeOwnerReview: true, RequiredStatusChecks: []string{"ci/build", "ci/test", "ci/lint", "ci/e2e"}})()
+	addPRComplianceMocks("open", testComplianceReviews)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/contents/.github/CODEOWNERS?ref=main", http.StatusNotFound, `{"message":"Not Found"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/contents/CODEOWNERS?ref=main", http.StatusOK,
+		`{"type":"file","encoding":"base64","content":"`+testComplianceCodeOwners+`"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/files?per_page=100", http.StatusOK,
+		`[{"filename":"src/main.go","status":"modified"},{"filename":"src/util.go","status":"added"},{"filename":"docs/guide.md","status":"modified"}]`)
+	addComplianceMock("https://api.github.com/orgs/myuser/teams/writers/members?per_page=100", http.StatusOK, `[{"login":"Reviewer1"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD2/status", http.StatusOK,
+		`{"state":"failure","sha":"HEAD2","statuses":[{"context":"ci/build","state":"success"},{"context":"ci/test","state":"failure"}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD2/check-runs?per_page=100", http.StatusOK,
+		`{"total_count":2,"check_runs":[{"id":1,"name":"ci/lint","status":"completed","conclusion":"success"},{"id":2,"name":"ci/test","status":"in_progress"}]}`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.False(t, result.Compliant)
+	assert.EqualValues(t, 5, len(result.Requirements))
+	assert.EqualValues(t, reportdomain.RequirementCodeOwners, result.Requirements[3].Name)
+	assert.EqualValues(t, "no approval from the code owners of docs/guide.md", result.Requirements[3].Detail)
+	assert.EqualValues(t, reportdomain.RequirementStatusChecks, result.Requirements[4].Name)
+	assert.EqualValues(t, "required status checks not passed: ci/test (failure), ci/e2e (missing)", result.Requirements[4].Detail)
+}
+
+func TestGetPRComplianceCompliant(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 1, RequireCodeOwnerReview: true, DismissStaleApprovals: true, RequiredStatusChecks: []string{"ci/build"}})()
+	addPRComplianceMocks("open", `[{"id":1,"user":{"login":"reviewer1"},"state":"APPROVED","commit_id":"HEAD2"}]`)
+	for _, location := range codeOwnersLocations {
+		addComplianceMock("https://api.github.com/repos/myuser/myrepo/contents/"+location+"?ref=main", http.StatusNotFound, `{"message":"Not Found"}`)
+	}
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD2/status", http.StatusOK,
+		`{"state":"success","sha":"HEAD2","statuses":[{"context":"ci/build","state":"success"}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD2/check-runs?per_page=100", http.StatusOK, `{"total_count":0,"check_runs":[]}`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.True(t, result.Compliant)
+	assert.EqualValues(t, 0, len(result.MissingRequirements))
+	assert.EqualValues(t, "the repo has no CODEOWNERS file", result.Requirements[3].Detail)
+}
+
+func TestGetPRComplianceErrorGettingCodeOwners(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{RequireCodeOwnerReview: true})()
+	addPRComplianceMocks("open", testComplianceReviews)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/contents/.github/CODEOWNERS?ref=main", http.StatusForbidden, `{"message":"Forbidden"}`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+}
+
+func TestGetPRComplianceErrorGettingStatus(t *testing.T) {
+	ResetService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredStatusChecks: []string{"ci/build"}})()
+	addPRComplianceMocks("open", testComplianceReviews)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD2/status", http.StatusNotFound, `{"message":"Not Found"}`)
+
+	result, err := ComplianceService.GetPRCompliance("myuser", "myrepo", "9")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetLatestReviews(t *testing.T) {
+	pullRequest := &githubdomain.GetSinglePullRequestResponse{User: githubdomain.GitUser{Login: "author"}, Head: githubdomain.RepoBase{SHA: "HEAD2"}}
+	reviews := []githubdomain.PullRequestReview{
+		{User: githubdomain.GitUser{Login: "reviewer1"}, State: githubdomain.ReviewStateChangesRequested, CommitID: "HEAD1"},
+		{User: githubdomain.GitUser{Login: "reviewer1"}, State: githubdomain.ReviewStateApproved, CommitID: "HEAD2"},
+		{User: githubdomain.GitUser{Login: "reviewer2"}, State: githubdomain.ReviewStateApproved, CommitID: "HEAD2"},
+		{User: githubdomain.GitUser{Login: "reviewer2"}, State: githubdomain.ReviewStateDismissed, CommitID: "HEAD2"},
+	}
+
+	approvers, stale, changesRequested := getLatestReviews(reviews, pullRequest, true)
+	assert.EqualValues(t, []string{"reviewer1"}, approvers)
+	assert.EqualValues(t, 0, len(stale))
+	assert.EqualValues(t, 0, len(changesRequested))
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"log"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+)
+
+//DataStore is where the Github data is persisted, nothing is persisted until a store is set
+var DataStore = store.NewDisabledStore()
+
+//SetDataStore sets the store the services read from before falling back to Github
+func SetDataStore(dataStore store.Store) {
+	DataStore = dataStore
+}
+
+//logStoreError logs a failure to use the store, the data can always be fetched from Github
+//so a problem with the store is never returned to the caller
+func logStoreError(action string, err error) {
+	if err != nil && err != store.ErrNotFound {
+		log.Println("error when trying to", action, "the data store:", err.Error())
+	}
+}
+
+//isPullRequestClosed determines if the PR has reached its final state, only closed PRs are read from the store
+//as open PRs may still be updated on Github
+func isPullRequestClosed(pullRequest *githubdomain.GetSinglePullRequestResponse) bool {
+	return pullRequest.State == "closed"
+}
+
+//hasPullRequestMergedInto determines if one of the PRs was merged into the branch, an empty branch accepts any branch
+//a commit can gain PRs at any time, such as when it moves on from develop to main, so its stored PRs are only final
+//for a branch once they hold the PR that merged it into that branch, an empty list is never final
+func hasPullRequestMergedInto(pullRequests []githubdomain.GetSinglePullRequestResponse, branch string) bool {
+	for counter := range pullRequests {
+		if isPRResultingInMerge(&pullRequests[counter]) && (branch == "" || pullRequests[counter].Base.Ref == branch) {
+			return true
+		}
+	}
+	return false
+}
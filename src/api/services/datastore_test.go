@@ -0,0 +1,406 @@
+package services
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+//setupTestDataStore uses a new database in a temporary directory, the returned function removes it
+func setupTestDataStore(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "services")
+	assert.Nil(t, err)
+	dataStore, err := store.NewBoltStore(filepath.Join(dir, "test.db"))
+	assert.Nil(t, err)
+
+	SetDataStore(dataStore)
+	return func() {
+		SetDataStore(store.NewDisabledStore())
+		dataStore.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestIsPullRequestClosed(t *testing.T) {
+	assert.True(t, isPullRequestClosed(&githubdomain.GetSinglePullRequestResponse{State: "closed"}))
+	assert.False(t, isPullRequestClosed(&githubdomain.GetSinglePullRequestResponse{State: "open"}))
+
+}
+
+func TestHasPullRequestMergedInto(t *testing.T) {
+	merged := githubdomain.GetSinglePullRequestResponse{State: "closed", MergeCommitSHA: "MERGE", Base: githubdomain.RepoBase{Ref: "develop"}}
+	assert.False(t, hasPullRequestMergedInto(nil, ""))
+	assert.False(t, hasPullRequestMergedInto([]githubdomain.GetSinglePullRequestResponse{{State: "closed"}}, ""))
+	assert.False(t, hasPullRequestMergedInto([]githubdomain.GetSinglePullRequestResponse{merged}, "main"))
+	assert.True(t, hasPullRequestMergedInto([]githubdomain.GetSinglePullRequestResponse{{State: "open"}, merged}, "develop"))
+	assert.True(t, hasPullRequestMergedInto([]githubdomain.GetSinglePullRequestResponse{merged}, ""))
+}
+
+func TestGetRepoSinglePRReadFromStoreOnceClosed(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSinglePRResponseStatusCode(),
+			Body:       testutils.GetMockDataSinglePRResponseMessage(),
+		},
+	})
+
+	result, err := RepositoryService.GetRepoSinglePR("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 9, result.Number)
+
+	//the PR is open so Github is called again
+	restclient.FlushMockups()
+	_, err = RepositoryService.GetRepoSinglePR("myuser", "myrepo", "9")
+	assert.NotNil(t, err)
+
+	//the closed PR is missing the details so Github is called again
+	result.State = "closed"
+	assert.Nil(t, DataStore.SavePullRequests("myuser", "myrepo", []githubdomain.GetSinglePullRequestResponse{*result}))
+	_, err = RepositoryService.GetRepoSinglePR("myuser", "myrepo", "9")
+	assert.NotNil(t, err)
+
+	result.Commits = 2
+	result.ChangedFiles = 3
+	assert.Nil(t, DataStore.SavePullRequests("myuser", "myrepo", []githubdomain.GetSinglePullRequestResponse{*result}))
+
+	result, err = RepositoryService.GetRepoSinglePR("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "closed", result.State)
+	assert.EqualValues(t, 3, result.ChangedFiles)
+}
+
+func TestGetRepoSinglePRKeepsDetailsWhenListed(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+	restclient.FlushMockups()
+
+	merged := time.Date(2020, 3, 10, 0, 0, 0, 0, time.UTC)
+	detailed := githubdomain.GetSinglePullRequestResponse{Number: 9, State: "closed", UpdatedAt: merged, Commits: 2, Additions: 40, MergedBy: githubdomain.GitUser{Login: "admin"}}
+	assert.Nil(t, DataStore.SavePullRequests("myuser", "myrepo", []githubdomain.GetSinglePullRequestResponse{detailed}))
+
+	//the same PR from a list has none of the details
+	listed := githubdomain.GetSinglePullRequestResponse{Number: 9, State: "closed", UpdatedAt: merged}
+	assert.Nil(t, DataStore.SaveCommitPullRequests("myuser", "myrepo", "AABCDEF123456", []githubdomain.GetSinglePullRequestResponse{listed}))
+
+	result, err := RepositoryService.GetRepoSinglePR("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 40, result.Additions)
+	assert.EqualValues(t, "admin", result.MergedBy.Login)
+}
+
+func TestGetPRReviewsReadFromStoreOnceClosed(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataPRReviewsResponseStatusCode(),
+			Body:       testutils.GetMockDataPRReviewsResponseMessage(),
+		},
+	})
+
+	result, err := RepositoryService.GetPRReviews("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+
+	restclient.FlushMockups()
+	assert.Nil(t, DataStore.SavePullRequests("myuser", "myrepo", []githubdomain.GetSinglePullRequestResponse{{Number: 9, State: "closed"}}))
+
+	result, err = RepositoryService.GetPRReviews("myuser", "myrepo", "9")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, 80, result[0].ID)
+}
+
+func TestGetCommitPullRequestsReadFromStoreWhenMerged(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
+		},
+	})
+
+	result, err := RepositoryService.GetSingleCommitPR("myuser", "myrepo", "AABCDEF123456")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+
+	restclient.FlushMockups()
+	result, err = getCommitPullRequests("myuser", "myrepo", "AABCDEF123456", "main")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, 9, result[0].Number)
+
+	//the PR wasn't merged into develop so the commit may still gain one and the PRs are fetched again
+	result, err = getCommitPullRequests("myuser", "myrepo", "AABCDEF123456", "develop")
+	assert.NotNil(t, err)
+	assert.Nil(t, result)
+}
+
+func TestGetCommitPullRequestsFetchesWhenNoneStored(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[]`)),
+		},
+	})
+
+	result, err := getCommitPullRequests("myuser", "myrepo", "AABCDEF123456", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(result))
+
+	//the commit has since been added to a PR
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
+		},
+	})
+	result, err = getCommitPullRequests("myuser", "myrepo", "AABCDEF123456", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, 9, result[0].Number)
+}
+
+func TestGetRepoSingleCommitReadFromStore(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleCommitResponseMessage(),
+		},
+	})
+
+	result, err := RepositoryService.GetRepoSingleCommit("myuser", "myrepo", "AABCDEF123456")
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+
+	restclient.FlushMockups()
+	stored, err := RepositoryService.GetRepoSingleCommit("myuser", "myrepo", "AABCDEF123456")
+	assert.Nil(t, err)
+	assert.EqualValues(t, result.SHA, stored.SHA)
+	assert.EqualValues(t, len(result.Parents), len(stored.Parents))
+}
+
+func TestGetRepoSingleCommitWithoutParentsIsFetchedAgain(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	assert.Nil(t, DataStore.SaveCommits("myuser", "myrepo", []githubdomain.GetCommitInfo{{SHA: "AABCDEF123456"}}))
+
+	restclient.FlushMockups()
+	result, err := RepositoryService.GetRepoSingleCommit("myuser", "myrepo", "AABCDEF123456")
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+}
+
+func TestGetRepoSingleCommitWithoutVerificationIsFetchedAgain(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	commit := githubdomain.GetCommitInfo{SHA: "AABCDEF123456", Parents: []githubdomain.Parent{{SHA: "ABC"}}, Stats: &githubdomain.CommitStats{Total: 1}}
+	assert.Nil(t, DataStore.SaveCommits("myuser", "myrepo", []githubdomain.GetCommitInfo{commit}))
+
+	restclient.FlushMockups()
+	result, err := RepositoryService.GetRepoSingleCommit("myuser", "myrepo", "AABCDEF123456")
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+}
+
+func TestGetRepoCommitsInDateRangeReadFromStore(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	toDate := time.Date(2019, 12, 10, 0, 0, 0, 0, time.UTC)
+	fromDate := toDate.AddDate(0, -1, 0)
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.Format(githubprovider.FmtGithubDate) + "&until=" + toDate.Format(githubprovider.FmtGithubDate) + "&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataCommitsResponseStatusCode(),
+			Body:       testutils.GetMockDataCommitsResponseMessage(),
+		},
+	})
+
+	result, fromStore, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.False(t, fromStore)
+	assert.NotEqual(t, 0, len(result))
+
+	restclient.FlushMockups()
+	stored, fromStore, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.True(t, fromStore)
+	assert.EqualValues(t, len(result), len(stored))
+
+	//a range that hasn't been fetched still goes to Github
+	_, _, err = getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate.AddDate(0, -1, 0), toDate, nil)
+	assert.NotNil(t, err)
+}
+
+func TestGetRepoCommitsInDateRangeStoresEveryPage(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	toDate := time.Date(2019, 12, 10, 0, 0, 0, 0, time.UTC)
+	fromDate := toDate.AddDate(0, -1, 0)
+	commitsURL := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.Format(githubprovider.FmtGithubDate) + "&until=" + toDate.Format(githubprovider.FmtGithubDate) + "&per_page=100"
+	secondPage := "https://api.github.com/repositories/1/commits?sha=main&per_page=100&page=2"
+
+	//the coverage isn't recorded when a later page can't be read
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        commitsURL,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"SHA1","commit":{"message":"first","author":{"date":"2019-12-01T10:00:00Z"},"committer":{"date":"2019-12-01T10:00:00Z"}}},{"sha":"SHA2","commit":{"message":"second","author":{"date":"2019-12-02T10:00:00Z"},"committer":{"date":"2019-12-02T10:00:00Z"}}}]`)),
+		},
+	})
+	addComplianceMock(secondPage, http.StatusBadGateway, `{"message":"Server Error"}`)
+	_, _, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
+	assert.EqualValues(t, http.StatusBadGateway, err.Status())
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        commitsURL,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"sha":"SHA1","commit":{"message":"first","author":{"date":"2019-12-01T10:00:00Z"},"committer":{"date":"2019-12-01T10:00:00Z"}}},{"sha":"SHA2","commit":{"message":"second","author":{"date":"2019-12-02T10:00:00Z"},"committer":{"date":"2019-12-02T10:00:00Z"}}}]`)),
+		},
+	})
+	addComplianceMock(secondPage, http.StatusOK, `[{"sha":"SHA3","commit":{"message":"third","author":{"date":"2019-12-03T10:00:00Z"},"committer":{"date":"2019-12-03T10:00:00Z"}}}]`)
+	result, fromStore, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.False(t, fromStore)
+	assert.EqualValues(t, 3, len(result))
+
+	//every page was saved so the whole range is read from the store
+	restclient.FlushMockups()
+	stored, fromStore, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.True(t, fromStore)
+	assert.EqualValues(t, 3, len(stored))
+}
+
+func TestGetRepoCommitsInDateRangeForPathsFromStore(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+
+	toDate := time.Date(2019, 12, 10, 0, 0, 0, 0, time.UTC)
+	fromDate := toDate.AddDate(0, -1, 0)
+	commitsURL := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.Format(githubprovider.FmtGithubDate) + "&until=" + toDate.Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	//commits fetched for a path aren't all the commits on the branch so aren't stored as such
+	restclient.FlushMockups()
+	addComplianceMock(commitsURL+"&path=docs", http.StatusOK, `[{"sha":"DOCS1"}]`)
+	result, fromStore, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, policydomain.PathFilter{"/docs/"})
+	assert.Nil(t, err)
+	assert.False(t, fromStore)
+	assert.EqualValues(t, "DOCS1", result[0].SHA)
+
+	restclient.FlushMockups()
+	_, _, err = getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
+	assert.NotNil(t, err)
+
+	//the stored commits are checked against the files of each commit
+	restclient.AddMockup(restclient.Mock{
+		URL:        commitsURL,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataCommitsResponseStatusCode(),
+			Body:       testutils.GetMockDataCommitsResponseMessage(),
+		},
+	})
+	_, _, err = getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
+	assert.Nil(t, err)
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleCommitResponseMessage(),
+		},
+	})
+	result, fromStore, err = getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, policydomain.PathFilter{"*.txt"})
+	assert.Nil(t, err)
+	assert.True(t, fromStore)
+	assert.EqualValues(t, 1, len(result))
+
+	//the files are now stored along with the commit
+	restclient.FlushMockups()
+	result, fromStore, err = getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, policydomain.PathFilter{"*.go"})
+	assert.Nil(t, err)
+	assert.True(t, fromStore)
+	assert.EqualValues(t, 0, len(result))
+}
+
+func TestFilterCommitsByFilesKeepsCommitWithTooManyFiles(t *testing.T) {
+	ResetService()
+	defer setupTestDataStore(t)()
+	restclient.FlushMockups()
+
+	//Github only lists the first 300 files so the commit may change a matching file that wasn't listed
+	commit := githubdomain.GetCommitInfo{SHA: "LARGE1", Parents: []githubdomain.Parent{{SHA: "PARENT1"}}, Stats: &githubdomain.CommitStats{}}
+	commit.Commit.Verification = &githubdomain.CommitVerification{}
+	for counter := 0; counter < maxCommitFiles; counter++ {
+		commit.Files = append(commit.Files, githubdomain.ChangedFile{Filename: fmt.Sprintf("vendor/file%d.go", counter)})
+	}
+	assert.Nil(t, DataStore.SaveCommits("myuser", "myrepo", []githubdomain.GetCommitInfo{commit}))
+
+	result, err := filterCommitsByFiles("myuser", "myrepo", []githubdomain.GetCommitInfo{{SHA: "LARGE1"}}, policydomain.PathFilter{"/docs/"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+
+	commit.Files = commit.Files[:1]
+	assert.Nil(t, DataStore.SaveCommits("myuser", "myrepo", []githubdomain.GetCommitInfo{commit}))
+	result, err = filterCommitsByFiles("myuser", "myrepo", []githubdomain.GetCommitInfo{{SHA: "LARGE1"}}, policydomain.PathFilter{"/docs/"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(result))
+}
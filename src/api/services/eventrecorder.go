@@ -0,0 +1,76 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+)
+
+//eventRecorder keeps hold of the Github data received via webhooks so the compliance
+//of each commit can be worked out incrementally rather than by polling Github
+type eventRecorder interface {
+	RecordCommits(owner string, repo string, branch string, commits []githubdomain.GetCommitInfo) error
+	RecordPullRequest(owner string, repo string, pullRequest *githubdomain.GetSinglePullRequestResponse) error
+	RecordPullRequestReview(owner string, repo string, pullNumber int64, review *githubdomain.PullRequestReview) error
+}
+
+//storeEventRecorder records the events in the data store so they are available to the reports
+//the mutex stops two deliveries updating the same stored list at once
+type storeEventRecorder struct {
+	mutex sync.Mutex
+}
+
+func newStoreEventRecorder() *storeEventRecorder {
+	return &storeEventRecorder{}
+}
+
+//dataRecorder is shared by everything that records data in the store so the lists are updated one at a time
+var dataRecorder = newStoreEventRecorder()
+
+//RecordCommits stores the commits pushed to the branch
+//push events don't include the parents of a commit so a copy already fetched from Github is kept in preference
+func (r *storeEventRecorder) RecordCommits(owner string, repo string, branch string, commits []githubdomain.GetCommitInfo) error {
+	for counter := range commits {
+		stored, err := DataStore.GetCommit(owner, repo, commits[counter].SHA)
+		if err == nil {
+			commits[counter] = *stored
+		} else if err != store.ErrNotFound {
+			return err
+		}
+	}
+	return DataStore.SaveBranchCommits(owner, repo, branch, commits)
+}
+
+//RecordPullRequest stores the latest state of the PR
+//the PRs of a commit aren't linked here as one event can't say which other PRs hold the commit, they are left to Github
+func (r *storeEventRecorder) RecordPullRequest(owner string, repo string, pullRequest *githubdomain.GetSinglePullRequestResponse) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return DataStore.SavePullRequests(owner, repo, []githubdomain.GetSinglePullRequestResponse{*pullRequest})
+}
+
+//RecordPullRequestReview stores the review, replacing any earlier version of the same review
+func (r *storeEventRecorder) RecordPullRequestReview(owner string, repo string, pullNumber int64, review *githubdomain.PullRequestReview) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	reviews, err := DataStore.GetPullRequestReviews(owner, repo, pullNumber)
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+
+	found := false
+	for counter := range reviews {
+		if reviews[counter].ID == review.ID {
+			reviews[counter] = *review
+			found = true
+			break
+		}
+	}
+	if !found {
+		reviews = append(reviews, *review)
+	}
+	return DataStore.SavePullRequestReviews(owner, repo, pullNumber, reviews)
+}
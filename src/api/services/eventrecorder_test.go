@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func getRecordedMergedPR(number int64, baseRef string, mergeSHA string, headSHA string) *githubdomain.GetSinglePullRequestResponse {
+	pull := githubdomain.GetSinglePullRequestResponse{Number: number, State: "closed", Merged: true, MergeCommitSHA: mergeSHA}
+	pull.Base.Ref = baseRef
+	pull.Head.SHA = headSHA
+	return &pull
+}
+
+func TestStoreEventRecorderPullRequestIsNotLinkedToCommits(t *testing.T) {
+	defer setupTestDataStore(t)()
+	recorder := newStoreEventRecorder()
+
+	assert.Nil(t, recorder.RecordPullRequest("owner", "repo", getRecordedMergedPR(4, "main", "SHA2", "SHA1")))
+
+	pull, err := DataStore.GetPullRequest("owner", "repo", 4)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "main", pull.Base.Ref)
+
+	_, err = DataStore.GetCommitPullRequests("owner", "repo", "SHA1")
+	assert.EqualValues(t, store.ErrNotFound, err)
+	_, err = DataStore.GetCommitPullRequests("owner", "repo", "SHA2")
+	assert.EqualValues(t, store.ErrNotFound, err)
+}
+
+func TestStoreEventRecorderKeepsFetchedCommits(t *testing.T) {
+	defer setupTestDataStore(t)()
+	recorder := newStoreEventRecorder()
+
+	fetched := githubdomain.GetCommitInfo{SHA: "SHA1", Parents: []githubdomain.Parent{{SHA: "SHA0"}}}
+	assert.Nil(t, DataStore.SaveCommits("owner", "repo", []githubdomain.GetCommitInfo{fetched}))
+	assert.Nil(t, recorder.RecordCommits("owner", "repo", "main", []githubdomain.GetCommitInfo{{SHA: "SHA1"}}))
+
+	stored, err := DataStore.GetCommit("owner", "repo", "SHA1")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(stored.Parents))
+}
+
+func TestStoreEventRecorderReviewIsReplaced(t *testing.T) {
+	defer setupTestDataStore(t)()
+	recorder := newStoreEventRecorder()
+
+	assert.Nil(t, recorder.RecordPullRequestReview("owner", "repo", 4, &githubdomain.PullRequestReview{ID: 1, State: githubdomain.ReviewStateApproved}))
+	assert.Nil(t, recorder.RecordPullRequestReview("owner", "repo", 4, &githubdomain.PullRequestReview{ID: 2, State: githubdomain.ReviewStateCommented}))
+	assert.Nil(t, recorder.RecordPullRequestReview("owner", "repo", 4, &githubdomain.PullRequestReview{ID: 1, State: githubdomain.ReviewStateDismissed}))
+
+	reviews, err := DataStore.GetPullRequestReviews("owner", "repo", 4)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(reviews))
+	assert.EqualValues(t, githubdomain.ReviewStateDismissed, reviews[0].State)
+	assert.EqualValues(t, githubdomain.ReviewStateCommented, reviews[1].State)
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/identitydomain"
+)
+
+//IdentityMailmap maps the names and emails used in commits to people, it is empty until a mailmap is set
+var IdentityMailmap = identitydomain.NewMailmap()
+
+//SetIdentityMailmap sets the mailmap used to work out who is behind each commit
+func SetIdentityMailmap(mailmap *identitydomain.Mailmap) {
+	IdentityMailmap = mailmap
+}
+
+//newIdentityResolver creates a resolver that knows the emails used by the commits linked to a Github account
+//so the other commits using those emails are attributed to the same person
+func newIdentityResolver(commits []githubdomain.GetCommitInfo) *identitydomain.Resolver {
+	resolver := identitydomain.NewResolver(IdentityMailmap)
+	for _, commit := range commits {
+		resolver.Observe(commit.Author.Login, commit.Commit.Author.Email)
+	}
+	return resolver
+}
+
+//resolveCommitAuthor returns the person who wrote the commit
+func resolveCommitAuthor(resolver *identitydomain.Resolver, commit *githubdomain.GetCommitInfo) identitydomain.Identity {
+	return resolver.Resolve(commit.Author.Login, commit.Commit.Author.Name, commit.Commit.Author.Email)
+}
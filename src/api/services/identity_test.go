@@ -0,0 +1,27 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/identitydomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIdentityResolver(t *testing.T) {
+	mailmap, err := identitydomain.ParseMailmap("<jane@example.com> <jane@old.example.com>")
+	assert.Nil(t, err)
+	SetIdentityMailmap(mailmap)
+	defer SetIdentityMailmap(identitydomain.NewMailmap())
+
+	linked := githubdomain.GetCommitInfo{SHA: "AAA111", Author: githubdomain.GitUser{Login: "janedoe"}}
+	linked.Commit.Author.Email = "jane@example.com"
+	unlinked := githubdomain.GetCommitInfo{SHA: "BBB222"}
+	unlinked.Commit.Author.Name = "Jane"
+	unlinked.Commit.Author.Email = "jane@old.example.com"
+
+	resolver := newIdentityResolver([]githubdomain.GetCommitInfo{linked, unlinked})
+	identity := resolveCommitAuthor(resolver, &unlinked)
+	assert.EqualValues(t, "janedoe", identity.ID)
+	assert.EqualValues(t, "jane@example.com", identity.Email)
+}
@@ -0,0 +1,513 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/identitydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/messagedomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/metricsdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+type metricsService struct{}
+
+type metricsServiceInterface interface {
+	GetPRMetrics(owner string, repo string, fromDate string, toDate string, author string, baseRef string) (*metricsdomain.PullRequestMetrics, errors.APIError)
+	GetDORAMetrics(owner string, repo string, fromDate string, toDate string, source string, environment string) (*metricsdomain.DORAMetrics, errors.APIError)
+	GetContributorActivity(owner string, repo string, fromDate string, toDate string, includeBots string) (*metricsdomain.ContributorActivityReport, errors.APIError)
+}
+
+const (
+	errorInvalidFromDate     = "invalid from parameter, expected a date such as 2020-01-31"
+	errorInvalidToDate       = "invalid to parameter, expected a date such as 2020-01-31"
+	errorInvalidDateRange    = "invalid date range, the from date must not be after the to date"
+	errorInvalidSourceParam  = "invalid source parameter, expected deployments or releases"
+	errorInvalidBotsParam    = "invalid include_bots parameter, expected true or false"
+	metricsDateFormat        = "2006-01-02"
+	defaultMetricsPeriodDays = 90
+	defaultEnvironment       = "production"
+)
+
+//MetricsService defines the metrics service to use
+var MetricsService metricsServiceInterface
+
+func init() {
+	MetricsService = &metricsService{}
+}
+
+//ResetMetricsService calls the init function again
+func ResetMetricsService() {
+	MetricsService = &metricsService{}
+}
+
+//validateDateRange parses the dates of a period such as 2020-01-31, both dates are included in the period
+//so the returned end is the start of the day after the to date, the period defaults to the last 90 days
+func validateDateRange(fromDate string, toDate string, now time.Time) (time.Time, time.Time, errors.APIError) {
+	end := now.UTC()
+	if toDate = strings.TrimSpace(toDate); toDate != "" {
+		parsed, err := time.Parse(metricsDateFormat, toDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.NewBadRequestError(errorInvalidToDate)
+		}
+		end = parsed.AddDate(0, 0, 1)
+	}
+
+	start := end.AddDate(0, 0, -defaultMetricsPeriodDays)
+	if fromDate = strings.TrimSpace(fromDate); fromDate != "" {
+		parsed, err := time.Parse(metricsDateFormat, fromDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.NewBadRequestError(errorInvalidFromDate)
+		}
+		start = parsed
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.NewBadRequestError(errorInvalidDateRange)
+	}
+	return start, end, nil
+}
+
+//GetPRMetrics returns the cycle time and review latency of the PRs created in the period
+//the PRs can be limited to those raised by an author or against a base branch
+func (s *metricsService) GetPRMetrics(owner string, repo string, fromDate string, toDate string, author string, baseRef string) (*metricsdomain.PullRequestMetrics, errors.APIError) {
+	var err errors.APIError
+	owner, repo, err = validateAllCommitsInputs(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := validateDateRange(fromDate, toDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	author = strings.TrimSpace(author)
+	baseRef = strings.TrimSpace(baseRef)
+
+	dataAsOf := time.Now().UTC()
+	pulls, err := getPullRequestsCreatedBetween(owner, repo, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := newIdentityResolver(nil)
+	timings := make([]metricsdomain.PullRequestTimings, 0)
+	for counter := range pulls {
+		pullRequest := &pulls[counter]
+		if author != "" && !strings.EqualFold(pullRequest.User.Login, author) {
+			continue
+		}
+		if baseRef != "" && pullRequest.Base.Ref != baseRef {
+			continue
+		}
+
+		//the PRs in the list don't say how many lines they change so each is fetched on its own
+		pullNumber := strconv.FormatInt(pullRequest.Number, 10)
+		details, err := RepositoryService.GetRepoSinglePR(owner, repo, pullNumber)
+		if err != nil {
+			return nil, err
+		}
+		reviews, err := RepositoryService.GetPRReviews(owner, repo, pullNumber)
+		if err != nil {
+			return nil, err
+		}
+		timing := getPullRequestTimings(resolver, pullRequest, reviews)
+		timing.SetSize(details.Additions, details.Deletions, details.ChangedFiles)
+		timings = append(timings, timing)
+	}
+
+	metrics := metricsdomain.NewPullRequestMetrics(owner, repo, start, end, timings, dataAsOf)
+	metrics.Author = author
+	metrics.BaseRef = baseRef
+	metrics.Sizes = metricsdomain.NewSizeDistribution(metrics.PullRequests, ReviewPolicies.ForRepo(owner, repo).LargeChangeLines)
+	if directory := getTeamDirectory(); directory.HasTeams() {
+		metrics.SetTeams(directory)
+	}
+	return metrics, nil
+}
+
+//getPullRequestsUpdatedSince returns the PRs updated since the start, any PR created, merged or reviewed
+//since the start has been updated since then, the store keeps any copy of a PR fetched on its own so its size isn't lost
+func getPullRequestsUpdatedSince(owner string, repo string, start time.Time) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError) {
+	pulls, errProvider := githubprovider.GetRepoPRsUpdatedSince(config.GetGithubAccessToken(), owner, repo, start)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+	logStoreError("save PRs to", DataStore.SavePullRequests(owner, repo, pulls))
+	return pulls, nil
+}
+
+//getPullRequestsCreatedBetween returns the PRs created in the period
+func getPullRequestsCreatedBetween(owner string, repo string, start time.Time, end time.Time) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError) {
+	pulls, err := getPullRequestsUpdatedSince(owner, repo, start)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]githubdomain.GetSinglePullRequestResponse, 0)
+	for _, pullRequest := range pulls {
+		if isInPeriod(pullRequest.CreatedAt, start, end) {
+			result = append(result, pullRequest)
+		}
+	}
+	return result, nil
+}
+
+//getPullRequestTimings works out when the PR reached each stage, reviews by the author of the PR don't count
+//the first review is the first submitted review of any kind and the approval is the first approving review
+func getPullRequestTimings(resolver *identitydomain.Resolver, pullRequest *githubdomain.GetSinglePullRequestResponse, reviews []githubdomain.PullRequestReview) metricsdomain.PullRequestTimings {
+	timings := metricsdomain.PullRequestTimings{
+		Number:    pullRequest.Number,
+		Title:     pullRequest.Title,
+		Author:    pullRequest.User.Login,
+		AuthorID:  resolver.Resolve(pullRequest.User.Login, "", "").ID,
+		BaseRef:   pullRequest.Base.Ref,
+		State:     pullRequest.State,
+		CreatedAt: pullRequest.CreatedAt,
+	}
+	if !pullRequest.MergedAt.IsZero() {
+		mergedAt := pullRequest.MergedAt
+		timings.MergedAt = &mergedAt
+	}
+	if !pullRequest.ClosedAt.IsZero() {
+		closedAt := pullRequest.ClosedAt
+		timings.ClosedAt = &closedAt
+	}
+
+	for counter := range reviews {
+		review := &reviews[counter]
+		if review.State == githubdomain.ReviewStatePending || review.SubmittedAt.IsZero() {
+			continue
+		}
+		if strings.EqualFold(review.User.Login, pullRequest.User.Login) {
+			continue
+		}
+		submittedAt := review.SubmittedAt
+		if timings.FirstReviewAt == nil || submittedAt.Before(*timings.FirstReviewAt) {
+			timings.FirstReviewAt = &submittedAt
+		}
+		if review.State == githubdomain.ReviewStateApproved && (timings.ApprovedAt == nil || submittedAt.Before(*timings.ApprovedAt)) {
+			timings.ApprovedAt = &submittedAt
+		}
+	}
+	return timings
+}
+
+//GetDORAMetrics returns the deployment frequency, lead time for changes, change failure rate and time to restore
+//of the repo over the period, the deployments are read from the Github deployments to the environment, production
+//by default, or from the releases of the repo
+func (s *metricsService) GetDORAMetrics(owner string, repo string, fromDate string, toDate string, source string, environment string) (*metricsdomain.DORAMetrics, errors.APIError) {
+	var err errors.APIError
+	owner, repo, err = validateAllCommitsInputs(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := validateDateRange(fromDate, toDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if source = strings.TrimSpace(source); source == "" {
+		source = metricsdomain.DeploymentSourceDeployments
+	}
+	if environment = strings.TrimSpace(environment); environment == "" {
+		environment = defaultEnvironment
+	}
+
+	dataAsOf := time.Now().UTC()
+	var deployments []metricsdomain.Deployment
+	switch source {
+	case metricsdomain.DeploymentSourceDeployments:
+		deployments, err = getGithubDeployments(owner, repo, environment, start, end)
+	case metricsdomain.DeploymentSourceReleases:
+		environment = ""
+		deployments, err = getReleaseDeployments(owner, repo, start, end)
+	default:
+		return nil, errors.NewBadRequestError(errorInvalidSourceParam)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return metricsdomain.NewDORAMetrics(owner, repo, source, environment, start, end, deployments, dataAsOf), nil
+}
+
+//getGithubDeployments returns the finished deployments to the environment in the period, oldest first
+//a deployment succeeded if any of its statuses is success, it becomes inactive once replaced, and failed
+//if its latest status is failure or error, deployments still in progress are left out
+//the deployments before the period are read back to the last successful one, where the changes of the first deployment start
+func getGithubDeployments(owner string, repo string, environment string, start time.Time, end time.Time) ([]metricsdomain.Deployment, errors.APIError) {
+	statuses := newDeploymentStatuses(owner, repo)
+	isBase := func(deployment *githubdomain.Deployment) bool {
+		deploymentStatuses, errProvider := statuses.get(deployment.ID)
+		if errProvider != nil {
+			return true
+		}
+		outcome, _ := getDeploymentOutcome(deploymentStatuses)
+		return outcome == metricsdomain.DeploymentSucceeded
+	}
+	githubDeployments, errProvider := githubprovider.GetDeploymentsSince(config.GetGithubAccessToken(), owner, repo, environment, start, isBase)
+	if errProvider == nil {
+		errProvider = statuses.err
+	}
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	result := make([]metricsdomain.Deployment, 0)
+	previousSHA := ""
+	for counter := len(githubDeployments) - 1; counter >= 0; counter-- {
+		githubDeployment := &githubDeployments[counter]
+		if !githubDeployment.CreatedAt.Before(end) {
+			break
+		}
+		deploymentStatuses, errProvider := statuses.get(githubDeployment.ID)
+		if errProvider != nil {
+			return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+		}
+		outcome, deployedAt := getDeploymentOutcome(deploymentStatuses)
+		if outcome == "" {
+			continue
+		}
+
+		//deployments before the period only mark where the changes of the first deployment in the period start
+		if githubDeployment.CreatedAt.Before(start) {
+			if outcome == metricsdomain.DeploymentSucceeded {
+				previousSHA = githubDeployment.SHA
+			}
+			continue
+		}
+
+		deployment := metricsdomain.Deployment{
+			ID:         strconv.FormatInt(githubDeployment.ID, 10),
+			SHA:        githubDeployment.SHA,
+			Ref:        githubDeployment.Ref,
+			DeployedAt: deployedAt,
+			Outcome:    outcome,
+		}
+		if err := setDeployedCommits(owner, repo, previousSHA, githubDeployment.SHA, &deployment); err != nil {
+			return nil, err
+		}
+		if outcome == metricsdomain.DeploymentSucceeded {
+			previousSHA = githubDeployment.SHA
+		}
+		result = append(result, deployment)
+	}
+	return result, nil
+}
+
+//deploymentStatuses holds the statuses of each deployment so they are only fetched once
+//the first error fetching them is kept so it can be reported once the deployments have been read
+type deploymentStatuses struct {
+	owner    string
+	repo     string
+	statuses map[int64][]githubdomain.DeploymentStatus
+	err      *githubdomain.GithubErrorResponse
+}
+
+func newDeploymentStatuses(owner string, repo string) *deploymentStatuses {
+	return &deploymentStatuses{owner: owner, repo: repo, statuses: make(map[int64][]githubdomain.DeploymentStatus)}
+}
+
+//get returns the statuses of the deployment, newest first
+func (d *deploymentStatuses) get(deploymentID int64) ([]githubdomain.DeploymentStatus, *githubdomain.GithubErrorResponse) {
+	if statuses, ok := d.statuses[deploymentID]; ok {
+		return statuses, nil
+	}
+	statuses, errProvider := githubprovider.GetDeploymentStatuses(config.GetGithubAccessToken(), d.owner, d.repo, deploymentID)
+	if errProvider != nil {
+		if d.err == nil {
+			d.err = errProvider
+		}
+		return nil, errProvider
+	}
+	d.statuses[deploymentID] = statuses
+	return statuses, nil
+}
+
+//getDeploymentOutcome returns whether the deployment succeeded or failed and when, the statuses are newest first
+//an empty outcome means the deployment hasn't finished
+func getDeploymentOutcome(statuses []githubdomain.DeploymentStatus) (string, time.Time) {
+	for counter := len(statuses) - 1; counter >= 0; counter-- {
+		if statuses[counter].State == githubdomain.DeploymentStateSuccess {
+			return metricsdomain.DeploymentSucceeded, statuses[counter].CreatedAt
+		}
+	}
+	if len(statuses) > 0 && (statuses[0].State == githubdomain.DeploymentStateFailure || statuses[0].State == githubdomain.DeploymentStateError) {
+		return metricsdomain.DeploymentFailed, statuses[0].CreatedAt
+	}
+	return "", time.Time{}
+}
+
+//getReleaseDeployments returns the releases published in the period as deployments, oldest first
+//drafts and pre-releases don't reach production so are left out
+func getReleaseDeployments(owner string, repo string, start time.Time, end time.Time) ([]metricsdomain.Deployment, errors.APIError) {
+	releases, errProvider := githubprovider.GetReleasesSince(config.GetGithubAccessToken(), owner, repo, start)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	result := make([]metricsdomain.Deployment, 0)
+	previousTag := ""
+	for counter := len(releases) - 1; counter >= 0; counter-- {
+		release := &releases[counter]
+		if release.Draft || release.Prerelease {
+			continue
+		}
+		publishedAt := release.PublishedAt
+		if publishedAt.IsZero() {
+			publishedAt = release.CreatedAt
+		}
+		if !publishedAt.Before(end) {
+			break
+		}
+		if publishedAt.Before(start) {
+			previousTag = release.TagName
+			continue
+		}
+
+		deployment := metricsdomain.Deployment{
+			ID:         release.TagName,
+			Ref:        release.TagName,
+			DeployedAt: publishedAt,
+			Outcome:    metricsdomain.DeploymentSucceeded,
+		}
+		if err := setDeployedCommits(owner, repo, previousTag, release.TagName, &deployment); err != nil {
+			return nil, err
+		}
+		previousTag = release.TagName
+		result = append(result, deployment)
+	}
+	return result, nil
+}
+
+//setDeployedCommits records the commits deployed since the previous deployment, along with their lead times and
+//any reverts or hotfixes, nothing is known about the commits of a deployment without a previous one
+func setDeployedCommits(owner string, repo string, base string, head string, deployment *metricsdomain.Deployment) errors.APIError {
+	if base == "" || head == "" {
+		return nil
+	}
+	compare, errProvider := githubprovider.GetCompareAllCommits(config.GetGithubAccessToken(), owner, repo, base, head)
+	if errProvider != nil {
+		return errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	deployment.Commits = len(compare.Commits)
+	for _, commit := range compare.Commits {
+		if leadTime := deployment.DeployedAt.Sub(commit.Commit.Committer.Date); leadTime >= 0 {
+			deployment.LeadTimes = append(deployment.LeadTimes, leadTime)
+		}
+		if messagedomain.ParseCommitMessage(commit.Commit.Message).IsRevert() {
+			deployment.RevertCommits = append(deployment.RevertCommits, commit.SHA)
+		}
+		if metricsdomain.IsHotfixCommit(commit.Commit.Message) {
+			deployment.HotfixCommits = append(deployment.HotfixCommits, commit.SHA)
+		}
+	}
+	return nil
+}
+
+//isInPeriod determines if the time falls in the period, which includes its start but not its end
+func isInPeriod(t time.Time, start time.Time, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
+
+//GetContributorActivity returns who wrote and who reviewed the code of the repo over the period
+//commits are counted on the default branch and each is attributed to a person using the mailmap, Github noreply emails
+//and the emails used with a login in the period
+//bots are left out unless include_bots is true
+func (s *metricsService) GetContributorActivity(owner string, repo string, fromDate string, toDate string, includeBots string) (*metricsdomain.ContributorActivityReport, errors.APIError) {
+	var err errors.APIError
+	owner, repo, err = validateAllCommitsInputs(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := validateDateRange(fromDate, toDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	withBots := false
+	if includeBots = strings.TrimSpace(includeBots); includeBots != "" {
+		var errParse error
+		if withBots, errParse = strconv.ParseBool(includeBots); errParse != nil {
+			return nil, errors.NewBadRequestError(errorInvalidBotsParam)
+		}
+	}
+
+	repoInfo, err := RepositoryService.GetRepo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	dataAsOf := time.Now().UTC()
+	commits, _, err := getRepoCommitsInDateRange(owner, repo, repoInfo.DefaultBranch, start, end, nil)
+	if err != nil {
+		return nil, err
+	}
+	pulls, err := getPullRequestsUpdatedSince(owner, repo, start)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := newIdentityResolver(commits)
+	contributors := metricsdomain.NewContributors()
+	for counter := range commits {
+		commit := &commits[counter]
+		if withBots || !metricsdomain.IsBot(commit.Author.Login, commit.Author.Type, commit.Commit.Author.Email) {
+			contributors.Get(resolveCommitAuthor(resolver, commit)).Commits++
+		}
+	}
+
+	for counter := range pulls {
+		pullRequest := &pulls[counter]
+		if !pullRequest.CreatedAt.Before(end) {
+			continue
+		}
+		if withBots || !metricsdomain.IsBot(pullRequest.User.Login, pullRequest.User.Type, "") {
+			if isInPeriod(pullRequest.CreatedAt, start, end) {
+				contributors.Get(resolver.Resolve(pullRequest.User.Login, "", "")).PullRequestsOpened++
+			}
+			if isInPeriod(pullRequest.MergedAt, start, end) {
+				contributors.Get(resolver.Resolve(pullRequest.User.Login, "", "")).PullRequestsMerged++
+			}
+		}
+
+		reviews, err := RepositoryService.GetPRReviews(owner, repo, strconv.FormatInt(pullRequest.Number, 10))
+		if err != nil {
+			return nil, err
+		}
+		addReviewActivity(contributors, resolver, pullRequest, reviews, start, end, withBots)
+	}
+
+	return &metricsdomain.ContributorActivityReport{
+		Owner:        owner,
+		Repo:         repo,
+		Branch:       repoInfo.DefaultBranch,
+		FromDate:     start,
+		ToDate:       end,
+		DataAsOf:     dataAsOf,
+		IncludeBots:  withBots,
+		Contributors: contributors.List(),
+	}, nil
+}
+
+//addReviewActivity counts the reviews submitted in the period, reviews by the author of the PR are only comments on their own work
+func addReviewActivity(contributors *metricsdomain.Contributors, resolver *identitydomain.Resolver, pullRequest *githubdomain.GetSinglePullRequestResponse, reviews []githubdomain.PullRequestReview, start time.Time, end time.Time, withBots bool) {
+	for _, review := range reviews {
+		if review.State == githubdomain.ReviewStatePending || !isInPeriod(review.SubmittedAt, start, end) {
+			continue
+		}
+		if strings.EqualFold(review.User.Login, pullRequest.User.Login) {
+			continue
+		}
+		if !withBots && metricsdomain.IsBot(review.User.Login, review.User.Type, "") {
+			continue
+		}
+		activity := contributors.Get(resolver.Resolve(review.User.Login, "", ""))
+		activity.ReviewsGiven++
+		if review.State == githubdomain.ReviewStateApproved {
+			activity.ApprovalsGiven++
+		}
+	}
+}
@@ -0,0 +1,526 @@
+package services
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/metricsdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testMetricsPulls = `[
+		{"number":4,"state":"open","user":{"login":"author2"},"base":{"ref":"release"},"created_at":"2020-03-12T09:00:00Z","updated_at":"2020-03-13T09:00:00Z"},
+		{"number":3,"state":"open","user":{"login":"author1"},"base":{"ref":"main"},"created_at":"2020-03-11T09:00:00Z","updated_at":"2020-03-12T09:00:00Z"},
+		{"number":2,"state":"closed","user":{"login":"author1"},"base":{"ref":"main"},"created_at":"2020-03-03T09:00:00Z","updated_at":"2020-03-04T10:00:00Z","closed_at":"2020-03-04T10:00:00Z","merged_at":"2020-03-04T10:00:00Z"},
+		{"number":1,"state":"closed","user":{"login":"author1"},"base":{"ref":"main"},"created_at":"2020-02-20T09:00:00Z","updated_at":"2020-03-02T10:00:00Z","closed_at":"2020-03-02T10:00:00Z"},
+		{"number":0,"state":"closed","user":{"login":"author1"},"base":{"ref":"main"},"created_at":"2020-01-20T09:00:00Z","updated_at":"2020-01-21T10:00:00Z"}
+	]`
+	testMetricsPullsURL = "https://api.github.com/repos/myuser/myrepo/pulls?state=all&sort=updated&direction=desc&per_page=100"
+)
+
+//addMetricsMocks mocks the PRs of myuser/myrepo along with the size and reviews of PRs 2, 3 and 4
+func addMetricsMocks() {
+	restclient.FlushMockups()
+	addComplianceMock(testMetricsPullsURL, http.StatusOK, testMetricsPulls)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/2", http.StatusOK, `{"number":2,"state":"closed","updated_at":"2020-03-04T10:00:00Z","commits":1,"additions":20,"deletions":10,"changed_files":2}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/3", http.StatusOK, `{"number":3,"state":"open","updated_at":"2020-03-12T09:00:00Z","commits":9,"additions":1200,"deletions":3,"changed_files":40}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/4", http.StatusOK, `{"number":4,"state":"open","updated_at":"2020-03-13T09:00:00Z","commits":1,"additions":2,"deletions":1,"changed_files":1}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/2/reviews?per_page=100", http.StatusOK, `[
+		{"id":1,"user":{"login":"author1"},"state":"COMMENTED","submitted_at":"2020-03-03T09:30:00Z"},
+		{"id":2,"user":{"login":"reviewer1"},"state":"CHANGES_REQUESTED","submitted_at":"2020-03-03T11:00:00Z"},
+		{"id":3,"user":{"login":"reviewer1"},"state":"APPROVED","submitted_at":"2020-03-04T09:00:00Z"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/3/reviews?per_page=100", http.StatusOK, `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/4/reviews?per_page=100", http.StatusOK,
+		`[{"id":4,"user":{"login":"reviewer1"},"state":"APPROVED","submitted_at":"2020-03-12T10:00:00Z"}]`)
+}
+
+func TestValidateDateRange(t *testing.T) {
+	now := time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	start, end, err := validateDateRange("", "", now)
+	assert.Nil(t, err)
+	assert.True(t, now.Equal(end))
+	assert.True(t, now.AddDate(0, 0, -90).Equal(start))
+
+	start, end, err = validateDateRange("2020-03-01", "2020-03-14", now)
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC).Equal(start))
+	assert.True(t, time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC).Equal(end))
+
+	_, _, err = validateDateRange("01/03/2020", "", now)
+	assert.EqualValues(t, errorInvalidFromDate, err.Message())
+	_, _, err = validateDateRange("", "2020-13-01", now)
+	assert.EqualValues(t, errorInvalidToDate, err.Message())
+	_, _, err = validateDateRange("2020-03-02", "2020-03-01", now)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, errorInvalidDateRange, err.Message())
+}
+
+func TestGetPRMetricsInvalidInputs(t *testing.T) {
+	ResetMetricsService()
+	result, err := MetricsService.GetPRMetrics(" ", "myrepo", "", "", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, errorInvalidOwnerParam, err.Message())
+
+	result, err = MetricsService.GetPRMetrics("myuser", "myrepo", "yesterday", "", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, errorInvalidFromDate, err.Message())
+}
+
+func TestGetPRMetricsErrorGettingPRs(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	restclient.FlushMockups()
+	addComplianceMock(testMetricsPullsURL, http.StatusUnauthorized, `{"message":"Bad credentials"}`)
+
+	result, err := MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+}
+
+func TestGetPRMetricsErrorGettingReviews(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addMetricsMocks()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/3/reviews?per_page=100", http.StatusNotFound, `{"message":"Not Found"}`)
+
+	result, err := MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetPRMetrics(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addMetricsMocks()
+
+	result, err := MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, result.Overall.PullRequests)
+	assert.EqualValues(t, 1, result.Overall.Merged)
+	assert.EqualValues(t, 2, result.Overall.TimeToFirstReview.Count)
+	assert.EqualValues(t, 1, result.Overall.TimeToFirstReview.P50)
+	assert.EqualValues(t, 2, result.Overall.TimeToFirstReview.P90)
+	assert.EqualValues(t, 24, result.Overall.TimeToApproval.P90)
+	assert.EqualValues(t, 25, result.Overall.TimeToMerge.P50)
+	assert.EqualValues(t, 3, len(result.Weekly))
+	assert.EqualValues(t, 0, result.Weekly[0].PullRequests)
+	assert.EqualValues(t, 1, result.Weekly[1].PullRequests)
+	assert.EqualValues(t, 2, result.Weekly[2].PullRequests)
+	assert.EqualValues(t, 2, result.PullRequests[0].Number)
+	assert.EqualValues(t, 4, result.PullRequests[2].Number)
+	assert.Nil(t, result.Teams)
+	assert.EqualValues(t, metricsdomain.SizeS, result.PullRequests[0].Size)
+	assert.EqualValues(t, 1203, result.PullRequests[1].ChangedLines())
+	assert.EqualValues(t, 3, result.Sizes.PullRequests)
+	assert.EqualValues(t, 30, result.Sizes.P50Lines)
+	assert.EqualValues(t, 1, result.Sizes.Buckets[0].PullRequests)
+	assert.EqualValues(t, 1, result.Sizes.Buckets[1].PullRequests)
+	assert.EqualValues(t, 1, result.Sizes.Buckets[4].PullRequests)
+	assert.EqualValues(t, 1000, result.Sizes.LargeChangeLines)
+	assert.EqualValues(t, []int64{3}, result.Sizes.LargePullRequests)
+}
+
+func TestGetPRMetricsSizesFromStore(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	defer setupTestDataStore(t)()
+	addMetricsMocks()
+
+	result, err := MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 30, result.PullRequests[0].ChangedLines())
+
+	//the list of PRs is saved again but the merged PR keeps its size so is read from the store
+	addMetricsMocks()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/2", http.StatusNotFound, `{"message":"Not Found"}`)
+	result, err = MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, result.PullRequests[0].Number)
+	assert.EqualValues(t, 30, result.PullRequests[0].ChangedLines())
+	assert.EqualValues(t, 1203, result.PullRequests[1].ChangedLines())
+	assert.EqualValues(t, 30, result.Sizes.P50Lines)
+	assert.EqualValues(t, []int64{3}, result.Sizes.LargePullRequests)
+}
+
+func TestGetPRMetricsErrorGettingPR(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addMetricsMocks()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/4", http.StatusNotFound, `{"message":"Not Found"}`)
+
+	result, err := MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetPRMetricsByTeam(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addMetricsMocks()
+	SetTeamDirectory(&teamdomain.Directory{Teams: []teamdomain.Team{{Name: "payments", Members: []string{"Author2"}}}})
+	defer SetTeamDirectory(teamdomain.NewDirectory())
+
+	result, err := MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(result.Teams))
+	assert.EqualValues(t, "payments", result.Teams[0].Team)
+	assert.EqualValues(t, 1, result.Teams[0].PullRequests)
+	assert.EqualValues(t, 1, result.Teams[0].TimeToFirstReview.P50)
+	assert.EqualValues(t, teamdomain.Unassigned, result.Teams[1].Team)
+	assert.EqualValues(t, 2, result.Teams[1].PullRequests)
+}
+
+func TestGetPRMetricsFilters(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addMetricsMocks()
+
+	result, err := MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "Author1", "main")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Author1", result.Author)
+	assert.EqualValues(t, "main", result.BaseRef)
+	assert.EqualValues(t, 2, result.Overall.PullRequests)
+
+	addMetricsMocks()
+	result, err = MetricsService.GetPRMetrics("myuser", "myrepo", "2020-03-01", "2020-03-14", "", "release")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, result.Overall.PullRequests)
+	assert.EqualValues(t, 4, result.PullRequests[0].Number)
+}
+
+func TestGetPullRequestTimings(t *testing.T) {
+	created := time.Date(2020, 3, 3, 9, 0, 0, 0, time.UTC)
+	pullRequest := &githubdomain.GetSinglePullRequestResponse{Number: 7, User: githubdomain.GitUser{Login: "author"}, CreatedAt: created}
+	reviews := []githubdomain.PullRequestReview{
+		{User: githubdomain.GitUser{Login: "author"}, State: githubdomain.ReviewStateApproved, SubmittedAt: created.Add(time.Minute)},
+		{User: githubdomain.GitUser{Login: "reviewer"}, State: githubdomain.ReviewStatePending},
+		{User: githubdomain.GitUser{Login: "reviewer"}, State: githubdomain.ReviewStateApproved, SubmittedAt: created.Add(3 * time.Hour)},
+		{User: githubdomain.GitUser{Login: "other"}, State: githubdomain.ReviewStateCommented, SubmittedAt: created.Add(2 * time.Hour)},
+	}
+
+	timings := getPullRequestTimings(newIdentityResolver(nil), pullRequest, reviews)
+	assert.EqualValues(t, "author", timings.AuthorID)
+	assert.True(t, created.Add(2*time.Hour).Equal(*timings.FirstReviewAt))
+	assert.True(t, created.Add(3*time.Hour).Equal(*timings.ApprovedAt))
+	assert.Nil(t, timings.MergedAt)
+	assert.Nil(t, timings.ClosedAt)
+}
+
+const testDeploymentsURL = "https://api.github.com/repos/myuser/myrepo/deployments?environment=production&per_page=100"
+
+//addDORADeploymentMocks mocks three deployments in March 2020 and one before it, along with their statuses and changes
+//the second deployment fails and the third one contains a revert
+func addDORADeploymentMocks() {
+	restclient.FlushMockups()
+	addComplianceMock(testDeploymentsURL, http.StatusOK, `[
+		{"id":5,"sha":"EEE555","ref":"main","created_at":"2020-03-20T10:00:00Z"},
+		{"id":4,"sha":"DDD444","ref":"main","created_at":"2020-03-10T10:00:00Z"},
+		{"id":3,"sha":"CCC333","ref":"main","created_at":"2020-03-05T10:00:00Z"},
+		{"id":2,"sha":"BBB222","ref":"main","created_at":"2020-03-03T10:00:00Z"},
+		{"id":1,"sha":"AAA111","ref":"main","created_at":"2020-02-20T10:00:00Z"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/1/statuses?per_page=100", http.StatusOK,
+		`[{"id":12,"state":"inactive","created_at":"2020-03-03T10:05:00Z"},{"id":11,"state":"success","created_at":"2020-02-20T10:05:00Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/2/statuses?per_page=100", http.StatusOK,
+		`[{"id":21,"state":"success","created_at":"2020-03-03T10:05:00Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/3/statuses?per_page=100", http.StatusOK,
+		`[{"id":32,"state":"failure","created_at":"2020-03-05T10:10:00Z"},{"id":31,"state":"in_progress","created_at":"2020-03-05T10:01:00Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/4/statuses?per_page=100", http.StatusOK,
+		`[{"id":41,"state":"success","created_at":"2020-03-10T10:05:00Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/5/statuses?per_page=100", http.StatusOK,
+		`[{"id":51,"state":"queued","created_at":"2020-03-20T10:01:00Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/AAA111...BBB222?per_page=100", http.StatusOK,
+		`{"status":"ahead","commits":[{"sha":"B1","commit":{"message":"Add a feature","committer":{"date":"2020-03-02T10:05:00Z"}}},{"sha":"B2","commit":{"message":"Tidy up","committer":{"date":"2020-03-03T08:05:00Z"}}}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/BBB222...CCC333?per_page=100", http.StatusOK,
+		`{"status":"ahead","commits":[{"sha":"C1","commit":{"message":"Change the config","committer":{"date":"2020-03-05T09:05:00Z"}}}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/BBB222...DDD444?per_page=100", http.StatusOK,
+		`{"status":"ahead","commits":[{"sha":"C1","commit":{"message":"Change the config","committer":{"date":"2020-03-05T09:05:00Z"}}},{"sha":"D1","commit":{"message":"Revert \"Add a feature\"\n\nThis reverts commit b1b1b1b1.","committer":{"date":"2020-03-10T09:05:00Z"}}}]}`)
+}
+
+func TestGetDORAMetricsInvalidInputs(t *testing.T) {
+	ResetMetricsService()
+	result, err := MetricsService.GetDORAMetrics("myuser", " ", "", "", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, errorInvalidRepoParam, err.Message())
+
+	result, err = MetricsService.GetDORAMetrics("myuser", "myrepo", "", "", "tags", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, errorInvalidSourceParam, err.Message())
+}
+
+func TestGetDORAMetricsErrorGettingDeployments(t *testing.T) {
+	ResetMetricsService()
+	restclient.FlushMockups()
+	addComplianceMock(testDeploymentsURL, http.StatusNotFound, `{"message":"Not Found"}`)
+
+	result, err := MetricsService.GetDORAMetrics("myuser", "myrepo", "2020-03-01", "2020-03-31", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetDORAMetricsErrorGettingStatuses(t *testing.T) {
+	ResetMetricsService()
+	addDORADeploymentMocks()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/3/statuses?per_page=100", http.StatusForbidden, `{"message":"Forbidden"}`)
+
+	result, err := MetricsService.GetDORAMetrics("myuser", "myrepo", "2020-03-01", "2020-03-31", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+}
+
+func TestGetDORAMetricsErrorComparingDeployments(t *testing.T) {
+	ResetMetricsService()
+	addDORADeploymentMocks()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/BBB222...DDD444?per_page=100", http.StatusNotFound, `{"message":"Not Found"}`)
+
+	result, err := MetricsService.GetDORAMetrics("myuser", "myrepo", "2020-03-01", "2020-03-31", "", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetDORAMetricsFromDeployments(t *testing.T) {
+	ResetMetricsService()
+	addDORADeploymentMocks()
+
+	result, err := MetricsService.GetDORAMetrics("myuser", "myrepo", "2020-03-01", "2020-03-31", "", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "deployments", result.Source)
+	assert.EqualValues(t, "production", result.Environment)
+	assert.EqualValues(t, 3, result.TotalDeployments)
+	assert.EqualValues(t, 1, result.FailedDeployments)
+	assert.EqualValues(t, 0.45, result.DeploymentsPerWeek)
+
+	assert.EqualValues(t, "2", result.Deployments[0].ID)
+	assert.EqualValues(t, 2, result.Deployments[0].Commits)
+	assert.EqualValues(t, metricsdomain.DeploymentFailed, result.Deployments[1].Outcome)
+	assert.EqualValues(t, []string{"D1"}, result.Deployments[2].RevertCommits)
+
+	//lead times of 24h and 2h for the first deployment then 121h and 1h for the last
+	assert.EqualValues(t, 4, result.LeadTimeForChanges.Count)
+	assert.EqualValues(t, 2, result.LeadTimeForChanges.P50)
+	assert.EqualValues(t, 121, result.LeadTimeForChanges.P90)
+
+	//the failed deployment and the reverted first deployment
+	assert.EqualValues(t, 2, result.ChangeFailures)
+	assert.EqualValues(t, 0.67, result.ChangeFailureRate)
+	assert.EqualValues(t, 2, result.TimeToRestore.Count)
+}
+
+func TestGetDORAMetricsLastDeploymentBeforePeriodFailed(t *testing.T) {
+	ResetMetricsService()
+	restclient.FlushMockups()
+	addComplianceMock(testDeploymentsURL, http.StatusOK, `[
+		{"id":3,"sha":"CCC333","ref":"main","created_at":"2020-03-05T10:00:00Z"},
+		{"id":2,"sha":"BBB222","ref":"main","created_at":"2020-02-25T10:00:00Z"},
+		{"id":1,"sha":"AAA111","ref":"main","created_at":"2020-02-20T10:00:00Z"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/1/statuses?per_page=100", http.StatusOK,
+		`[{"id":11,"state":"success","created_at":"2020-02-20T10:05:00Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/2/statuses?per_page=100", http.StatusOK,
+		`[{"id":21,"state":"failure","created_at":"2020-02-25T10:05:00Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/deployments/3/statuses?per_page=100", http.StatusOK,
+		`[{"id":31,"state":"success","created_at":"2020-03-05T10:05:00Z"}]`)
+	//the changes of the first deployment start from the last deployment that succeeded
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/AAA111...CCC333?per_page=100", http.StatusOK,
+		`{"status":"ahead","commits":[{"sha":"B1","commit":{"message":"Add a feature","committer":{"date":"2020-02-24T10:05:00Z"}}},{"sha":"C1","commit":{"message":"Fix the feature","committer":{"date":"2020-03-05T08:05:00Z"}}}]}`)
+
+	result, err := MetricsService.GetDORAMetrics("myuser", "myrepo", "2020-03-01", "2020-03-31", "", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, result.TotalDeployments)
+	assert.EqualValues(t, 2, result.Deployments[0].Commits)
+}
+
+func TestGetDORAMetricsFromReleases(t *testing.T) {
+	ResetMetricsService()
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/releases?per_page=100", http.StatusOK, `[
+		{"id":4,"tag_name":"v1.2.1","created_at":"2020-03-12T10:00:00Z","published_at":"2020-03-12T10:00:00Z"},
+		{"id":3,"tag_name":"v1.2.0-rc1","prerelease":true,"created_at":"2020-03-09T10:00:00Z","published_at":"2020-03-09T10:00:00Z"},
+		{"id":2,"tag_name":"v1.2.0","created_at":"2020-03-10T10:00:00Z","published_at":"2020-03-10T10:00:00Z"},
+		{"id":1,"tag_name":"v1.1.0","created_at":"2020-02-10T10:00:00Z","published_at":"2020-02-10T10:00:00Z"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/v1.1.0...v1.2.0?per_page=100", http.StatusOK,
+		`{"status":"ahead","commits":[{"sha":"A1","commit":{"message":"Add a feature","committer":{"date":"2020-03-09T10:00:00Z"}}}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/v1.2.0...v1.2.1?per_page=100", http.StatusOK,
+		`{"status":"ahead","commits":[{"sha":"A2","commit":{"message":"Merge pull request #3 from myuser/hotfix/feature","committer":{"date":"2020-03-12T08:00:00Z"}}}]}`)
+
+	result, err := MetricsService.GetDORAMetrics("myuser", "myrepo", "2020-03-01", "2020-03-31", "releases", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "releases", result.Source)
+	assert.EqualValues(t, "", result.Environment)
+	assert.EqualValues(t, 2, result.TotalDeployments)
+	assert.EqualValues(t, "v1.2.0", result.Deployments[0].ID)
+	assert.True(t, result.Deployments[0].ChangeFailure)
+	assert.EqualValues(t, []string{"A2"}, result.Deployments[1].HotfixCommits)
+	assert.EqualValues(t, 0.5, result.ChangeFailureRate)
+	assert.EqualValues(t, 48, result.TimeToRestore.P50)
+	assert.EqualValues(t, 2, result.LeadTimeForChanges.P50)
+	assert.EqualValues(t, 24, result.LeadTimeForChanges.P90)
+}
+
+func TestGetDeploymentOutcome(t *testing.T) {
+	outcome, _ := getDeploymentOutcome(nil)
+	assert.EqualValues(t, "", outcome)
+
+	deployedAt := time.Date(2020, 3, 5, 10, 0, 0, 0, time.UTC)
+	outcome, at := getDeploymentOutcome([]githubdomain.DeploymentStatus{{State: githubdomain.DeploymentStateError, CreatedAt: deployedAt}})
+	assert.EqualValues(t, metricsdomain.DeploymentFailed, outcome)
+	assert.True(t, deployedAt.Equal(at))
+}
+
+//addContributorMocks mocks the repo along with its commits, PRs and reviews in the first two weeks of March 2020
+func addContributorMocks() {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=2020-03-01T00:00:00Z&until=2020-03-15T00:00:00Z&per_page=100", http.StatusOK, `[
+		{"sha":"C1","commit":{"author":{"name":"The Octocat","email":"octo@example.com"}},"author":{"login":"octocat","type":"User"}},
+		{"sha":"C2","commit":{"author":{"name":"Octo","email":"Octo@example.com"}},"author":null},
+		{"sha":"C3","commit":{"author":{"name":"Jane","email":"jane@example.com"}},"author":null},
+		{"sha":"C4","commit":{"author":{"name":"dependabot[bot]","email":"1+dependabot[bot]@users.noreply.github.com"}},"author":{"login":"dependabot[bot]","type":"Bot"}}
+	]`)
+	addComplianceMock(testMetricsPullsURL, http.StatusOK, `[
+		{"number":3,"state":"open","user":{"login":"dependabot[bot]","type":"Bot"},"created_at":"2020-03-12T09:00:00Z","updated_at":"2020-03-13T09:00:00Z"},
+		{"number":2,"state":"closed","user":{"login":"octocat","type":"User"},"created_at":"2020-03-03T09:00:00Z","updated_at":"2020-03-04T10:00:00Z","merged_at":"2020-03-04T10:00:00Z"},
+		{"number":1,"state":"closed","user":{"login":"reviewer1","type":"User"},"created_at":"2020-02-20T09:00:00Z","updated_at":"2020-03-02T10:00:00Z","merged_at":"2020-03-02T10:00:00Z"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/1/reviews?per_page=100", http.StatusOK, `[
+		{"id":1,"user":{"login":"octocat","type":"User"},"state":"APPROVED","submitted_at":"2020-02-21T09:00:00Z"},
+		{"id":2,"user":{"login":"octocat","type":"User"},"state":"COMMENTED","submitted_at":"2020-03-01T09:00:00Z"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/2/reviews?per_page=100", http.StatusOK, `[
+		{"id":3,"user":{"login":"octocat","type":"User"},"state":"COMMENTED","submitted_at":"2020-03-03T10:00:00Z"},
+		{"id":4,"user":{"login":"reviewer1","type":"User"},"state":"APPROVED","submitted_at":"2020-03-04T09:00:00Z"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/3/reviews?per_page=100", http.StatusOK,
+		`[{"id":5,"user":{"login":"ci-bot[bot]","type":"Bot"},"state":"APPROVED","submitted_at":"2020-03-12T10:00:00Z"}]`)
+}
+
+func TestGetContributorActivityInvalidInputs(t *testing.T) {
+	ResetMetricsService()
+	result, err := MetricsService.GetContributorActivity("myuser", "myrepo", "", "", "maybe")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, errorInvalidBotsParam, err.Message())
+
+	result, err = MetricsService.GetContributorActivity("myuser", "myrepo", "", "March", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, errorInvalidToDate, err.Message())
+}
+
+func TestGetContributorActivityErrorGettingRepo(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusNotFound, `{"message":"Not Found"}`)
+
+	result, err := MetricsService.GetContributorActivity("myuser", "myrepo", "2020-03-01", "2020-03-14", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetContributorActivityErrorGettingReviews(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addContributorMocks()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/2/reviews?per_page=100", http.StatusForbidden, `{"message":"Forbidden"}`)
+
+	result, err := MetricsService.GetContributorActivity("myuser", "myrepo", "2020-03-01", "2020-03-14", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+}
+
+func TestGetContributorActivity(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addContributorMocks()
+
+	result, err := MetricsService.GetContributorActivity("myuser", "myrepo", "2020-03-01", "2020-03-14", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "main", result.Branch)
+	assert.False(t, result.IncludeBots)
+	assert.EqualValues(t, 3, len(result.Contributors))
+
+	octocat := result.Contributors[0]
+	assert.EqualValues(t, "octocat", octocat.ID)
+	assert.EqualValues(t, 2, octocat.Commits)
+	assert.EqualValues(t, 1, octocat.PullRequestsOpened)
+	assert.EqualValues(t, 1, octocat.PullRequestsMerged)
+	//the review of their own PR isn't counted
+	assert.EqualValues(t, 1, octocat.ReviewsGiven)
+	assert.EqualValues(t, 0, octocat.ApprovalsGiven)
+
+	reviewer := result.Contributors[1]
+	assert.EqualValues(t, "reviewer1", reviewer.ID)
+	assert.EqualValues(t, 0, reviewer.PullRequestsOpened)
+	assert.EqualValues(t, 1, reviewer.PullRequestsMerged)
+	assert.EqualValues(t, 1, reviewer.ApprovalsGiven)
+
+	assert.EqualValues(t, "jane@example.com", result.Contributors[2].ID)
+	assert.EqualValues(t, 1, result.Contributors[2].Commits)
+}
+
+func TestGetContributorActivityFollowsCommitPages(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addContributorMocks()
+	secondPage := "https://api.github.com/repositories/1/commits?sha=main&per_page=100&page=2"
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=2020-03-01T00:00:00Z&until=2020-03-15T00:00:00Z&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Link": []string{"<" + secondPage + `>; rel="next"`}},
+			Body: ioutil.NopCloser(strings.NewReader(`[
+				{"sha":"C1","commit":{"author":{"name":"The Octocat","email":"octo@example.com"}},"author":{"login":"octocat","type":"User"}},
+				{"sha":"C3","commit":{"author":{"name":"Jane","email":"jane@example.com"}},"author":null}
+			]`)),
+		},
+	})
+	addComplianceMock(secondPage, http.StatusOK, `[
+		{"sha":"C5","commit":{"author":{"name":"The Octocat","email":"octo@example.com"}},"author":{"login":"octocat","type":"User"}},
+		{"sha":"C6","commit":{"author":{"name":"Jane","email":"jane@example.com"}},"author":null}
+	]`)
+
+	result, err := MetricsService.GetContributorActivity("myuser", "myrepo", "2020-03-01", "2020-03-14", "")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(result.Contributors))
+	commits := make(map[string]int)
+	for _, contributor := range result.Contributors {
+		commits[contributor.ID] = contributor.Commits
+	}
+	//the commits on the second page are counted too
+	assert.EqualValues(t, 2, commits["octocat"])
+	assert.EqualValues(t, 2, commits["jane@example.com"])
+}
+
+func TestGetContributorActivityIncludingBots(t *testing.T) {
+	ResetService()
+	ResetMetricsService()
+	addContributorMocks()
+
+	result, err := MetricsService.GetContributorActivity("myuser", "myrepo", "2020-03-01", "2020-03-14", "true")
+	assert.Nil(t, err)
+	assert.True(t, result.IncludeBots)
+	assert.EqualValues(t, 5, len(result.Contributors))
+	assert.EqualValues(t, "dependabot[bot]", result.Contributors[1].ID)
+	assert.EqualValues(t, 1, result.Contributors[1].Commits)
+	assert.EqualValues(t, 1, result.Contributors[1].PullRequestsOpened)
+}
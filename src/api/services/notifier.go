@@ -0,0 +1,13 @@
+package services
+
+import (
+	"github.com/greendinosaur/gh-commit-info/src/api/notifications"
+)
+
+//ReportNotifier is told about the outcome of the scheduled reports, nothing is sent until a notifier is set
+var ReportNotifier = notifications.NewDisabledNotifier()
+
+//SetReportNotifier sets the notifier told about the outcome of the scheduled reports
+func SetReportNotifier(notifier notifications.Notifier) {
+	ReportNotifier = notifier
+}
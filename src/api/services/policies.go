@@ -0,0 +1,13 @@
+package services
+
+import (
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+)
+
+//ReviewPolicies are the review rules open PRs are evaluated against, the defaults are used until policies are set
+var ReviewPolicies = policydomain.NewDefaultPolicies()
+
+//SetReviewPolicies sets the review rules open PRs are evaluated against
+func SetReviewPolicies(policies *policydomain.Policies) {
+	ReviewPolicies = policies
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+type protectionService struct{}
+
+type protectionServiceInterface interface {
+	GetBranchProtectionReport(owner string, repo string) (*reportdomain.BranchProtectionReport, errors.APIError)
+	GetOrgBranchProtectionReport(owner string) (*reportdomain.OrgBranchProtectionReport, errors.APIError)
+}
+
+//ProtectionService defines the branch protection service to use
+var ProtectionService protectionServiceInterface
+
+func init() {
+	ProtectionService = &protectionService{}
+}
+
+//ResetProtectionService calls the init function again
+func ResetProtectionService() {
+	ProtectionService = &protectionService{}
+}
+
+//GetBranchProtectionReport compares the protection of the default branch of the repo against the baseline of its policy
+func (s *protectionService) GetBranchProtectionReport(owner string, repo string) (*reportdomain.BranchProtectionReport, errors.APIError) {
+	repoInfo, err := RepositoryService.GetRepo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return getBranchProtectionReport(strings.TrimSpace(owner), repoInfo)
+}
+
+//GetOrgBranchProtectionReport compares the protection of the default branch of every repo in the organisation against the baseline of its policy
+//archived repos can't be changed so are left out
+func (s *protectionService) GetOrgBranchProtectionReport(owner string) (*reportdomain.OrgBranchProtectionReport, errors.APIError) {
+	owner = strings.TrimSpace(owner)
+	if len(owner) == 0 {
+		return nil, errors.NewBadRequestError(errorInvalidOwnerParam)
+	}
+
+	orgRepos, errProvider := githubprovider.GetOrgRepos(config.GetGithubAccessToken(), owner)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	report := reportdomain.NewOrgBranchProtectionReport(owner)
+	for counter := range orgRepos {
+		if orgRepos[counter].Archived {
+			continue
+		}
+		//a repo whose protection can't be read doesn't stop the others being checked
+		repoReport, err := getBranchProtectionReport(owner, &orgRepos[counter])
+		if err != nil {
+			repoReport = reportdomain.NewUnknownBranchProtectionReport(owner, orgRepos[counter].Name, orgRepos[counter].DefaultBranch, err.Message())
+		}
+		report.AddRepo(repoReport)
+	}
+	return report, nil
+}
+
+//getBranchProtectionReport compares the protection of the default branch of the repo against the baseline of its policy
+//Github says a branch that isn't protected can't be found, any other not found, such as for a token without admin access, is an error
+func getBranchProtectionReport(owner string, repoInfo *githubdomain.GetRepoInfo) (*reportdomain.BranchProtectionReport, errors.APIError) {
+	protection, errProvider := githubprovider.GetBranchProtection(config.GetGithubAccessToken(), owner, repoInfo.Name, repoInfo.DefaultBranch)
+	if errProvider != nil && !errProvider.IsBranchNotProtected() {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	policy := ReviewPolicies.ForRepo(owner, repoInfo.Name)
+	return reportdomain.NewBranchProtectionReport(owner, repoInfo.Name, repoInfo.DefaultBranch, protection, policy.GetBranchProtectionBaseline()), nil
+}
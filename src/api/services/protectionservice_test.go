@@ -0,0 +1,131 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBranchProtectionReport(t *testing.T) {
+	ResetService()
+	ResetProtectionService()
+	restclient.FlushMockups()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 2, EnforceAdmins: true, RequiredStatusChecks: []string{"ci/build"}})()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"develop"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/branches/develop/protection", http.StatusOK,
+		`{"required_status_checks":{"contexts":["ci/build"]},"enforce_admins":{"enabled":false},"required_pull_request_reviews":{"required_approving_review_count":2}}`)
+
+	result, err := ProtectionService.GetBranchProtectionReport("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "develop", result.Branch)
+	assert.True(t, result.Protected)
+	assert.False(t, result.Compliant)
+	assert.EqualValues(t, 2, result.Baseline.RequiredApprovals)
+	assert.EqualValues(t, []string{"admins can bypass the protection"}, result.Drift)
+}
+
+func TestGetBranchProtectionReportNotProtected(t *testing.T) {
+	ResetService()
+	ResetProtectionService()
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/branches/main/protection", http.StatusNotFound, `{"message":"Branch not protected"}`)
+
+	result, err := ProtectionService.GetBranchProtectionReport("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.False(t, result.Protected)
+	assert.EqualValues(t, []string{"the branch main isn't protected", "0 required approvals, the baseline is 1", "stale approvals aren't dismissed"}, result.Drift)
+}
+
+func TestGetBranchProtectionReportErrors(t *testing.T) {
+	ResetService()
+	ResetProtectionService()
+	result, err := ProtectionService.GetBranchProtectionReport("myuser", " ")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusNotFound, `{"message":"Not Found"}`)
+	result, err = ProtectionService.GetBranchProtectionReport("myuser", "myrepo")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/branches/main/protection", http.StatusForbidden, `{"message":"Resource not accessible by integration"}`)
+	result, err = ProtectionService.GetBranchProtectionReport("myuser", "myrepo")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+	assert.EqualValues(t, "Resource not accessible by integration", err.Message())
+
+	//a token without admin access can't find the protection, which doesn't mean the branch isn't protected
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/branches/main/protection", http.StatusNotFound, `{"message":"Not Found"}`)
+	result, err = ProtectionService.GetBranchProtectionReport("myuser", "myrepo")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+	assert.EqualValues(t, "Not Found", err.Message())
+}
+
+func TestGetOrgBranchProtectionReport(t *testing.T) {
+	ResetProtectionService()
+	restclient.FlushMockups()
+	SetReviewPolicies(&policydomain.Policies{
+		Default: policydomain.ReviewPolicy{RequiredApprovals: 1},
+		Repos:   []policydomain.RepoPolicy{{Repo: "myorg/payments", ReviewPolicy: policydomain.ReviewPolicy{RequiredApprovals: 2}}},
+	})
+	defer SetReviewPolicies(policydomain.NewDefaultPolicies())
+	addComplianceMock("https://api.github.com/orgs/myorg/repos?per_page=100", http.StatusOK, `[
+		{"name":"web","default_branch":"main"},
+		{"name":"payments","default_branch":"main"},
+		{"name":"legacy","default_branch":"master","archived":true},
+		{"name":"docs","default_branch":"gh-pages"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myorg/web/branches/main/protection", http.StatusOK, `{"required_pull_request_reviews":{"required_approving_review_count":1}}`)
+	addComplianceMock("https://api.github.com/repos/myorg/payments/branches/main/protection", http.StatusOK, `{"required_pull_request_reviews":{"required_approving_review_count":1}}`)
+	addComplianceMock("https://api.github.com/repos/myorg/docs/branches/gh-pages/protection", http.StatusNotFound, `{"message":"Branch not protected"}`)
+
+	result, err := ProtectionService.GetOrgBranchProtectionReport("myorg")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, result.TotalRepos)
+	assert.EqualValues(t, 1, result.TotalCompliant)
+	assert.EqualValues(t, 2, result.TotalWithDrift)
+	assert.EqualValues(t, 1, result.TotalUnprotected)
+	assert.EqualValues(t, 2, result.DriftBySetting[reportdomain.ProtectionSettingApprovals])
+	assert.EqualValues(t, []string{"1 required approvals, the baseline is 2"}, result.Repos[1].Drift)
+}
+
+func TestGetOrgBranchProtectionReportErrors(t *testing.T) {
+	ResetProtectionService()
+	result, err := ProtectionService.GetOrgBranchProtectionReport("")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/orgs/myorg/repos?per_page=100", http.StatusNotFound, `{"message":"Not Found"}`)
+	result, err = ProtectionService.GetOrgBranchProtectionReport("myorg")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+
+	//the repos whose protection can't be read are reported as unknown and the others are still checked
+	addComplianceMock("https://api.github.com/orgs/myorg/repos?per_page=100", http.StatusOK, `[
+		{"name":"web","default_branch":"main"},
+		{"name":"api","default_branch":"main"},
+		{"name":"docs","default_branch":"gh-pages"}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myorg/web/branches/main/protection", http.StatusForbidden, `{"message":"Forbidden"}`)
+	addComplianceMock("https://api.github.com/repos/myorg/api/branches/main/protection", http.StatusNotFound, `{"message":"Not Found"}`)
+	addComplianceMock("https://api.github.com/repos/myorg/docs/branches/gh-pages/protection", http.StatusNotFound, `{"message":"Branch not protected"}`)
+	result, err = ProtectionService.GetOrgBranchProtectionReport("myorg")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, result.TotalRepos)
+	assert.EqualValues(t, 2, result.TotalUnknown)
+	assert.EqualValues(t, 1, result.TotalUnprotected)
+	assert.EqualValues(t, 1, result.TotalWithDrift)
+	assert.EqualValues(t, "Forbidden", result.Repos[0].Error)
+	assert.EqualValues(t, "Not Found", result.Repos[1].Error)
+	assert.False(t, result.Repos[2].IsUnknown())
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/messagedomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+type publishService struct{}
+
+type publishServiceInterface interface {
+	PublishCommitCompliance(owner string, repo string, SHA string, target string) (*reportdomain.CommitCompliance, errors.APIError)
+}
+
+const (
+	errorInvalidPublishTarget = "invalid target parameter, expected status or check_run"
+)
+
+//PublishService defines the publish service to use
+var PublishService publishServiceInterface
+
+func init() {
+	PublishService = &publishService{}
+}
+
+//ResetPublishService calls the init function again
+func ResetPublishService() {
+	PublishService = &publishService{}
+}
+
+//PublishCommitCompliance works out the code review verdict of the commit against the default branch, or the base branch of the open PR it is the head of, and publishes it
+//to Github as a commit status or a check run named code-review-compliance, the status is used if no target is given
+//check runs can only be created when the access token belongs to a Github App
+func (s *publishService) PublishCommitCompliance(owner string, repo string, SHA string, target string) (*reportdomain.CommitCompliance, errors.APIError) {
+	var err errors.APIError
+	owner, repo, SHA, err = validateSingleCommitPRInputs(owner, repo, SHA)
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		target = reportdomain.PublishTargetStatus
+	}
+	if target != reportdomain.PublishTargetStatus && target != reportdomain.PublishTargetCheckRun {
+		return nil, errors.NewBadRequestError(errorInvalidPublishTarget)
+	}
+
+	compliance, err := getCommitCompliance(owner, repo, SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	if target == reportdomain.PublishTargetCheckRun {
+		err = publishCheckRun(compliance)
+	} else {
+		err = publishCommitStatus(compliance)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return compliance, nil
+}
+
+//getCommitCompliance works out the verdict of the commit against the default branch of the repo
+//a commit at the head of an open PR hasn't been merged yet so is judged on whether the PR could be merged into its base branch now
+//the message of the commit is checked against the rules of the repo, unless it is a merge commit
+func getCommitCompliance(owner string, repo string, SHA string) (*reportdomain.CommitCompliance, errors.APIError) {
+	repoInfo, err := RepositoryService.GetRepo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	commitInfo, err := RepositoryService.GetRepoSingleCommit(owner, repo, SHA)
+	if err != nil {
+		return nil, err
+	}
+	pullsForCommit, err := RepositoryService.GetSingleCommitPR(owner, repo, commitInfo.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	var compliance *reportdomain.CommitCompliance
+	resolver := newIdentityResolver([]githubdomain.GetCommitInfo{*commitInfo})
+	if headPull := getOpenPullRequestForHead(pullsForCommit, commitInfo.SHA); headPull != nil {
+		pullCompliance, err := ComplianceService.GetPRCompliance(owner, repo, strconv.FormatInt(headPull.Number, 10))
+		if err != nil {
+			return nil, err
+		}
+		commitReview, err := getCommitReviewFromPulls(owner, repo, headPull.Base.Ref, commitInfo, nil, make(map[string]bool), resolver)
+		if err != nil {
+			return nil, err
+		}
+		setCommitReviewPR(commitReview, reportdomain.ReviewStatusNotReviewed, headPull)
+		compliance = reportdomain.NewPullRequestHeadCompliance(owner, repo, *commitReview, pullCompliance)
+	} else {
+		commitReview, err := getCommitReviewFromPulls(owner, repo, repoInfo.DefaultBranch, commitInfo, pullsForCommit, make(map[string]bool), resolver)
+		if err != nil {
+			return nil, err
+		}
+		compliance = reportdomain.NewCommitCompliance(owner, repo, repoInfo.DefaultBranch, *commitReview)
+	}
+
+	if rules := ReviewPolicies.ForRepo(owner, repo).CommitMessages; !rules.IsEmpty() && !isMergeCommit(commitInfo) {
+		message := messagedomain.ParseCommitMessage(commitInfo.Commit.Message)
+		compliance.SetMessageViolations(message.Check(&rules, commitInfo.Commit.Author.Email))
+	}
+	return compliance, nil
+}
+
+//getOpenPullRequestForHead returns the open PR the commit is the head of, if any
+func getOpenPullRequestForHead(pullsForCommit []githubdomain.GetSinglePullRequestResponse, SHA string) *githubdomain.GetSinglePullRequestResponse {
+	for counter := range pullsForCommit {
+		pull := &pullsForCommit[counter]
+		if pull.State == "open" && strings.EqualFold(pull.Head.SHA, SHA) {
+			return pull
+		}
+	}
+	return nil
+}
+
+//publishCommitStatus sets the verdict as the status of the commit
+func publishCommitStatus(compliance *reportdomain.CommitCompliance) errors.APIError {
+	request := githubdomain.CreateCommitStatusRequest{
+		State:       githubdomain.CommitStatusFailure,
+		Description: compliance.Description(),
+		Context:     reportdomain.ComplianceContext,
+	}
+	if compliance.Passed() {
+		request.State = githubdomain.CommitStatusSuccess
+	}
+
+	status, errProvider := githubprovider.CreateCommitStatus(config.GetGithubAccessToken(), compliance.Owner, compliance.Repo, compliance.Commit.SHA, request)
+	if errProvider != nil {
+		return errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+	compliance.PublishedTo = reportdomain.PublishTargetStatus
+	compliance.URL = status.URL
+	return nil
+}
+
+//publishCheckRun creates a completed check run holding the verdict
+func publishCheckRun(compliance *reportdomain.CommitCompliance) errors.APIError {
+	completedAt := time.Now().UTC()
+	request := githubdomain.CreateCheckRunRequest{
+		Name:        reportdomain.ComplianceContext,
+		HeadSHA:     compliance.Commit.SHA,
+		Status:      githubdomain.CheckRunStatusCompleted,
+		Conclusion:  githubdomain.CheckRunConclusionFailure,
+		CompletedAt: &completedAt,
+		Output:      &githubdomain.CheckRunOutput{Title: compliance.Title(), Summary: compliance.Summary()},
+	}
+	if compliance.Passed() {
+		request.Conclusion = githubdomain.CheckRunConclusionSuccess
+	}
+
+	checkRun, errProvider := githubprovider.CreateCheckRun(config.GetGithubAccessToken(), compliance.Owner, compliance.Repo, request)
+	if errProvider != nil {
+		return errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+	compliance.PublishedTo = reportdomain.PublishTargetCheckRun
+	compliance.URL = checkRun.HTMLURL
+	return nil
+}
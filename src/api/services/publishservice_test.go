@@ -0,0 +1,240 @@
+package services
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+//addPublishCommitMocks mocks the repo, the commit and the PRs of the commit AABCDEF123456
+func addPublishCommitMocks(pulls func() *http.Response) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleCommitResponseMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response:   pulls(),
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/main...AABCDEF123456",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"identical","ahead_by":0,"behind_by":0,"total_commits":0,"commits":[]}`)),
+		},
+	})
+}
+
+func getApprovedPullsResponse() *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Body: testutils.GetMockDataApprovedPRForCommitResponsesMessage()}
+}
+
+func getNoPullsResponse() *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Body: testutils.GetMockDataNoPRForCommitResponsesMessage()}
+}
+
+func TestPublishCommitComplianceInvalidInputs(t *testing.T) {
+	ResetPublishService()
+
+	result, err := PublishService.PublishCommitCompliance("myuser", "myrepo", " ", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, errorInvalidSHAParam, err.Message())
+
+	result, err = PublishService.PublishCommitCompliance("myuser", "myrepo", "AABCDEF123456", "comment")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, errorInvalidPublishTarget, err.Message())
+}
+
+func TestPublishCommitComplianceErrorGettingCommit(t *testing.T) {
+	ResetService()
+	ResetPublishService()
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	result, err := PublishService.PublishCommitCompliance("myuser", "myrepo", "AABCDEF123456", "")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+}
+
+func TestPublishCommitComplianceStatusPass(t *testing.T) {
+	ResetService()
+	ResetPublishService()
+	addPublishCommitMocks(getApprovedPullsResponse)
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/statuses/AABCDEF123456",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":1,"url":"https://api.github.com/repos/myuser/myrepo/statuses/AABCDEF123456","state":"success","context":"code-review-compliance"}`)),
+		},
+	})
+
+	result, err := PublishService.PublishCommitCompliance("myuser", "myrepo", "AABCDEF123456", "")
+	assert.Nil(t, err)
+	assert.True(t, result.Passed())
+	assert.EqualValues(t, "main", result.Branch)
+	assert.EqualValues(t, 9, result.Commit.PullNumber)
+	assert.EqualValues(t, reportdomain.PublishTargetStatus, result.PublishedTo)
+	assert.EqualValues(t, "https://api.github.com/repos/myuser/myrepo/statuses/AABCDEF123456", result.URL)
+}
+
+func TestPublishCommitComplianceCheckRunFail(t *testing.T) {
+	ResetService()
+	ResetPublishService()
+	addPublishCommitMocks(getNoPullsResponse)
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/check-runs",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":4,"head_sha":"AABCDEF123456","name":"code-review-compliance","status":"completed","conclusion":"failure","html_url":"https://github.com/myuser/myrepo/runs/4"}`)),
+		},
+	})
+
+	result, err := PublishService.PublishCommitCompliance("myuser", "myrepo", "AABCDEF123456", reportdomain.PublishTargetCheckRun)
+	assert.Nil(t, err)
+	assert.False(t, result.Passed())
+	assert.EqualValues(t, reportdomain.ReviewStatusNotReviewed, result.Commit.ReviewStatus)
+	assert.EqualValues(t, reportdomain.PublishTargetCheckRun, result.PublishedTo)
+	assert.EqualValues(t, "https://github.com/myuser/myrepo/runs/4", result.URL)
+}
+
+func TestPublishCommitComplianceMessageRules(t *testing.T) {
+	ResetService()
+	ResetPublishService()
+	defer setupPolicy(policydomain.ReviewPolicy{CommitMessages: policydomain.CommitMessageRules{Format: policydomain.MessageFormatConventional, RequireSignOff: true}})()
+	addPublishCommitMocks(getApprovedPullsResponse)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456", http.StatusOK,
+		`{"sha":"AABCDEF123456","commit":{"message":"Tidy up","author":{"name":"some name","email":"some@email.com"}},"parents":[{"sha":"PARENT"}],"stats":{"total":1}}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/main...AABCDEF123456", http.StatusOK, `{"status":"behind","behind_by":1}`)
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/statuses/AABCDEF123456",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":1,"state":"failure","context":"code-review-compliance"}`)),
+		},
+	})
+
+	result, err := PublishService.PublishCommitCompliance("myuser", "myrepo", "AABCDEF123456", "")
+	assert.Nil(t, err)
+	assert.False(t, result.Passed())
+	assert.EqualValues(t, reportdomain.ReviewStatusReviewed, result.Commit.ReviewStatus)
+	assert.EqualValues(t, []string{
+		"the message doesn't follow conventional commits, such as feat(api): add an endpoint",
+		"the message isn't signed off by the author some@email.com",
+	}, result.MessageViolations)
+}
+
+//addPublishHeadCommitMocks mocks the commit HEAD2 at the head of the open PR 9 into release along with the reviews of the PR
+func addPublishHeadCommitMocks(reviews string) {
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD2", http.StatusOK,
+		`{"sha":"HEAD2","commit":{"message":"Add a feature","author":{"name":"some name","email":"some@email.com"}},"parents":[{"sha":"HEAD1"}],"stats":{"total":1}}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD2/pulls?per_page=100", http.StatusOK,
+		`[{"number":9,"state":"open","title":"Add a feature","user":{"login":"author"},"base":{"ref":"release"},"head":{"ref":"feature","sha":"HEAD2"}}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusOK,
+		`{"number":9,"state":"open","title":"Add a feature","user":{"login":"author"},"base":{"ref":"release","sha":"BASE"},"head":{"ref":"feature","sha":"HEAD2"}}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100", http.StatusOK, reviews)
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/statuses/HEAD2",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":1,"url":"https://api.github.com/repos/myuser/myrepo/statuses/HEAD2","context":"code-review-compliance"}`)),
+		},
+	})
+}
+
+func TestPublishCommitComplianceHeadOfOpenPR(t *testing.T) {
+	ResetService()
+	ResetPublishService()
+	ResetComplianceService()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 1})()
+
+	//the commit isn't on the default branch yet so is judged by the PR into its base branch
+	addPublishHeadCommitMocks(`[{"id":1,"user":{"login":"reviewer1"},"state":"APPROVED","commit_id":"HEAD2"}]`)
+	result, err := PublishService.PublishCommitCompliance("myuser", "myrepo", "HEAD2", "")
+	assert.Nil(t, err)
+	assert.True(t, result.Passed())
+	assert.EqualValues(t, "release", result.Branch)
+	assert.EqualValues(t, 9, result.Commit.PullNumber)
+	assert.EqualValues(t, []string{"reviewer1"}, result.PullRequest.Approvers)
+	assert.EqualValues(t, "PR #9 into release meets the review policy", result.Description())
+
+	addPublishHeadCommitMocks(`[]`)
+	result, err = PublishService.PublishCommitCompliance("myuser", "myrepo", "HEAD2", "")
+	assert.Nil(t, err)
+	assert.False(t, result.Passed())
+	assert.EqualValues(t, "PR #9 into release is missing: 0 of 1 required approvals", result.Description())
+}
+
+func TestPublishCommitComplianceErrorPublishingCheckRun(t *testing.T) {
+	ResetService()
+	ResetPublishService()
+	addPublishCommitMocks(getNoPullsResponse)
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/check-runs",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusForbidden,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "You must authenticate via a GitHub App."}`)),
+		},
+	})
+
+	result, err := PublishService.PublishCommitCompliance("myuser", "myrepo", "AABCDEF123456", reportdomain.PublishTargetCheckRun)
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+	assert.EqualValues(t, "You must authenticate via a GitHub App.", err.Message())
+
+}
+
+func TestPublishCommitComplianceErrorPublishingStatus(t *testing.T) {
+	ResetService()
+	ResetPublishService()
+	addPublishCommitMocks(getNoPullsResponse)
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/statuses/AABCDEF123456",
+		HTTPMethod: http.MethodPost,
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		},
+	})
+
+	result, err := PublishService.PublishCommitCompliance("myuser", "myrepo", "AABCDEF123456", reportdomain.PublishTargetStatus)
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
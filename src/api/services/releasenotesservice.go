@@ -0,0 +1,95 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+type releaseNotesService struct{}
+
+type releaseNotesServiceInterface interface {
+	GetReleaseNotes(owner string, repo string, from string, to string) (*reportdomain.ReleaseNotes, errors.APIError)
+}
+
+const (
+	errorInvalidFromRef = "invalid from parameter, expected a tag, branch or commit SHA such as v1.2.0"
+	errorInvalidToRef   = "invalid to parameter, expected a tag, branch or commit SHA such as v1.3.0"
+)
+
+//ReleaseNotesService defines the release notes service to use
+var ReleaseNotesService releaseNotesServiceInterface
+
+func init() {
+	ReleaseNotesService = &releaseNotesService{}
+}
+
+//ResetReleaseNotesService calls the init function again
+func ResetReleaseNotesService() {
+	ReleaseNotesService = &releaseNotesService{}
+}
+
+//GetReleaseNotes lists the PRs merged between the from and to refs, grouped by their labels or conventional commit type
+//each commit between the refs is mapped to the PRs it belongs to, only PRs whose merge commit is between the refs are included
+func (s *releaseNotesService) GetReleaseNotes(owner string, repo string, from string, to string) (*reportdomain.ReleaseNotes, errors.APIError) {
+	var err errors.APIError
+	owner, repo, err = validateAllCommitsInputs(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	if from = strings.TrimSpace(from); from == "" {
+		return nil, errors.NewBadRequestError(errorInvalidFromRef)
+	}
+	if to = strings.TrimSpace(to); to == "" {
+		return nil, errors.NewBadRequestError(errorInvalidToRef)
+	}
+
+	compare, errProvider := githubprovider.GetCompareAllCommits(config.GetGithubAccessToken(), owner, repo, from, to)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	inRange := make(map[string]bool)
+	for _, commit := range compare.Commits {
+		inRange[commit.SHA] = true
+	}
+
+	result := &reportdomain.ReleaseNotes{
+		Owner:            owner,
+		Repo:             repo,
+		From:             from,
+		To:               to,
+		TotalCommits:     len(compare.Commits),
+		CommitsWithoutPR: make([]reportdomain.ReleaseNoteCommit, 0),
+	}
+	seen := make(map[int64]bool)
+	notes := make([]reportdomain.ReleaseNote, 0)
+	for counter := range compare.Commits {
+		commit := &compare.Commits[counter]
+		pullRequests, err := RepositoryService.GetSingleCommitPR(owner, repo, commit.SHA)
+		if err != nil {
+			return nil, err
+		}
+
+		merged := false
+		for index := range pullRequests {
+			pullRequest := &pullRequests[index]
+			if pullRequest.MergedAt.IsZero() {
+				continue
+			}
+			merged = true
+			if inRange[pullRequest.MergeCommitSHA] && !seen[pullRequest.Number] {
+				seen[pullRequest.Number] = true
+				notes = append(notes, reportdomain.NewReleaseNote(pullRequest))
+			}
+		}
+		if !merged {
+			result.CommitsWithoutPR = append(result.CommitsWithoutPR, reportdomain.NewReleaseNoteCommit(commit))
+		}
+	}
+	result.SetSections(notes)
+	return result, nil
+}
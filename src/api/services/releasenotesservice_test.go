@@ -0,0 +1,101 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+const testReleaseCompareURL = "https://api.github.com/repos/myuser/myrepo/compare/v1.2.0...v1.3.0?per_page=100"
+
+//addReleaseNotesMocks mocks the commits between v1.2.0 and v1.3.0 of myuser/myrepo and the PRs of each commit
+//SHA1 is on a feature branch merged by SHA2, SHA3 was squash merged, SHA4 has no PR and SHA5 belongs to a PR merged elsewhere
+func addReleaseNotesMocks() {
+	restclient.FlushMockups()
+	addComplianceMock(testReleaseCompareURL, http.StatusOK,
+		`{"status":"ahead","ahead_by":5,"total_commits":5,"commits":[{"sha":"SHA1"},{"sha":"SHA2"},{"sha":"SHA3"},{"sha":"SHA4","commit":{"message":"hotfix\n\nstraight to main","author":{"name":"some name"}}},{"sha":"SHA5"}]}`)
+	mergedPR := `[{"number":10,"state":"closed","title":"feat(api): add release notes","user":{"login":"alice"},"labels":[],"merged_at":"2020-01-02T00:00:00Z","merge_commit_sha":"SHA2"}]`
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA1/pulls?per_page=100", http.StatusOK, mergedPR)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA2/pulls?per_page=100", http.StatusOK, mergedPR)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA3/pulls?per_page=100", http.StatusOK,
+		`[{"number":11,"state":"closed","title":"Handle empty repos","user":{"login":"bob"},"labels":[{"name":"bug"}],"merged_at":"2020-01-03T00:00:00Z","merge_commit_sha":"SHA3"},
+		  {"number":12,"state":"closed","title":"Abandoned attempt","user":{"login":"bob"},"merge_commit_sha":"OTHER"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA4/pulls?per_page=100", http.StatusOK, `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA5/pulls?per_page=100", http.StatusOK,
+		`[{"number":13,"state":"closed","title":"fix: backport","user":{"login":"carol"},"merged_at":"2020-01-04T00:00:00Z","merge_commit_sha":"ELSEWHERE"}]`)
+}
+
+func TestGetReleaseNotesInvalidInputs(t *testing.T) {
+	tests := []struct {
+		owner, repo, from, to string
+		expected              string
+	}{
+		{"", "myrepo", "v1.2.0", "v1.3.0", errorInvalidOwnerParam},
+		{"myuser", "", "v1.2.0", "v1.3.0", errorInvalidRepoParam},
+		{"myuser", "myrepo", " ", "v1.3.0", errorInvalidFromRef},
+		{"myuser", "myrepo", "v1.2.0", "", errorInvalidToRef},
+	}
+	for _, test := range tests {
+		result, err := ReleaseNotesService.GetReleaseNotes(test.owner, test.repo, test.from, test.to)
+		assert.Nil(t, result)
+		assert.EqualValues(t, http.StatusBadRequest, err.Status())
+		assert.EqualValues(t, test.expected, err.Message())
+	}
+}
+
+func TestGetReleaseNotesErrorComparing(t *testing.T) {
+	restclient.FlushMockups()
+	addComplianceMock(testReleaseCompareURL, http.StatusNotFound, `{"message":"Not Found"}`)
+
+	result, err := ReleaseNotesService.GetReleaseNotes("myuser", "myrepo", "v1.2.0", "v1.3.0")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetReleaseNotesEscapesRefs(t *testing.T) {
+	ResetService()
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/compare/release%2F1.2...release%2F1.3?per_page=100", http.StatusOK,
+		`{"status":"ahead","ahead_by":1,"total_commits":1,"commits":[{"sha":"SHA4","commit":{"message":"hotfix","author":{"name":"some name"}}}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA4/pulls?per_page=100", http.StatusOK, `[]`)
+
+	result, err := ReleaseNotesService.GetReleaseNotes("myuser", "myrepo", " release/1.2", "release/1.3 ")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "release/1.2", result.From)
+	assert.EqualValues(t, "release/1.3", result.To)
+	assert.EqualValues(t, 1, len(result.CommitsWithoutPR))
+}
+
+func TestGetReleaseNotesErrorGettingPRs(t *testing.T) {
+	ResetService()
+	addReleaseNotesMocks()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA3/pulls?per_page=100", http.StatusUnauthorized, `{"message":"Requires authentication"}`)
+
+	result, err := ReleaseNotesService.GetReleaseNotes("myuser", "myrepo", "v1.2.0", "v1.3.0")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+}
+
+func TestGetReleaseNotes(t *testing.T) {
+	ResetService()
+	addReleaseNotesMocks()
+
+	result, err := ReleaseNotesService.GetReleaseNotes("myuser", "myrepo", " v1.2.0 ", "v1.3.0")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "v1.2.0", result.From)
+	assert.EqualValues(t, 5, result.TotalCommits)
+	assert.EqualValues(t, 2, result.TotalPullRequests)
+
+	assert.EqualValues(t, 2, len(result.Sections))
+	assert.EqualValues(t, reportdomain.SectionFeatures, result.Sections[0].Title)
+	assert.EqualValues(t, 10, result.Sections[0].PullRequests[0].Number)
+	assert.EqualValues(t, reportdomain.SectionBugFixes, result.Sections[1].Title)
+	assert.EqualValues(t, 11, result.Sections[1].PullRequests[0].Number)
+
+	assert.EqualValues(t, 1, len(result.CommitsWithoutPR))
+	assert.EqualValues(t, "SHA4", result.CommitsWithoutPR[0].SHA)
+	assert.EqualValues(t, "hotfix", result.CommitsWithoutPR[0].Message)
+}
@@ -1,13 +1,18 @@
 package services
 
 import (
-	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/greendinosaur/gh-commit-info/src/api/config"
 	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/identitydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/messagedomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
 	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
 	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
 )
@@ -15,20 +20,24 @@ import (
 type reposService struct{}
 
 type reposServiceInterface interface {
+	GetRepo(owner string, repo string) (*githubdomain.GetRepoInfo, errors.APIError)
 	GetRepoPRs(owner string, repo string, scope string) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError)
 	GetRepoSinglePR(owner string, repo string, pullNumber string) (*githubdomain.GetSinglePullRequestResponse, errors.APIError)
+	GetPRReviews(owner string, repo string, pullNumber string) ([]githubdomain.PullRequestReview, errors.APIError)
+	GetPRFiles(owner string, repo string, pullNumber string) ([]githubdomain.ChangedFile, errors.APIError)
 	GetSingleCommitPR(owner string, repo string, SHA string) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError)
-	GetRepoCommits(owner string, repo string) ([]githubdomain.GetCommitInfo, errors.APIError)
+	GetRepoCommits(owner string, repo string, paths []string) ([]githubdomain.GetCommitInfo, errors.APIError)
 	GetRepoSingleCommit(owner string, repo string, SHA string) (*githubdomain.GetCommitInfo, errors.APIError)
-	GetCodeReviewReport(owner string, repo string, fromDate time.Time, endDate time.Time) (string, errors.APIError)
+	GetCodeReviewReport(owner string, repo string, fromDate time.Time, endDate time.Time, paths []string) (*reportdomain.CodeReviewReport, errors.APIError)
 }
 
 const (
-	errorInvalidOwnerParam = "invalid owner parameter"
-	errorInvalidRepoParam  = "invalid repo parameter"
-	errorInvalidScopeParam = "invalid scope parameter"
-	errorInvalidSHAParam   = "invalid SHA parameter"
-	errorInvalidPullParam  = "invalid pull parameter"
+	errorInvalidOwnerParam  = "invalid owner parameter"
+	errorInvalidRepoParam   = "invalid repo parameter"
+	errorInvalidScopeParam  = "invalid scope parameter"
+	errorInvalidSHAParam    = "invalid SHA parameter"
+	errorInvalidPullParam   = "invalid pull parameter"
+	errorInvalidBranchParam = "invalid branch parameter"
 )
 
 //RepositoryService defines the service to use
@@ -145,6 +154,22 @@ func validateAllCommitsInputs(owner string, repo string) (string, string, errors
 
 }
 
+//GetRepo returns the metadata for the given repo, including its default branch
+func (s *reposService) GetRepo(owner string, repo string) (*githubdomain.GetRepoInfo, errors.APIError) {
+	var err errors.APIError
+	owner, repo, err = validateAllCommitsInputs(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	response, errProvider := githubprovider.GetRepo(config.GetGithubAccessToken(), owner, repo)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	return response, nil
+}
+
 //GetRepoPRs returns pull request information for the given repo
 func (s *reposService) GetRepoPRs(owner string, repo string, scope string) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError) {
 	//firstly check the input params are valid and create an error otherwise
@@ -171,6 +196,14 @@ func (s *reposService) GetRepoSinglePR(owner string, repo string, pullNumber str
 	if err != nil {
 		return nil, err
 	}
+	//closed PRs won't change so can be read from the store, unless the stored copy came from a list of PRs and is missing the details
+	number, _ := strconv.ParseInt(pullNumber, 10, 64)
+	stored, errStore := DataStore.GetPullRequest(owner, repo, number)
+	logStoreError("read a PR from", errStore)
+	if errStore == nil && isPullRequestClosed(stored) && stored.HasDetails() {
+		return stored, nil
+	}
+
 	//then call the provider with valid parameters
 	response, errProvider := githubprovider.GetRepoSinglePR(config.GetGithubAccessToken(), owner, repo, pullNumber)
 
@@ -178,6 +211,49 @@ func (s *reposService) GetRepoSinglePR(owner string, repo string, pullNumber str
 		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
 	}
 
+	logStoreError("save a PR to", DataStore.SavePullRequests(owner, repo, []githubdomain.GetSinglePullRequestResponse{*response}))
+	return response, nil
+}
+
+//GetPRReviews returns the reviews of a single pull request
+func (s *reposService) GetPRReviews(owner string, repo string, pullNumber string) ([]githubdomain.PullRequestReview, errors.APIError) {
+	var err errors.APIError
+	owner, repo, pullNumber, err = validateSinglePRInputs(owner, repo, pullNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	//reviews can still be added until the PR is closed so only then are the stored reviews used
+	number, _ := strconv.ParseInt(pullNumber, 10, 64)
+	if stored, errStore := DataStore.GetPullRequest(owner, repo, number); errStore == nil && isPullRequestClosed(stored) {
+		reviews, errStore := DataStore.GetPullRequestReviews(owner, repo, number)
+		logStoreError("read PR reviews from", errStore)
+		if errStore == nil {
+			return reviews, nil
+		}
+	}
+
+	response, errProvider := githubprovider.GetPRReviews(config.GetGithubAccessToken(), owner, repo, pullNumber)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	logStoreError("save PR reviews to", DataStore.SavePullRequestReviews(owner, repo, number, response))
+	return response, nil
+}
+
+//GetPRFiles returns the files changed by a single pull request along with the lines added and deleted in each
+func (s *reposService) GetPRFiles(owner string, repo string, pullNumber string) ([]githubdomain.ChangedFile, errors.APIError) {
+	var err errors.APIError
+	owner, repo, pullNumber, err = validateSinglePRInputs(owner, repo, pullNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	response, errProvider := githubprovider.GetPRFiles(config.GetGithubAccessToken(), owner, repo, pullNumber)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
 	return response, nil
 }
 
@@ -190,49 +266,190 @@ func (s *reposService) GetSingleCommitPR(owner string, repo string, SHA string)
 		return nil, err
 	}
 
+	//a commit can gain PRs at any time so they are always fetched, the store is kept up to date for getCommitPullRequests
 	response, errProvider := githubprovider.GetSingleCommitPR(config.GetGithubAccessToken(), owner, repo, SHA)
 	if errProvider != nil {
 		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
 	}
 
+	logStoreError("save the PRs of a commit to", DataStore.SaveCommitPullRequests(owner, repo, SHA, response))
 	return response, nil
 
 }
 
-//GetRepoCommits returns all commits from the given repo
-func (s *reposService) GetRepoCommits(owner string, repo string) ([]githubdomain.GetCommitInfo, errors.APIError) {
+//getCommitPullRequests returns the PRs associated with the commit, the stored PRs are used once they hold a PR merged
+//into the branch as later PRs don't change how the commit got there, an empty branch accepts a PR merged into any branch
+func getCommitPullRequests(owner string, repo string, SHA string, branch string) ([]githubdomain.GetSinglePullRequestResponse, errors.APIError) {
+	stored, errStore := DataStore.GetCommitPullRequests(owner, repo, SHA)
+	logStoreError("read the PRs of a commit from", errStore)
+	if errStore == nil && hasPullRequestMergedInto(stored, branch) {
+		return stored, nil
+	}
+	return RepositoryService.GetSingleCommitPR(owner, repo, SHA)
+}
+
+//GetRepoCommits returns all commits for a repo, limited to those changing files matching the path patterns if any are given
+//the message of each commit is parsed into its parts
+func (s *reposService) GetRepoCommits(owner string, repo string, paths []string) ([]githubdomain.GetCommitInfo, errors.APIError) {
 	var err errors.APIError
 	owner, repo, err = validateAllCommitsInputs(owner, repo)
 	if err != nil {
 		return nil, err
 	}
+	filter, err := validatePathFilter(paths)
+	if err != nil {
+		return nil, err
+	}
 
-	response, errProvider := githubprovider.GetRepoCommits(config.GetGithubAccessToken(), owner, repo)
-	if errProvider != nil {
-		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	commits, err := getCommitsChangingPaths(owner, repo, filter, func(path string) ([]githubdomain.GetCommitInfo, errors.APIError) {
+		response, errProvider := githubprovider.GetRepoCommits(config.GetGithubAccessToken(), owner, repo, path)
+		if errProvider != nil {
+			return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+		}
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	for counter := range commits {
+		commits[counter].SetParsedMessage()
+	}
+	return commits, nil
+}
 
-	return response, nil
+//validatePathFilter checks the path patterns a report or listing is limited to
+func validatePathFilter(paths []string) (policydomain.PathFilter, errors.APIError) {
+	filter, err := policydomain.NewPathFilter(paths)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	return filter, nil
 }
 
-//getRepoCommitsInDateRange returns all commits from the given repo in the indicated date range
-func getRepoCommitsInDateRange(owner string, repo string, fromDate time.Time, toDate time.Time) ([]githubdomain.GetCommitInfo, errors.APIError) {
+//getCommitsChangingPaths returns the commits changing files matching the filter, newest first
+//Github is asked for the commits of each path covered by the filter and the files of each commit are only
+//checked when that can include commits not matching the filter, every commit is returned for an empty filter
+func getCommitsChangingPaths(owner string, repo string, filter policydomain.PathFilter, fetch func(path string) ([]githubdomain.GetCommitInfo, errors.APIError)) ([]githubdomain.GetCommitInfo, errors.APIError) {
+	paths, exact := filter.GithubPaths()
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+
+	seen := make(map[string]bool)
+	result := make([]githubdomain.GetCommitInfo, 0)
+	for _, path := range paths {
+		commits, err := fetch(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range commits {
+			if !seen[commit.SHA] {
+				seen[commit.SHA] = true
+				result = append(result, commit)
+			}
+		}
+	}
+	if len(paths) > 1 {
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].Commit.Committer.Date.After(result[j].Commit.Committer.Date)
+		})
+	}
+
+	if exact {
+		return result, nil
+	}
+	return filterCommitsByFiles(owner, repo, result, filter)
+}
+
+//maxCommitFiles is the most files Github lists for a single commit
+const maxCommitFiles = 300
+
+//filterCommitsByFiles keeps the commits changing a file matching the filter, a renamed file matches on either name
+//the files of each commit are read from the store when it has them and fetched from Github otherwise
+//Github only lists the first 300 files of a commit so a commit with that many is kept as it may change a matching file
+func filterCommitsByFiles(owner string, repo string, commits []githubdomain.GetCommitInfo, filter policydomain.PathFilter) ([]githubdomain.GetCommitInfo, errors.APIError) {
+	if filter.IsEmpty() {
+		return commits, nil
+	}
+
+	result := make([]githubdomain.GetCommitInfo, 0)
+	for counter := range commits {
+		detailed, err := getSingleCommit(owner, repo, commits[counter].SHA)
+		if err != nil {
+			return nil, err
+		}
+		if len(detailed.Files) >= maxCommitFiles {
+			result = append(result, commits[counter])
+			continue
+		}
+
+		var filenames []string
+		for _, file := range detailed.Files {
+			filenames = append(filenames, file.Filename)
+			if file.PreviousFilename != "" {
+				filenames = append(filenames, file.PreviousFilename)
+			}
+		}
+		if filter.MatchesAny(filenames) {
+			result = append(result, commits[counter])
+		}
+	}
+	return result, nil
+}
+
+//getRepoCommitsInDateRange returns all commits on the given branch of the repo in the indicated date range
+//along with whether they were read from the store rather than Github
+//only the commits changing files matching the filter are returned, these aren't all the commits on the branch so aren't stored as such
+func getRepoCommitsInDateRange(owner string, repo string, branch string, fromDate time.Time, toDate time.Time, filter policydomain.PathFilter) ([]githubdomain.GetCommitInfo, bool, errors.APIError) {
 	var err errors.APIError
 	owner, repo, err = validateAllCommitsInputs(owner, repo)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	response, errProvider := githubprovider.GetRepoCommitsInDateRange(config.GetGithubAccessToken(), owner, repo, fromDate, toDate)
+	branch = strings.TrimSpace(branch)
+	if len(branch) == 0 {
+		return nil, false, errors.NewBadRequestError(errorInvalidBranchParam)
+	}
 
-	if errProvider != nil {
-		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	stored, errStore := DataStore.GetBranchCommits(owner, repo, branch, fromDate, toDate)
+	logStoreError("read the commits on a branch from", errStore)
+	if errStore == nil {
+		filtered, err := filterCommitsByFiles(owner, repo, stored, filter)
+		return filtered, true, err
 	}
 
-	return response, nil
+	fetch := func(path string) ([]githubdomain.GetCommitInfo, errors.APIError) {
+		response, errProvider := githubprovider.GetRepoCommitsInDateRange(config.GetGithubAccessToken(), owner, repo, branch, fromDate, toDate, path)
+		if errProvider != nil {
+			return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+		}
+		return response, nil
+	}
+	if !filter.IsEmpty() {
+		filtered, err := getCommitsChangingPaths(owner, repo, filter, fetch)
+		return filtered, false, err
+	}
+
+	response, err := fetch("")
+	if err != nil {
+		return nil, false, err
+	}
+
+	//new commits can still arrive in the future, or be pushed with a recent date, so the store only covers up to the overlap
+	logStoreError("save the commits on a branch to", DataStore.SaveBranchCommits(owner, repo, branch, response))
+	if coveredTo := time.Now().Add(-commitsOverlap); toDate.After(coveredTo) {
+		toDate = coveredTo
+	}
+	if fromDate.Before(toDate) {
+		logStoreError("save the commits on a branch to", DataStore.AddBranchCoverage(owner, repo, branch, fromDate, toDate))
+	}
+
+	return response, false, nil
 }
 
-//GetRepoSingleCommit returns details about a specific commit inside the indicated repo
+//GetRepoSingleCommit returns details about a specific commit inside the indicated repo, with its message parsed into its parts
+//the commits it reverts or was cherry picked from are linked to it along with the PRs they were merged by
 func (s *reposService) GetRepoSingleCommit(owner string, repo string, SHA string) (*githubdomain.GetCommitInfo, errors.APIError) {
 	var err errors.APIError
 	owner, repo, SHA, err = validateSingleCommitPRInputs(owner, repo, SHA)
@@ -240,18 +457,88 @@ func (s *reposService) GetRepoSingleCommit(owner string, repo string, SHA string
 		return nil, err
 	}
 
+	commit, err := getSingleCommit(owner, repo, SHA)
+	if err != nil {
+		return nil, err
+	}
+	commit.SetParsedMessage()
+	if commit.Relations, err = getCommitRelations(owner, repo, commit.ParsedMessage); err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+//getSingleCommit returns the commit from the store, or from Github if the stored commit is missing any details
+func getSingleCommit(owner string, repo string, SHA string) (*githubdomain.GetCommitInfo, errors.APIError) {
+	//a commit never changes once it exists, those recorded from a push event have no parents or verification and those listed
+	//on a branch have no files so both are fetched again
+	stored, errStore := DataStore.GetCommit(owner, repo, SHA)
+	logStoreError("read a commit from", errStore)
+	if errStore == nil && len(stored.Parents) > 0 && stored.Stats != nil && stored.Commit.Verification != nil {
+		return stored, nil
+	}
+
 	response, errProvider := githubprovider.GetRepoSingleCommit(config.GetGithubAccessToken(), owner, repo, SHA)
 	if errProvider != nil {
 		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
 	}
 
+	logStoreError("save a commit to", DataStore.SaveCommits(owner, repo, []githubdomain.GetCommitInfo{*response}))
 	return response, nil
 }
 
+//getCommitRelations links the commit to the commits its message says it reverts or was cherry picked from
+//along with the merged PR each of them came from, an earlier commit Github doesn't know about is marked as not found
+func getCommitRelations(owner string, repo string, message *messagedomain.CommitMessage) ([]githubdomain.CommitRelation, errors.APIError) {
+	var result []githubdomain.CommitRelation
+	for _, related := range []struct {
+		relationType string
+		SHAs         []string
+	}{{githubdomain.RelationRevert, message.Reverts}, {githubdomain.RelationCherryPick, message.CherryPickedFrom}} {
+		for _, SHA := range related.SHAs {
+			relation, err := getCommitRelation(owner, repo, related.relationType, SHA)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, *relation)
+		}
+	}
+	return result, nil
+}
+
+//getCommitRelation looks up the earlier commit and the merged PR it came from
+//Github replies unprocessable rather than not found for a SHA it doesn't know about
+func getCommitRelation(owner string, repo string, relationType string, SHA string) (*githubdomain.CommitRelation, errors.APIError) {
+	relation := &githubdomain.CommitRelation{Type: relationType, SHA: SHA}
+	original, err := getSingleCommit(owner, repo, SHA)
+	if err != nil && (err.Status() == http.StatusNotFound || err.Status() == http.StatusUnprocessableEntity) {
+		return relation, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	relation.Found = true
+	relation.SHA = original.SHA
+	relation.Message = messagedomain.ParseCommitMessage(original.Commit.Message).Header
+
+	pullRequests, err := getCommitPullRequests(owner, repo, original.SHA, "")
+	if err != nil {
+		return nil, err
+	}
+	for counter := range pullRequests {
+		if isPRResultingInMerge(&pullRequests[counter]) {
+			relation.PullNumber = pullRequests[counter].Number
+			relation.PullTitle = pullRequests[counter].Title
+			break
+		}
+	}
+	return relation, nil
+}
+
 //isMergeCommit determines if a commit is a merge commit
 func isMergeCommit(commitInfo *githubdomain.GetCommitInfo) bool {
 	//business logic from github that a merge commit has two parents, other commits don't
-	//assumption is that commits have been retrieved from the master branch only
+	//assumption is that commits have been retrieved from a single branch only, normally the default branch
 
 	if len(commitInfo.Parents) > 1 {
 		return true
@@ -268,73 +555,364 @@ func isPRResultingInMerge(pullRequest *githubdomain.GetSinglePullRequestResponse
 	return false
 }
 
-//GetCodeReviewReport returns a text file that summarises the commit and PR data and also
+//getCompareCommits compares the head ref against the base ref of the given repo
+func getCompareCommits(owner string, repo string, base string, head string) (*githubdomain.GetCompareCommitsResponse, errors.APIError) {
+	var err errors.APIError
+	owner, repo, err = validateAllCommitsInputs(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	response, errProvider := githubprovider.GetCompareCommits(config.GetGithubAccessToken(), owner, repo, base, head)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	return response, nil
+}
+
+//isCommitReachableFromBranch determines if the commit is part of the history of the branch
+//commits already known to be on the branch are checked first to save calling Github
+func isCommitReachableFromBranch(owner string, repo string, branch string, SHA string, branchCommits map[string]bool) (bool, errors.APIError) {
+	if branchCommits[SHA] {
+		return true, nil
+	}
+
+	//the compare API describes the head relative to the base, if the commit is behind or identical to
+	//the branch then it is an ancestor of the branch
+	compare, err := getCompareCommits(owner, repo, branch, SHA)
+	if err != nil {
+		return false, err
+	}
+
+	reachable := compare.Status == "behind" || compare.Status == "identical"
+	branchCommits[SHA] = reachable
+	return reachable, nil
+}
+
+//isPRMergedIntoBranch determines if the PR targeted the branch and its merge commit is in the history of the branch
+func isPRMergedIntoBranch(owner string, repo string, branch string, pullRequest *githubdomain.GetSinglePullRequestResponse, branchCommits map[string]bool) (bool, errors.APIError) {
+	if pullRequest.Base.Ref != branch {
+		return false, nil
+	}
+
+	return isCommitReachableFromBranch(owner, repo, branch, pullRequest.MergeCommitSHA, branchCommits)
+}
+
+//getLastSyncTime returns when the repo was last synced into the store, zero if it never has been
+func getLastSyncTime(owner string, repo string) time.Time {
+	state, errStore := DataStore.GetSyncState(owner, repo)
+	logStoreError("read the sync state from", errStore)
+	if errStore != nil {
+		return time.Time{}
+	}
+	return state.LastSyncCompleted
+}
+
+//setCommitReviewPR records the review status of the commit along with the PR it was reviewed in
+func setCommitReviewPR(commitReview *reportdomain.CommitReview, reviewStatus string, pullRequest *githubdomain.GetSinglePullRequestResponse) {
+	commitReview.ReviewStatus = reviewStatus
+	commitReview.PullNumber = pullRequest.Number
+	commitReview.PullTitle = pullRequest.Title
+	commitReview.PullBaseRef = pullRequest.Base.Ref
+	commitReview.PullAuthor = pullRequest.User.Login
+	commitReview.PullMergedBy = pullRequest.MergedBy.Login
+}
+
+//getCommitReview works out whether the commit on the branch was merged via a PR into that branch
+//the commit is marked as a merge commit and linked to the PR that merged it, the authors of both are resolved to people
+func getCommitReview(owner string, repo string, branch string, repoCommitInfo *githubdomain.GetCommitInfo, branchCommits map[string]bool, resolver *identitydomain.Resolver) (*reportdomain.CommitReview, errors.APIError) {
+	//now get the associated PRs and find one that has been closed and has a merge commit
+	//may be multiple PRs associated with this commit
+	pullsForCommit, err := getCommitPullRequests(owner, repo, repoCommitInfo.SHA, branch)
+
+	if err != nil {
+		return nil, err
+	}
+	return getCommitReviewFromPulls(owner, repo, branch, repoCommitInfo, pullsForCommit, branchCommits, resolver)
+}
+
+//getCommitReviewFromPulls works out whether the commit on the branch was merged via one of the PRs it belongs to
+func getCommitReviewFromPulls(owner string, repo string, branch string, repoCommitInfo *githubdomain.GetCommitInfo, pullsForCommit []githubdomain.GetSinglePullRequestResponse, branchCommits map[string]bool, resolver *identitydomain.Resolver) (*reportdomain.CommitReview, errors.APIError) {
+	repoCommitInfo.IsMergeCommit = isMergeCommit(repoCommitInfo)
+
+	commitReview := reportdomain.CommitReview{
+		SHA:           repoCommitInfo.SHA,
+		Author:        repoCommitInfo.Commit.Author.Name,
+		AuthorID:      resolveCommitAuthor(resolver, repoCommitInfo).ID,
+		Date:          repoCommitInfo.Commit.Author.Date,
+		Message:       repoCommitInfo.Commit.Message,
+		IsMergeCommit: repoCommitInfo.IsMergeCommit,
+		ReviewStatus:  reportdomain.ReviewStatusNotReviewed,
+	}
+
+	//now we have the array of PRs iterate through and see if there is a merged and closed PR into the audited branch
+	//a PR merged into a different branch only counts as reviewed on another branch
+	for pullCounter := range pullsForCommit {
+		pull := &pullsForCommit[pullCounter]
+		if !isPRResultingInMerge(pull) {
+			continue
+		}
+
+		isMergedIntoBranch, err := isPRMergedIntoBranch(owner, repo, branch, pull, branchCommits)
+		if err != nil {
+			return nil, err
+		}
+
+		if isMergedIntoBranch {
+			//assume there is only one PR into the audited branch so can stop looking
+			repoCommitInfo.PRForMerge = pull
+			setCommitReviewPR(&commitReview, reportdomain.ReviewStatusReviewed, pull)
+			break
+		}
+		if commitReview.ReviewStatus == reportdomain.ReviewStatusNotReviewed {
+			setCommitReviewPR(&commitReview, reportdomain.ReviewStatusReviewedOnOtherBranch, pull)
+		}
+	}
+	if commitReview.PullAuthor != "" {
+		commitReview.PullAuthorID = resolver.Resolve(commitReview.PullAuthor, "", "").ID
+	}
+	if commitReview.PullMergedBy != "" {
+		commitReview.PullMergedByID = resolver.Resolve(commitReview.PullMergedBy, "", "").ID
+	}
+	return &commitReview, nil
+}
+
+//GetCodeReviewReport returns a report that summarises the commit and PR data and also
 //provides a list of the relevant commits and PRs
 //this function will need to:
-//1. get all the commits in a given timeframe
+//0. find the default branch of the repo as this is the branch being audited
+//1. get all the commits on that branch in a given timeframe
 //2. for each commit, determine if a merge commit or proper commit
-//3. for each proper commit, determine if it has an approved PR that resulted in the merge commit
-//4. summarise the results (#total commits, #merge commits, #commits with PR, #commits with no PR)
+//3. for each proper commit, determine if it has an approved PR into the audited branch that resulted in the merge commit
+//4. summarise the results (#total commits, #merge commits, #commits with PR, #commits reviewed on other branches, #commits with no PR)
 //5. summarise the commits (sha, committer, date, commit message)
 //6. summarise the PRs (PR title, approver, raiser, date)
-//7. output this all as a text file that can be streamed back via the a client via an API
+//7. output this all as a report that can be streamed back via the a client via an API
 //Note PR reviews are stored in a different object and require a different GitHub API call
 //therefore, if want to get the list of approvers who approved the PR, need another API call
 //should look to do this
-func (s *reposService) GetCodeReviewReport(owner string, repo string, fromDate time.Time, endDate time.Time) (string, errors.APIError) {
+func (s *reposService) GetCodeReviewReport(owner string, repo string, fromDate time.Time, endDate time.Time, paths []string) (*reportdomain.CodeReviewReport, errors.APIError) {
+	filter, err := validatePathFilter(paths)
+	if err != nil {
+		return nil, err
+	}
 
-	//set-up the counters for the statistics to report on later
-	totalMergeCommits := 0
-	totalCommitsWithPR := 0
-	totalCommitsWithNoPR := 0
+	//firstly, find out which branch to audit, repos don't always use master
+	repoInfo, err := RepositoryService.GetRepo(owner, repo)
 
-	//create an array where we can store indices for those commits that aren't merge commits and have no PRs
-	var indexCommitsWithNoPR []int
+	if err != nil {
+		return nil, err
+	}
+	branch := repoInfo.DefaultBranch
 
-	//firstly, get hold of all the commits of interest
-	repoCommits, err := getRepoCommitsInDateRange(owner, repo, fromDate, endDate)
+	//then get hold of all the commits of interest on that branch
+	dataAsOf := time.Now().UTC()
+	repoCommits, fromStore, err := getRepoCommitsInDateRange(owner, repo, branch, fromDate, endDate, filter)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	//now we can loop over each commit and get hold of the associated PRs
-	for commitCounter, repoCommitInfo := range repoCommits {
+	report := reportdomain.CodeReviewReport{
+		Owner:         owner,
+		Repo:          repo,
+		Branch:        branch,
+		FromDate:      fromDate,
+		ToDate:        endDate,
+		Paths:         filter,
+		TotalCommits:  len(repoCommits),
+		DataFromStore: fromStore,
+		DataAsOf:      dataAsOf,
+	}
+	if fromStore {
+		report.DataAsOf = getLastSyncTime(owner, repo)
+	}
 
-		repoCommitInfo.IsMergeCommit = isMergeCommit(&repoCommitInfo)
-		if repoCommitInfo.IsMergeCommit {
-			totalMergeCommits++
+	//keep track of the commits known to be on the branch, used to check whether a PR was merged into it
+	branchCommits := make(map[string]bool)
+	for _, repoCommitInfo := range repoCommits {
+		branchCommits[repoCommitInfo.SHA] = true
+	}
+
+	//the tickets referenced by each commit, or the PR that merged it, and the signature of each commit are checked when the policy requires them
+	//so are the required checks of the PR each reviewed commit was merged in, and its approvals are checked for rubber stamps when the policy enables it
+	policy := ReviewPolicies.ForRepo(owner, repo)
+	headChecks := make(map[string]*githubdomain.CommitChecks)
+	pulls := newMergedPulls(owner, repo)
+	var bypass *bypassChecker
+	if policy.DetectBypassMerges {
+		bypass = newBypassChecker(pulls, &policy)
+		report.BypassMergesChecked = true
+	}
+	var stamps *rubberStampChecker
+	if policy.RubberStamps.Enabled {
+		stamps = newRubberStampChecker(pulls, &policy)
+		report.RubberStampsChecked = true
+	}
+	var tickets *ticketChecker
+	if policy.Tickets.Required {
+		if tickets, err = newTicketChecker(&policy.Tickets); err != nil {
+			return nil, err
 		}
+	}
 
-		//now get the associated PRs and find one that has been closed and has a merge commit
-		//may be multiple PRs associated with this commit
-		pullsForCommit, err := RepositoryService.GetSingleCommitPR(owner, repo, repoCommitInfo.SHA)
+	//now we can loop over each commit and get hold of the associated PRs
+	resolver := newIdentityResolver(repoCommits)
+	for commitCounter := range repoCommits {
+		repoCommitInfo := &repoCommits[commitCounter]
 
+		commitReview, err := getCommitReview(owner, repo, branch, repoCommitInfo, branchCommits, resolver)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		//now we have the array of PRs iterate through and see if there is a merged and closed PR
-		//assume there is only one PR so will break the loop once found
-		isApprovedPR := false
-		for _, pull := range pullsForCommit {
-			if isPRResultingInMerge(&pull) {
-				repoCommitInfo.PRForMerge = &pull
-				totalCommitsWithPR++
-				isApprovedPR = true
-				break
+		if tickets != nil {
+			references, err := tickets.checkCommit(repoCommitInfo, repoCommitInfo.PRForMerge)
+			if err != nil {
+				return nil, err
 			}
+			report.SetTickets(commitReview, references)
+		}
+		if message := messagedomain.ParseCommitMessage(repoCommitInfo.Commit.Message); message.IsRevert() || message.IsCherryPick() {
+			relations, err := getCommitRelations(owner, repo, message)
+			if err != nil {
+				return nil, err
+			}
+			report.SetRelations(commitReview, relations)
+		}
+		if policy.Signatures.Required {
+			verification, err := getCommitVerification(owner, repo, repoCommitInfo)
+			if err != nil {
+				return nil, err
+			}
+			report.SetSignature(commitReview, verification, policy.Signatures.IsExempt(repoCommitInfo.Author.Login, repoCommitInfo.Commit.Author.Email))
+		}
+		if commitReview.ReviewStatus == reportdomain.ReviewStatusReviewed {
+			if bypass != nil {
+				if err := setBypassMerge(&report, commitReview, bypass, resolver); err != nil {
+					return nil, err
+				}
+			}
+			if stamps != nil {
+				approvals, err := stamps.check(commitReview.PullNumber)
+				if err != nil {
+					return nil, err
+				}
+				for _, approval := range approvals {
+					report.AddSuspiciousApproval(approval)
+				}
+			}
+		}
+		if len(policy.RequiredStatusChecks) > 0 && repoCommitInfo.PRForMerge != nil {
+			if err := setMergedPRChecks(owner, repo, &report, commitReview, repoCommitInfo.PRForMerge, policy.RequiredStatusChecks, headChecks); err != nil {
+				return nil, err
+			}
+		}
+		if repoCommitInfo.IsMergeCommit {
+			report.TotalMergeCommits++
+		}
+
+		switch commitReview.ReviewStatus {
+		case reportdomain.ReviewStatusReviewed:
+			report.TotalCommitsWithPR++
+		case reportdomain.ReviewStatusReviewedOnOtherBranch:
+			report.TotalCommitsReviewedOnOtherBranches++
+		default:
+			report.TotalCommitsWithNoPR++
+		}
+		report.Commits = append(report.Commits, *commitReview)
+	}
+
+	if directory := getTeamDirectory(); directory.HasTeams() {
+		if err := setCommitReviewMergers(owner, repo, report.Commits, resolver); err != nil {
+			return nil, err
 		}
-		if isApprovedPR == false {
-			//no PR associated with this commit so need to store for reporting purposes
-			indexCommitsWithNoPR = append(indexCommitsWithNoPR, commitCounter)
-			totalCommitsWithNoPR++
+		report.SetTeams(directory)
+	}
+	return &report, nil
+}
+
+//getCommitChecks returns the commit statuses and the check runs on the commit, together they are the checks Github requires before a merge
+func getCommitChecks(owner string, repo string, ref string) (*githubdomain.CommitChecks, errors.APIError) {
+	statuses, errProvider := githubprovider.GetCombinedStatus(config.GetGithubAccessToken(), owner, repo, ref)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+	checkRuns, errProvider := githubprovider.GetCheckRuns(config.GetGithubAccessToken(), owner, repo, ref)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+	return &githubdomain.CommitChecks{SHA: ref, Statuses: statuses, CheckRuns: checkRuns}, nil
+}
+
+//setMergedPRChecks records the state the required checks had when the PR of the reviewed commit was merged
+//the checks are those on the head commit of the PR, the commits of a PR share it so each head commit is only looked up once
+func setMergedPRChecks(owner string, repo string, report *reportdomain.CodeReviewReport, commitReview *reportdomain.CommitReview, pullRequest *githubdomain.GetSinglePullRequestResponse, requiredChecks []string, headChecks map[string]*githubdomain.CommitChecks) errors.APIError {
+	checks, ok := headChecks[pullRequest.Head.SHA]
+	if !ok {
+		var err errors.APIError
+		if checks, err = getCommitChecks(owner, repo, pullRequest.Head.SHA); err != nil {
+			return err
 		}
+		headChecks[pullRequest.Head.SHA] = checks
+	}
+	report.SetChecks(commitReview, checks, requiredChecks, pullRequest.MergedAt)
+	return nil
+}
 
+//setBypassMerge records who merged the PR of the reviewed commit, used to spot self-merged PRs, and adds the PR to the bypass merges of the report if its merge bypassed the policy
+func setBypassMerge(report *reportdomain.CodeReviewReport, commitReview *reportdomain.CommitReview, bypass *bypassChecker, resolver *identitydomain.Resolver) errors.APIError {
+	pullRequest, bypassMerge, err := bypass.check(commitReview.PullNumber)
+	if err != nil {
+		return err
 	}
+	if commitReview.PullMergedBy == "" && pullRequest.MergedBy.Login != "" {
+		commitReview.PullMergedBy = pullRequest.MergedBy.Login
+		commitReview.PullMergedByID = resolver.Resolve(commitReview.PullMergedBy, "", "").ID
+	}
+	if bypassMerge != nil {
+		bypassMerge.MergedByID = commitReview.PullMergedByID
+		report.AddBypassMerge(*bypassMerge)
+	}
+	return nil
+}
 
-	//can now summarise the information and return this
-	summaryInfo := fmt.Sprintf("#Total Commits: %d, #Merged Commits: %d,  #Commits with PRs: %d, #Commits with No PRs: %d",
-		len(repoCommits), totalMergeCommits, totalCommitsWithPR, totalCommitsWithNoPR)
+//setCommitReviewMergers sets who merged the PR of each reviewed commit not already known, used to spot self-merged PRs
+//the PRs listed for a commit don't say who merged them so each PR is fetched once
+func setCommitReviewMergers(owner string, repo string, commits []reportdomain.CommitReview, resolver *identitydomain.Resolver) errors.APIError {
+	mergers := make(map[int64]string)
+	for counter := range commits {
+		commit := &commits[counter]
+		if commit.ReviewStatus != reportdomain.ReviewStatusReviewed || commit.PullMergedBy != "" {
+			continue
+		}
+
+		mergedBy, ok := mergers[commit.PullNumber]
+		if !ok {
+			pullRequest, err := RepositoryService.GetRepoSinglePR(owner, repo, strconv.FormatInt(commit.PullNumber, 10))
+			if err != nil {
+				return err
+			}
+			mergedBy = pullRequest.MergedBy.Login
+			mergers[commit.PullNumber] = mergedBy
+		}
+		if mergedBy != "" {
+			commit.PullMergedBy = mergedBy
+			commit.PullMergedByID = resolver.Resolve(mergedBy, "", "").ID
+		}
+	}
+	return nil
+}
 
-	return summaryInfo, nil
+//getCommitVerification returns the outcome of Github verifying the signature of the commit
+//commits recorded from a push event don't have one so the commit is fetched again, it is nil if Github still doesn't say
+func getCommitVerification(owner string, repo string, commit *githubdomain.GetCommitInfo) (*githubdomain.CommitVerification, errors.APIError) {
+	if commit.Commit.Verification != nil {
+		return commit.Commit.Verification, nil
+	}
+	single, err := RepositoryService.GetRepoSingleCommit(owner, repo, commit.SHA)
+	if err != nil {
+		return nil, err
+	}
+	return single.Commit.Verification, nil
 }
@@ -10,6 +10,11 @@ import (
 	"time"
 
 	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
 	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
 	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +25,59 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+//these test the logic for getting the repo metadata
+func TestGetRepoInvalidOwner(t *testing.T) {
+	result, err := RepositoryService.GetRepo("", "repo")
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageOwner, err.Message())
+}
+
+func TestGetRepoInvalidRepo(t *testing.T) {
+	result, err := RepositoryService.GetRepo("owner", "")
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageRepo, err.Message())
+}
+
+func TestGetRepoErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	response, err := RepositoryService.GetRepo("myuser", "myrepo")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageAuthentication, err.Message())
+}
+
+func TestGetRepoNoError(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
+
+	response, err := RepositoryService.GetRepo("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+	assert.EqualValues(t, "myuser/myrepo", response.FullName)
+	assert.EqualValues(t, "main", response.DefaultBranch)
+}
+
 //these test the logic for checking parameters
 func TestGetPRsInvalidOwner(t *testing.T) {
 	result, err := RepositoryService.GetRepoPRs("", "valid", "open")
@@ -78,7 +136,7 @@ func TestValidateInpusValidStateValues(t *testing.T) {
 func TestGetPRsErrorFromGithub(t *testing.T) {
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/pulls?state=all",
+		URL:        "https://api.github.com/repos/test/user1/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
@@ -98,7 +156,7 @@ func TestGetPRsNoError(t *testing.T) {
 	restclient.FlushMockups()
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/pulls?state=all",
+		URL:        "https://api.github.com/repos/test/user1/pulls?state=all&per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
@@ -183,6 +241,61 @@ func TestRepoSinglePRErrorFromGithub(t *testing.T) {
 
 }
 
+func TestGetPRReviewsInvalidPR(t *testing.T) {
+	result, err := RepositoryService.GetPRReviews("valid", "repo", "asd")
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessagePull, err.Message())
+}
+
+func TestGetPRReviewsErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/test/user1/pulls/1/reviews?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	response, err := RepositoryService.GetPRReviews("test", "user1", "1")
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageAuthentication, err.Message())
+}
+
+func TestGetPRFiles(t *testing.T) {
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/test/user1/pulls/1/files?per_page=100", http.StatusOK,
+		`[{"filename":"src/main.go","status":"modified","additions":10,"deletions":2,"changes":12},{"filename":"README.md","status":"added","additions":5,"changes":5}]`)
+
+	response, err := RepositoryService.GetPRFiles("test", "user1", "1")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(response))
+	assert.EqualValues(t, "src/main.go", response[0].Filename)
+	assert.EqualValues(t, githubdomain.FileStatusAdded, response[1].Status)
+}
+
+func TestGetPRFilesInvalidPR(t *testing.T) {
+	result, err := RepositoryService.GetPRFiles("valid", "repo", "asd")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessagePull, err.Message())
+}
+
+func TestGetPRFilesErrorFromGithub(t *testing.T) {
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/test/user1/pulls/1/files?per_page=100", http.StatusUnauthorized, `{"message":"Requires authentication"}`)
+
+	response, err := RepositoryService.GetPRFiles("test", "user1", "1")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageAuthentication, err.Message())
+}
+
 func TestRepoSinglePRNoError(t *testing.T) {
 	restclient.FlushMockups()
 
@@ -247,7 +360,7 @@ func TestSingleCommitPRInvalidSHA(t *testing.T) {
 func TestSingleCommitPRErrorFromGithub(t *testing.T) {
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/commits/ABC/pulls",
+		URL:        "https://api.github.com/repos/test/user1/commits/ABC/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
@@ -265,7 +378,7 @@ func TestSingleCommitPRErrorFromGithub(t *testing.T) {
 func TestSingleCommitPRNoError(t *testing.T) {
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls",
+		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
@@ -283,7 +396,7 @@ func TestSingleCommitPRNoError(t *testing.T) {
 
 //these test the logic for getting multiple repo commits
 func TestGetRepoCommitsInvalidOwner(t *testing.T) {
-	result, err := RepositoryService.GetRepoCommits("", "repo")
+	result, err := RepositoryService.GetRepoCommits("", "repo", nil)
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusBadRequest, err.Status())
@@ -292,7 +405,7 @@ func TestGetRepoCommitsInvalidOwner(t *testing.T) {
 }
 
 func TestGetRepoCommitsInvalidRepo(t *testing.T) {
-	result, err := RepositoryService.GetRepoCommits("owner", "")
+	result, err := RepositoryService.GetRepoCommits("owner", "", nil)
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusBadRequest, err.Status())
@@ -311,7 +424,7 @@ func TestGetRepoCommitsErrorFromGithub(t *testing.T) {
 		},
 	})
 
-	response, err := RepositoryService.GetRepoCommits("test", "user1")
+	response, err := RepositoryService.GetRepoCommits("test", "user1", nil)
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
@@ -329,18 +442,84 @@ func TestGetRepoCommitsNoError(t *testing.T) {
 		},
 	})
 
-	response, err := RepositoryService.GetRepoCommits("test", "user1")
+	response, err := RepositoryService.GetRepoCommits("test", "user1", nil)
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
 	assert.EqualValues(t, 1, len(response))
 	assert.EqualValues(t, "http://www.github.com", response[0].URL)
 	assert.EqualValues(t, "AABCDEF123456", response[0].SHA)
+	assert.EqualValues(t, "some commit message", response[0].ParsedMessage.Subject)
 
 	//will just test one of the items has been marshalled okay
 	//the domain object tests this fully so no need for duplication
 	assert.EqualValues(t, "http://www.github.com", response[0].Commit.URL)
 }
 
+func TestGetRepoCommitsInvalidPath(t *testing.T) {
+	result, err := RepositoryService.GetRepoCommits("owner", "repo", []string{"services/[payments"})
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, "invalid path pattern services/[payments", err.Message())
+}
+
+func TestGetRepoCommitsForPaths(t *testing.T) {
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/test/user1/commits?path=services%2Fpayments", http.StatusOK,
+		`[{"sha":"SHA1","commit":{"committer":{"date":"2019-12-01T00:00:00Z"}}},{"sha":"SHA3","commit":{"committer":{"date":"2019-11-01T00:00:00Z"}}}]`)
+	addComplianceMock("https://api.github.com/repos/test/user1/commits?path=docs", http.StatusOK,
+		`[{"sha":"SHA2","commit":{"committer":{"date":"2019-11-15T00:00:00Z"}}},{"sha":"SHA1","commit":{"committer":{"date":"2019-12-01T00:00:00Z"}}}]`)
+
+	//each path is asked for separately and the commits merged newest first
+	response, err := RepositoryService.GetRepoCommits("test", "user1", []string{"services/payments/**", " /docs/ ", ""})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(response))
+	assert.EqualValues(t, "SHA1", response[0].SHA)
+	assert.EqualValues(t, "SHA2", response[1].SHA)
+	assert.EqualValues(t, "SHA3", response[2].SHA)
+}
+
+//addGlobCommitMocks mocks the commits of test/user1 and the files of its only commit, a mocked body can only be read once
+func addGlobCommitMocks() {
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/test/user1/commits",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataCommitsResponseStatusCode(),
+			Body:       testutils.GetMockDataCommitsResponseMessage(),
+		},
+	})
+	addComplianceMock("https://api.github.com/repos/test/user1/commits/AABCDEF123456", http.StatusOK,
+		`{"sha":"AABCDEF123456","parents":[{"sha":"PARENT"}],"stats":{"total":3},"files":[{"filename":"src/main.go","previous_filename":"docs/old.txt"}]}`)
+}
+
+func TestGetRepoCommitsForGlob(t *testing.T) {
+	//a pattern that can match anywhere means every commit has its files checked
+	addGlobCommitMocks()
+	response, err := RepositoryService.GetRepoCommits("test", "user1", []string{"*.go"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(response))
+
+	//a renamed file matches on its old name
+	addGlobCommitMocks()
+	response, err = RepositoryService.GetRepoCommits("test", "user1", []string{"*.txt"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(response))
+
+	addGlobCommitMocks()
+	response, err = RepositoryService.GetRepoCommits("test", "user1", []string{"*.md"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(response))
+
+	//the files of a commit can't be read
+	addGlobCommitMocks()
+	addComplianceMock("https://api.github.com/repos/test/user1/commits/AABCDEF123456", http.StatusNotFound, `{"message":"Not Found"}`)
+	response, err = RepositoryService.GetRepoCommits("test", "user1", []string{"*.go"})
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
 //these test the logic for getting a single commit for a repo
 func TestRepoCommitInvalidOwner(t *testing.T) {
 	result, err := RepositoryService.GetRepoSingleCommit("", "repo", "asd")
@@ -404,12 +583,69 @@ func TestRepoSingleCommitNoError(t *testing.T) {
 	assert.Nil(t, err)
 	assert.EqualValues(t, "http://www.github.com", response.URL)
 	assert.EqualValues(t, "AABCDEF123456", response.SHA)
+	assert.EqualValues(t, "some commit message", response.ParsedMessage.Subject)
 
 	//will just test one of the items has been marshalled okay
 	//the domain object tests this fully so no need for duplication
 	assert.EqualValues(t, response.Commit.URL, "http://www.github.com")
 }
 
+//addRelatedCommitMocks mocks a commit of myuser/myrepo that reverts one commit, merged by PR 7, and was cherry picked from another Github doesn't know about
+func addRelatedCommitMocks() {
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/REVERTSHA", http.StatusOK,
+		`{"sha":"REVERTSHA","commit":{"message":"Revert \"feat: add an endpoint\"\n\nThis reverts commit 0123abcd.\n\n(cherry picked from commit 4567cdef)"},"parents":[{"sha":"BASE"}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/0123abcd", http.StatusOK,
+		`{"sha":"0123abcd00000000000000000000000000000000","commit":{"message":"feat: add an endpoint\n\nwith a body"},"parents":[{"sha":"BASE"}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/0123abcd00000000000000000000000000000000/pulls?per_page=100", http.StatusOK,
+		`[{"number":6,"state":"closed","title":"Abandoned"},{"number":7,"state":"closed","title":"Add an endpoint","merge_commit_sha":"0123abcd00000000000000000000000000000000"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/4567cdef", http.StatusUnprocessableEntity,
+		`{"message":"No commit found for SHA: 4567cdef"}`)
+}
+
+func TestRepoSingleCommitRelations(t *testing.T) {
+	ResetService()
+	addRelatedCommitMocks()
+
+	response, err := RepositoryService.GetRepoSingleCommit("myuser", "myrepo", "REVERTSHA")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"0123abcd"}, response.ParsedMessage.Reverts)
+	assert.EqualValues(t, []githubdomain.CommitRelation{
+		{Type: githubdomain.RelationRevert, SHA: "0123abcd00000000000000000000000000000000", Found: true, Message: "feat: add an endpoint", PullNumber: 7, PullTitle: "Add an endpoint"},
+		{Type: githubdomain.RelationCherryPick, SHA: "4567cdef"},
+	}, response.Relations)
+
+	addRelatedCommitMocks()
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/0123abcd", http.StatusUnauthorized, `{"message":"Requires authentication"}`)
+	response, err = RepositoryService.GetRepoSingleCommit("myuser", "myrepo", "REVERTSHA")
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+}
+
+func TestGetCodeReviewReportRelations(t *testing.T) {
+	ResetService()
+	addRelatedCommitMocks()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[
+		{"sha":"REVERTSHA","commit":{"message":"Revert \"feat: add an endpoint\"\n\nThis reverts commit 0123abcd.\n\n(cherry picked from commit 4567cdef)"}},
+		{"sha":"OTHERSHA","commit":{"message":"fix: something"}}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/REVERTSHA/pulls?per_page=100", http.StatusOK, `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/OTHERSHA/pulls?per_page=100", http.StatusOK, `[]`)
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, response.TotalReverts)
+	assert.EqualValues(t, 1, response.TotalCherryPicks)
+	assert.EqualValues(t, 2, len(response.Commits[0].Relations))
+	assert.EqualValues(t, 7, response.Commits[0].Relations[0].PullNumber)
+	assert.EqualValues(t, 0, len(response.Commits[1].Relations))
+}
+
 //these test the logic for determining whether the commit is a merge commit
 func TestMergeCommitIsMerge(t *testing.T) {
 	restclient.FlushMockups()
@@ -453,7 +689,7 @@ func TestMergeCommitIsNotMerge(t *testing.T) {
 func TestIsPRResultingInMergeSuccess(t *testing.T) {
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls",
+		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
@@ -472,7 +708,7 @@ func TestIsPRResultingInMergeSuccess(t *testing.T) {
 func TestIsPRResultingInMergeFailInvalidPRState(t *testing.T) {
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls",
+		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
@@ -491,7 +727,7 @@ func TestIsPRResultingInMergeFailInvalidPRState(t *testing.T) {
 func TestIsPRResultingInMergeFailInvalidMergeSHA(t *testing.T) {
 	restclient.FlushMockups()
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls",
+		URL:        "https://api.github.com/repos/test/user1/commits/sha123/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: http.StatusOK,
@@ -509,11 +745,23 @@ func TestIsPRResultingInMergeFailInvalidMergeSHA(t *testing.T) {
 
 //these test GetRepoCommits
 
+func TestGetRepoCommitsInDateRangeInvalidBranch(t *testing.T) {
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+
+	response, _, err := getRepoCommitsInDateRange("myuser", "myrepo", " ", fromDate, toDate, nil)
+
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageBranch, err.Message())
+}
+
 func TestGetRepoCommitsInDateRangeInvalidOwner(t *testing.T) {
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
 
-	response, err := getRepoCommitsInDateRange("", "owner", fromDate, toDate)
+	response, _, err := getRepoCommitsInDateRange("", "owner", "main", fromDate, toDate, nil)
 
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
@@ -526,7 +774,7 @@ func TestGetRepoCommitsInDateRangeInvalidRepo(t *testing.T) {
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
 
-	response, err := getRepoCommitsInDateRange("myuser", "", fromDate, toDate)
+	response, _, err := getRepoCommitsInDateRange("myuser", "", "main", fromDate, toDate, nil)
 
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
@@ -539,7 +787,7 @@ func TestGetRepoCommitsInDateRangeErrorWithGithub(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -550,7 +798,7 @@ func TestGetRepoCommitsInDateRangeErrorWithGithub(t *testing.T) {
 		},
 	})
 
-	response, err := getRepoCommitsInDateRange("myuser", "myrepo", fromDate, toDate)
+	response, _, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
@@ -562,7 +810,7 @@ func TestGetRepoCommitsInDateRangeSuccess(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -573,7 +821,7 @@ func TestGetRepoCommitsInDateRangeSuccess(t *testing.T) {
 		},
 	})
 
-	response, err := getRepoCommitsInDateRange("myuser", "myrepo", fromDate, toDate)
+	response, _, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", fromDate, toDate, nil)
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
 	assert.EqualValues(t, len(response), 1)
@@ -592,7 +840,16 @@ func TestGetCodeReviewReportErrorGettingCommits(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -603,12 +860,33 @@ func TestGetCodeReviewReportErrorGettingCommits(t *testing.T) {
 		},
 	})
 
-	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate)
-	assert.NotNil(t, response)
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageAuthentication, err.Message())
+}
+
+func TestGetCodeReviewReportErrorGettingRepo(t *testing.T) {
+	//simulate getting an error when finding the default branch of the repo
+	restclient.FlushMockups()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
 	assert.EqualValues(t, testutils.ErrorMessageAuthentication, err.Message())
-	assert.EqualValues(t, "", response)
+	assert.Nil(t, response)
 }
 
 func TestGetCodeReviewReportErrorGettingPR(t *testing.T) {
@@ -618,7 +896,16 @@ func TestGetCodeReviewReportErrorGettingPR(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -630,7 +917,7 @@ func TestGetCodeReviewReportErrorGettingPR(t *testing.T) {
 	})
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls",
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
@@ -638,22 +925,39 @@ func TestGetCodeReviewReportErrorGettingPR(t *testing.T) {
 		},
 	})
 
-	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate)
-	assert.NotNil(t, response)
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, response)
 	assert.NotNil(t, err) //need to check the error message
-	assert.EqualValues(t, "", response)
 	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
 	assert.EqualValues(t, testutils.ErrorMessageAuthentication, err.Message())
 }
 
+//addMergedPRMocks mocks a PR of myuser/myrepo approved by a reviewer and merged by someone with write permission to the repo
+func addMergedPRMocks(number string) {
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/"+number, http.StatusOK,
+		`{"number":`+number+`,"state":"closed","merged":true,"title":"Add a feature","user":{"login":"author"},"head":{"sha":"HEAD`+number+`"},"merged_by":{"login":"merger"}}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/"+number+"/reviews?per_page=100", http.StatusOK,
+		`[{"id":1,"user":{"login":"reviewer"},"state":"APPROVED","commit_id":"HEAD`+number+`"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/collaborators/merger/permission", http.StatusOK, `{"permission":"write"}`)
+}
+
 func TestGetCodeReviewReportSuccessMergeCommit(t *testing.T) {
 	//need to have test data where there is a merge commit
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.Format(githubprovider.FmtGithubDate) + "&until=" + toDate.Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.Format(githubprovider.FmtGithubDate) + "&until=" + toDate.Format(githubprovider.FmtGithubDate) + "&per_page=100"
 
 	fmt.Println(urlForMock)
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
+
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
 		HTTPMethod: http.MethodGet,
@@ -664,18 +968,19 @@ func TestGetCodeReviewReportSuccessMergeCommit(t *testing.T) {
 	})
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls",
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
 			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
 		},
 	})
+	addMergedPRMocks("9")
 
-	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate)
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
-	assert.EqualValues(t, "#Total Commits: 1, #Merged Commits: 1,  #Commits with PRs: 1, #Commits with No PRs: 0", response)
+	assert.EqualValues(t, "#Branch: main, #Total Commits: 1, #Merged Commits: 1,  #Commits with PRs: 1, #Commits reviewed on other branches: 0, #Commits with No PRs: 0", response.Summary())
 }
 
 func TestGetCodeReviewReportSuccessCommitWithPR(t *testing.T) {
@@ -683,7 +988,16 @@ func TestGetCodeReviewReportSuccessCommitWithPR(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -695,19 +1009,381 @@ func TestGetCodeReviewReportSuccessCommitWithPR(t *testing.T) {
 	})
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls",
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
 			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
 		},
 	})
+	addMergedPRMocks("9")
 
-	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate)
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
-	assert.EqualValues(t, "#Total Commits: 1, #Merged Commits: 0,  #Commits with PRs: 1, #Commits with No PRs: 0", response)
+	assert.EqualValues(t, "#Branch: main, #Total Commits: 1, #Merged Commits: 0,  #Commits with PRs: 1, #Commits reviewed on other branches: 0, #Commits with No PRs: 0", response.Summary())
+	assert.EqualValues(t, "some loing id", response.Commits[0].AuthorID)
+	assert.EqualValues(t, "My Login ID", response.Commits[0].PullAuthor)
+	assert.EqualValues(t, "my login id", response.Commits[0].PullAuthorID)
+
+}
+
+func TestGetCodeReviewReportForPaths(t *testing.T) {
+	restclient.FlushMockups()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, []string{"services/[payments"})
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlForMock + "&path=services%2Fpayments",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleSliceNonMergeCommitResponsesMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
+		},
+	})
+	addMergedPRMocks("9")
+
+	response, err = RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, []string{"services/payments/"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"services/payments/"}, response.Paths)
+	assert.EqualValues(t, 1, response.TotalCommits)
+	assert.EqualValues(t, 1, response.TotalCommitsWithPR)
+}
+
+func TestGetCodeReviewReportByTeam(t *testing.T) {
+	restclient.FlushMockups()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+	SetTeamDirectory(&teamdomain.Directory{Teams: []teamdomain.Team{{Name: "payments", Members: []string{"My Login ID"}}}})
+	defer SetTeamDirectory(teamdomain.NewDirectory())
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlForMock,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleSliceNonMergeCommitResponsesMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
+		},
+	})
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusOK,
+		`{"number":9,"state":"closed","merged":true,"user":{"login":"My Login ID"},"merged_by":{"login":"my login id"}}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9/reviews?per_page=100", http.StatusOK, `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/collaborators/my login id/permission", http.StatusOK, `{"permission":"write"}`)
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "my login id", response.Commits[0].PullMergedBy)
+	assert.True(t, response.Commits[0].IsSelfMerged())
+	assert.EqualValues(t, 2, len(response.Teams))
+	assert.EqualValues(t, reportdomain.TeamReviewSummary{Team: "payments", TotalSelfMergedPRs: 1}, response.Teams[0])
+	assert.EqualValues(t, reportdomain.TeamReviewSummary{Team: teamdomain.Unassigned, TotalCommits: 1, TotalCommitsWithPR: 1}, response.Teams[1])
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlForMock,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleSliceNonMergeCommitResponsesMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
+		},
+	})
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/9", http.StatusNotFound, `{"message":"Not Found"}`)
+
+	response, err = RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetCodeReviewReportTickets(t *testing.T) {
+	ResetService()
+	restclient.FlushMockups()
+	_, cleanup := setupIssueTracker()
+	defer cleanup()
+	defer setupPolicy(policydomain.ReviewPolicy{Tickets: policydomain.TicketRules{Required: true, AllowedStates: []string{"In Progress"}}})()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[
+		{"sha":"SHA1","commit":{"message":"PAY-12 handle refunds","author":{"name":"alice"}}},
+		{"sha":"SHA2","commit":{"message":"Tidy up","author":{"name":"bob"}}},
+		{"sha":"SHA3","commit":{"message":"More work","author":{"name":"carol"}}}
+	]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA1/pulls?per_page=100", http.StatusOK, `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA2/pulls?per_page=100", http.StatusOK,
+		`[{"number":5,"state":"closed","title":"Fix the build","body":"Closes OPS-7","user":{"login":"bob"},"base":{"ref":"main"},"merge_commit_sha":"SHA2"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA3/pulls?per_page=100", http.StatusOK, `[]`)
+	addMergedPRMocks("5")
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.True(t, response.TicketsChecked)
+	assert.EqualValues(t, 1, response.TotalCommitsMissingTickets)
+	assert.EqualValues(t, 1, response.TotalCommitsWithInvalidTickets)
+	assert.EqualValues(t, ticketdomain.StatusValid, response.Commits[0].TicketStatus)
+	assert.EqualValues(t, ticketdomain.StatusInvalid, response.Commits[1].TicketStatus)
+	assert.EqualValues(t, ticketdomain.SourcePullBody, response.Commits[1].Tickets[0].Source)
+	assert.EqualValues(t, ticketdomain.StatusMissing, response.Commits[2].TicketStatus)
+}
+
+func TestGetCodeReviewReportSignatures(t *testing.T) {
+	ResetService()
+	restclient.FlushMockups()
+	defer setupPolicy(policydomain.ReviewPolicy{Signatures: policydomain.SignatureRules{Required: true, Exempt: []string{"renovate[bot]"}}})()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[
+		{"sha":"SHA1","commit":{"message":"signed","verification":{"verified":true,"reason":"valid"}}},
+		{"sha":"SHA2","commit":{"message":"not signed","verification":{"verified":false,"reason":"unsigned"}}},
+		{"sha":"SHA3","commit":{"message":"bad signature","verification":{"verified":false,"reason":"bad_email","signature":"sig"}}},
+		{"sha":"SHA4","author":{"login":"renovate[bot]"},"commit":{"message":"bump yaml","verification":{"verified":false,"reason":"unsigned"}}},
+		{"sha":"SHA5","commit":{"message":"recorded from a push event"}}
+	]`)
+	for _, SHA := range []string{"SHA1", "SHA2", "SHA3", "SHA4", "SHA5"} {
+		addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/"+SHA+"/pulls?per_page=100", http.StatusOK, `[]`)
+	}
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA5", http.StatusOK,
+		`{"sha":"SHA5","commit":{"message":"recorded from a push event","verification":{"verified":true,"reason":"valid"}},"parents":[{"sha":"SHA4"}],"stats":{"total":1}}`)
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.True(t, response.SignaturesChecked)
+	assert.EqualValues(t, 1, response.TotalUnsignedCommits)
+	assert.EqualValues(t, 1, response.TotalUnverifiedCommits)
+	assert.EqualValues(t, reportdomain.SignatureStatusVerified, response.Commits[0].SignatureStatus)
+	assert.EqualValues(t, reportdomain.SignatureStatusUnsigned, response.Commits[1].SignatureStatus)
+	assert.EqualValues(t, "bad_email", response.Commits[2].SignatureReason)
+	assert.EqualValues(t, reportdomain.SignatureStatusExempt, response.Commits[3].SignatureStatus)
+	assert.EqualValues(t, reportdomain.SignatureStatusVerified, response.Commits[4].SignatureStatus)
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[{"sha":"SHA5","commit":{"message":"recorded from a push event"}}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA5/pulls?per_page=100", http.StatusOK, `[]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA5", http.StatusNotFound, `{"message":"Not Found"}`)
+	response, err = RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetCodeReviewReportChecks(t *testing.T) {
+	ResetService()
+	restclient.FlushMockups()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredStatusChecks: []string{"ci/build", "ci/test"}})()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[
+		{"sha":"SHA1","commit":{"message":"Merge pull request #5 from myuser/feature"}},
+		{"sha":"SHA2","commit":{"message":"Add a feature"}},
+		{"sha":"SHA3","commit":{"message":"Fix the build"}},
+		{"sha":"SHA4","commit":{"message":"pushed straight to main"}}
+	]`)
+	pullFive := `[{"number":5,"state":"closed","title":"Add a feature","base":{"ref":"main"},"head":{"sha":"HEAD5"},"merge_commit_sha":"SHA1","merged_at":"2020-03-02T10:00:00Z"}]`
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA1/pulls?per_page=100", http.StatusOK, pullFive)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA2/pulls?per_page=100", http.StatusOK, pullFive)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA3/pulls?per_page=100", http.StatusOK,
+		`[{"number":6,"state":"closed","title":"Fix the build","base":{"ref":"main"},"head":{"sha":"HEAD6"},"merge_commit_sha":"SHA3","merged_at":"2020-03-03T10:00:00Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA4/pulls?per_page=100", http.StatusOK, `[]`)
+	//the test run of PR 5 failed and only passed when it was run again after the merge
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD5/status", http.StatusOK,
+		`{"state":"success","sha":"HEAD5","statuses":[{"context":"ci/build","state":"success","updated_at":"2020-03-02T09:00:00Z"}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD5/check-runs?per_page=100", http.StatusOK, `{"total_count":2,"check_runs":[
+		{"id":1,"name":"ci/test","status":"completed","conclusion":"failure","started_at":"2020-03-02T09:00:00Z","completed_at":"2020-03-02T09:10:00Z"},
+		{"id":2,"name":"ci/test","status":"completed","conclusion":"success","started_at":"2020-03-02T11:00:00Z","completed_at":"2020-03-02T11:10:00Z"}
+	]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD6/status", http.StatusOK,
+		`{"state":"pending","sha":"HEAD6","statuses":[{"context":"ci/build","state":"pending","updated_at":"2020-03-03T09:00:00Z"}]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD6/check-runs?per_page=100", http.StatusOK, `{"total_count":0,"check_runs":[]}`)
+	addMergedPRMocks("5")
+	addMergedPRMocks("6")
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.True(t, response.ChecksChecked)
+	assert.EqualValues(t, 2, response.TotalCommitsWithFailingChecks)
+	assert.EqualValues(t, 1, response.TotalCommitsWithPendingChecks)
+	assert.EqualValues(t, reportdomain.ChecksStatusFailing, response.Commits[0].ChecksStatus)
+	assert.EqualValues(t, []string{"ci/test (failure)"}, response.Commits[1].ChecksNotPassed)
+	assert.EqualValues(t, reportdomain.ChecksStatusPending, response.Commits[2].ChecksStatus)
+	assert.EqualValues(t, []string{"ci/build (pending)", "ci/test (missing)"}, response.Commits[2].ChecksNotPassed)
+	assert.EqualValues(t, "", response.Commits[3].ChecksStatus)
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[{"sha":"SHA1","commit":{"message":"Merge pull request #5 from myuser/feature"}}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA1/pulls?per_page=100", http.StatusOK, pullFive)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD5/status", http.StatusOK, `{"state":"success","sha":"HEAD5","statuses":[]}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/HEAD5/check-runs?per_page=100", http.StatusForbidden, `{"message":"Forbidden"}`)
+	addMergedPRMocks("5")
+	response, err = RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+}
 
+func TestGetCodeReviewReportBypassMerges(t *testing.T) {
+	ResetService()
+	restclient.FlushMockups()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 1, DetectBypassMerges: true})()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[
+		{"sha":"SHA1","commit":{"message":"Merge pull request #5 from myuser/feature"}},
+		{"sha":"SHA2","commit":{"message":"Add a feature"}},
+		{"sha":"SHA3","commit":{"message":"Fix the build"}},
+		{"sha":"SHA4","commit":{"message":"Tidy up"}}
+	]`)
+	pullFive := `[{"number":5,"state":"closed","title":"Add a feature","base":{"ref":"main"},"merge_commit_sha":"SHA1"}]`
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA1/pulls?per_page=100", http.StatusOK, pullFive)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA2/pulls?per_page=100", http.StatusOK, pullFive)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA3/pulls?per_page=100", http.StatusOK,
+		`[{"number":6,"state":"closed","title":"Fix the build","base":{"ref":"main"},"merge_commit_sha":"SHA3"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA4/pulls?per_page=100", http.StatusOK,
+		`[{"number":7,"state":"closed","title":"Tidy up","base":{"ref":"main"},"merge_commit_sha":"SHA4"}]`)
+	//PR 5 was merged without an approval, PR 6 by a site admin who is also an admin of the repo and PR 7 by someone who has since left
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/5", http.StatusOK,
+		`{"number":5,"state":"closed","title":"Add a feature","user":{"login":"alice"},"head":{"sha":"HEAD5"},"merged_by":{"login":"alice"},"merged_at":"2020-03-02T10:00:00Z"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/5/reviews?per_page=100", http.StatusOK,
+		`[{"id":1,"user":{"login":"bob"},"state":"COMMENTED","commit_id":"HEAD5"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/collaborators/alice/permission", http.StatusOK, `{"permission":"write"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/6", http.StatusOK,
+		`{"number":6,"state":"closed","title":"Fix the build","user":{"login":"bob"},"head":{"sha":"HEAD6"},"merged_by":{"login":"octocat","site_admin":true},"merged_at":"2020-03-03T10:00:00Z"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/6/reviews?per_page=100", http.StatusOK,
+		`[{"id":2,"user":{"login":"alice"},"state":"APPROVED","commit_id":"HEAD6"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/collaborators/octocat/permission", http.StatusOK, `{"permission":"admin"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/7", http.StatusOK,
+		`{"number":7,"state":"closed","title":"Tidy up","user":{"login":"bob"},"head":{"sha":"HEAD7"},"merged_by":{"login":"carol"},"merged_at":"2020-03-04T10:00:00Z"}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/7/reviews?per_page=100", http.StatusOK,
+		`[{"id":3,"user":{"login":"alice"},"state":"APPROVED","commit_id":"HEAD7"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/collaborators/carol/permission", http.StatusNotFound, `{"message":"Not Found"}`)
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.True(t, response.BypassMergesChecked)
+	assert.EqualValues(t, 2, response.TotalBypassMerges)
+	assert.EqualValues(t, reportdomain.BypassMerge{PullNumber: 5, PullTitle: "Add a feature", PullAuthor: "alice", MergedBy: "alice", MergedByID: "alice",
+		MergedAt: time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC), Approvers: []string{}, Reasons: []string{reportdomain.BypassReasonNoApproval}}, response.BypassMerges[0])
+	assert.EqualValues(t, 6, response.BypassMerges[1].PullNumber)
+	assert.EqualValues(t, []string{"alice"}, response.BypassMerges[1].Approvers)
+	assert.EqualValues(t, []string{reportdomain.BypassReasonSiteAdmin, reportdomain.BypassReasonRepoAdmin}, response.BypassMerges[1].Reasons)
+	assert.EqualValues(t, "carol", response.Commits[3].PullMergedBy)
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[{"sha":"SHA4","commit":{"message":"Tidy up"}}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA4/pulls?per_page=100", http.StatusOK,
+		`[{"number":7,"state":"closed","title":"Tidy up","base":{"ref":"main"},"merge_commit_sha":"SHA4"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/7", http.StatusOK,
+		`{"number":7,"state":"closed","title":"Tidy up","user":{"login":"bob"},"head":{"sha":"HEAD7"},"merged_by":{"login":"carol"}}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/7/reviews?per_page=100", http.StatusOK,
+		`[{"id":3,"user":{"login":"alice"},"state":"APPROVED","commit_id":"HEAD7"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/collaborators/carol/permission", http.StatusForbidden, `{"message":"Forbidden"}`)
+	//a permission that can't be read doesn't fail the report, the merge is listed so it can be checked by hand
+	response, err = RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, response.TotalBypassMerges)
+	assert.EqualValues(t, []string{reportdomain.BypassReasonPermissionUnknown}, response.BypassMerges[0].Reasons)
+}
+
+func TestGetCodeReviewReportBypassMergesNotChecked(t *testing.T) {
+	ResetService()
+	restclient.FlushMockups()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 1})()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	//only the repo, the commits and the PRs of the commits are needed
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[{"sha":"SHA4","commit":{"message":"Tidy up"}}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA4/pulls?per_page=100", http.StatusOK,
+		`[{"number":7,"state":"closed","title":"Tidy up","base":{"ref":"main"},"merge_commit_sha":"SHA4"}]`)
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, response.TotalCommitsWithPR)
+	assert.False(t, response.BypassMergesChecked)
+	assert.EqualValues(t, 0, response.TotalBypassMerges)
+}
+
+func TestGetCodeReviewReportRubberStamps(t *testing.T) {
+	ResetService()
+	restclient.FlushMockups()
+	defer setupPolicy(policydomain.ReviewPolicy{RequiredApprovals: 1,
+		RubberStamps: policydomain.RubberStampRules{Enabled: true, MinReviewSeconds: 300, LargeChangeLines: 500, MinSignals: 2}})()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo", http.StatusOK, `{"name":"myrepo","default_branch":"main"}`)
+	addComplianceMock(urlForMock, http.StatusOK, `[
+		{"sha":"SHA1","commit":{"message":"Merge pull request #5 from myuser/feature"}},
+		{"sha":"SHA2","commit":{"message":"Add a feature"}},
+		{"sha":"SHA3","commit":{"message":"pushed straight to main"}}
+	]`)
+	pullFive := `[{"number":5,"state":"closed","title":"Add a feature","base":{"ref":"main"},"merge_commit_sha":"SHA1"}]`
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA1/pulls?per_page=100", http.StatusOK, pullFive)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA2/pulls?per_page=100", http.StatusOK, pullFive)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/commits/SHA3/pulls?per_page=100", http.StatusOK, `[]`)
+	//bob approved a large change within a minute of it being opened
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/5", http.StatusOK,
+		`{"number":5,"state":"closed","title":"Add a feature","user":{"login":"alice"},"head":{"sha":"HEAD5"},"merged_by":{"login":"alice"},"created_at":"2020-03-02T09:00:00Z","additions":1500}`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/pulls/5/reviews?per_page=100", http.StatusOK,
+		`[{"id":1,"user":{"login":"bob"},"state":"APPROVED","commit_id":"HEAD5","submitted_at":"2020-03-02T09:00:45Z"}]`)
+	addComplianceMock("https://api.github.com/repos/myuser/myrepo/collaborators/alice/permission", http.StatusOK, `{"permission":"write"}`)
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, err)
+	assert.True(t, response.RubberStampsChecked)
+	assert.EqualValues(t, 0, response.TotalBypassMerges)
+	assert.EqualValues(t, 1, response.TotalSuspiciousApprovals)
+	assert.EqualValues(t, reportdomain.SuspiciousApproval{PullNumber: 5, PullTitle: "Add a feature", Approver: "bob", ApprovedAt: time.Date(2020, 3, 2, 9, 0, 45, 0, time.UTC),
+		ReviewSeconds: 45, ChangedLines: 1500, Signals: []string{"approved 45s after the PR was opened", "the PR changes 1500 lines"}}, response.SuspiciousApprovals[0])
 }
 
 func TestGetCodeReviewReportSuccessCommitWithNoPR(t *testing.T) {
@@ -715,7 +1391,16 @@ func TestGetCodeReviewReportSuccessCommitWithNoPR(t *testing.T) {
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -727,7 +1412,7 @@ func TestGetCodeReviewReportSuccessCommitWithNoPR(t *testing.T) {
 	})
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls",
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
@@ -735,20 +1420,28 @@ func TestGetCodeReviewReportSuccessCommitWithNoPR(t *testing.T) {
 		},
 	})
 
-	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate)
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
-	assert.EqualValues(t, "#Total Commits: 1, #Merged Commits: 0,  #Commits with PRs: 0, #Commits with No PRs: 1", response)
+	assert.EqualValues(t, "#Branch: main, #Total Commits: 1, #Merged Commits: 0,  #Commits with PRs: 0, #Commits reviewed on other branches: 0, #Commits with No PRs: 1", response.Summary())
 
 }
 
-
 func TestGetCodeReviewReportSuccessCommitWithNonApprovedPR(t *testing.T) {
 	//need to have test data where there is a commit with an unapproved PR
 	restclient.FlushMockups()
 	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
 	toDate := time.Now().UTC()
-	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate)
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
 
 	restclient.AddMockup(restclient.Mock{
 		URL:        urlForMock,
@@ -760,7 +1453,7 @@ func TestGetCodeReviewReportSuccessCommitWithNonApprovedPR(t *testing.T) {
 	})
 
 	restclient.AddMockup(restclient.Mock{
-		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls",
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
 		HTTPMethod: http.MethodGet,
 		Response: &http.Response{
 			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
@@ -768,9 +1461,154 @@ func TestGetCodeReviewReportSuccessCommitWithNonApprovedPR(t *testing.T) {
 		},
 	})
 
-	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate)
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
-	assert.EqualValues(t, "#Total Commits: 1, #Merged Commits: 1,  #Commits with PRs: 0, #Commits with No PRs: 1", response)
+	assert.EqualValues(t, "#Branch: main, #Total Commits: 1, #Merged Commits: 1,  #Commits with PRs: 0, #Commits reviewed on other branches: 0, #Commits with No PRs: 1", response.Summary())
 
 }
+
+func TestGetCodeReviewReportSuccessCommitWithPROnOtherBranch(t *testing.T) {
+	//need to have test data where the only PR for the commit was merged into a different branch
+	restclient.FlushMockups()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlForMock,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleSliceNonMergeCommitResponsesMessage(),
+		},
+	})
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPROnOtherBranchForCommitResponsesMessage(),
+		},
+	})
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.NotNil(t, response)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "#Branch: main, #Total Commits: 1, #Merged Commits: 0,  #Commits with PRs: 0, #Commits reviewed on other branches: 1, #Commits with No PRs: 0", response.Summary())
+	assert.EqualValues(t, reportdomain.ReviewStatusReviewedOnOtherBranch, response.Commits[0].ReviewStatus)
+	assert.EqualValues(t, 10, response.Commits[0].PullNumber)
+	assert.EqualValues(t, "develop", response.Commits[0].PullBaseRef)
+}
+
+//addCodeReviewMocksForUnlistedMergeCommit sets up a commit whose PR was merged into main via a merge commit
+//that isn't in the list of commits returned for the period, so the compare API has to be used
+func addCodeReviewMocksForUnlistedMergeCommit(fromDate time.Time, toDate time.Time) {
+	urlForMock := "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + fromDate.UTC().Format(githubprovider.FmtGithubDate) + "&until=" + toDate.UTC().Format(githubprovider.FmtGithubDate) + "&per_page=100"
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlForMock,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataSingleCommitResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleSliceNonMergeCommitResponsesMessage(),
+		},
+	})
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"url":"some URL","id":123456,"number":9,"state":"closed","title":"Title of the PR","merged_at":"2019-10-28T14:30:10.578369Z","merge_commit_sha":"MERGE123456","base":{"label":"myuser:main","ref":"main","sha":"ABCDEF123456768"}}]`)),
+		},
+	})
+}
+
+func TestGetCodeReviewReportSuccessMergeCommitReachable(t *testing.T) {
+	restclient.FlushMockups()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	addCodeReviewMocksForUnlistedMergeCommit(fromDate, toDate)
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/main...MERGE123456",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"behind","ahead_by":0,"behind_by":2,"total_commits":0,"commits":[]}`)),
+		},
+	})
+	addMergedPRMocks("9")
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.NotNil(t, response)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, response.TotalCommitsWithPR)
+	assert.EqualValues(t, 0, response.TotalCommitsReviewedOnOtherBranches)
+	assert.EqualValues(t, reportdomain.ReviewStatusReviewed, response.Commits[0].ReviewStatus)
+}
+
+func TestGetCodeReviewReportSuccessMergeCommitNotReachable(t *testing.T) {
+	restclient.FlushMockups()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	addCodeReviewMocksForUnlistedMergeCommit(fromDate, toDate)
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/main...MERGE123456",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"diverged","ahead_by":1,"behind_by":2,"total_commits":1,"commits":[]}`)),
+		},
+	})
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.NotNil(t, response)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, response.TotalCommitsWithPR)
+	assert.EqualValues(t, 1, response.TotalCommitsReviewedOnOtherBranches)
+	assert.EqualValues(t, reportdomain.ReviewStatusReviewedOnOtherBranch, response.Commits[0].ReviewStatus)
+}
+
+func TestGetCodeReviewReportErrorComparingCommits(t *testing.T) {
+	restclient.FlushMockups()
+	fromDate := time.Now().UTC().AddDate(-1, 0, 0)
+	toDate := time.Now().UTC()
+	addCodeReviewMocksForUnlistedMergeCommit(fromDate, toDate)
+
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/compare/main...MERGE123456",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	response, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", fromDate, toDate, nil)
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageAuthentication, err.Message())
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+//findSuspiciousApprovals returns the approvals of the PR that look like rubber stamps, only the approvals counting towards the policy are checked
+//the comments the approvers left on the changes are only read from Github when the rules use them
+func findSuspiciousApprovals(owner string, repo string, pullRequest *githubdomain.GetSinglePullRequestResponse, reviews []githubdomain.PullRequestReview, approvers []string, rules *policydomain.RubberStampRules) ([]reportdomain.SuspiciousApproval, errors.APIError) {
+	if len(approvers) == 0 {
+		return nil, nil
+	}
+
+	commentsBy := make(map[string]int)
+	if rules.MinReviewComments > 0 {
+		comments, errProvider := githubprovider.GetPRReviewComments(config.GetGithubAccessToken(), owner, repo, strconv.FormatInt(pullRequest.Number, 10))
+		if errProvider != nil {
+			return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+		}
+		for _, comment := range comments {
+			commentsBy[strings.ToLower(comment.User.Login)]++
+		}
+	}
+
+	var result []reportdomain.SuspiciousApproval
+	for _, approver := range approvers {
+		review := getLatestApproval(reviews, approver)
+		if review == nil {
+			continue
+		}
+		if approval := reportdomain.NewSuspiciousApproval(pullRequest, review, commentsBy[strings.ToLower(approver)], rules); approval != nil {
+			result = append(result, *approval)
+		}
+	}
+	return result, nil
+}
+
+//getLatestApproval returns the latest approval by the user, the reviews are oldest first
+func getLatestApproval(reviews []githubdomain.PullRequestReview, login string) *githubdomain.PullRequestReview {
+	var result *githubdomain.PullRequestReview
+	for counter := range reviews {
+		if reviews[counter].State == githubdomain.ReviewStateApproved && strings.EqualFold(reviews[counter].User.Login, login) {
+			result = &reviews[counter]
+		}
+	}
+	return result
+}
+
+//rubberStampChecker finds the approvals of the PRs merged into the audited branch that look like rubber stamps, each PR is only checked once
+type rubberStampChecker struct {
+	pulls      *mergedPulls
+	policy     *policydomain.ReviewPolicy
+	suspicious map[int64][]reportdomain.SuspiciousApproval
+}
+
+func newRubberStampChecker(pulls *mergedPulls, policy *policydomain.ReviewPolicy) *rubberStampChecker {
+	return &rubberStampChecker{
+		pulls:      pulls,
+		policy:     policy,
+		suspicious: make(map[int64][]reportdomain.SuspiciousApproval),
+	}
+}
+
+//check returns the approvals of the merged PR that look like rubber stamps
+func (c *rubberStampChecker) check(pullNumber int64) ([]reportdomain.SuspiciousApproval, errors.APIError) {
+	if approvals, ok := c.suspicious[pullNumber]; ok {
+		return approvals, nil
+	}
+
+	pullRequest, reviews, err := c.pulls.get(pullNumber)
+	if err != nil {
+		return nil, err
+	}
+	approvers, _, _ := getLatestReviews(reviews, pullRequest, c.policy.DismissStaleApprovals)
+	approvals, err := findSuspiciousApprovals(c.pulls.owner, c.pulls.repo, pullRequest, reviews, approvers, &c.policy.RubberStamps)
+	if err != nil {
+		return nil, err
+	}
+	c.suspicious[pullNumber] = approvals
+	return approvals, nil
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/robfig/cron/v3"
+)
+
+type schedulerService struct {
+	mutex     sync.Mutex
+	startedAt time.Time
+	entries   []*scheduleEntry
+}
+
+//scheduleEntry keeps track of when a schedule is next due and how it last ran
+type scheduleEntry struct {
+	schedule scheduledomain.Schedule
+	cron     cron.Schedule
+	next     time.Time
+	lastRun  *scheduledomain.ScheduleRun
+}
+
+type schedulerServiceInterface interface {
+	GetSchedules() []scheduledomain.ScheduleStatus
+	GetScheduleRuns(name string, limit string) ([]scheduledomain.ScheduleRun, errors.APIError)
+}
+
+const (
+	errorScheduleNotFound      = "the schedule does not exist"
+	errorInvalidRunsLimit      = "invalid limit, expected a number between 1 and 100"
+	errorReadingScheduleRuns   = "unable to read the runs of the schedule"
+	defaultScheduleRunsLimit   = 20
+	maxScheduleRunsLimit       = 100
+	schedulerTick              = time.Minute
+	maxMissedRuns              = 100
+	maxPreviousRunLookback     = 10 * 365 * 24 * time.Hour
+	initialPreviousRunLookback = time.Hour
+)
+
+//SchedulerService defines the scheduler service to use
+var SchedulerService schedulerServiceInterface
+
+func init() {
+	SchedulerService = &schedulerService{}
+}
+
+//ResetSchedulerService calls the init function again
+func ResetSchedulerService() {
+	SchedulerService = &schedulerService{}
+}
+
+//newSchedulerService creates a scheduler for the schedules, each one is next due after its last recorded run
+//so the runs missed while the app wasn't running are picked up, a schedule with no history is next due after now
+func newSchedulerService(schedules []scheduledomain.Schedule, now time.Time) (*schedulerService, error) {
+	scheduler := &schedulerService{startedAt: now}
+	for _, schedule := range schedules {
+		cronSchedule, err := config.ParseScheduleCron(&schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron %s for %s: %s", schedule.Cron, schedule.Name, err.Error())
+		}
+
+		entry := &scheduleEntry{schedule: schedule, cron: cronSchedule, next: cronSchedule.Next(now)}
+		runs, errStore := DataStore.GetScheduleRuns(schedule.Name, 1)
+		logStoreError("read the schedule runs from", errStore)
+		if errStore == nil && len(runs) > 0 {
+			entry.lastRun = &runs[0]
+			entry.next = cronSchedule.Next(runs[0].ScheduledFor)
+		}
+		scheduler.entries = append(scheduler.entries, entry)
+	}
+	return scheduler, nil
+}
+
+//GetSchedules returns each schedule along with when it next runs and how it last ran
+func (s *schedulerService) GetSchedules() []scheduledomain.ScheduleStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make([]scheduledomain.ScheduleStatus, 0, len(s.entries))
+	for _, entry := range s.entries {
+		result = append(result, scheduledomain.ScheduleStatus{Schedule: entry.schedule, NextRun: entry.next, LastRun: entry.lastRun})
+	}
+	return result
+}
+
+//GetScheduleRuns returns the most recent runs of the schedule, newest first
+func (s *schedulerService) GetScheduleRuns(name string, limit string) ([]scheduledomain.ScheduleRun, errors.APIError) {
+	if s.getEntry(strings.TrimSpace(name)) == nil {
+		return nil, errors.NewNotFoundAPIError(errorScheduleNotFound)
+	}
+
+	runsLimit := defaultScheduleRunsLimit
+	if limit = strings.TrimSpace(limit); limit != "" {
+		var err error
+		runsLimit, err = strconv.Atoi(limit)
+		if err != nil || runsLimit < 1 || runsLimit > maxScheduleRunsLimit {
+			return nil, errors.NewBadRequestError(errorInvalidRunsLimit)
+		}
+	}
+
+	runs, errStore := DataStore.GetScheduleRuns(strings.TrimSpace(name), runsLimit)
+	if errStore == store.ErrNotFound {
+		return make([]scheduledomain.ScheduleRun, 0), nil
+	}
+	if errStore != nil {
+		logStoreError("read the schedule runs from", errStore)
+		return nil, errors.NewInternalServerError(errorReadingScheduleRuns)
+	}
+	return runs, nil
+}
+
+//getEntry returns the schedule with the given name, nil if there isn't one
+func (s *schedulerService) getEntry(name string) *scheduleEntry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, entry := range s.entries {
+		if entry.schedule.Name == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+//getDueRuns returns the times each schedule was due to run up to now and moves the schedule on to its next run
+//only the latest maxMissedRuns are returned if the app has been down for a long time
+func (s *schedulerService) getDueRuns(entry *scheduleEntry, now time.Time) []time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var due []time.Time
+	//the cron library returns a zero time for a schedule that never matches, such as the 30th of February
+	for next := entry.next; !next.IsZero() && !next.After(now); next = entry.cron.Next(next) {
+		due = append(due, next)
+		if len(due) > maxMissedRuns {
+			due = due[1:]
+		}
+	}
+	entry.next = entry.cron.Next(now)
+	return due
+}
+
+//setLastRun records the latest run of the schedule
+func (s *schedulerService) setLastRun(entry *scheduleEntry, run *scheduledomain.ScheduleRun) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry.lastRun = run
+}
+
+//runDue runs every schedule that has become due
+//a run due before the scheduler started was missed while the app wasn't running, these are handled as the schedule says:
+//skip records them as skipped, run_once only runs the latest and run_all runs each of them
+func (s *schedulerService) runDue(now time.Time) {
+	for _, entry := range s.entries {
+		due := s.getDueRuns(entry, now)
+		for counter, scheduledFor := range due {
+			missed := scheduledFor.Before(s.startedAt)
+			var run *scheduledomain.ScheduleRun
+			switch {
+			case !missed, entry.schedule.MissedRuns == scheduledomain.MissedRunsRunAll:
+				run = runSchedule(entry, scheduledFor, missed)
+			case entry.schedule.MissedRuns == scheduledomain.MissedRunsRunOnce && s.isLastMissedRun(due, counter):
+				run = runSchedule(entry, scheduledFor, missed)
+			default:
+				run = &scheduledomain.ScheduleRun{
+					Schedule:     entry.schedule.Name,
+					ScheduledFor: scheduledFor,
+					FromDate:     getPreviousRun(entry.cron, scheduledFor),
+					StartedAt:    time.Now().UTC(),
+					FinishedAt:   time.Now().UTC(),
+					Missed:       true,
+					Status:       scheduledomain.RunStatusSkipped,
+				}
+			}
+
+			logStoreError("save the schedule run to", DataStore.SaveScheduleRun(run))
+			s.setLastRun(entry, run)
+		}
+	}
+}
+
+//isLastMissedRun determines if the run is the latest of the missed runs
+func (s *schedulerService) isLastMissedRun(due []time.Time, counter int) bool {
+	return counter == len(due)-1 || !due[counter+1].Before(s.startedAt)
+}
+
+//getPreviousRun returns the time the schedule was due before the given time, the cron library only works forwards
+//so this looks back over an ever larger period until a run is found
+//a schedule that didn't run in the last ten years reports on the year before
+func getPreviousRun(cronSchedule cron.Schedule, scheduledFor time.Time) time.Time {
+	for lookback := initialPreviousRunLookback; lookback <= maxPreviousRunLookback; lookback *= 2 {
+		previous := time.Time{}
+		for next := cronSchedule.Next(scheduledFor.Add(-lookback)); !next.IsZero() && next.Before(scheduledFor); next = cronSchedule.Next(next) {
+			previous = next
+		}
+		if !previous.IsZero() {
+			return previous
+		}
+	}
+	return scheduledFor.AddDate(-1, 0, 0)
+}
+
+//runSchedule runs the report for each repo of the schedule over the period since its previous run
+//a failure for one repo doesn't stop the others, the run is then recorded as partial
+//each report is passed to the notifier, a failure to notify is recorded but doesn't change the status of the run
+func runSchedule(entry *scheduleEntry, scheduledFor time.Time, missed bool) *scheduledomain.ScheduleRun {
+	run := &scheduledomain.ScheduleRun{
+		Schedule:     entry.schedule.Name,
+		ScheduledFor: scheduledFor,
+		FromDate:     getPreviousRun(entry.cron, scheduledFor),
+		StartedAt:    time.Now().UTC(),
+		Missed:       missed,
+		Reports:      make([]reportdomain.CodeReviewReport, 0),
+	}
+
+	repos, err := getScheduleRepos(&entry.schedule)
+	if err != nil {
+		run.Errors = append(run.Errors, fmt.Sprintf("%s: %s", entry.schedule.Owner, err.Message()))
+	}
+	for _, repo := range repos {
+		report, err := RepositoryService.GetCodeReviewReport(entry.schedule.Owner, repo, run.FromDate, scheduledFor, entry.schedule.Paths)
+		if err != nil {
+			run.Errors = append(run.Errors, fmt.Sprintf("%s: %s", repo, err.Message()))
+			continue
+		}
+		run.Reports = append(run.Reports, *report)
+		if err := ReportNotifier.Notify(report); err != nil {
+			run.NotificationErrors = append(run.NotificationErrors, fmt.Sprintf("%s: %s", repo, err.Error()))
+		}
+	}
+
+	run.FinishedAt = time.Now().UTC()
+	switch {
+	case len(run.Errors) == 0:
+		run.Status = scheduledomain.RunStatusSucceeded
+	case len(run.Reports) > 0:
+		run.Status = scheduledomain.RunStatusPartial
+	default:
+		run.Status = scheduledomain.RunStatusFailed
+	}
+	if run.Status != scheduledomain.RunStatusSucceeded {
+		log.Println("scheduled report", entry.schedule.Name, "did not succeed:", strings.Join(run.Errors, ", "))
+	}
+	return run
+}
+
+//getScheduleRepos returns the repos the schedule reports on, an org wide schedule covers every repo that isn't archived
+func getScheduleRepos(schedule *scheduledomain.Schedule) ([]string, errors.APIError) {
+	if !schedule.IsOrgWide() {
+		return []string{schedule.Repo}, nil
+	}
+
+	orgRepos, errProvider := githubprovider.GetOrgRepos(config.GetGithubAccessToken(), schedule.Owner)
+	if errProvider != nil {
+		return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+
+	var repos []string
+	for _, orgRepo := range orgRepos {
+		if !orgRepo.Archived {
+			repos = append(repos, orgRepo.Name)
+		}
+	}
+	return repos, nil
+}
+
+//StartScheduler runs the scheduled reports until the returned function is called
+//any runs missed while the app wasn't running are dealt with straight away
+func StartScheduler(schedules []scheduledomain.Schedule) (func(), error) {
+	scheduler, err := newSchedulerService(schedules, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	SchedulerService = scheduler
+
+	ticker := time.NewTicker(schedulerTick)
+	done := make(chan bool)
+
+	go func() {
+		scheduler.runDue(time.Now())
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				scheduler.runDue(now)
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}, nil
+}
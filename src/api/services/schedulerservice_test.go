@@ -0,0 +1,308 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/reportdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/notifications"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+//reportServiceRecorder records the code review reports that are run, reports for the failing repo return an error
+type reportServiceRecorder struct {
+	reposServiceInterface
+	failingRepo string
+	reports     []reportdomain.CodeReviewReport
+}
+
+func (s *reportServiceRecorder) GetCodeReviewReport(owner string, repo string, fromDate time.Time, endDate time.Time, paths []string) (*reportdomain.CodeReviewReport, errors.APIError) {
+	if repo == s.failingRepo {
+		return nil, errors.NewNotFoundAPIError("Not Found")
+	}
+	report := reportdomain.CodeReviewReport{Owner: owner, Repo: repo, FromDate: fromDate, ToDate: endDate, Paths: paths}
+	s.reports = append(s.reports, report)
+	return &report, nil
+}
+
+//setupReportRecorder replaces the repository service with a recorder, the returned function puts it back
+func setupReportRecorder(failingRepo string) (*reportServiceRecorder, func()) {
+	recorder := &reportServiceRecorder{failingRepo: failingRepo}
+	RepositoryService = recorder
+	return recorder, ResetService
+}
+
+func getTestSchedule(missedRuns string) scheduledomain.Schedule {
+	return scheduledomain.Schedule{
+		Name:       "daily",
+		Cron:       "0 9 * * *",
+		Timezone:   "Europe/London",
+		Report:     scheduledomain.ReportCodeReview,
+		Owner:      "myuser",
+		Repo:       "myrepo",
+		MissedRuns: missedRuns,
+	}
+}
+
+func TestGetPreviousRun(t *testing.T) {
+	schedule := getTestSchedule(scheduledomain.MissedRunsSkip)
+	daily, err := config.ParseScheduleCron(&schedule)
+	assert.Nil(t, err)
+	scheduledFor := time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC)
+	assert.True(t, scheduledFor.AddDate(0, 0, -1).Equal(getPreviousRun(daily, scheduledFor)))
+
+	schedule.Cron = "0 6 1 1 *"
+	yearly, err := config.ParseScheduleCron(&schedule)
+	assert.Nil(t, err)
+	scheduledFor = time.Date(2020, 1, 1, 6, 0, 0, 0, time.UTC)
+	assert.True(t, time.Date(2019, 1, 1, 6, 0, 0, 0, time.UTC).Equal(getPreviousRun(yearly, scheduledFor)))
+}
+
+func TestGetSchedulesNoneConfigured(t *testing.T) {
+	ResetSchedulerService()
+	assert.EqualValues(t, 0, len(SchedulerService.GetSchedules()))
+
+	runs, err := SchedulerService.GetScheduleRuns("daily", "")
+	assert.Nil(t, runs)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestGetScheduleRunsInvalidLimit(t *testing.T) {
+	scheduler, err := newSchedulerService([]scheduledomain.Schedule{getTestSchedule(scheduledomain.MissedRunsSkip)}, time.Now())
+	assert.Nil(t, err)
+
+	for _, limit := range []string{"abc", "0", "101"} {
+		runs, err := scheduler.GetScheduleRuns("daily", limit)
+		assert.Nil(t, runs)
+		assert.NotNil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	}
+
+	//nothing is stored without a data store
+	runs, err := scheduler.GetScheduleRuns("daily", "5")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(runs))
+}
+
+func TestNewSchedulerServiceInvalidCron(t *testing.T) {
+	schedule := getTestSchedule(scheduledomain.MissedRunsSkip)
+	schedule.Cron = "not a cron"
+	scheduler, err := newSchedulerService([]scheduledomain.Schedule{schedule}, time.Now())
+	assert.Nil(t, scheduler)
+	assert.NotNil(t, err)
+}
+
+func TestRunDueRunsScheduleOnTime(t *testing.T) {
+	recorder, reset := setupReportRecorder("")
+	defer reset()
+	defer setupTestDataStore(t)()
+
+	started := time.Date(2020, 1, 10, 8, 0, 0, 0, time.UTC)
+	scheduler, err := newSchedulerService([]scheduledomain.Schedule{getTestSchedule(scheduledomain.MissedRunsSkip)}, started)
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC).Equal(scheduler.GetSchedules()[0].NextRun))
+
+	//nothing is due yet
+	scheduler.runDue(started.Add(30 * time.Minute))
+	assert.EqualValues(t, 0, len(recorder.reports))
+
+	scheduler.runDue(started.Add(time.Hour))
+	assert.EqualValues(t, 1, len(recorder.reports))
+	assert.True(t, time.Date(2020, 1, 9, 9, 0, 0, 0, time.UTC).Equal(recorder.reports[0].FromDate))
+	assert.True(t, time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC).Equal(recorder.reports[0].ToDate))
+
+	status := scheduler.GetSchedules()[0]
+	assert.True(t, time.Date(2020, 1, 11, 9, 0, 0, 0, time.UTC).Equal(status.NextRun))
+	assert.EqualValues(t, scheduledomain.RunStatusSucceeded, status.LastRun.Status)
+	assert.False(t, status.LastRun.Missed)
+
+	runs, errRuns := scheduler.GetScheduleRuns("daily", "")
+	assert.Nil(t, errRuns)
+	assert.EqualValues(t, 1, len(runs))
+	assert.EqualValues(t, "myrepo", runs[0].Reports[0].Repo)
+}
+
+//runMissedSchedule runs the schedule on the 10th then again three days later, as if the app had been down in between
+func runMissedSchedule(t *testing.T, missedRuns string) []scheduledomain.ScheduleRun {
+	schedule := getTestSchedule(missedRuns)
+	scheduler, err := newSchedulerService([]scheduledomain.Schedule{schedule}, time.Date(2020, 1, 10, 8, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	scheduler.runDue(time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC))
+
+	restarted := time.Date(2020, 1, 13, 12, 0, 0, 0, time.UTC)
+	scheduler, err = newSchedulerService([]scheduledomain.Schedule{schedule}, restarted)
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2020, 1, 11, 9, 0, 0, 0, time.UTC).Equal(scheduler.GetSchedules()[0].NextRun))
+	scheduler.runDue(restarted)
+	assert.True(t, time.Date(2020, 1, 14, 9, 0, 0, 0, time.UTC).Equal(scheduler.GetSchedules()[0].NextRun))
+
+	runs, errRuns := scheduler.GetScheduleRuns("daily", "")
+	assert.Nil(t, errRuns)
+	return runs
+}
+
+func TestRunDueMissedRunsSkipped(t *testing.T) {
+	recorder, reset := setupReportRecorder("")
+	defer reset()
+	defer setupTestDataStore(t)()
+
+	runs := runMissedSchedule(t, scheduledomain.MissedRunsSkip)
+	assert.EqualValues(t, 1, len(recorder.reports))
+	assert.EqualValues(t, 4, len(runs))
+	for _, run := range runs[:3] {
+		assert.True(t, run.Missed)
+		assert.EqualValues(t, scheduledomain.RunStatusSkipped, run.Status)
+	}
+	assert.True(t, time.Date(2020, 1, 13, 9, 0, 0, 0, time.UTC).Equal(runs[0].ScheduledFor))
+	assert.EqualValues(t, scheduledomain.RunStatusSucceeded, runs[3].Status)
+}
+
+func TestRunDueMissedRunsRunOnce(t *testing.T) {
+	recorder, reset := setupReportRecorder("")
+	defer reset()
+	defer setupTestDataStore(t)()
+
+	runs := runMissedSchedule(t, scheduledomain.MissedRunsRunOnce)
+	assert.EqualValues(t, 2, len(recorder.reports))
+	assert.True(t, time.Date(2020, 1, 13, 9, 0, 0, 0, time.UTC).Equal(recorder.reports[1].ToDate))
+	assert.EqualValues(t, 4, len(runs))
+	assert.True(t, runs[0].Missed)
+	assert.EqualValues(t, scheduledomain.RunStatusSucceeded, runs[0].Status)
+	assert.EqualValues(t, scheduledomain.RunStatusSkipped, runs[1].Status)
+	assert.EqualValues(t, scheduledomain.RunStatusSkipped, runs[2].Status)
+}
+
+func TestRunDueMissedRunsRunAll(t *testing.T) {
+	recorder, reset := setupReportRecorder("")
+	defer reset()
+	defer setupTestDataStore(t)()
+
+	runs := runMissedSchedule(t, scheduledomain.MissedRunsRunAll)
+	assert.EqualValues(t, 4, len(recorder.reports))
+	for _, run := range runs {
+		assert.EqualValues(t, scheduledomain.RunStatusSucceeded, run.Status)
+	}
+	assert.True(t, runs[2].Missed)
+	assert.False(t, runs[3].Missed)
+}
+
+func TestRunScheduleOrgWide(t *testing.T) {
+	_, reset := setupReportRecorder("broken")
+	defer reset()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/repos?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataOrgReposResponseStatusCode(),
+			Body:       testutils.GetMockDataOrgReposResponseMessage(),
+		},
+	})
+
+	schedule := getTestSchedule(scheduledomain.MissedRunsSkip)
+	schedule.Owner = "myorg"
+	schedule.Repo = ""
+	scheduler, err := newSchedulerService([]scheduledomain.Schedule{schedule}, time.Now())
+	assert.Nil(t, err)
+
+	run := runSchedule(scheduler.entries[0], time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC), false)
+	assert.EqualValues(t, scheduledomain.RunStatusPartial, run.Status)
+	assert.EqualValues(t, 1, len(run.Reports))
+	assert.EqualValues(t, "myrepo", run.Reports[0].Repo)
+	assert.EqualValues(t, []string{"broken: Not Found"}, run.Errors)
+}
+
+func TestRunScheduleOrgReposError(t *testing.T) {
+	_, reset := setupReportRecorder("")
+	defer reset()
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/repos?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	schedule := getTestSchedule(scheduledomain.MissedRunsSkip)
+	schedule.Owner = "myorg"
+	schedule.Repo = ""
+	scheduler, err := newSchedulerService([]scheduledomain.Schedule{schedule}, time.Now())
+	assert.Nil(t, err)
+
+	run := runSchedule(scheduler.entries[0], time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC), false)
+	assert.EqualValues(t, scheduledomain.RunStatusFailed, run.Status)
+	assert.EqualValues(t, 0, len(run.Reports))
+	assert.EqualValues(t, []string{"myorg: " + testutils.ErrorMessageAuthentication}, run.Errors)
+}
+
+func TestStartScheduler(t *testing.T) {
+	defer ResetSchedulerService()
+
+	schedule := getTestSchedule(scheduledomain.MissedRunsSkip)
+	stop, err := StartScheduler([]scheduledomain.Schedule{schedule})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(SchedulerService.GetSchedules()))
+	assert.EqualValues(t, "daily", SchedulerService.GetSchedules()[0].Name)
+	stop()
+
+	schedule.Timezone = "Mars/Olympus"
+	stop, err = StartScheduler([]scheduledomain.Schedule{schedule})
+	assert.Nil(t, stop)
+	assert.NotNil(t, err)
+}
+
+//reportNotifierRecorder records the reports it is told about, failing for the given repo
+type reportNotifierRecorder struct {
+	failingRepo string
+	notified    []string
+}
+
+func (n *reportNotifierRecorder) Notify(report *reportdomain.CodeReviewReport) error {
+	if report.Repo == n.failingRepo {
+		return fmt.Errorf("webhook returned status 500")
+	}
+	n.notified = append(n.notified, report.Repo)
+	return nil
+}
+
+func TestRunScheduleNotifies(t *testing.T) {
+	_, reset := setupReportRecorder("")
+	defer reset()
+	notifier := &reportNotifierRecorder{failingRepo: "broken"}
+	SetReportNotifier(notifier)
+	defer SetReportNotifier(notifications.NewDisabledNotifier())
+
+	restclient.FlushMockups()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/orgs/myorg/repos?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataOrgReposResponseStatusCode(),
+			Body:       testutils.GetMockDataOrgReposResponseMessage(),
+		},
+	})
+
+	schedule := getTestSchedule(scheduledomain.MissedRunsSkip)
+	schedule.Owner = "myorg"
+	schedule.Repo = ""
+	scheduler, err := newSchedulerService([]scheduledomain.Schedule{schedule}, time.Now())
+	assert.Nil(t, err)
+
+	run := runSchedule(scheduler.entries[0], time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC), false)
+	assert.EqualValues(t, scheduledomain.RunStatusSucceeded, run.Status)
+	assert.EqualValues(t, 2, len(run.Reports))
+	assert.EqualValues(t, []string{"myrepo"}, notifier.notified)
+	assert.EqualValues(t, []string{"broken: webhook returned status 500"}, run.NotificationErrors)
+}
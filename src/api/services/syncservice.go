@@ -0,0 +1,227 @@
+package services
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/syncdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+type syncService struct {
+	mutex   sync.Mutex
+	running map[string]bool
+}
+
+type syncServiceInterface interface {
+	SyncRepo(owner string, repo string) (*syncdomain.SyncState, errors.APIError)
+	GetSyncState(owner string, repo string) (*syncdomain.SyncState, errors.APIError)
+}
+
+const (
+	errorSyncInProgress = "a sync of the repo is already in progress"
+	errorRepoNotSynced  = "the repo has not been synced"
+	errorSavingSync     = "unable to save the sync state"
+)
+
+//commitsOverlap is how far back before the last sync the commits are fetched again
+//Github filters commits on their date rather than when they were pushed so a commit pushed with an older date is
+//only picked up by a sync if it is dated within the overlap, older ones are left to the push webhooks
+const commitsOverlap = 7 * 24 * time.Hour
+
+//SyncService defines the sync service to use
+var SyncService syncServiceInterface
+
+func init() {
+	SyncService = newSyncService()
+}
+
+//ResetSyncService calls the init function again
+func ResetSyncService() {
+	SyncService = newSyncService()
+}
+
+func newSyncService() *syncService {
+	return &syncService{running: make(map[string]bool)}
+}
+
+//start marks the repo as being synced, returning false if a sync is already running
+func (s *syncService) start(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.running[key] {
+		return false
+	}
+	s.running[key] = true
+	return true
+}
+
+//finish marks the repo as no longer being synced
+func (s *syncService) finish(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.running, key)
+}
+
+//GetSyncState returns how far the repo has been synced
+func (s *syncService) GetSyncState(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+	var err errors.APIError
+	owner, repo, err = validateAllCommitsInputs(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	state, errStore := DataStore.GetSyncState(owner, repo)
+	if errStore == store.ErrNotFound {
+		return nil, errors.NewNotFoundAPIError(errorRepoNotSynced)
+	}
+	if errStore != nil {
+		logStoreError("read the sync state from", errStore)
+		return nil, errors.NewInternalServerError(errorSavingSync)
+	}
+	return state, nil
+}
+
+//SyncRepo copies the commits on the default branch and the PRs of the repo into the data store
+//only data newer than the cursors of the last sync is fetched, the first sync fetches the whole history
+//the outcome is recorded in the sync state whether the sync works or not
+func (s *syncService) SyncRepo(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+	var err errors.APIError
+	owner, repo, err = validateAllCommitsInputs(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.ToLower(owner + "/" + repo)
+	if !s.start(key) {
+		return nil, errors.NewAPIError(http.StatusConflict, errorSyncInProgress)
+	}
+	defer s.finish(key)
+
+	state, errStore := DataStore.GetSyncState(owner, repo)
+	if errStore != nil {
+		logStoreError("read the sync state from", errStore)
+		state = &syncdomain.SyncState{Owner: owner, Repo: repo}
+	}
+	state.LastSyncStarted = time.Now().UTC()
+
+	if err := syncRepoData(state); err != nil {
+		state.LastError = err.Message()
+		state.LastErrorAt = time.Now().UTC()
+		logStoreError("save the sync state to", DataStore.SaveSyncState(state))
+		return nil, err
+	}
+
+	state.LastSyncCompleted = time.Now().UTC()
+	if errStore := DataStore.SaveSyncState(state); errStore != nil {
+		logStoreError("save the sync state to", errStore)
+		return nil, errors.NewInternalServerError(errorSavingSync)
+	}
+	return state, nil
+}
+
+//syncRepoData fetches the new commits and PRs, moving the cursors on as each is stored
+func syncRepoData(state *syncdomain.SyncState) errors.APIError {
+	repoInfo, err := RepositoryService.GetRepo(state.Owner, state.Repo)
+	if err != nil {
+		return err
+	}
+	//a change of default branch means its history needs fetching from the start
+	if state.Branch != repoInfo.DefaultBranch {
+		state.Branch = repoInfo.DefaultBranch
+		state.CommitsCursor = time.Time{}
+	}
+
+	//each sync goes back over the overlap as commits can be pushed with an older date
+	commitsSyncedTo := state.LastSyncStarted
+	fetchFrom := state.CommitsCursor
+	if !fetchFrom.IsZero() {
+		fetchFrom = fetchFrom.Add(-commitsOverlap)
+	}
+	commits, errProvider := githubprovider.GetRepoCommitsSince(config.GetGithubAccessToken(), state.Owner, state.Repo, state.Branch, fetchFrom)
+	if errProvider != nil {
+		return errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+	if errStore := DataStore.SaveBranchCommits(state.Owner, state.Repo, state.Branch, commits); errStore != nil {
+		logStoreError("save the commits on a branch to", errStore)
+		return errors.NewInternalServerError(errorSavingSync)
+	}
+	//only the commits older than the overlap won't be fetched again so only they can be relied on
+	if coveredTo := commitsSyncedTo.Add(-commitsOverlap); fetchFrom.Before(coveredTo) {
+		if errStore := DataStore.AddBranchCoverage(state.Owner, state.Repo, state.Branch, fetchFrom, coveredTo); errStore != nil {
+			logStoreError("save the commits on a branch to", errStore)
+			return errors.NewInternalServerError(errorSavingSync)
+		}
+	}
+	state.CommitsCursor = commitsSyncedTo
+	state.LastSyncCommits = len(commits)
+
+	pulls, errProvider := githubprovider.GetRepoPRsUpdatedSince(config.GetGithubAccessToken(), state.Owner, state.Repo, state.PullsCursor)
+	if errProvider != nil {
+		return errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+	}
+	for counter := range pulls {
+		if errStore := dataRecorder.RecordPullRequest(state.Owner, state.Repo, &pulls[counter]); errStore != nil {
+			logStoreError("save a PR to", errStore)
+			return errors.NewInternalServerError(errorSavingSync)
+		}
+	}
+	state.PullsCursor = getPullsCursor(state.PullsCursor, pulls)
+	state.LastSyncPulls = len(pulls)
+	return nil
+}
+
+//getPullsCursor returns the latest update time of the PRs, using Github's time avoids any clock differences
+func getPullsCursor(cursor time.Time, pulls []githubdomain.GetSinglePullRequestResponse) time.Time {
+	for _, pull := range pulls {
+		if pull.UpdatedAt.After(cursor) {
+			cursor = pull.UpdatedAt
+		}
+	}
+	return cursor
+}
+
+//StartPeriodicSync syncs each of the repos, given as owner/repo, every interval until the returned function is called
+func StartPeriodicSync(repos []string, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan bool)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				syncRepos(repos)
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+//syncRepos syncs each of the repos in turn, a failure is logged and recorded against the repo
+func syncRepos(repos []string) {
+	for _, fullName := range repos {
+		parts := strings.SplitN(fullName, "/", 2)
+		if len(parts) != 2 {
+			log.Println("unable to sync repo, expected owner/repo but got", fullName)
+			continue
+		}
+		if _, err := SyncService.SyncRepo(parts[0], parts[1]); err != nil {
+			log.Println("unable to sync repo", fullName, err.Message())
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/syncdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	urlSyncPulls = "https://api.github.com/repos/myuser/myrepo/pulls?state=all&sort=updated&direction=desc&per_page=100"
+)
+
+func addSyncRepoMock() {
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataRepoResponseStatusCode(),
+			Body:       testutils.GetMockDataRepoResponseMessage(),
+		},
+	})
+}
+
+func TestSyncRepoInvalidInputs(t *testing.T) {
+	ResetSyncService()
+
+	result, err := SyncService.SyncRepo("", "myrepo")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageOwner, err.Message())
+
+	result, err = SyncService.GetSyncState("myuser", " ")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, testutils.ErrorMessageRepo, err.Message())
+}
+
+func TestGetSyncStateNotSynced(t *testing.T) {
+	ResetSyncService()
+	defer setupTestDataStore(t)()
+
+	result, err := SyncService.GetSyncState("myuser", "myrepo")
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+	assert.EqualValues(t, errorRepoNotSynced, err.Message())
+}
+
+func TestSyncRepoAlreadyInProgress(t *testing.T) {
+	service := newSyncService()
+	SyncService = service
+	defer ResetSyncService()
+
+	assert.True(t, service.start("myuser/myrepo"))
+	result, err := SyncService.SyncRepo("MyUser", "myrepo")
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+
+	service.finish("myuser/myrepo")
+	assert.True(t, service.start("myuser/myrepo"))
+}
+
+func TestSyncRepoFirstThenIncremental(t *testing.T) {
+	ResetService()
+	ResetSyncService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	addSyncRepoMock()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataCommitsResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleSliceCommitResponsesMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlSyncPulls,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
+		},
+	})
+
+	state, err := SyncService.SyncRepo("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.NotNil(t, state)
+	assert.EqualValues(t, "main", state.Branch)
+	assert.EqualValues(t, 1, state.LastSyncCommits)
+	assert.EqualValues(t, 1, state.LastSyncPulls)
+	assert.EqualValues(t, state.LastSyncStarted, state.CommitsCursor)
+	assert.EqualValues(t, time.Date(2019, 10, 28, 14, 30, 10, 578369000, time.UTC), state.PullsCursor.UTC())
+	assert.True(t, state.IsSynced())
+	assert.False(t, state.Failed())
+
+	stored, err := SyncService.GetSyncState("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.EqualValues(t, state.LastSyncCompleted, stored.LastSyncCompleted)
+
+	//the whole history has been synced so the commits and the PR merging them can be read from the store
+	commits, fromStore, err := getRepoCommitsInDateRange("myuser", "myrepo", "main", time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), nil)
+	assert.Nil(t, err)
+	assert.True(t, fromStore)
+	assert.EqualValues(t, 1, len(commits))
+
+	pull, errStore := DataStore.GetPullRequest("myuser", "myrepo", 9)
+	assert.Nil(t, errStore)
+	assert.True(t, isPRResultingInMerge(pull))
+
+	//so a report over the period only needs the repo, the PRs of the commit and the details and reviews of the PR from Github
+	//the PR came from a list of PRs, which doesn't say who merged it
+	restclient.FlushMockups()
+	addSyncRepoMock()
+	addMergedPRMocks("9")
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits/AABCDEF123456/pulls?per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
+		},
+	})
+	report, err := RepositoryService.GetCodeReviewReport("myuser", "myrepo", time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, report.TotalCommitsWithPR)
+	assert.True(t, report.DataFromStore)
+	assert.EqualValues(t, state.LastSyncCompleted, report.DataAsOf)
+
+	//the next sync only asks for what is new, going back over the overlap
+	restclient.FlushMockups()
+	addSyncRepoMock()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + state.CommitsCursor.Add(-commitsOverlap).Format(githubprovider.FmtGithubDate) + "&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataCommitsResponseStatusCode(),
+			Body:       testutils.GetMockDataNoPRForCommitResponsesMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlSyncPulls,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
+			Body:       testutils.GetMockDataApprovedPRForCommitResponsesMessage(),
+		},
+	})
+
+	state, err = SyncService.SyncRepo("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, state.LastSyncCommits)
+	assert.EqualValues(t, 1, state.LastSyncPulls)
+}
+
+func TestSyncRepoPicksUpCommitPushedWithOlderDate(t *testing.T) {
+	ResetService()
+	ResetSyncService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	addSyncRepoMock()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataCommitsResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleSliceCommitResponsesMessage(),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlSyncPulls,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
+			Body:       ioutil.NopCloser(strings.NewReader(`[]`)),
+		},
+	})
+	state, err := SyncService.SyncRepo("myuser", "myrepo")
+	assert.Nil(t, err)
+
+	//the recent commits aren't relied on as one dated before the sync can still be pushed
+	dated := state.CommitsCursor.Add(-time.Hour)
+	_, errStore := DataStore.GetBranchCommits("myuser", "myrepo", "main", dated.Add(-time.Hour), state.CommitsCursor)
+	assert.EqualValues(t, store.ErrNotFound, errStore)
+
+	late := []githubdomain.GetCommitInfo{{SHA: "LATE123456"}}
+	late[0].Commit.Committer.Date = dated
+	body, _ := json.Marshal(late)
+
+	restclient.FlushMockups()
+	addSyncRepoMock()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&since=" + state.CommitsCursor.Add(-commitsOverlap).Format(githubprovider.FmtGithubDate) + "&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataCommitsResponseStatusCode(),
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		},
+	})
+	restclient.AddMockup(restclient.Mock{
+		URL:        urlSyncPulls,
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataPRsResponseStatusCode(),
+			Body:       ioutil.NopCloser(strings.NewReader(`[]`)),
+		},
+	})
+	state, err = SyncService.SyncRepo("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, state.LastSyncCommits)
+
+	stored, errStore := DataStore.GetCommit("myuser", "myrepo", "LATE123456")
+	assert.Nil(t, errStore)
+	assert.EqualValues(t, dated.Unix(), stored.Commit.Committer.Date.Unix())
+}
+
+func TestSyncRepoErrorIsRecorded(t *testing.T) {
+	ResetService()
+	ResetSyncService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	addSyncRepoMock()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataUnauthorisedResponseStatusCode(),
+			Body:       testutils.GetMockDataUnauthorisedResponseMessage(),
+		},
+	})
+
+	result, err := SyncService.SyncRepo("myuser", "myrepo")
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+
+	state, err := SyncService.GetSyncState("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.EqualValues(t, testutils.ErrorMessageAuthentication, state.LastError)
+	assert.True(t, state.Failed())
+	assert.False(t, state.IsSynced())
+	assert.True(t, state.CommitsCursor.IsZero())
+}
+
+func TestSyncRepoPullsErrorKeepsCommitProgress(t *testing.T) {
+	ResetService()
+	ResetSyncService()
+	defer setupTestDataStore(t)()
+
+	restclient.FlushMockups()
+	addSyncRepoMock()
+	restclient.AddMockup(restclient.Mock{
+		URL:        "https://api.github.com/repos/myuser/myrepo/commits?sha=main&per_page=100",
+		HTTPMethod: http.MethodGet,
+		Response: &http.Response{
+			StatusCode: testutils.GetMockDataCommitsResponseStatusCode(),
+			Body:       testutils.GetMockDataSingleSliceCommitResponsesMessage(),
+		},
+	})
+
+	_, err := SyncService.SyncRepo("myuser", "myrepo")
+	assert.NotNil(t, err)
+
+	state, err := SyncService.GetSyncState("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.True(t, state.Failed())
+	assert.False(t, state.CommitsCursor.IsZero())
+	assert.True(t, state.PullsCursor.IsZero())
+}
+
+func TestGetPullsCursor(t *testing.T) {
+	cursor := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	assert.EqualValues(t, cursor, getPullsCursor(cursor, nil))
+
+	pulls := []githubdomain.GetSinglePullRequestResponse{
+		{Number: 1, UpdatedAt: cursor.AddDate(0, 0, 2)},
+		{Number: 2, UpdatedAt: cursor.AddDate(0, 0, 5)},
+		{Number: 3, UpdatedAt: cursor.AddDate(0, 0, -1)},
+	}
+	assert.EqualValues(t, cursor.AddDate(0, 0, 5), getPullsCursor(cursor, pulls))
+}
+
+//syncServiceRecorder records the repos that are synced
+type syncServiceRecorder struct {
+	synced chan string
+}
+
+func (s *syncServiceRecorder) SyncRepo(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+	s.synced <- owner + "/" + repo
+	return &syncdomain.SyncState{Owner: owner, Repo: repo}, nil
+}
+
+func (s *syncServiceRecorder) GetSyncState(owner string, repo string) (*syncdomain.SyncState, errors.APIError) {
+	return nil, nil
+}
+
+func TestStartPeriodicSync(t *testing.T) {
+	recorder := &syncServiceRecorder{synced: make(chan string, 10)}
+	SyncService = recorder
+	defer ResetSyncService()
+
+	stop := StartPeriodicSync([]string{"myuser/myrepo", "notarepo"}, 10*time.Millisecond)
+	select {
+	case fullName := <-recorder.synced:
+		assert.EqualValues(t, "myuser/myrepo", fullName)
+	case <-time.After(time.Second):
+		assert.Fail(t, "repo was not synced")
+	}
+	stop()
+}
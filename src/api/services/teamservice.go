@@ -0,0 +1,84 @@
+package services
+
+import (
+	"log"
+	"sync"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/providers/githubprovider"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+type teamService struct{}
+
+type teamServiceInterface interface {
+	GetTeams() *teamdomain.Directory
+	SyncTeams() (*teamdomain.Directory, errors.APIError)
+}
+
+//TeamService defines the team service to use
+var TeamService teamServiceInterface
+
+//the team directory is replaced as a whole when it is set or synced, so reports only ever see a complete directory
+var (
+	teamDirectory      = teamdomain.NewDirectory()
+	teamDirectoryMutex sync.RWMutex
+)
+
+func init() {
+	TeamService = &teamService{}
+}
+
+//ResetTeamService calls the init function again
+func ResetTeamService() {
+	TeamService = &teamService{}
+}
+
+//SetTeamDirectory sets the teams the reports and metrics are broken down by
+func SetTeamDirectory(directory *teamdomain.Directory) {
+	teamDirectoryMutex.Lock()
+	defer teamDirectoryMutex.Unlock()
+	teamDirectory = directory
+}
+
+//getTeamDirectory returns the teams the reports and metrics are broken down by
+func getTeamDirectory() *teamdomain.Directory {
+	teamDirectoryMutex.RLock()
+	defer teamDirectoryMutex.RUnlock()
+	return teamDirectory
+}
+
+//GetTeams returns the team directory, including the members last synced from Github
+func (s *teamService) GetTeams() *teamdomain.Directory {
+	return getTeamDirectory()
+}
+
+//SyncTeams fetches the members of the Github teams linked to the teams in the directory
+//the directory is left as it was if any of the Github teams can't be fetched
+func (s *teamService) SyncTeams() (*teamdomain.Directory, errors.APIError) {
+	current := getTeamDirectory()
+	synced := &teamdomain.Directory{Org: current.Org, Teams: make([]teamdomain.Team, len(current.Teams))}
+	copy(synced.Teams, current.Teams)
+
+	for counter := range synced.Teams {
+		team := &synced.Teams[counter]
+		if team.GithubTeam == "" {
+			continue
+		}
+
+		members, errProvider := githubprovider.GetTeamMembers(config.GetGithubAccessToken(), synced.Org, team.GithubTeam)
+		if errProvider != nil {
+			return nil, errors.NewAPIError(errProvider.StatusCode, errProvider.Message)
+		}
+
+		team.SyncedMembers = make([]string, 0, len(members))
+		for _, member := range members {
+			team.SyncedMembers = append(team.SyncedMembers, member.Login)
+		}
+		log.Println("synced", len(members), "members of the Github team", synced.Org+"/"+team.GithubTeam, "into team", team.Name)
+	}
+
+	SetTeamDirectory(synced)
+	return synced, nil
+}
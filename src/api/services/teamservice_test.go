@@ -0,0 +1,64 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/teamdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestTeamDirectory() *teamdomain.Directory {
+	return &teamdomain.Directory{Org: "myorg", Teams: []teamdomain.Team{
+		{Name: "payments", GithubTeam: "payments-devs", Members: []string{"alice"}},
+		{Name: "platform", Members: []string{"carol"}},
+	}}
+}
+
+func TestGetTeams(t *testing.T) {
+	ResetTeamService()
+	assert.False(t, TeamService.GetTeams().HasTeams())
+
+	SetTeamDirectory(getTestTeamDirectory())
+	defer SetTeamDirectory(teamdomain.NewDirectory())
+	assert.EqualValues(t, 2, len(TeamService.GetTeams().Teams))
+}
+
+func TestSyncTeams(t *testing.T) {
+	ResetTeamService()
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/orgs/myorg/teams/payments-devs/members?per_page=100", http.StatusOK, `[{"login":"Bob"},{"login":"dave"}]`)
+	directory := getTestTeamDirectory()
+	SetTeamDirectory(directory)
+	defer SetTeamDirectory(teamdomain.NewDirectory())
+
+	synced, err := TeamService.SyncTeams()
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"Bob", "dave"}, synced.Teams[0].SyncedMembers)
+	assert.Nil(t, synced.Teams[1].SyncedMembers)
+	assert.EqualValues(t, []string{"payments"}, TeamService.GetTeams().TeamsOf("bob"))
+	assert.EqualValues(t, []string{"payments"}, TeamService.GetTeams().TeamsOf("alice"))
+	//the configured directory is left untouched
+	assert.Nil(t, directory.Teams[0].SyncedMembers)
+
+	//members removed from the Github team are dropped on the next sync
+	addComplianceMock("https://api.github.com/orgs/myorg/teams/payments-devs/members?per_page=100", http.StatusOK, `[{"login":"dave"}]`)
+	synced, err = TeamService.SyncTeams()
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{teamdomain.Unassigned}, synced.TeamsOf("bob"))
+}
+
+func TestSyncTeamsError(t *testing.T) {
+	ResetTeamService()
+	restclient.FlushMockups()
+	addComplianceMock("https://api.github.com/orgs/myorg/teams/payments-devs/members?per_page=100", http.StatusNotFound, `{"message":"Not Found"}`)
+	directory := getTestTeamDirectory()
+	SetTeamDirectory(directory)
+	defer SetTeamDirectory(teamdomain.NewDirectory())
+
+	synced, err := TeamService.SyncTeams()
+	assert.Nil(t, synced)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+	assert.True(t, directory == TeamService.GetTeams())
+}
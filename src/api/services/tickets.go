@@ -0,0 +1,96 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/tracker"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+const (
+	errorInvalidTicketPattern = "unable to use the ticket patterns of the policy"
+)
+
+//IssueTracker is where the tickets referenced by commits and PRs are looked up, only the keys are checked until a tracker is set
+var IssueTracker = tracker.NewDisabledTracker()
+
+//SetIssueTracker sets where the tickets referenced by commits and PRs are looked up
+func SetIssueTracker(issueTracker tracker.Tracker) {
+	IssueTracker = issueTracker
+}
+
+//ticketChecker finds the tickets referenced by commits and PRs, each ticket is only looked up once
+type ticketChecker struct {
+	rules    *policydomain.TicketRules
+	patterns []*regexp.Regexp
+	issues   map[string]*ticketdomain.Issue
+}
+
+//newTicketChecker creates a checker for the rules, the patterns are checked when the policy is loaded so shouldn't fail here
+func newTicketChecker(rules *policydomain.TicketRules) (*ticketChecker, errors.APIError) {
+	patterns, err := rules.CompilePatterns()
+	if err != nil {
+		return nil, errors.NewInternalServerError(errorInvalidTicketPattern)
+	}
+	return &ticketChecker{rules: rules, patterns: patterns, issues: make(map[string]*ticketdomain.Issue)}, nil
+}
+
+//check finds the tickets referenced in the text and looks each of them up in the issue tracker
+//a ticket that doesn't exist is recorded against the reference, any other failure to look it up is returned
+func (c *ticketChecker) check(source string, text string) ([]ticketdomain.Reference, errors.APIError) {
+	var result []ticketdomain.Reference
+	for _, key := range ticketdomain.ExtractKeys(c.patterns, text) {
+		issue, looked := c.issues[key]
+		if !looked {
+			var err error
+			issue, err = IssueTracker.GetIssue(key)
+			switch err {
+			case nil:
+			case tracker.ErrDisabled:
+				result = append(result, ticketdomain.NewUnverifiedReference(key, source))
+				continue
+			case tracker.ErrNotFound:
+				issue = nil
+			default:
+				return nil, errors.NewAPIError(http.StatusBadGateway, fmt.Sprintf("unable to look up the ticket %s: %s", key, err.Error()))
+			}
+			c.issues[key] = issue
+		}
+		result = append(result, ticketdomain.NewReference(key, source, issue, c.rules))
+	}
+	return result, nil
+}
+
+//checkPullRequest finds the tickets referenced in the title and body of the PR
+func (c *ticketChecker) checkPullRequest(pullRequest *githubdomain.GetSinglePullRequestResponse) ([]ticketdomain.Reference, errors.APIError) {
+	result, err := c.check(ticketdomain.SourcePullTitle, pullRequest.Title)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.check(ticketdomain.SourcePullBody, pullRequest.Body)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, body...), nil
+}
+
+//checkCommit finds the tickets referenced in the message of the commit and in the PR that merged it, if there is one
+func (c *ticketChecker) checkCommit(commit *githubdomain.GetCommitInfo, pullRequest *githubdomain.GetSinglePullRequestResponse) ([]ticketdomain.Reference, errors.APIError) {
+	result, err := c.check(ticketdomain.SourceCommitMessage, commit.Commit.Message)
+	if err != nil {
+		return nil, err
+	}
+	if pullRequest == nil {
+		return result, nil
+	}
+	fromPR, err := c.checkPullRequest(pullRequest)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, fromPR...), nil
+}
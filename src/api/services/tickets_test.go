@@ -0,0 +1,91 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/policydomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/tracker"
+	"github.com/stretchr/testify/assert"
+)
+
+//countingTracker counts the lookups made of the tracker it wraps, it fails every lookup when it has an error
+type countingTracker struct {
+	tracker.Tracker
+	lookups int
+	err     error
+}
+
+func (t *countingTracker) GetIssue(key string) (*ticketdomain.Issue, error) {
+	t.lookups++
+	if t.err != nil {
+		return nil, t.err
+	}
+	return t.Tracker.GetIssue(key)
+}
+
+//setupIssueTracker makes the tracker hold PAY-12 in progress and OPS-7 done, the returned function takes the tracker away again
+func setupIssueTracker() (*countingTracker, func()) {
+	issueTracker := &countingTracker{Tracker: tracker.NewLocalTracker([]ticketdomain.Issue{
+		{Key: "PAY-12", Summary: "Handle refunds", Status: "In Progress"},
+		{Key: "OPS-7", Summary: "Fix the build", Status: "Done"},
+	})}
+	SetIssueTracker(issueTracker)
+	return issueTracker, func() {
+		SetIssueTracker(tracker.NewDisabledTracker())
+	}
+}
+
+func TestTicketCheckerInvalidPattern(t *testing.T) {
+	checker, err := newTicketChecker(&policydomain.TicketRules{Patterns: []string{"("}})
+	assert.Nil(t, checker)
+	assert.EqualValues(t, http.StatusInternalServerError, err.Status())
+	assert.EqualValues(t, errorInvalidTicketPattern, err.Message())
+}
+
+func TestTicketCheckerWithoutTracker(t *testing.T) {
+	checker, _ := newTicketChecker(&policydomain.TicketRules{Required: true, AllowedStates: []string{"In Progress"}})
+	references, err := checker.check(ticketdomain.SourceCommitMessage, "PAY-99: something")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []ticketdomain.Reference{{Key: "PAY-99", Source: ticketdomain.SourceCommitMessage, Valid: true}}, references)
+}
+
+func TestTicketCheckerLooksUpEachTicketOnce(t *testing.T) {
+	issueTracker, cleanup := setupIssueTracker()
+	defer cleanup()
+
+	checker, _ := newTicketChecker(&policydomain.TicketRules{Required: true, AllowedStates: []string{"In Progress"}})
+	commit := &githubdomain.GetCommitInfo{}
+	commit.Commit.Message = "PAY-12 handle refunds\n\nSee PAY-404"
+	pullRequest := &githubdomain.GetSinglePullRequestResponse{Title: "PAY-12: Handle refunds", Body: "Also fixes OPS-7 and PAY-404"}
+
+	references, err := checker.checkCommit(commit, pullRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, len(references))
+	assert.EqualValues(t, ticketdomain.Reference{Key: "PAY-12", Source: ticketdomain.SourceCommitMessage, Status: "In Progress", Valid: true}, references[0])
+	assert.EqualValues(t, "the ticket PAY-404 doesn't exist", references[1].Problem)
+	assert.EqualValues(t, ticketdomain.SourcePullTitle, references[2].Source)
+	assert.EqualValues(t, "the ticket OPS-7 is Done, expected one of In Progress", references[3].Problem)
+	assert.EqualValues(t, ticketdomain.SourcePullBody, references[4].Source)
+	assert.EqualValues(t, 3, issueTracker.lookups)
+
+	references, err = checker.checkCommit(commit, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(references))
+	assert.EqualValues(t, 3, issueTracker.lookups)
+}
+
+func TestTicketCheckerTrackerFailure(t *testing.T) {
+	issueTracker, cleanup := setupIssueTracker()
+	defer cleanup()
+	issueTracker.err = fmt.Errorf("jira returned status 503 looking up PAY-12")
+
+	checker, _ := newTicketChecker(&policydomain.TicketRules{Required: true})
+	references, err := checker.checkPullRequest(&githubdomain.GetSinglePullRequestResponse{Title: "PAY-12: Handle refunds"})
+	assert.Nil(t, references)
+	assert.EqualValues(t, http.StatusBadGateway, err.Status())
+	assert.EqualValues(t, "unable to look up the ticket PAY-12: jira returned status 503 looking up PAY-12", err.Message())
+}
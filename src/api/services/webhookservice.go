@@ -0,0 +1,234 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/config"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/errors"
+)
+
+type webhookService struct {
+	mutex      sync.Mutex
+	deliveries map[string]time.Time
+	recorder   eventRecorder
+}
+
+type webhookServiceInterface interface {
+	HandleGithubEvent(eventType string, deliveryID string, signature string, payload []byte) (*githubdomain.WebhookDelivery, errors.APIError)
+}
+
+const (
+	errorWebhookSecretNotConfigured = "webhook secret is not configured"
+	errorInvalidWebhookSignature    = "invalid webhook signature"
+	errorInvalidWebhookDelivery     = "invalid webhook delivery id"
+	errorInvalidWebhookEvent        = "invalid webhook event"
+	errorInvalidWebhookPayload      = "invalid webhook payload"
+	errorDuplicateWebhookDelivery   = "webhook delivery has already been received"
+	errorRecordingWebhookEvent      = "unable to record webhook event"
+
+	webhookSignaturePrefix = "sha256="
+	//deliveries are remembered for long enough to cover Github's own redelivery window
+	webhookDeliveryRetention = 72 * time.Hour
+
+	//WebhookStatusProcessed indicates the event was parsed and recorded
+	WebhookStatusProcessed = "processed"
+	//WebhookStatusIgnored indicates the event was valid but isn't needed
+	WebhookStatusIgnored = "ignored"
+)
+
+//WebhookService defines the webhook service to use
+var WebhookService webhookServiceInterface
+
+func init() {
+	WebhookService = newWebhookService()
+}
+
+//ResetWebhookService calls the init function again, forgetting all deliveries
+func ResetWebhookService() {
+	WebhookService = newWebhookService()
+}
+
+func newWebhookService() *webhookService {
+	return &webhookService{
+		deliveries: make(map[string]time.Time),
+		recorder:   dataRecorder,
+	}
+}
+
+//HandleGithubEvent verifies the webhook delivery came from Github and records the event it contains
+//a delivery ID already received is rejected, the IDs are kept in the data store when it is enabled so this holds across
+//restarts and instances, without a data store the protection only covers the deliveries received by this process
+func (s *webhookService) HandleGithubEvent(eventType string, deliveryID string, signature string, payload []byte) (*githubdomain.WebhookDelivery, errors.APIError) {
+	eventType = strings.TrimSpace(eventType)
+	deliveryID = strings.TrimSpace(deliveryID)
+
+	if len(eventType) == 0 {
+		return nil, errors.NewBadRequestError(errorInvalidWebhookEvent)
+	}
+
+	if len(deliveryID) == 0 {
+		return nil, errors.NewBadRequestError(errorInvalidWebhookDelivery)
+	}
+
+	if err := verifyWebhookSignature(signature, payload); err != nil {
+		return nil, err
+	}
+
+	if !s.reserveDelivery(deliveryID, time.Now()) {
+		return nil, errors.NewAPIError(http.StatusConflict, errorDuplicateWebhookDelivery)
+	}
+
+	status, err := s.recordEvent(eventType, payload)
+	if err != nil {
+		//let a redelivery of the same event be processed if recording it failed on our side
+		if err.Status() >= http.StatusInternalServerError {
+			s.releaseDelivery(deliveryID)
+		}
+		return nil, err
+	}
+
+	return &githubdomain.WebhookDelivery{
+		ID:     deliveryID,
+		Event:  eventType,
+		Status: status,
+	}, nil
+}
+
+//verifyWebhookSignature checks the signature is the HMAC of the payload using the shared secret
+func verifyWebhookSignature(signature string, payload []byte) errors.APIError {
+	secret := config.GetGithubWebhookSecret()
+	if len(secret) == 0 {
+		log.Println("webhook received but no webhook secret is configured")
+		return errors.NewInternalServerError(errorWebhookSecretNotConfigured)
+	}
+
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return errors.NewAPIError(http.StatusUnauthorized, errorInvalidWebhookSignature)
+	}
+
+	received, err := hex.DecodeString(strings.TrimPrefix(signature, webhookSignaturePrefix))
+	if err != nil {
+		return errors.NewAPIError(http.StatusUnauthorized, errorInvalidWebhookSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	if !hmac.Equal(received, mac.Sum(nil)) {
+		return errors.NewAPIError(http.StatusUnauthorized, errorInvalidWebhookSignature)
+	}
+	return nil
+}
+
+//reserveDelivery remembers the delivery ID, returning false if it has already been seen by this process or, when enabled, the data store
+//expired deliveries are pruned at the same time so the map doesn't grow forever, a delivery is still accepted if the store can't be used
+func (s *webhookService) reserveDelivery(deliveryID string, now time.Time) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, received := range s.deliveries {
+		if now.Sub(received) > webhookDeliveryRetention {
+			delete(s.deliveries, id)
+		}
+	}
+
+	if _, found := s.deliveries[deliveryID]; found {
+		return false
+	}
+	reserved, errStore := DataStore.ReserveWebhookDelivery(deliveryID, now, webhookDeliveryRetention)
+	logStoreError("reserve a webhook delivery in", errStore)
+	if errStore == nil && !reserved {
+		return false
+	}
+	s.deliveries[deliveryID] = now
+	return true
+}
+
+//releaseDelivery forgets the delivery ID
+func (s *webhookService) releaseDelivery(deliveryID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.deliveries, deliveryID)
+	logStoreError("release a webhook delivery in", DataStore.ReleaseWebhookDelivery(deliveryID))
+}
+
+//recordEvent parses the payload and passes it to the recorder, returning the status of the delivery
+func (s *webhookService) recordEvent(eventType string, payload []byte) (string, errors.APIError) {
+	switch eventType {
+	case githubdomain.WebhookEventPing:
+		return WebhookStatusProcessed, nil
+	case githubdomain.WebhookEventPush:
+		return s.recordPushEvent(payload)
+	case githubdomain.WebhookEventPullRequest:
+		return s.recordPullRequestEvent(payload)
+	case githubdomain.WebhookEventPullRequestReview:
+		return s.recordPullRequestReviewEvent(payload)
+	default:
+		return WebhookStatusIgnored, nil
+	}
+}
+
+//recordPushEvent records the commits pushed to the default branch, other branches and tags are ignored
+func (s *webhookService) recordPushEvent(payload []byte) (string, errors.APIError) {
+	var event githubdomain.PushEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Println("unable to unmarshal push event", err.Error())
+		return "", errors.NewBadRequestError(errorInvalidWebhookPayload)
+	}
+
+	branch := event.Branch()
+	if event.Deleted || branch == "" || branch != event.Repository.DefaultBranch {
+		return WebhookStatusIgnored, nil
+	}
+
+	commits := make([]githubdomain.GetCommitInfo, 0, len(event.Commits))
+	for counter := range event.Commits {
+		commits = append(commits, event.Commits[counter].ToCommitInfo())
+	}
+
+	if err := s.recorder.RecordCommits(event.Repository.Owner.Login, event.Repository.Name, branch, commits); err != nil {
+		log.Println("unable to record push event", err.Error())
+		return "", errors.NewInternalServerError(errorRecordingWebhookEvent)
+	}
+	return WebhookStatusProcessed, nil
+}
+
+//recordPullRequestEvent records the latest state of the PR
+func (s *webhookService) recordPullRequestEvent(payload []byte) (string, errors.APIError) {
+	var event githubdomain.PullRequestEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Println("unable to unmarshal pull request event", err.Error())
+		return "", errors.NewBadRequestError(errorInvalidWebhookPayload)
+	}
+
+	if err := s.recorder.RecordPullRequest(event.Repository.Owner.Login, event.Repository.Name, &event.PullRequest); err != nil {
+		log.Println("unable to record pull request event", err.Error())
+		return "", errors.NewInternalServerError(errorRecordingWebhookEvent)
+	}
+	return WebhookStatusProcessed, nil
+}
+
+//recordPullRequestReviewEvent records the review, webhooks send the state in lower case unlike the API
+func (s *webhookService) recordPullRequestReviewEvent(payload []byte) (string, errors.APIError) {
+	var event githubdomain.PullRequestReviewEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Println("unable to unmarshal pull request review event", err.Error())
+		return "", errors.NewBadRequestError(errorInvalidWebhookPayload)
+	}
+
+	event.Review.State = strings.ToUpper(event.Review.State)
+	if err := s.recorder.RecordPullRequestReview(event.Repository.Owner.Login, event.Repository.Name, event.PullRequest.Number, &event.Review); err != nil {
+		log.Println("unable to record pull request review event", err.Error())
+		return "", errors.NewInternalServerError(errorRecordingWebhookEvent)
+	}
+	return WebhookStatusProcessed, nil
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/store"
+	"github.com/greendinosaur/gh-commit-info/src/api/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testWebhookSecret = "mysecret"
+
+//failingEventRecorder is used to check what happens when events can't be recorded
+type failingEventRecorder struct{}
+
+func (r *failingEventRecorder) RecordCommits(owner string, repo string, branch string, commits []githubdomain.GetCommitInfo) error {
+	return errors.New("unable to record")
+}
+
+func (r *failingEventRecorder) RecordPullRequest(owner string, repo string, pullRequest *githubdomain.GetSinglePullRequestResponse) error {
+	return errors.New("unable to record")
+}
+
+func (r *failingEventRecorder) RecordPullRequestReview(owner string, repo string, pullNumber int64, review *githubdomain.PullRequestReview) error {
+	return errors.New("unable to record")
+}
+
+func setupWebhookTest() *webhookService {
+	os.Setenv("SECRET_GITHUB_WEBHOOK_SECRET", testWebhookSecret)
+	service := newWebhookService()
+	WebhookService = service
+	return service
+}
+
+func handleSignedEvent(service *webhookService, eventType string, deliveryID string, payload []byte) (*githubdomain.WebhookDelivery, int) {
+	result, err := service.HandleGithubEvent(eventType, deliveryID, testutils.GetWebhookSignature(testWebhookSecret, payload), payload)
+	if err != nil {
+		return result, err.Status()
+	}
+	return result, http.StatusOK
+}
+
+func TestHandleGithubEventInvalidEvent(t *testing.T) {
+	service := setupWebhookTest()
+	payload := testutils.GetMockDataPingEventPayload()
+	result, err := service.HandleGithubEvent("", "delivery1", testutils.GetWebhookSignature(testWebhookSecret, payload), payload)
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, errorInvalidWebhookEvent, err.Message())
+}
+
+func TestHandleGithubEventInvalidDeliveryID(t *testing.T) {
+	service := setupWebhookTest()
+	payload := testutils.GetMockDataPingEventPayload()
+	result, err := service.HandleGithubEvent(githubdomain.WebhookEventPing, " ", testutils.GetWebhookSignature(testWebhookSecret, payload), payload)
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, errorInvalidWebhookDelivery, err.Message())
+}
+
+func TestHandleGithubEventSecretNotConfigured(t *testing.T) {
+	service := setupWebhookTest()
+	os.Setenv("SECRET_GITHUB_WEBHOOK_SECRET", "")
+	defer os.Setenv("SECRET_GITHUB_WEBHOOK_SECRET", testWebhookSecret)
+
+	payload := testutils.GetMockDataPingEventPayload()
+	result, err := service.HandleGithubEvent(githubdomain.WebhookEventPing, "delivery1", testutils.GetWebhookSignature("", payload), payload)
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.Status())
+	assert.EqualValues(t, errorWebhookSecretNotConfigured, err.Message())
+}
+
+func TestHandleGithubEventInvalidSignatures(t *testing.T) {
+	service := setupWebhookTest()
+	payload := testutils.GetMockDataPingEventPayload()
+
+	signatures := []string{
+		"",
+		"sha1=abcdef",
+		"sha256=not-hex",
+		testutils.GetWebhookSignature("wrongsecret", payload),
+		testutils.GetWebhookSignature(testWebhookSecret, []byte("a different payload")),
+	}
+
+	for _, signature := range signatures {
+		result, err := service.HandleGithubEvent(githubdomain.WebhookEventPing, "delivery1", signature, payload)
+		assert.Nil(t, result)
+		assert.NotNil(t, err)
+		assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+		assert.EqualValues(t, errorInvalidWebhookSignature, err.Message())
+	}
+}
+
+func TestHandleGithubEventPing(t *testing.T) {
+	service := setupWebhookTest()
+	result, status := handleSignedEvent(service, githubdomain.WebhookEventPing, "delivery1", testutils.GetMockDataPingEventPayload())
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.NotNil(t, result)
+	assert.EqualValues(t, "delivery1", result.ID)
+	assert.EqualValues(t, githubdomain.WebhookEventPing, result.Event)
+	assert.EqualValues(t, WebhookStatusProcessed, result.Status)
+}
+
+func TestHandleGithubEventUnsupportedEventIsIgnored(t *testing.T) {
+	service := setupWebhookTest()
+	result, status := handleSignedEvent(service, "issues", "delivery1", []byte(`{"action":"opened"}`))
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.NotNil(t, result)
+	assert.EqualValues(t, WebhookStatusIgnored, result.Status)
+}
+
+func TestHandleGithubEventDuplicateDelivery(t *testing.T) {
+	service := setupWebhookTest()
+	payload := testutils.GetMockDataPingEventPayload()
+
+	_, status := handleSignedEvent(service, githubdomain.WebhookEventPing, "delivery1", payload)
+	assert.EqualValues(t, http.StatusOK, status)
+
+	result, err := service.HandleGithubEvent(githubdomain.WebhookEventPing, "delivery1", testutils.GetWebhookSignature(testWebhookSecret, payload), payload)
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+	assert.EqualValues(t, errorDuplicateWebhookDelivery, err.Message())
+
+	_, status = handleSignedEvent(service, githubdomain.WebhookEventPing, "delivery2", payload)
+	assert.EqualValues(t, http.StatusOK, status)
+}
+
+func TestHandleGithubEventBadSignatureDoesNotUseDeliveryID(t *testing.T) {
+	service := setupWebhookTest()
+	payload := testutils.GetMockDataPingEventPayload()
+
+	_, err := service.HandleGithubEvent(githubdomain.WebhookEventPing, "delivery1", "sha256=abcdef", payload)
+	assert.NotNil(t, err)
+
+	_, status := handleSignedEvent(service, githubdomain.WebhookEventPing, "delivery1", payload)
+	assert.EqualValues(t, http.StatusOK, status)
+}
+
+func TestReserveDeliveryPrunesExpiredDeliveries(t *testing.T) {
+	service := setupWebhookTest()
+	now := time.Now()
+
+	assert.True(t, service.reserveDelivery("delivery1", now.Add(-webhookDeliveryRetention-time.Minute)))
+	assert.True(t, service.reserveDelivery("delivery2", now.Add(-time.Minute)))
+
+	assert.True(t, service.reserveDelivery("delivery1", now))
+	assert.False(t, service.reserveDelivery("delivery2", now))
+	assert.EqualValues(t, 2, len(service.deliveries))
+}
+
+func TestHandleGithubEventDuplicateDeliveryFromStore(t *testing.T) {
+	defer setupTestDataStore(t)()
+	payload := testutils.GetMockDataPingEventPayload()
+
+	_, status := handleSignedEvent(setupWebhookTest(), githubdomain.WebhookEventPing, "delivery1", payload)
+	assert.EqualValues(t, http.StatusOK, status)
+
+	//a new process still knows about the delivery
+	service := setupWebhookTest()
+	_, status = handleSignedEvent(service, githubdomain.WebhookEventPing, "delivery1", payload)
+	assert.EqualValues(t, http.StatusConflict, status)
+	assert.EqualValues(t, 0, len(service.deliveries))
+
+	//a delivery that couldn't be recorded is forgotten by the store too
+	service.recorder = &failingEventRecorder{}
+	_, status = handleSignedEvent(service, githubdomain.WebhookEventPush, "delivery2", testutils.GetMockDataPushEventPayload())
+	assert.EqualValues(t, http.StatusInternalServerError, status)
+	_, status = handleSignedEvent(setupWebhookTest(), githubdomain.WebhookEventPing, "delivery2", payload)
+	assert.EqualValues(t, http.StatusOK, status)
+}
+
+func TestHandleGithubEventInvalidPayloads(t *testing.T) {
+	service := setupWebhookTest()
+	events := []string{githubdomain.WebhookEventPush, githubdomain.WebhookEventPullRequest, githubdomain.WebhookEventPullRequestReview}
+
+	for counter, event := range events {
+		result, status := handleSignedEvent(service, event, fmt.Sprintf("delivery%d", counter), []byte(`{"ref":`))
+		assert.Nil(t, result)
+		assert.EqualValues(t, http.StatusBadRequest, status)
+	}
+}
+
+func TestHandleGithubEventPushToOtherBranchIsIgnored(t *testing.T) {
+	service := setupWebhookTest()
+	defer setupTestDataStore(t)()
+	result, status := handleSignedEvent(service, githubdomain.WebhookEventPush, "delivery1", testutils.GetMockDataPushEventOtherBranchPayload())
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.EqualValues(t, WebhookStatusIgnored, result.Status)
+
+	_, err := DataStore.GetCommit("myuser", "myrepo", "FEDCBA654321")
+	assert.EqualValues(t, store.ErrNotFound, err)
+}
+
+func TestHandleGithubEventPushThenMergedPR(t *testing.T) {
+	service := setupWebhookTest()
+	defer setupTestDataStore(t)()
+
+	result, status := handleSignedEvent(service, githubdomain.WebhookEventPush, "delivery1", testutils.GetMockDataPushEventPayload())
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.EqualValues(t, WebhookStatusProcessed, result.Status)
+
+	_, err := DataStore.GetCommit("myuser", "myrepo", "AABCDEF123456")
+	assert.Nil(t, err)
+
+	result, status = handleSignedEvent(service, githubdomain.WebhookEventPullRequest, "delivery2", testutils.GetMockDataPullRequestMergedEventPayload())
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.EqualValues(t, WebhookStatusProcessed, result.Status)
+
+	pull, err := DataStore.GetPullRequest("MyUser", "MyRepo", 9)
+	assert.Nil(t, err)
+	assert.True(t, isPRResultingInMerge(pull))
+
+	//the PR event can't say which other PRs hold the commit so they are still read from Github
+	_, err = DataStore.GetCommitPullRequests("myuser", "myrepo", "AABCDEF123456")
+	assert.EqualValues(t, store.ErrNotFound, err)
+}
+
+func TestHandleGithubEventPullRequestReview(t *testing.T) {
+	service := setupWebhookTest()
+	defer setupTestDataStore(t)()
+
+	result, status := handleSignedEvent(service, githubdomain.WebhookEventPullRequestReview, "delivery1", testutils.GetMockDataPullRequestReviewEventPayload())
+	assert.EqualValues(t, http.StatusOK, status)
+	assert.EqualValues(t, WebhookStatusProcessed, result.Status)
+
+	reviews, err := DataStore.GetPullRequestReviews("myuser", "myrepo", 9)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(reviews))
+	assert.EqualValues(t, 80, reviews[0].ID)
+	assert.EqualValues(t, githubdomain.ReviewStateApproved, reviews[0].State)
+}
+
+func TestHandleGithubEventRecordingFailsAllowsRedelivery(t *testing.T) {
+	service := setupWebhookTest()
+	service.recorder = &failingEventRecorder{}
+
+	payloads := map[string][]byte{
+		githubdomain.WebhookEventPush:              testutils.GetMockDataPushEventPayload(),
+		githubdomain.WebhookEventPullRequest:       testutils.GetMockDataPullRequestMergedEventPayload(),
+		githubdomain.WebhookEventPullRequestReview: testutils.GetMockDataPullRequestReviewEventPayload(),
+	}
+
+	for event, payload := range payloads {
+		result, status := handleSignedEvent(service, event, "delivery1", payload)
+		assert.Nil(t, result)
+		assert.EqualValues(t, http.StatusInternalServerError, status)
+	}
+	assert.EqualValues(t, 0, len(service.deliveries))
+}
@@ -0,0 +1,490 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/syncdomain"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketCommits     = []byte("commits")
+	bucketPulls       = []byte("pulls")
+	bucketCommitPulls = []byte("commit_pulls")
+	bucketReviews     = []byte("reviews")
+	bucketBranches    = []byte("branches")
+	keyCoverage       = []byte("coverage")
+	keySyncState      = []byte("sync_state")
+)
+
+//the branch commit index is keyed on the commit date so commits can be found by date range
+const branchCommitDateFormat = "2006-01-02T15:04:05Z"
+
+//the schedule runs are keyed on the time they were scheduled for so they are kept in order
+const scheduleRunDateFormat = "2006-01-02T15:04:05.000000000Z"
+
+//boltStore persists the data in an embedded bbolt database, each repo has a bucket holding
+//nested buckets for its commits, PRs, reviews and branches
+type boltStore struct {
+	db *bolt.DB
+}
+
+//NewBoltStore opens, or creates, the database at the given path and migrates it to the latest schema
+func NewBoltStore(path string) (Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+//Close closes the database
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+//getRepoKey returns the key of the repo bucket, Github treats owner and repo names case insensitively
+func getRepoKey(owner string, repo string) []byte {
+	return []byte(strings.ToLower(fmt.Sprintf("%s/%s", owner, repo)))
+}
+
+//getPullKey returns the key for a PR, zero padded so the PRs are kept in order
+func getPullKey(pullNumber int64) []byte {
+	return []byte(fmt.Sprintf("%020d", pullNumber))
+}
+
+//getBranchCommitKey returns the key of a commit in the branch index
+func getBranchCommitKey(commit *githubdomain.GetCommitInfo) []byte {
+	return []byte(commit.Commit.Committer.Date.UTC().Format(branchCommitDateFormat) + "/" + commit.SHA)
+}
+
+//getRepoBucket returns the named bucket of the repo, nil is returned if nothing has been stored yet
+func getRepoBucket(tx *bolt.Tx, owner string, repo string, name []byte) *bolt.Bucket {
+	repoBucket := tx.Bucket(bucketRepos).Bucket(getRepoKey(owner, repo))
+	if repoBucket == nil {
+		return nil
+	}
+	return repoBucket.Bucket(name)
+}
+
+//createRepoBucket returns the named bucket of the repo, creating it if needed
+func createRepoBucket(tx *bolt.Tx, owner string, repo string, name []byte) (*bolt.Bucket, error) {
+	repoBucket, err := tx.Bucket(bucketRepos).CreateBucketIfNotExists(getRepoKey(owner, repo))
+	if err != nil {
+		return nil, err
+	}
+	return repoBucket.CreateBucketIfNotExists(name)
+}
+
+//getBranchBucket returns the bucket for the branch, nil is returned if nothing has been stored yet
+func getBranchBucket(tx *bolt.Tx, owner string, repo string, branch string) *bolt.Bucket {
+	branches := getRepoBucket(tx, owner, repo, bucketBranches)
+	if branches == nil {
+		return nil
+	}
+	return branches.Bucket([]byte(branch))
+}
+
+//createBranchBucket returns the bucket for the branch, creating it if needed
+func createBranchBucket(tx *bolt.Tx, owner string, repo string, branch string) (*bolt.Bucket, error) {
+	branches, err := createRepoBucket(tx, owner, repo, bucketBranches)
+	if err != nil {
+		return nil, err
+	}
+	return branches.CreateBucketIfNotExists([]byte(branch))
+}
+
+//putJSON stores the value as json under the key
+func putJSON(bucket *bolt.Bucket, key []byte, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
+
+//getJSON reads the json stored under the key into the value, ErrNotFound is returned if the key isn't there
+func getJSON(bucket *bolt.Bucket, key []byte, value interface{}) error {
+	if bucket == nil {
+		return ErrNotFound
+	}
+	data := bucket.Get(key)
+	if data == nil {
+		return ErrNotFound
+	}
+	return json.Unmarshal(data, value)
+}
+
+//saveCommits stores the commits in the commits bucket of the repo
+//a stored copy with the stats and files of the commit isn't replaced by a copy from a list of commits which has neither
+func saveCommits(tx *bolt.Tx, owner string, repo string, commits []githubdomain.GetCommitInfo) error {
+	bucket, err := createRepoBucket(tx, owner, repo, bucketCommits)
+	if err != nil {
+		return err
+	}
+	for counter := range commits {
+		if commits[counter].Stats == nil {
+			var stored githubdomain.GetCommitInfo
+			if err := getJSON(bucket, []byte(commits[counter].SHA), &stored); err == nil && stored.Stats != nil {
+				continue
+			}
+		}
+		if err := putJSON(bucket, []byte(commits[counter].SHA), &commits[counter]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//SaveCommits stores the commits, a commit never changes so any stored copy is replaced unless it has more detail
+func (s *boltStore) SaveCommits(owner string, repo string, commits []githubdomain.GetCommitInfo) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return saveCommits(tx, owner, repo, commits)
+	})
+}
+
+//GetCommit returns the stored commit
+func (s *boltStore) GetCommit(owner string, repo string, SHA string) (*githubdomain.GetCommitInfo, error) {
+	var result githubdomain.GetCommitInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(getRepoBucket(tx, owner, repo, bucketCommits), []byte(SHA), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+//SaveBranchCommits stores the commits and records that they are part of the branch
+func (s *boltStore) SaveBranchCommits(owner string, repo string, branch string, commits []githubdomain.GetCommitInfo) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := saveCommits(tx, owner, repo, commits); err != nil {
+			return err
+		}
+
+		branchBucket, err := createBranchBucket(tx, owner, repo, branch)
+		if err != nil {
+			return err
+		}
+		index, err := branchBucket.CreateBucketIfNotExists(bucketCommits)
+		if err != nil {
+			return err
+		}
+		for counter := range commits {
+			if err := index.Put(getBranchCommitKey(&commits[counter]), []byte(commits[counter].SHA)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//getBranchCoverage returns the date ranges for which all the commits on the branch have been stored
+func getBranchCoverage(branchBucket *bolt.Bucket) ([]DateRange, error) {
+	var coverage []DateRange
+	if err := getJSON(branchBucket, keyCoverage, &coverage); err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	return coverage, nil
+}
+
+//AddBranchCoverage records that all the commits on the branch in the date range have been stored
+func (s *boltStore) AddBranchCoverage(owner string, repo string, branch string, fromDate time.Time, toDate time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		branchBucket, err := createBranchBucket(tx, owner, repo, branch)
+		if err != nil {
+			return err
+		}
+		coverage, err := getBranchCoverage(branchBucket)
+		if err != nil {
+			return err
+		}
+		coverage = mergeDateRange(coverage, DateRange{From: fromDate.UTC(), To: toDate.UTC()})
+		return putJSON(branchBucket, keyCoverage, coverage)
+	})
+}
+
+//GetBranchCommits returns the commits on the branch in the date range, newest first as Github does
+//ErrNotFound is returned unless the whole date range has been stored
+func (s *boltStore) GetBranchCommits(owner string, repo string, branch string, fromDate time.Time, toDate time.Time) ([]githubdomain.GetCommitInfo, error) {
+	requested := DateRange{From: fromDate.UTC(), To: toDate.UTC()}
+	result := make([]githubdomain.GetCommitInfo, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		branchBucket := getBranchBucket(tx, owner, repo, branch)
+		if branchBucket == nil {
+			return ErrNotFound
+		}
+
+		coverage, err := getBranchCoverage(branchBucket)
+		if err != nil {
+			return err
+		}
+		covered := false
+		for _, dateRange := range coverage {
+			if dateRange.Contains(requested) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return ErrNotFound
+		}
+
+		index := branchBucket.Bucket(bucketCommits)
+		if index == nil {
+			return nil
+		}
+		commits := getRepoBucket(tx, owner, repo, bucketCommits)
+
+		//the keys start with the date so seek to the end of the range and walk backwards
+		from := []byte(requested.From.Format(branchCommitDateFormat))
+		to := []byte(requested.To.Format(branchCommitDateFormat) + "0")
+		cursor := index.Cursor()
+		key, SHA := cursor.Seek(to)
+		if key == nil {
+			key, SHA = cursor.Last()
+		}
+		for ; key != nil && bytes.Compare(key, from) >= 0; key, SHA = cursor.Prev() {
+			if bytes.Compare(key, to) >= 0 {
+				continue
+			}
+			var commit githubdomain.GetCommitInfo
+			if err := getJSON(commits, SHA, &commit); err != nil {
+				return err
+			}
+			result = append(result, commit)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//savePullRequests stores the PRs in the pulls bucket of the repo
+//a stored copy with the details of the PR isn't replaced by a copy from a list of PRs, which has none of them, unless the list copy is newer
+func savePullRequests(tx *bolt.Tx, owner string, repo string, pullRequests []githubdomain.GetSinglePullRequestResponse) error {
+	bucket, err := createRepoBucket(tx, owner, repo, bucketPulls)
+	if err != nil {
+		return err
+	}
+	for counter := range pullRequests {
+		if !pullRequests[counter].HasDetails() {
+			var stored githubdomain.GetSinglePullRequestResponse
+			if err := getJSON(bucket, getPullKey(pullRequests[counter].Number), &stored); err == nil && stored.HasDetails() && !pullRequests[counter].UpdatedAt.After(stored.UpdatedAt) {
+				continue
+			}
+		}
+		if err := putJSON(bucket, getPullKey(pullRequests[counter].Number), &pullRequests[counter]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//SavePullRequests stores the PRs, replacing any stored copy with the latest state unless it has more detail
+func (s *boltStore) SavePullRequests(owner string, repo string, pullRequests []githubdomain.GetSinglePullRequestResponse) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return savePullRequests(tx, owner, repo, pullRequests)
+	})
+}
+
+//GetPullRequest returns the stored PR
+func (s *boltStore) GetPullRequest(owner string, repo string, pullNumber int64) (*githubdomain.GetSinglePullRequestResponse, error) {
+	var result githubdomain.GetSinglePullRequestResponse
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(getRepoBucket(tx, owner, repo, bucketPulls), getPullKey(pullNumber), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+//SaveCommitPullRequests stores the PRs and records that they are the ones associated with the commit
+func (s *boltStore) SaveCommitPullRequests(owner string, repo string, SHA string, pullRequests []githubdomain.GetSinglePullRequestResponse) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := savePullRequests(tx, owner, repo, pullRequests); err != nil {
+			return err
+		}
+
+		bucket, err := createRepoBucket(tx, owner, repo, bucketCommitPulls)
+		if err != nil {
+			return err
+		}
+		pullNumbers := make([]int64, 0, len(pullRequests))
+		for _, pullRequest := range pullRequests {
+			pullNumbers = append(pullNumbers, pullRequest.Number)
+		}
+		return putJSON(bucket, []byte(SHA), pullNumbers)
+	})
+}
+
+//GetCommitPullRequests returns the stored PRs associated with the commit
+//an empty list is returned if the commit is known to have no PRs
+func (s *boltStore) GetCommitPullRequests(owner string, repo string, SHA string) ([]githubdomain.GetSinglePullRequestResponse, error) {
+	var result []githubdomain.GetSinglePullRequestResponse
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var pullNumbers []int64
+		if err := getJSON(getRepoBucket(tx, owner, repo, bucketCommitPulls), []byte(SHA), &pullNumbers); err != nil {
+			return err
+		}
+
+		pulls := getRepoBucket(tx, owner, repo, bucketPulls)
+		result = make([]githubdomain.GetSinglePullRequestResponse, len(pullNumbers))
+		for counter, pullNumber := range pullNumbers {
+			if err := getJSON(pulls, getPullKey(pullNumber), &result[counter]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//SavePullRequestReviews stores all the reviews of the PR, replacing those already stored
+func (s *boltStore) SavePullRequestReviews(owner string, repo string, pullNumber int64, reviews []githubdomain.PullRequestReview) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := createRepoBucket(tx, owner, repo, bucketReviews)
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, getPullKey(pullNumber), reviews)
+	})
+}
+
+//GetPullRequestReviews returns the stored reviews of the PR
+func (s *boltStore) GetPullRequestReviews(owner string, repo string, pullNumber int64) ([]githubdomain.PullRequestReview, error) {
+	var result []githubdomain.PullRequestReview
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(getRepoBucket(tx, owner, repo, bucketReviews), getPullKey(pullNumber), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//SaveSyncState stores the sync state of the repo
+func (s *boltStore) SaveSyncState(state *syncdomain.SyncState) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		repoBucket, err := tx.Bucket(bucketRepos).CreateBucketIfNotExists(getRepoKey(state.Owner, state.Repo))
+		if err != nil {
+			return err
+		}
+		return putJSON(repoBucket, keySyncState, state)
+	})
+}
+
+//GetSyncState returns the stored sync state of the repo
+func (s *boltStore) GetSyncState(owner string, repo string) (*syncdomain.SyncState, error) {
+	var result syncdomain.SyncState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(bucketRepos).Bucket(getRepoKey(owner, repo)), keySyncState, &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+//SaveScheduleRun stores the run of a schedule, replacing any earlier run for the same scheduled time
+func (s *boltStore) SaveScheduleRun(run *scheduledomain.ScheduleRun) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		scheduleBucket, err := tx.Bucket(bucketSchedules).CreateBucketIfNotExists([]byte(run.Schedule))
+		if err != nil {
+			return err
+		}
+		return putJSON(scheduleBucket, []byte(run.ScheduledFor.UTC().Format(scheduleRunDateFormat)), run)
+	})
+}
+
+//GetScheduleRuns returns the most recent runs of the schedule, newest first
+//a limit of zero or less returns every run
+func (s *boltStore) GetScheduleRuns(schedule string, limit int) ([]scheduledomain.ScheduleRun, error) {
+	var result []scheduledomain.ScheduleRun
+	err := s.db.View(func(tx *bolt.Tx) error {
+		scheduleBucket := tx.Bucket(bucketSchedules).Bucket([]byte(schedule))
+		if scheduleBucket == nil {
+			return ErrNotFound
+		}
+
+		cursor := scheduleBucket.Cursor()
+		for key, value := cursor.Last(); key != nil && (limit <= 0 || len(result) < limit); key, value = cursor.Prev() {
+			var run scheduledomain.ScheduleRun
+			if err := json.Unmarshal(value, &run); err != nil {
+				return err
+			}
+			result = append(result, run)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//ReserveWebhookDelivery remembers the delivery ID, returning false if it was already received within the retention period
+//deliveries received before the retention period are removed at the same time so the bucket doesn't grow forever
+func (s *boltStore) ReserveWebhookDelivery(deliveryID string, receivedAt time.Time, retention time.Duration) (bool, error) {
+	reserved := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketWebhooks)
+		var expired [][]byte
+		err := bucket.ForEach(func(key []byte, value []byte) error {
+			var received time.Time
+			if err := json.Unmarshal(value, &received); err != nil {
+				return err
+			}
+			if receivedAt.Sub(received) > retention {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		if bucket.Get([]byte(deliveryID)) != nil {
+			return nil
+		}
+		if err := putJSON(bucket, []byte(deliveryID), receivedAt); err != nil {
+			return err
+		}
+		reserved = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return reserved, nil
+}
+
+//ReleaseWebhookDelivery forgets the delivery ID so a redelivery can be processed
+func (s *boltStore) ReleaseWebhookDelivery(deliveryID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketWebhooks).Delete([]byte(deliveryID))
+	})
+}
@@ -0,0 +1,320 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/syncdomain"
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+)
+
+//openTestStore creates a new database in a temporary directory, the returned function removes it
+func openTestStore(t *testing.T) (Store, string, func()) {
+	dir, err := ioutil.TempDir("", "store")
+	assert.Nil(t, err)
+	path := filepath.Join(dir, "test.db")
+
+	store, err := NewBoltStore(path)
+	assert.Nil(t, err)
+	return store, path, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func getTestCommit(SHA string, date time.Time) githubdomain.GetCommitInfo {
+	commit := githubdomain.GetCommitInfo{SHA: SHA}
+	commit.Commit.Message = "commit " + SHA
+	commit.Commit.Committer.Date = date
+	return commit
+}
+
+func TestNewBoltStoreSetsSchemaVersion(t *testing.T) {
+	store, path, cleanup := openTestStore(t)
+	defer cleanup()
+	assert.Nil(t, store.Close())
+
+	//opening again must leave the version alone
+	store, err := NewBoltStore(path)
+	assert.Nil(t, err)
+	assert.Nil(t, store.(*boltStore).db.View(func(tx *bolt.Tx) error {
+		assert.EqualValues(t, len(migrations), getSchemaVersion(tx))
+		assert.NotNil(t, tx.Bucket(bucketRepos))
+		return nil
+	}))
+}
+
+func TestNewBoltStoreRejectsNewerSchema(t *testing.T) {
+	store, path, cleanup := openTestStore(t)
+	defer cleanup()
+
+	assert.Nil(t, store.(*boltStore).db.Update(func(tx *bolt.Tx) error {
+		return setSchemaVersion(tx, len(migrations)+1)
+	}))
+	assert.Nil(t, store.Close())
+
+	result, err := NewBoltStore(path)
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+}
+
+func TestNewBoltStoreInvalidPath(t *testing.T) {
+	result, err := NewBoltStore(filepath.Join("missing", "directory", "test.db"))
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+}
+
+func TestBoltStoreCommits(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	_, err := store.GetCommit("owner", "repo", "SHA1")
+	assert.EqualValues(t, ErrNotFound, err)
+
+	assert.Nil(t, store.SaveCommits("Owner", "Repo", []githubdomain.GetCommitInfo{getTestCommit("SHA1", time.Now())}))
+
+	result, err := store.GetCommit("owner", "repo", "SHA1")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "SHA1", result.SHA)
+	assert.EqualValues(t, "commit SHA1", result.Commit.Message)
+
+	_, err = store.GetCommit("owner", "repo", "SHA2")
+	assert.EqualValues(t, ErrNotFound, err)
+}
+
+func TestBoltStoreCommitsKeepsDetail(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	detailed := getTestCommit("SHA1", time.Now())
+	detailed.Stats = &githubdomain.CommitStats{Additions: 10, Deletions: 2, Total: 12}
+	detailed.Files = []githubdomain.ChangedFile{{Filename: "file1.txt"}}
+	assert.Nil(t, store.SaveCommits("owner", "repo", []githubdomain.GetCommitInfo{detailed}))
+	assert.Nil(t, store.SaveCommits("owner", "repo", []githubdomain.GetCommitInfo{getTestCommit("SHA1", time.Now())}))
+
+	result, err := store.GetCommit("owner", "repo", "SHA1")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 12, result.Stats.Total)
+	assert.EqualValues(t, "file1.txt", result.Files[0].Filename)
+}
+
+func TestBoltStoreBranchCommits(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	start := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	commits := []githubdomain.GetCommitInfo{
+		getTestCommit("SHA3", start.AddDate(0, 0, 3)),
+		getTestCommit("SHA2", start.AddDate(0, 0, 2)),
+		getTestCommit("SHA1", start.AddDate(0, 0, 1)),
+	}
+
+	assert.Nil(t, store.SaveBranchCommits("owner", "repo", "main", commits))
+
+	//nothing is returned until the date range has been covered
+	_, err := store.GetBranchCommits("owner", "repo", "main", start, start.AddDate(0, 0, 5))
+	assert.EqualValues(t, ErrNotFound, err)
+
+	assert.Nil(t, store.AddBranchCoverage("owner", "repo", "main", start, start.AddDate(0, 0, 5)))
+
+	result, err := store.GetBranchCommits("owner", "repo", "main", start, start.AddDate(0, 0, 5))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(result))
+	assert.EqualValues(t, "SHA3", result[0].SHA)
+	assert.EqualValues(t, "SHA1", result[2].SHA)
+
+	//the end dates are inclusive
+	result, err = store.GetBranchCommits("owner", "repo", "main", start.AddDate(0, 0, 2), start.AddDate(0, 0, 3))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(result))
+	assert.EqualValues(t, "SHA3", result[0].SHA)
+	assert.EqualValues(t, "SHA2", result[1].SHA)
+
+	result, err = store.GetBranchCommits("owner", "repo", "main", start.AddDate(0, 0, 4), start.AddDate(0, 0, 5))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(result))
+
+	_, err = store.GetBranchCommits("owner", "repo", "main", start.AddDate(0, 0, -1), start.AddDate(0, 0, 5))
+	assert.EqualValues(t, ErrNotFound, err)
+
+	_, err = store.GetBranchCommits("owner", "repo", "develop", start, start.AddDate(0, 0, 5))
+	assert.EqualValues(t, ErrNotFound, err)
+}
+
+func TestBoltStorePullRequests(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	_, err := store.GetPullRequest("owner", "repo", 9)
+	assert.EqualValues(t, ErrNotFound, err)
+
+	pulls := []githubdomain.GetSinglePullRequestResponse{{Number: 9, State: "open"}, {Number: 10, State: "closed"}}
+	assert.Nil(t, store.SavePullRequests("owner", "repo", pulls))
+
+	pulls[0].State = "closed"
+	assert.Nil(t, store.SavePullRequests("owner", "repo", pulls[:1]))
+
+	result, err := store.GetPullRequest("owner", "repo", 9)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "closed", result.State)
+
+	result, err = store.GetPullRequest("owner", "repo", 10)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, result.Number)
+}
+
+func TestBoltStorePullRequestsKeepsDetail(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	updated := time.Date(2020, 3, 10, 0, 0, 0, 0, time.UTC)
+	detailed := githubdomain.GetSinglePullRequestResponse{Number: 9, State: "open", UpdatedAt: updated, Commits: 2, Additions: 40}
+	assert.Nil(t, store.SavePullRequests("owner", "repo", []githubdomain.GetSinglePullRequestResponse{detailed}))
+
+	//a list copy of the same PR doesn't replace the details
+	listed := githubdomain.GetSinglePullRequestResponse{Number: 9, State: "open", UpdatedAt: updated}
+	assert.Nil(t, store.SaveCommitPullRequests("owner", "repo", "SHA1", []githubdomain.GetSinglePullRequestResponse{listed}))
+
+	result, err := store.GetPullRequest("owner", "repo", 9)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 40, result.Additions)
+
+	//a newer list copy does as the details are out of date
+	listed.State = "closed"
+	listed.UpdatedAt = updated.AddDate(0, 0, 1)
+	assert.Nil(t, store.SavePullRequests("owner", "repo", []githubdomain.GetSinglePullRequestResponse{listed}))
+
+	result, err = store.GetPullRequest("owner", "repo", 9)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "closed", result.State)
+	assert.False(t, result.HasDetails())
+}
+
+func TestBoltStoreCommitPullRequests(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	_, err := store.GetCommitPullRequests("owner", "repo", "SHA1")
+	assert.EqualValues(t, ErrNotFound, err)
+
+	pulls := []githubdomain.GetSinglePullRequestResponse{{Number: 9, State: "closed"}}
+	assert.Nil(t, store.SaveCommitPullRequests("owner", "repo", "SHA1", pulls))
+	assert.Nil(t, store.SaveCommitPullRequests("owner", "repo", "SHA2", nil))
+
+	result, err := store.GetCommitPullRequests("owner", "repo", "SHA1")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, 9, result[0].Number)
+
+	//the PR is also stored in its own right
+	pull, err := store.GetPullRequest("owner", "repo", 9)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "closed", pull.State)
+
+	result, err = store.GetCommitPullRequests("owner", "repo", "SHA2")
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.EqualValues(t, 0, len(result))
+}
+
+func TestBoltStorePullRequestReviews(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	_, err := store.GetPullRequestReviews("owner", "repo", 9)
+	assert.EqualValues(t, ErrNotFound, err)
+
+	reviews := []githubdomain.PullRequestReview{{ID: 80, State: githubdomain.ReviewStateApproved}}
+	assert.Nil(t, store.SavePullRequestReviews("owner", "repo", 9, reviews))
+
+	result, err := store.GetPullRequestReviews("owner", "repo", 9)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(result))
+	assert.EqualValues(t, githubdomain.ReviewStateApproved, result[0].State)
+}
+
+func TestBoltStoreSyncState(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	_, err := store.GetSyncState("myuser", "myrepo")
+	assert.EqualValues(t, ErrNotFound, err)
+
+	state := syncdomain.SyncState{Owner: "MyUser", Repo: "myrepo", Branch: "main", LastSyncCommits: 3}
+	assert.Nil(t, store.SaveSyncState(&state))
+
+	result, err := store.GetSyncState("myuser", "myrepo")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "main", result.Branch)
+	assert.EqualValues(t, 3, result.LastSyncCommits)
+}
+
+func TestBoltStoreScheduleRuns(t *testing.T) {
+	store, _, cleanup := openTestStore(t)
+	defer cleanup()
+
+	_, err := store.GetScheduleRuns("weekly", 10)
+	assert.EqualValues(t, ErrNotFound, err)
+
+	london, err := time.LoadLocation("Europe/London")
+	assert.Nil(t, err)
+	first := time.Date(2020, 1, 6, 9, 0, 0, 0, london)
+	for i := 0; i < 3; i++ {
+		run := scheduledomain.ScheduleRun{Schedule: "weekly", ScheduledFor: first.AddDate(0, 0, 7*i), Status: scheduledomain.RunStatusSucceeded}
+		assert.Nil(t, store.SaveScheduleRun(&run))
+	}
+	//saving the same scheduled time again replaces the run
+	assert.Nil(t, store.SaveScheduleRun(&scheduledomain.ScheduleRun{Schedule: "weekly", ScheduledFor: first, Status: scheduledomain.RunStatusFailed}))
+	assert.Nil(t, store.SaveScheduleRun(&scheduledomain.ScheduleRun{Schedule: "monthly", ScheduledFor: first, Status: scheduledomain.RunStatusSkipped}))
+
+	result, err := store.GetScheduleRuns("weekly", 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(result))
+	assert.True(t, first.AddDate(0, 0, 14).Equal(result[0].ScheduledFor))
+	assert.True(t, first.Equal(result[2].ScheduledFor))
+	assert.EqualValues(t, scheduledomain.RunStatusFailed, result[2].Status)
+
+	result, err = store.GetScheduleRuns("weekly", 2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(result))
+	assert.True(t, first.AddDate(0, 0, 7).Equal(result[1].ScheduledFor))
+}
+
+func TestBoltStoreWebhookDeliveries(t *testing.T) {
+	store, path, cleanup := openTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	retention := 72 * time.Hour
+	reserved, err := store.ReserveWebhookDelivery("delivery1", now.Add(-retention-time.Minute), retention)
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+	reserved, err = store.ReserveWebhookDelivery("delivery2", now.Add(-time.Minute), retention)
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+
+	//the deliveries are still known after the database is reopened
+	assert.Nil(t, store.Close())
+	store, err = NewBoltStore(path)
+	assert.Nil(t, err)
+
+	reserved, err = store.ReserveWebhookDelivery("delivery2", now, retention)
+	assert.Nil(t, err)
+	assert.False(t, reserved)
+	//the first delivery has expired
+	reserved, err = store.ReserveWebhookDelivery("delivery1", now, retention)
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+
+	assert.Nil(t, store.ReleaseWebhookDelivery("delivery2"))
+	reserved, err = store.ReserveWebhookDelivery("delivery2", now, retention)
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+	assert.Nil(t, store.Close())
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketMeta       = []byte("meta")
+	bucketRepos      = []byte("repos")
+	bucketSchedules  = []byte("schedules")
+	bucketWebhooks   = []byte("webhook_deliveries")
+	keySchemaVersion = []byte("schema_version")
+)
+
+//migration changes the layout of the database from one schema version to the next
+type migration func(tx *bolt.Tx) error
+
+//migrations are applied in order, the schema version is the number that have been applied
+//never change or remove an existing migration, always add a new one to the end
+var migrations = []migration{
+	//1. create the top level buckets, each repo gets its own nested bucket inside repos
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketRepos)
+		return err
+	},
+	//2. create the bucket holding the run history of the scheduled reports, each schedule gets its own nested bucket
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketSchedules)
+		return err
+	},
+	//3. create the bucket holding the IDs of the webhook deliveries received along with when they were received
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketWebhooks)
+		return err
+	},
+}
+
+//getSchemaVersion returns the schema version of the database, zero for a new database
+func getSchemaVersion(tx *bolt.Tx) int {
+	meta := tx.Bucket(bucketMeta)
+	if meta == nil {
+		return 0
+	}
+	value := meta.Get(keySchemaVersion)
+	if len(value) != 8 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(value))
+}
+
+//setSchemaVersion records the schema version of the database
+func setSchemaVersion(tx *bolt.Tx, version int) error {
+	meta, err := tx.CreateBucketIfNotExists(bucketMeta)
+	if err != nil {
+		return err
+	}
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(version))
+	return meta.Put(keySchemaVersion, value)
+}
+
+//migrate brings the database up to the latest schema version
+//a database written by a newer version of the app is rejected rather than risk corrupting it
+func migrate(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		version := getSchemaVersion(tx)
+		if version > len(migrations) {
+			return fmt.Errorf("database schema version %d is newer than the supported version %d", version, len(migrations))
+		}
+
+		for ; version < len(migrations); version++ {
+			if err := migrations[version](tx); err != nil {
+				return fmt.Errorf("unable to migrate database to schema version %d: %s", version+1, err.Error())
+			}
+		}
+		return setSchemaVersion(tx, version)
+	})
+}
@@ -0,0 +1,147 @@
+//Package store persists the data fetched from Github so it doesn't need to be fetched again
+//when a report is re-run over the same period
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/githubdomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/syncdomain"
+)
+
+//ErrNotFound is returned when the requested data hasn't been stored
+var ErrNotFound = errors.New("not found in store")
+
+//Store defines the data that can be persisted for each repo
+type Store interface {
+	SaveCommits(owner string, repo string, commits []githubdomain.GetCommitInfo) error
+	GetCommit(owner string, repo string, SHA string) (*githubdomain.GetCommitInfo, error)
+	SaveBranchCommits(owner string, repo string, branch string, commits []githubdomain.GetCommitInfo) error
+	AddBranchCoverage(owner string, repo string, branch string, fromDate time.Time, toDate time.Time) error
+	GetBranchCommits(owner string, repo string, branch string, fromDate time.Time, toDate time.Time) ([]githubdomain.GetCommitInfo, error)
+	SavePullRequests(owner string, repo string, pullRequests []githubdomain.GetSinglePullRequestResponse) error
+	GetPullRequest(owner string, repo string, pullNumber int64) (*githubdomain.GetSinglePullRequestResponse, error)
+	SaveCommitPullRequests(owner string, repo string, SHA string, pullRequests []githubdomain.GetSinglePullRequestResponse) error
+	GetCommitPullRequests(owner string, repo string, SHA string) ([]githubdomain.GetSinglePullRequestResponse, error)
+	SavePullRequestReviews(owner string, repo string, pullNumber int64, reviews []githubdomain.PullRequestReview) error
+	GetPullRequestReviews(owner string, repo string, pullNumber int64) ([]githubdomain.PullRequestReview, error)
+	SaveSyncState(state *syncdomain.SyncState) error
+	GetSyncState(owner string, repo string) (*syncdomain.SyncState, error)
+	SaveScheduleRun(run *scheduledomain.ScheduleRun) error
+	GetScheduleRuns(schedule string, limit int) ([]scheduledomain.ScheduleRun, error)
+	ReserveWebhookDelivery(deliveryID string, receivedAt time.Time, retention time.Duration) (bool, error)
+	ReleaseWebhookDelivery(deliveryID string) error
+	Close() error
+}
+
+//DateRange is a period of time, both dates are inclusive
+type DateRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+//Contains determines if the other range falls entirely within this one
+func (r DateRange) Contains(other DateRange) bool {
+	return !other.From.Before(r.From) && !other.To.After(r.To)
+}
+
+//mergeDateRange adds the new range to the existing ones, joining any that overlap
+func mergeDateRange(ranges []DateRange, newRange DateRange) []DateRange {
+	result := make([]DateRange, 0, len(ranges)+1)
+	for _, existing := range ranges {
+		if existing.To.Before(newRange.From) || existing.From.After(newRange.To) {
+			result = append(result, existing)
+			continue
+		}
+		if existing.From.Before(newRange.From) {
+			newRange.From = existing.From
+		}
+		if existing.To.After(newRange.To) {
+			newRange.To = existing.To
+		}
+	}
+	return append(result, newRange)
+}
+
+//disabledStore is used when no database has been configured, nothing is saved and nothing is ever found
+type disabledStore struct{}
+
+//NewDisabledStore returns a store that doesn't persist anything so every request goes to Github
+func NewDisabledStore() Store {
+	return &disabledStore{}
+}
+
+func (s *disabledStore) SaveCommits(owner string, repo string, commits []githubdomain.GetCommitInfo) error {
+	return nil
+}
+
+func (s *disabledStore) GetCommit(owner string, repo string, SHA string) (*githubdomain.GetCommitInfo, error) {
+	return nil, ErrNotFound
+}
+
+func (s *disabledStore) SaveBranchCommits(owner string, repo string, branch string, commits []githubdomain.GetCommitInfo) error {
+	return nil
+}
+
+func (s *disabledStore) AddBranchCoverage(owner string, repo string, branch string, fromDate time.Time, toDate time.Time) error {
+	return nil
+}
+
+func (s *disabledStore) GetBranchCommits(owner string, repo string, branch string, fromDate time.Time, toDate time.Time) ([]githubdomain.GetCommitInfo, error) {
+	return nil, ErrNotFound
+}
+
+func (s *disabledStore) SavePullRequests(owner string, repo string, pullRequests []githubdomain.GetSinglePullRequestResponse) error {
+	return nil
+}
+
+func (s *disabledStore) GetPullRequest(owner string, repo string, pullNumber int64) (*githubdomain.GetSinglePullRequestResponse, error) {
+	return nil, ErrNotFound
+}
+
+func (s *disabledStore) SaveCommitPullRequests(owner string, repo string, SHA string, pullRequests []githubdomain.GetSinglePullRequestResponse) error {
+	return nil
+}
+
+func (s *disabledStore) GetCommitPullRequests(owner string, repo string, SHA string) ([]githubdomain.GetSinglePullRequestResponse, error) {
+	return nil, ErrNotFound
+}
+
+func (s *disabledStore) SavePullRequestReviews(owner string, repo string, pullNumber int64, reviews []githubdomain.PullRequestReview) error {
+	return nil
+}
+
+func (s *disabledStore) GetPullRequestReviews(owner string, repo string, pullNumber int64) ([]githubdomain.PullRequestReview, error) {
+	return nil, ErrNotFound
+}
+
+func (s *disabledStore) SaveSyncState(state *syncdomain.SyncState) error {
+	return nil
+}
+
+func (s *disabledStore) GetSyncState(owner string, repo string) (*syncdomain.SyncState, error) {
+	return nil, ErrNotFound
+}
+
+func (s *disabledStore) SaveScheduleRun(run *scheduledomain.ScheduleRun) error {
+	return nil
+}
+
+func (s *disabledStore) GetScheduleRuns(schedule string, limit int) ([]scheduledomain.ScheduleRun, error) {
+	return nil, ErrNotFound
+}
+
+//ReserveWebhookDelivery always succeeds as nothing is remembered, so every delivery looks new
+func (s *disabledStore) ReserveWebhookDelivery(deliveryID string, receivedAt time.Time, retention time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (s *disabledStore) ReleaseWebhookDelivery(deliveryID string) error {
+	return nil
+}
+
+func (s *disabledStore) Close() error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/scheduledomain"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/syncdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateRangeContains(t *testing.T) {
+	now := time.Now().UTC()
+	dateRange := DateRange{From: now.AddDate(0, 0, -10), To: now}
+
+	assert.True(t, dateRange.Contains(dateRange))
+	assert.True(t, dateRange.Contains(DateRange{From: now.AddDate(0, 0, -5), To: now.AddDate(0, 0, -1)}))
+	assert.False(t, dateRange.Contains(DateRange{From: now.AddDate(0, 0, -11), To: now}))
+	assert.False(t, dateRange.Contains(DateRange{From: now.AddDate(0, 0, -5), To: now.Add(time.Second)}))
+}
+
+func TestMergeDateRange(t *testing.T) {
+	now := time.Now().UTC()
+
+	ranges := mergeDateRange(nil, DateRange{From: now.AddDate(0, 0, -10), To: now.AddDate(0, 0, -8)})
+	ranges = mergeDateRange(ranges, DateRange{From: now.AddDate(0, 0, -5), To: now.AddDate(0, 0, -3)})
+	assert.EqualValues(t, 2, len(ranges))
+
+	//overlaps both ranges so they all join together
+	ranges = mergeDateRange(ranges, DateRange{From: now.AddDate(0, 0, -9), To: now.AddDate(0, 0, -4)})
+	assert.EqualValues(t, 1, len(ranges))
+	assert.EqualValues(t, now.AddDate(0, 0, -10), ranges[0].From)
+	assert.EqualValues(t, now.AddDate(0, 0, -3), ranges[0].To)
+}
+
+func TestDisabledStore(t *testing.T) {
+	store := NewDisabledStore()
+	now := time.Now()
+
+	assert.Nil(t, store.SaveCommits("owner", "repo", nil))
+	assert.Nil(t, store.SaveBranchCommits("owner", "repo", "main", nil))
+	assert.Nil(t, store.AddBranchCoverage("owner", "repo", "main", now, now))
+	assert.Nil(t, store.SavePullRequests("owner", "repo", nil))
+	assert.Nil(t, store.SaveCommitPullRequests("owner", "repo", "SHA", nil))
+	assert.Nil(t, store.SavePullRequestReviews("owner", "repo", 1, nil))
+
+	_, err := store.GetCommit("owner", "repo", "SHA")
+	assert.EqualValues(t, ErrNotFound, err)
+	_, err = store.GetBranchCommits("owner", "repo", "main", now, now)
+	assert.EqualValues(t, ErrNotFound, err)
+	_, err = store.GetPullRequest("owner", "repo", 1)
+	assert.EqualValues(t, ErrNotFound, err)
+	_, err = store.GetCommitPullRequests("owner", "repo", "SHA")
+	assert.EqualValues(t, ErrNotFound, err)
+	_, err = store.GetPullRequestReviews("owner", "repo", 1)
+	assert.EqualValues(t, ErrNotFound, err)
+	assert.Nil(t, store.SaveSyncState(&syncdomain.SyncState{Owner: "owner", Repo: "repo"}))
+	_, err = store.GetSyncState("owner", "repo")
+	assert.EqualValues(t, ErrNotFound, err)
+	assert.Nil(t, store.SaveScheduleRun(&scheduledomain.ScheduleRun{Schedule: "weekly"}))
+	_, err = store.GetScheduleRuns("weekly", 10)
+	assert.EqualValues(t, ErrNotFound, err)
+	reserved, err := store.ReserveWebhookDelivery("delivery1", now, time.Hour)
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+	assert.Nil(t, store.ReleaseWebhookDelivery("delivery1"))
+	assert.Nil(t, store.Close())
+}
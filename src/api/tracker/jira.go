@@ -0,0 +1,74 @@
+package tracker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/clients/restclient"
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+)
+
+const (
+	urlGetJiraIssue = "%s/rest/api/2/issue/%s?fields=summary,status"
+)
+
+//jiraIssue is the part of an issue returned by the Jira REST API that is needed
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+//jiraTracker looks up the tickets through the Jira REST API
+type jiraTracker struct {
+	baseURL  string
+	username string
+	token    string
+}
+
+//NewJiraTracker returns a tracker using the Jira at the base URL, such as https://myorg.atlassian.net
+//basic authentication is used when a username is given, Jira Cloud expects an email address and an API token
+func NewJiraTracker(baseURL string, username string, token string) Tracker {
+	return &jiraTracker{baseURL: strings.TrimRight(baseURL, "/"), username: username, token: token}
+}
+
+//GetIssue looks up the ticket, Jira also replies not found when the user isn't allowed to see the ticket
+func (t *jiraTracker) GetIssue(key string) (*ticketdomain.Issue, error) {
+	headers := http.Header{}
+	headers.Set("Accept", "application/json")
+	if t.username != "" {
+		headers.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(t.username+":"+t.token)))
+	}
+
+	response, err := restclient.Get(fmt.Sprintf(urlGetJiraIssue, t.baseURL, url.PathEscape(key)), headers)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	bytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira returned status %d looking up %s", response.StatusCode, key)
+	}
+
+	var issue jiraIssue
+	if err := json.Unmarshal(bytes, &issue); err != nil {
+		return nil, fmt.Errorf("unable to decode the jira ticket %s: %s", key, err.Error())
+	}
+	return &ticketdomain.Issue{Key: issue.Key, Summary: issue.Fields.Summary, Status: issue.Fields.Status.Name}, nil
+}
@@ -0,0 +1,65 @@
+package tracker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//startJiraServer starts a stand-in Jira that knows about PAY-12 and needs the user myuser with the token mytoken
+func startJiraServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, token, ok := r.BasicAuth(); !ok || username != "myuser" || token != "mytoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/rest/api/2/issue/PAY-12":
+			w.Write([]byte(`{"key":"PAY-12","fields":{"summary":"Handle refunds","status":{"name":"In Progress"}}}`))
+		case "/rest/api/2/issue/PAY-99":
+			w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+		}
+	}))
+}
+
+func TestJiraTrackerGetIssue(t *testing.T) {
+	server := startJiraServer()
+	defer server.Close()
+
+	issue, err := NewJiraTracker(server.URL+"/", "myuser", "mytoken").GetIssue("PAY-12")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PAY-12", issue.Key)
+	assert.EqualValues(t, "Handle refunds", issue.Summary)
+	assert.EqualValues(t, "In Progress", issue.Status)
+}
+
+func TestJiraTrackerGetIssueNotFound(t *testing.T) {
+	server := startJiraServer()
+	defer server.Close()
+
+	issue, err := NewJiraTracker(server.URL, "myuser", "mytoken").GetIssue("PAY-13")
+	assert.Nil(t, issue)
+	assert.EqualValues(t, ErrNotFound, err)
+}
+
+func TestJiraTrackerGetIssueErrors(t *testing.T) {
+	server := startJiraServer()
+	defer server.Close()
+
+	issue, err := NewJiraTracker(server.URL, "myuser", "wrongtoken").GetIssue("PAY-12")
+	assert.Nil(t, issue)
+	assert.EqualValues(t, "jira returned status 401 looking up PAY-12", err.Error())
+
+	issue, err = NewJiraTracker(server.URL, "myuser", "mytoken").GetIssue("PAY-99")
+	assert.Nil(t, issue)
+	assert.Contains(t, err.Error(), "unable to decode the jira ticket PAY-99")
+
+	issue, err = NewJiraTracker("http://127.0.0.1:0", "", "").GetIssue("PAY-12")
+	assert.Nil(t, issue)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,55 @@
+//Package tracker looks up the tickets referenced by commits and PRs in an issue tracker such as Jira
+package tracker
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+)
+
+var (
+	//ErrNotFound is returned when the ticket doesn't exist
+	ErrNotFound = errors.New("ticket not found")
+	//ErrDisabled is returned when no issue tracker has been configured so nothing can be looked up
+	ErrDisabled = errors.New("no issue tracker configured")
+)
+
+//Tracker looks up tickets by their key, such as PAY-123
+type Tracker interface {
+	GetIssue(key string) (*ticketdomain.Issue, error)
+}
+
+//disabledTracker is used when no issue tracker has been configured, nothing can be looked up
+type disabledTracker struct{}
+
+//NewDisabledTracker returns a tracker that can't look up any tickets
+func NewDisabledTracker() Tracker {
+	return &disabledTracker{}
+}
+
+func (t *disabledTracker) GetIssue(key string) (*ticketdomain.Issue, error) {
+	return nil, ErrDisabled
+}
+
+//localTracker holds the tickets in memory, it stands in for a real issue tracker
+type localTracker struct {
+	issues map[string]ticketdomain.Issue
+}
+
+//NewLocalTracker returns a tracker holding the given tickets, the keys aren't case sensitive
+func NewLocalTracker(issues []ticketdomain.Issue) Tracker {
+	tracker := &localTracker{issues: make(map[string]ticketdomain.Issue)}
+	for _, issue := range issues {
+		tracker.issues[strings.ToUpper(issue.Key)] = issue
+	}
+	return tracker
+}
+
+func (t *localTracker) GetIssue(key string) (*ticketdomain.Issue, error) {
+	issue, ok := t.issues[strings.ToUpper(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &issue, nil
+}
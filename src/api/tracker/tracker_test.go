@@ -0,0 +1,27 @@
+package tracker
+
+import (
+	"testing"
+
+	"github.com/greendinosaur/gh-commit-info/src/api/domain/ticketdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisabledTracker(t *testing.T) {
+	issue, err := NewDisabledTracker().GetIssue("PAY-12")
+	assert.Nil(t, issue)
+	assert.EqualValues(t, ErrDisabled, err)
+}
+
+func TestLocalTracker(t *testing.T) {
+	tracker := NewLocalTracker([]ticketdomain.Issue{{Key: "PAY-12", Summary: "Handle refunds", Status: "In Progress"}})
+
+	issue, err := tracker.GetIssue("pay-12")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PAY-12", issue.Key)
+	assert.EqualValues(t, "In Progress", issue.Status)
+
+	issue, err = tracker.GetIssue("PAY-13")
+	assert.Nil(t, issue)
+	assert.EqualValues(t, ErrNotFound, err)
+}
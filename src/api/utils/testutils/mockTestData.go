@@ -55,7 +55,7 @@ func GetMockDataSingleCommitResponseStatusCode() int {
 
 //GetMockDataSingleCommitResponseMessage represents mock data to be used for a SingleCommitResponse
 func GetMockDataSingleCommitResponseMessage() io.ReadCloser {
-	return ioutil.NopCloser(strings.NewReader(`{"url":"http://www.github.com","sha":"AABCDEF123456","commit":{"url":"http://www.github.com","author":{"name":"some name","email":"email@email.com","date":"2019-12-09T15:00:04.061358Z"},"committer":{"name":"some committer","email":"someemail@email.com","date":"2019-12-09T15:00:04.061358Z"},"message":"some commit message"},"author":{"login":"some loing id","id":9876,"type":"user","site_admin":true},"committer":{"login":"login id","id":12345,"type":"user","site_admin":false},"parents":[{"url":"http://test.com","sha":"ABCDEF123456768"},{"url":"http://test12.com","sha":"ABFGGG"}]}`))
+	return ioutil.NopCloser(strings.NewReader(`{"url":"http://www.github.com","sha":"AABCDEF123456","commit":{"url":"http://www.github.com","author":{"name":"some name","email":"email@email.com","date":"2019-12-09T15:00:04.061358Z"},"committer":{"name":"some committer","email":"someemail@email.com","date":"2019-12-09T15:00:04.061358Z"},"message":"some commit message","verification":{"verified":false,"reason":"unsigned","signature":"","payload":""}},"author":{"login":"some loing id","id":9876,"type":"user","site_admin":true},"committer":{"login":"login id","id":12345,"type":"user","site_admin":false},"parents":[{"url":"http://test.com","sha":"ABCDEF123456768"},{"url":"http://test12.com","sha":"ABFGGG"}],"stats":{"additions":10,"deletions":2,"total":12},"files":[{"filename":"file1.txt","status":"modified","additions":10,"deletions":2,"changes":12}]}`))
 }
 
 //GetMockDataSingleSliceCommitResponsesMessage returns a single commit that is a merge commit
@@ -75,7 +75,12 @@ func GetMockDataNoPRForCommitResponsesMessage() io.ReadCloser {
 
 //GetMockDataApprovedPRForCommitResponsesMessage returns a matching approved PR for the commit sha AABCDEF123456
 func GetMockDataApprovedPRForCommitResponsesMessage() io.ReadCloser {
-	return ioutil.NopCloser(strings.NewReader(`[{"url":"some URL","id":123456,"number":9,"state":"closed","title":"Title of the PR","created_at":"2019-11-27T14:30:10.578255Z","updated_at":"2019-10-28T14:30:10.578369Z","closed_at":"2019-10-28T14:30:10.578369Z","merged_at":"2019-10-28T14:30:10.578369Z","merge_commit_sha":"AABCDEF123456","user":{"login":"My Login ID","id":123456,"type":"A user","site_admin":true},"assignee":{"login":"A Second Login ID","id":8767,"type":"A user","site_admin":false},"base":{"label":"A label","ref":"A Reference","sha":"ABCDEF123456768"}}]`))
+	return ioutil.NopCloser(strings.NewReader(`[{"url":"some URL","id":123456,"number":9,"state":"closed","title":"Title of the PR","created_at":"2019-11-27T14:30:10.578255Z","updated_at":"2019-10-28T14:30:10.578369Z","closed_at":"2019-10-28T14:30:10.578369Z","merged_at":"2019-10-28T14:30:10.578369Z","merge_commit_sha":"AABCDEF123456","user":{"login":"My Login ID","id":123456,"type":"A user","site_admin":true},"assignee":{"login":"A Second Login ID","id":8767,"type":"A user","site_admin":false},"base":{"label":"myuser:main","ref":"main","sha":"ABCDEF123456768"}}]`))
+}
+
+//GetMockDataApprovedPROnOtherBranchForCommitResponsesMessage returns a PR for the commit sha AABCDEF123456 that was merged into develop
+func GetMockDataApprovedPROnOtherBranchForCommitResponsesMessage() io.ReadCloser {
+	return ioutil.NopCloser(strings.NewReader(`[{"url":"some URL","id":123457,"number":10,"state":"closed","title":"Title of the PR into develop","created_at":"2019-11-27T14:30:10.578255Z","updated_at":"2019-10-28T14:30:10.578369Z","closed_at":"2019-10-28T14:30:10.578369Z","merged_at":"2019-10-28T14:30:10.578369Z","merge_commit_sha":"DEVELOP123456","user":{"login":"My Login ID","id":123456,"type":"A user","site_admin":true},"assignee":{"login":"A Second Login ID","id":8767,"type":"A user","site_admin":false},"base":{"label":"myuser:develop","ref":"develop","sha":"ABCDEF123456768"}}]`))
 }
 
 //GetMockDataClosedPRForCommitResponsesMessage returns a matching closed but not merged PR for the commit sha AABCDEF123456
@@ -83,6 +88,36 @@ func GetMockDataClosedPRForCommitResponsesMessage() io.ReadCloser {
 	return ioutil.NopCloser(strings.NewReader(`[{"url":"some URL","id":123456,"number":9,"state":"closed","title":"Title of the PR","created_at":"2019-11-27T14:30:10.578255Z","updated_at":"2019-10-28T14:30:10.578369Z","closed_at":"2019-10-28T14:30:10.578369Z","user":{"login":"My Login ID","id":123456,"type":"A user","site_admin":true},"assignee":{"login":"A Second Login ID","id":8767,"type":"A user","site_admin":false},"base":{"label":"A label","ref":"A Reference","sha":"ABCDEF123456768"}}]`))
 }
 
+//GetMockDataRepoResponseStatusCode represents mock data to be used for a successful status code
+func GetMockDataRepoResponseStatusCode() int {
+	return http.StatusOK
+}
+
+//GetMockDataRepoResponseMessage returns a repo whose default branch is main
+func GetMockDataRepoResponseMessage() io.ReadCloser {
+	return ioutil.NopCloser(strings.NewReader(`{"id":1296269,"name":"myrepo","full_name":"myuser/myrepo","owner":{"login":"myuser","id":1,"type":"User","site_admin":false},"private":false,"html_url":"https://github.com/myuser/myrepo","description":"some description","fork":false,"url":"https://api.github.com/repos/myuser/myrepo","default_branch":"main","archived":false,"created_at":"2019-01-26T19:01:12Z","updated_at":"2019-12-09T15:00:04Z","pushed_at":"2019-12-09T15:00:04Z"}`))
+}
+
+//GetMockDataOrgReposResponseStatusCode represents mock data to be used for a successful status code
+func GetMockDataOrgReposResponseStatusCode() int {
+	return http.StatusOK
+}
+
+//GetMockDataOrgReposResponseMessage returns the repos of an organisation, the last of which is archived
+func GetMockDataOrgReposResponseMessage() io.ReadCloser {
+	return ioutil.NopCloser(strings.NewReader(`[{"id":1296269,"name":"myrepo","full_name":"myorg/myrepo","owner":{"login":"myorg","id":2,"type":"Organization","site_admin":false},"default_branch":"main","archived":false},{"id":1296270,"name":"broken","full_name":"myorg/broken","owner":{"login":"myorg","id":2,"type":"Organization","site_admin":false},"default_branch":"main","archived":false},{"id":1296271,"name":"old","full_name":"myorg/old","owner":{"login":"myorg","id":2,"type":"Organization","site_admin":false},"default_branch":"master","archived":true}]`))
+}
+
+//GetMockDataPRReviewsResponseStatusCode represents mock data to be used for a successful status code
+func GetMockDataPRReviewsResponseStatusCode() int {
+	return http.StatusOK
+}
+
+//GetMockDataPRReviewsResponseMessage returns a single approving review of PR 9
+func GetMockDataPRReviewsResponseMessage() io.ReadCloser {
+	return ioutil.NopCloser(strings.NewReader(`[{"id":80,"node_id":"MDE3OlB1bGxSZXF1ZXN0UmV2aWV3ODA=","user":{"login":"A Second Login ID","id":8767,"type":"User","site_admin":false},"body":"Looks good","state":"APPROVED","html_url":"https://github.com/myuser/myrepo/pull/9#pullrequestreview-80","pull_request_url":"https://api.github.com/repos/myuser/myrepo/pulls/9","author_association":"MEMBER","submitted_at":"2019-12-09T14:00:00Z","commit_id":"FEDCBA654321"}]`))
+}
+
 //represents the error messages returned when validating parameters provided to service functions
 const (
 	ErrorMessageAuthentication = "Requires authentication"
@@ -91,4 +126,5 @@ const (
 	ErrorMessageScope          = "invalid scope parameter"
 	ErrorMessagePull           = "invalid pull parameter"
 	ErrorMessageInvalidSHA     = "invalid SHA parameter"
+	ErrorMessageBranch         = "invalid branch parameter"
 )
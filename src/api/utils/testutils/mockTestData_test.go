@@ -16,6 +16,7 @@ func TestConstants(t *testing.T) {
 	assert.EqualValues(t, ErrorMessageScope, "invalid scope parameter")
 	assert.EqualValues(t, ErrorMessagePull, "invalid pull parameter")
 	assert.EqualValues(t, ErrorMessageInvalidSHA, "invalid SHA parameter")
+	assert.EqualValues(t, ErrorMessageBranch, "invalid branch parameter")
 
 }
 
@@ -39,6 +40,18 @@ func TestGetMockDataSingleCommitResponseStatusCode(t *testing.T) {
 	assert.EqualValues(t, http.StatusOK, GetMockDataSingleCommitResponseStatusCode())
 }
 
+func TestGetMockDataRepoResponseStatusCode(t *testing.T) {
+	assert.EqualValues(t, http.StatusOK, GetMockDataRepoResponseStatusCode())
+}
+
+func TestGetMockDataOrgReposResponseStatusCode(t *testing.T) {
+	assert.EqualValues(t, http.StatusOK, GetMockDataOrgReposResponseStatusCode())
+}
+
+func TestGetMockDataPRReviewsResponseStatusCode(t *testing.T) {
+	assert.EqualValues(t, http.StatusOK, GetMockDataPRReviewsResponseStatusCode())
+}
+
 func TestGetMockDataUnauthorisedResponseMessage(t *testing.T) {
 
 	buf := new(bytes.Buffer)
@@ -76,7 +89,7 @@ func TestGetMockDataSingleCommitResponseMessage(t *testing.T) {
 	buf := new(bytes.Buffer)
 	buf.ReadFrom(GetMockDataSingleCommitResponseMessage())
 	newStr := buf.String()
-	assert.EqualValues(t, `{"url":"http://www.github.com","sha":"AABCDEF123456","commit":{"url":"http://www.github.com","author":{"name":"some name","email":"email@email.com","date":"2019-12-09T15:00:04.061358Z"},"committer":{"name":"some committer","email":"someemail@email.com","date":"2019-12-09T15:00:04.061358Z"},"message":"some commit message"},"author":{"login":"some loing id","id":9876,"type":"user","site_admin":true},"committer":{"login":"login id","id":12345,"type":"user","site_admin":false},"parents":[{"url":"http://test.com","sha":"ABCDEF123456768"},{"url":"http://test12.com","sha":"ABFGGG"}]}`, newStr)
+	assert.EqualValues(t, `{"url":"http://www.github.com","sha":"AABCDEF123456","commit":{"url":"http://www.github.com","author":{"name":"some name","email":"email@email.com","date":"2019-12-09T15:00:04.061358Z"},"committer":{"name":"some committer","email":"someemail@email.com","date":"2019-12-09T15:00:04.061358Z"},"message":"some commit message","verification":{"verified":false,"reason":"unsigned","signature":"","payload":""}},"author":{"login":"some loing id","id":9876,"type":"user","site_admin":true},"committer":{"login":"login id","id":12345,"type":"user","site_admin":false},"parents":[{"url":"http://test.com","sha":"ABCDEF123456768"},{"url":"http://test12.com","sha":"ABFGGG"}],"stats":{"additions":10,"deletions":2,"total":12},"files":[{"filename":"file1.txt","status":"modified","additions":10,"deletions":2,"changes":12}]}`, newStr)
 }
 
 func TestGetMockDataSingleSliceCommitResponsesMessage(t *testing.T) {
@@ -108,7 +121,15 @@ func TestGetMockDataApprovedPRForCommitResponsesMessage(t *testing.T) {
 	buf := new(bytes.Buffer)
 	buf.ReadFrom(GetMockDataApprovedPRForCommitResponsesMessage())
 	newStr := buf.String()
-	assert.EqualValues(t, `[{"url":"some URL","id":123456,"number":9,"state":"closed","title":"Title of the PR","created_at":"2019-11-27T14:30:10.578255Z","updated_at":"2019-10-28T14:30:10.578369Z","closed_at":"2019-10-28T14:30:10.578369Z","merged_at":"2019-10-28T14:30:10.578369Z","merge_commit_sha":"AABCDEF123456","user":{"login":"My Login ID","id":123456,"type":"A user","site_admin":true},"assignee":{"login":"A Second Login ID","id":8767,"type":"A user","site_admin":false},"base":{"label":"A label","ref":"A Reference","sha":"ABCDEF123456768"}}]`, newStr)
+	assert.EqualValues(t, `[{"url":"some URL","id":123456,"number":9,"state":"closed","title":"Title of the PR","created_at":"2019-11-27T14:30:10.578255Z","updated_at":"2019-10-28T14:30:10.578369Z","closed_at":"2019-10-28T14:30:10.578369Z","merged_at":"2019-10-28T14:30:10.578369Z","merge_commit_sha":"AABCDEF123456","user":{"login":"My Login ID","id":123456,"type":"A user","site_admin":true},"assignee":{"login":"A Second Login ID","id":8767,"type":"A user","site_admin":false},"base":{"label":"myuser:main","ref":"main","sha":"ABCDEF123456768"}}]`, newStr)
+}
+
+func TestGetMockDataApprovedPROnOtherBranchForCommitResponsesMessage(t *testing.T) {
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(GetMockDataApprovedPROnOtherBranchForCommitResponsesMessage())
+	newStr := buf.String()
+	assert.EqualValues(t, `[{"url":"some URL","id":123457,"number":10,"state":"closed","title":"Title of the PR into develop","created_at":"2019-11-27T14:30:10.578255Z","updated_at":"2019-10-28T14:30:10.578369Z","closed_at":"2019-10-28T14:30:10.578369Z","merged_at":"2019-10-28T14:30:10.578369Z","merge_commit_sha":"DEVELOP123456","user":{"login":"My Login ID","id":123456,"type":"A user","site_admin":true},"assignee":{"login":"A Second Login ID","id":8767,"type":"A user","site_admin":false},"base":{"label":"myuser:develop","ref":"develop","sha":"ABCDEF123456768"}}]`, newStr)
 }
 
 func TestGetMockDataClosedPRForCommitResponsesMessage(t *testing.T) {
@@ -118,3 +139,27 @@ func TestGetMockDataClosedPRForCommitResponsesMessage(t *testing.T) {
 	newStr := buf.String()
 	assert.EqualValues(t, `[{"url":"some URL","id":123456,"number":9,"state":"closed","title":"Title of the PR","created_at":"2019-11-27T14:30:10.578255Z","updated_at":"2019-10-28T14:30:10.578369Z","closed_at":"2019-10-28T14:30:10.578369Z","user":{"login":"My Login ID","id":123456,"type":"A user","site_admin":true},"assignee":{"login":"A Second Login ID","id":8767,"type":"A user","site_admin":false},"base":{"label":"A label","ref":"A Reference","sha":"ABCDEF123456768"}}]`, newStr)
 }
+
+func TestGetMockDataRepoResponseMessage(t *testing.T) {
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(GetMockDataRepoResponseMessage())
+	newStr := buf.String()
+	assert.EqualValues(t, `{"id":1296269,"name":"myrepo","full_name":"myuser/myrepo","owner":{"login":"myuser","id":1,"type":"User","site_admin":false},"private":false,"html_url":"https://github.com/myuser/myrepo","description":"some description","fork":false,"url":"https://api.github.com/repos/myuser/myrepo","default_branch":"main","archived":false,"created_at":"2019-01-26T19:01:12Z","updated_at":"2019-12-09T15:00:04Z","pushed_at":"2019-12-09T15:00:04Z"}`, newStr)
+}
+
+func TestGetMockDataOrgReposResponseMessage(t *testing.T) {
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(GetMockDataOrgReposResponseMessage())
+	newStr := buf.String()
+	assert.EqualValues(t, `[{"id":1296269,"name":"myrepo","full_name":"myorg/myrepo","owner":{"login":"myorg","id":2,"type":"Organization","site_admin":false},"default_branch":"main","archived":false},{"id":1296270,"name":"broken","full_name":"myorg/broken","owner":{"login":"myorg","id":2,"type":"Organization","site_admin":false},"default_branch":"main","archived":false},{"id":1296271,"name":"old","full_name":"myorg/old","owner":{"login":"myorg","id":2,"type":"Organization","site_admin":false},"default_branch":"master","archived":true}]`, newStr)
+}
+
+func TestGetMockDataPRReviewsResponseMessage(t *testing.T) {
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(GetMockDataPRReviewsResponseMessage())
+	newStr := buf.String()
+	assert.EqualValues(t, `[{"id":80,"node_id":"MDE3OlB1bGxSZXF1ZXN0UmV2aWV3ODA=","user":{"login":"A Second Login ID","id":8767,"type":"User","site_admin":false},"body":"Looks good","state":"APPROVED","html_url":"https://github.com/myuser/myrepo/pull/9#pullrequestreview-80","pull_request_url":"https://api.github.com/repos/myuser/myrepo/pulls/9","author_association":"MEMBER","submitted_at":"2019-12-09T14:00:00Z","commit_id":"FEDCBA654321"}]`, newStr)
+}
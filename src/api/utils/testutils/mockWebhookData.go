@@ -0,0 +1,39 @@
+package testutils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+//GetWebhookSignature returns the X-Hub-Signature-256 header Github would send for the payload
+func GetWebhookSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+//GetMockDataPushEventPayload returns a push event to the main branch captured from Github
+func GetMockDataPushEventPayload() []byte {
+	return []byte(`{"ref":"refs/heads/main","before":"ABCDEF123456768","after":"AABCDEF123456","created":false,"deleted":false,"forced":false,"base_ref":null,"compare":"https://github.com/myuser/myrepo/compare/ABCDEF123456...AABCDEF12345","commits":[{"id":"AABCDEF123456","tree_id":"FFF000111222","distinct":true,"message":"Merge pull request #9 from myuser/feature\n\nTitle of the PR","timestamp":"2019-12-09T15:00:04+00:00","url":"https://github.com/myuser/myrepo/commit/AABCDEF123456","author":{"name":"some name","email":"email@email.com","username":"someuser"},"committer":{"name":"GitHub","email":"noreply@github.com","username":"web-flow"},"added":[],"removed":[],"modified":["README.md"]}],"head_commit":{"id":"AABCDEF123456","tree_id":"FFF000111222","distinct":true,"message":"Merge pull request #9 from myuser/feature\n\nTitle of the PR","timestamp":"2019-12-09T15:00:04+00:00","url":"https://github.com/myuser/myrepo/commit/AABCDEF123456","author":{"name":"some name","email":"email@email.com","username":"someuser"},"committer":{"name":"GitHub","email":"noreply@github.com","username":"web-flow"},"added":[],"removed":[],"modified":["README.md"]},"repository":{"id":1296269,"node_id":"MDEwOlJlcG9zaXRvcnkxMjk2MjY5","name":"myrepo","full_name":"myuser/myrepo","private":false,"owner":{"name":"myuser","email":"email@email.com","login":"myuser","id":1,"type":"User","site_admin":false},"html_url":"https://github.com/myuser/myrepo","created_at":1548529272,"updated_at":"2019-12-09T15:00:04Z","pushed_at":1575903604,"default_branch":"main","master_branch":"main"},"pusher":{"name":"someuser","email":"email@email.com"},"sender":{"login":"someuser","id":9876,"type":"User","site_admin":false}}`)
+}
+
+//GetMockDataPushEventOtherBranchPayload returns a push event to a feature branch captured from Github
+func GetMockDataPushEventOtherBranchPayload() []byte {
+	return []byte(`{"ref":"refs/heads/feature","before":"0000000000000000000000000000000000000000","after":"FEDCBA654321","created":true,"deleted":false,"forced":false,"base_ref":null,"commits":[{"id":"FEDCBA654321","tree_id":"FFF000111333","distinct":true,"message":"some commit message","timestamp":"2019-12-08T10:00:00+00:00","url":"https://github.com/myuser/myrepo/commit/FEDCBA654321","author":{"name":"some name","email":"email@email.com","username":"someuser"},"committer":{"name":"some name","email":"email@email.com","username":"someuser"},"added":["feature.go"],"removed":[],"modified":[]}],"head_commit":null,"repository":{"id":1296269,"name":"myrepo","full_name":"myuser/myrepo","private":false,"owner":{"name":"myuser","email":"email@email.com","login":"myuser","id":1,"type":"User","site_admin":false},"html_url":"https://github.com/myuser/myrepo","created_at":1548529272,"pushed_at":1575800000,"default_branch":"main"},"pusher":{"name":"someuser","email":"email@email.com"},"sender":{"login":"someuser","id":9876,"type":"User","site_admin":false}}`)
+}
+
+//GetMockDataPullRequestMergedEventPayload returns a pull_request event for PR 9 being merged into main captured from Github
+func GetMockDataPullRequestMergedEventPayload() []byte {
+	return []byte(`{"action":"closed","number":9,"pull_request":{"url":"https://api.github.com/repos/myuser/myrepo/pulls/9","id":123456,"number":9,"state":"closed","title":"Title of the PR","user":{"login":"someuser","id":9876,"type":"User","site_admin":false},"body":"Adds the feature","created_at":"2019-12-08T10:05:00Z","updated_at":"2019-12-09T15:00:05Z","closed_at":"2019-12-09T15:00:04Z","merged_at":"2019-12-09T15:00:04Z","merge_commit_sha":"AABCDEF123456","assignee":null,"head":{"label":"myuser:feature","ref":"feature","sha":"FEDCBA654321"},"base":{"label":"myuser:main","ref":"main","sha":"ABCDEF123456768"},"author_association":"OWNER","draft":false,"merged":true,"mergeable_state":"unknown","merged_by":{"login":"A Second Login ID","id":8767,"type":"User","site_admin":false},"commits":1},"repository":{"id":1296269,"name":"myrepo","full_name":"myuser/myrepo","private":false,"owner":{"login":"myuser","id":1,"type":"User","site_admin":false},"html_url":"https://github.com/myuser/myrepo","created_at":"2019-01-26T19:01:12Z","pushed_at":"2019-12-09T15:00:04Z","default_branch":"main"},"sender":{"login":"A Second Login ID","id":8767,"type":"User","site_admin":false}}`)
+}
+
+//GetMockDataPullRequestReviewEventPayload returns a pull_request_review event approving PR 9 captured from Github
+func GetMockDataPullRequestReviewEventPayload() []byte {
+	return []byte(`{"action":"submitted","review":{"id":80,"node_id":"MDE3OlB1bGxSZXF1ZXN0UmV2aWV3ODA=","user":{"login":"A Second Login ID","id":8767,"type":"User","site_admin":false},"body":"Looks good","commit_id":"FEDCBA654321","submitted_at":"2019-12-09T14:00:00Z","state":"approved","html_url":"https://github.com/myuser/myrepo/pull/9#pullrequestreview-80","pull_request_url":"https://api.github.com/repos/myuser/myrepo/pulls/9","author_association":"MEMBER"},"pull_request":{"url":"https://api.github.com/repos/myuser/myrepo/pulls/9","id":123456,"number":9,"state":"open","title":"Title of the PR","user":{"login":"someuser","id":9876,"type":"User","site_admin":false},"created_at":"2019-12-08T10:05:00Z","updated_at":"2019-12-09T14:00:00Z","closed_at":null,"merged_at":null,"merge_commit_sha":"FFF999888777","head":{"label":"myuser:feature","ref":"feature","sha":"FEDCBA654321"},"base":{"label":"myuser:main","ref":"main","sha":"ABCDEF123456768"},"author_association":"OWNER"},"repository":{"id":1296269,"name":"myrepo","full_name":"myuser/myrepo","private":false,"owner":{"login":"myuser","id":1,"type":"User","site_admin":false},"html_url":"https://github.com/myuser/myrepo","created_at":"2019-01-26T19:01:12Z","pushed_at":"2019-12-09T15:00:04Z","default_branch":"main"},"sender":{"login":"A Second Login ID","id":8767,"type":"User","site_admin":false}}`)
+}
+
+//GetMockDataPingEventPayload returns the ping event Github sends when a webhook is first set-up
+func GetMockDataPingEventPayload() []byte {
+	return []byte(`{"zen":"Design for failure.","hook_id":109948940,"hook":{"type":"Repository","id":109948940,"name":"web","active":true,"events":["push","pull_request","pull_request_review"],"config":{"content_type":"json","insecure_ssl":"0","url":"https://example.com/webhooks/github"}},"repository":{"id":1296269,"name":"myrepo","full_name":"myuser/myrepo","private":false,"owner":{"login":"myuser","id":1,"type":"User","site_admin":false},"default_branch":"main"},"sender":{"login":"myuser","id":1,"type":"User","site_admin":false}}`)
+}
@@ -0,0 +1,30 @@
+package testutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebhookSignature(t *testing.T) {
+	//example taken from the Github documentation on validating webhook deliveries
+	signature := GetWebhookSignature("It's a Secret to Everybody", []byte("Hello, World!"))
+	assert.EqualValues(t, "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", signature)
+}
+
+func TestMockWebhookPayloadsAreValidJSON(t *testing.T) {
+	payloads := [][]byte{
+		GetMockDataPushEventPayload(),
+		GetMockDataPushEventOtherBranchPayload(),
+		GetMockDataPullRequestMergedEventPayload(),
+		GetMockDataPullRequestReviewEventPayload(),
+		GetMockDataPingEventPayload(),
+	}
+
+	for _, payload := range payloads {
+		var target map[string]interface{}
+		assert.Nil(t, json.Unmarshal(payload, &target))
+		assert.NotNil(t, target["repository"])
+	}
+}